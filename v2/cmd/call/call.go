@@ -0,0 +1,382 @@
+// Package call implements the "go-restli call" subcommand, which calls the resources declared by a go-restli manifest
+// without generating any code, using the dynamic client.
+package call
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd/dynamic"
+	"github.com/PapaCharlie/go-restli/v2/d2"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/go-zookeeper/zk"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Command returns the "call" subcommand
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "call RESOURCE [METHOD]",
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(1, 2),
+	}
+	cmd.Short = strings.TrimSpace(`
+Calls the given method of the given resource, as declared in the manifest
+(go-restli-manifest.gr.json) written by the code generator. The request is
+validated and encoded according to the manifest, and the response is printed
+as JSON. If the method is omitted, the resource's methods are listed instead.
+Subresources are named after their full path, e.g. "collection/subcollection".`)
+	cmd.Example = strings.TrimSpace(`
+go-restli call --manifest go-restli-manifest.gr.json --host http://localhost:8080 collection get --key 123
+go-restli call -m go-restli-manifest.gr.json --host http://localhost:8080 collection batch_get --key 1 --key 2
+go-restli call -m go-restli-manifest.gr.json --host http://localhost:8080 collection search --params '{"keywords": "foo"}'
+go-restli call -m go-restli-manifest.gr.json --zk-hosts zk:2181 collection/subcollection create \
+	--path-key collectionId=123 --entity @entity.json`)
+
+	var manifestFile string
+	const manifestFlag = "manifest"
+	cmd.Flags().StringVarP(&manifestFile, manifestFlag, "m", "", "The manifest that declares the resource")
+	_ = cmd.MarkFlagRequired(manifestFlag)
+
+	var host string
+	cmd.Flags().StringVar(&host, "host", "", "The URL of the host to send the request to")
+
+	var zkHosts []string
+	cmd.Flags().StringSliceVar(&zkHosts, "zk-hosts", nil,
+		"The ZooKeeper hosts used to resolve the resource's host with D2, instead of --host")
+	var d2Service string
+	cmd.Flags().StringVar(&d2Service, "d2-service", "",
+		"The D2 service to resolve hosts from, if it is not the name of the resource")
+
+	var keys []string
+	cmd.Flags().StringArrayVarP(&keys, "key", "k", nil,
+		"The key of the entity, as JSON. Specify multiple times for batch methods. For assocKey finders, this is the "+
+			"partial association key the finder is called on. Keys that are not valid JSON are treated as strings, "+
+			"meaning string keys only need to be quoted if they would otherwise be valid JSON")
+	var pathKeys []string
+	cmd.Flags().StringArrayVar(&pathKeys, "path-key", nil,
+		"The key of a parent resource, in the form NAME=JSON (see --key)")
+	var params string
+	cmd.Flags().StringVarP(&params, "params", "p", "",
+		"The query parameters (or action parameters) of the request, as a JSON object")
+	var entities []string
+	cmd.Flags().StringArrayVarP(&entities, "entity", "e", nil,
+		"The entity (or patch for partial updates) of the request as a JSON object, or @FILE to read it from a file "+
+			"(@- for stdin). Specify multiple times for batch methods, in the same order as --key")
+
+	var tunnellingThreshold int
+	cmd.Flags().IntVar(&tunnellingThreshold, "query-tunnelling-threshold", 0,
+		"If positive, requests with queries longer than this threshold are tunnelled through POST")
+	var timeout time.Duration
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "The timeout of the request")
+	var verbose bool
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Print the request's method and URL to stderr")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) (err error) {
+		manifest, err := dynamic.ReadManifestFile(manifestFile)
+		if err != nil {
+			return errors.Wrapf(err, "go-restli: Could not read manifest from %q", manifestFile)
+		}
+
+		client := &restli.Client{
+			Client:                   &http.Client{Timeout: timeout},
+			QueryTunnellingThreshold: tunnellingThreshold,
+		}
+		if verbose {
+			client.Client.Transport = loggingTransport{http.DefaultTransport}
+		}
+		client.HostnameResolver, err = hostnameResolver(host, zkHosts, d2Service)
+		if err != nil {
+			return err
+		}
+
+		c := dynamic.NewClient(client, manifest)
+		if len(args) == 1 {
+			methods, err := c.Methods(args[0])
+			if err != nil {
+				return err
+			}
+			for _, m := range methods {
+				fmt.Fprintln(cmd.OutOrStdout(), m)
+			}
+			return nil
+		}
+
+		req, err := newRequest(keys, pathKeys, params, entities, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel()
+		res, err := c.Call(ctx, args[0], args[1], req)
+		if err != nil {
+			return err
+		}
+
+		out, err := formatResponse(res)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), out)
+		return err
+	}
+
+	return cmd
+}
+
+func hostnameResolver(host string, zkHosts []string, d2Service string) (restli.HostnameResolver, error) {
+	switch {
+	case host != "" && len(zkHosts) > 0:
+		return nil, errors.New("go-restli: Only one of --host and --zk-hosts can be specified")
+	case host != "":
+		hostUrl, err := url.Parse(host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "go-restli: Invalid host %q", host)
+		}
+		return &restli.SimpleHostnameResolver{Hostname: hostUrl}, nil
+	case len(zkHosts) > 0:
+		conn, _, err := zk.Connect(zkHosts, 10*time.Second, zk.WithLogInfo(false))
+		if err != nil {
+			return nil, errors.Wrapf(err, "go-restli: Could not connect to %q", zkHosts)
+		}
+		d2Client := &d2.Client{Conn: conn}
+		if d2Service != "" {
+			return d2Client.SingleServiceClient(d2Service), nil
+		}
+		return d2Client, nil
+	default:
+		return nil, errors.New("go-restli: One of --host or --zk-hosts must be specified")
+	}
+}
+
+func newRequest(keys, pathKeys []string, params string, entities []string, stdin io.Reader) (req *dynamic.Request, err error) {
+	req = new(dynamic.Request)
+
+	for _, k := range keys {
+		req.BatchKeys = append(req.BatchKeys, parseKey(k))
+	}
+	if len(req.BatchKeys) == 1 {
+		req.Key = req.BatchKeys[0]
+	}
+
+	for _, pk := range pathKeys {
+		name, key, ok := strings.Cut(pk, "=")
+		if !ok {
+			return nil, errors.Errorf("go-restli: Invalid path key %q, expected NAME=JSON", pk)
+		}
+		if req.PathKeys == nil {
+			req.PathKeys = restlidata.RawRecord{}
+		}
+		req.PathKeys[name] = parseKey(key)
+	}
+
+	if params != "" {
+		req.Params, err = parseRecord(params)
+		if err != nil {
+			return nil, errors.Wrap(err, "go-restli: Invalid --params")
+		}
+	}
+
+	for _, e := range entities {
+		if strings.HasPrefix(e, "@") {
+			var data []byte
+			if e == "@-" {
+				data, err = io.ReadAll(stdin)
+			} else {
+				data, err = os.ReadFile(e[1:])
+			}
+			if err != nil {
+				return nil, err
+			}
+			e = string(data)
+		}
+
+		var entity restlidata.RawRecord
+		entity, err = parseRecord(e)
+		if err != nil {
+			return nil, errors.Wrap(err, "go-restli: Invalid --entity")
+		}
+		req.BatchEntities = append(req.BatchEntities, entity)
+	}
+	if len(req.BatchEntities) == 1 {
+		req.Entity = req.BatchEntities[0]
+	}
+
+	return req, nil
+}
+
+// parseJSON parses the given JSON. Integral numbers are parsed as int64 instead of float64 such that large int64 keys
+// and fields don't lose precision.
+func parseJSON(data string) (v any, err error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return convertNumbers(v), nil
+}
+
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = convertNumbers(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = convertNumbers(v[k])
+		}
+	}
+	return v
+}
+
+func parseKey(key string) any {
+	v, err := parseJSON(key)
+	if err != nil {
+		return key
+	}
+	return v
+}
+
+func parseRecord(data string) (restlidata.RawRecord, error) {
+	v, err := parseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("expected a JSON object, got %q", data)
+	}
+	return record, nil
+}
+
+// formatResponse pretty-prints the set fields of the given response as a JSON object
+func formatResponse(res *dynamic.Response) (string, error) {
+	out := restlidata.RawRecord{"status": res.StatusCode}
+	set := func(key string, v any, ok bool) {
+		if ok {
+			out[key] = v
+		}
+	}
+	set("id", res.Id, res.Id != nil)
+	set("entity", res.Entity, res.Entity != nil)
+	set("elements", res.Elements, res.Elements != nil)
+	set("paging", res.Paging, res.Paging != nil)
+	set("metadata", res.Metadata, res.Metadata != nil)
+	set("value", res.Value, res.Value != nil)
+
+	if res.Results != nil {
+		results := make([]any, len(res.Results))
+		for i, r := range res.Results {
+			result := restlidata.RawRecord{"status": r.Status}
+			set := func(key string, v any, ok bool) {
+				if ok {
+					result[key] = v
+				}
+			}
+			set("key", r.Key, r.Key != nil)
+			set("entity", r.Entity, r.Entity != nil)
+			if r.Error != nil {
+				errorResponse, err := errorRecord(r.Error)
+				if err != nil {
+					return "", err
+				}
+				result["error"] = errorResponse
+			}
+			results[i] = result
+		}
+		out["results"] = results
+	}
+
+	if res.CriteriaResults != nil {
+		results := make([]any, len(res.CriteriaResults))
+		for i, r := range res.CriteriaResults {
+			result := restlidata.RawRecord{}
+			set := func(key string, v any, ok bool) {
+				if ok {
+					result[key] = v
+				}
+			}
+			set("elements", r.Elements, r.Elements != nil)
+			set("paging", r.Paging, r.Paging != nil)
+			set("metadata", r.Metadata, r.Metadata != nil)
+			if r.Error != nil {
+				errorResponse, err := errorRecord(r.Error)
+				if err != nil {
+					return "", err
+				}
+				result["error"] = errorResponse
+			}
+			results[i] = result
+		}
+		out["criteriaResults"] = results
+	}
+
+	writer := restlicodec.NewPrettyJsonWriter()
+	err := out.MarshalRestLi(writer)
+	if err != nil {
+		return "", err
+	}
+	return writer.Finalize(), nil
+}
+
+type loggingTransport struct {
+	http.RoundTripper
+}
+
+func (t loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log.Printf("%s %s", req.Method, req.URL)
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			if len(data) > 0 {
+				log.Printf("%s", bytes.TrimSpace(data))
+			}
+		}
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// errorRecord converts the given ErrorResponse to a RawRecord. ErrorResponse is a generated record, which can only be
+// converted to a RawRecord by marshaling it.
+func errorRecord(e *common.ErrorResponse) (restlidata.RawRecord, error) {
+	writer := restlicodec.NewCompactJsonWriter()
+	err := e.MarshalRestLi(writer)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := restlicodec.NewJsonReader([]byte(writer.Finalize()))
+	if err != nil {
+		return nil, err
+	}
+	errorResponse := restlidata.RawRecord{}
+	err = errorResponse.UnmarshalRestLi(reader)
+	if err != nil {
+		return nil, err
+	}
+	return errorResponse, nil
+}
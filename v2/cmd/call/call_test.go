@@ -0,0 +1,109 @@
+package call
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "packageRoot": "example.com/messages",
+  "dependencyDataTypes": [],
+  "inputDataTypes": [
+    {"record": {"name": "Message", "namespace": "test", "includes": [], "fields": [
+      {"name": "id", "type": {"primitive": "int64"}, "isOptional": true},
+      {"name": "text", "type": {"primitive": "string"}, "isOptional": false},
+      {"name": "priority", "type": {"primitive": "int32"}, "isOptional": true}
+    ]}}
+  ],
+  "resources": [{
+    "namespace": "test",
+    "resourcePathSegments": [{"resourceName": "messages", "pathKey": {"name": "messagesId", "type": {"primitive": "int64"}}}],
+    "resourceSchema": {"reference": {"name": "Message", "namespace": "test"}},
+    "readOnlyFields": ["id"],
+    "createOnlyFields": [],
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "REST_METHOD", "name": "batch_get", "onEntity": false, "params": []},
+      {"methodType": "FINDER", "name": "search", "onEntity": false, "params": [
+        {"name": "text", "type": {"primitive": "string"}, "isOptional": false}
+      ]}
+    ]
+  }]
+}`
+
+type capturedRequest struct {
+	method string
+	uri    string
+	header http.Header
+	body   string
+}
+
+func runCall(t *testing.T, response string, args ...string) (string, *capturedRequest) {
+	captured := new(capturedRequest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*captured = capturedRequest{method: r.Method, uri: r.RequestURI, header: r.Header, body: string(b)}
+		w.Header().Set(restli.ProtocolVersionHeader, restli.ProtocolVersion)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(manifest, []byte(testManifest), 0644))
+
+	out := new(bytes.Buffer)
+	cmd := Command()
+	cmd.SetOut(out)
+	cmd.SetArgs(append([]string{"--manifest", manifest, "--host", server.URL}, args...))
+	require.NoError(t, cmd.Execute())
+	return out.String(), captured
+}
+
+func TestGet(t *testing.T) {
+	out, captured := runCall(t, `{"id":1,"text":"hi","priority":2}`, "messages", "get", "--key", "1")
+	require.Equal(t, "/messages/1", captured.uri)
+	require.JSONEq(t, `{"status":200,"entity":{"id":1,"text":"hi","priority":2}}`, out)
+	require.Contains(t, out, "\n  \"entity\": {\n")
+}
+
+func TestBatchGet(t *testing.T) {
+	out, captured := runCall(t, `{
+		"results": {"2": {"text":"b"}},
+		"errors": {"1": {"status":404}}
+	}`, "messages", "batch_get", "--key", "2", "--key", "1")
+	require.Equal(t, "/messages?ids=List(1,2)", captured.uri)
+	require.JSONEq(t, `{"status":200,"results":[
+		{"key":2,"status":200,"entity":{"text":"b"}},
+		{"key":1,"status":404,"error":{"status":404}}
+	]}`, out)
+}
+
+func TestFinderWithTunnelling(t *testing.T) {
+	out, captured := runCall(t, `{"elements":[{"text":"a b"}]}`,
+		"messages", "search", "--params", `{"text": "a b"}`, "--query-tunnelling-threshold", "1")
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/messages", captured.uri)
+	require.Equal(t, http.MethodGet, captured.header.Get(restli.MethodOverrideHeader))
+	require.Equal(t, "q=search&text=a%20b", captured.body)
+	require.JSONEq(t, `{"status":200,"elements":[{"text":"a b"}]}`, out)
+}
+
+func TestListMethods(t *testing.T) {
+	out, _ := runCall(t, "", "messages")
+	require.Equal(t, "get\nbatch_get\nfinder:search\n", out)
+}
+
+func TestParseKey(t *testing.T) {
+	require.Equal(t, "abc", parseKey("abc"))
+	require.Equal(t, "123", parseKey(`"123"`))
+	require.Equal(t, map[string]any{"a": "b"}, parseKey(`{"a":"b"}`))
+	require.Equal(t, []any{int64(1) << 60, 1.5}, parseKey(`[1152921504606846976, 1.5]`))
+}
@@ -0,0 +1,74 @@
+// Package docs implements the "go-restli docs" subcommand, which renders static HTML documentation of the resources
+// and data types declared by a go-restli manifest.
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/codegen/docs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Command returns the "docs" subcommand
+func Command() *cobra.Command {
+	c := &cobra.Command{
+		Use:          "docs",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+	}
+	c.Short = strings.TrimSpace(`
+Renders static HTML documentation of the resources and data types declared in
+the manifest (go-restli-manifest.gr.json) written by the code generator. Every
+resource page lists its path template and methods with example ROR2 URLs and
+JSON bodies, and links to the pages of the data types it uses.`)
+	c.Example = strings.TrimSpace(`
+go-restli docs --manifest go-restli-manifest.gr.json --output docs --title "Fruits API"`)
+
+	var manifestFile string
+	const manifestFlag = "manifest"
+	c.Flags().StringVarP(&manifestFile, manifestFlag, "m", "", "The manifest that declares the resources")
+	_ = c.MarkFlagRequired(manifestFlag)
+
+	var output string
+	const outputFlag = "output"
+	c.Flags().StringVarP(&output, outputFlag, "o", "", "The directory to write the documentation to")
+	_ = c.MarkFlagRequired(outputFlag)
+
+	var title string
+	c.Flags().StringVar(&title, "title", "go-restli", "The title of the documentation")
+
+	c.RunE = func(c *cobra.Command, _ []string) error {
+		data, err := os.ReadFile(manifestFile)
+		if err != nil {
+			return err
+		}
+		manifest, err := cmd.ReadManifest(data)
+		if err != nil {
+			return errors.Wrapf(err, "go-restli: Could not read manifest from %q", manifestFile)
+		}
+
+		files, err := docs.Generate(manifest, title)
+		if err != nil {
+			return err
+		}
+		for name, contents := range files {
+			name = filepath.Join(output, filepath.FromSlash(name))
+			err = os.MkdirAll(filepath.Dir(name), os.ModePerm)
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(name, contents, 0644)
+			if err != nil {
+				return err
+			}
+		}
+		c.PrintErrf("Wrote %d files to %s\n", len(files), filepath.Join(output, docs.IndexFile))
+		return nil
+	}
+
+	return c
+}
@@ -0,0 +1,845 @@
+// Package dynamic provides a rest.li client that is driven by a go-restli manifest (go-restli-manifest.gr.json) loaded
+// at runtime, instead of generated bindings. Any method of any resource declared by the manifest can be called by name,
+// with restlidata.RawRecord inputs that are validated and encoded according to the manifest's types:
+//
+//	manifest, err := dynamic.ReadManifestFile("go-restli-manifest.gr.json")
+//	c := dynamic.NewClient(restliClient, manifest)
+//	res, err := c.Call(ctx, "collection/subcollection", "get", &dynamic.Request{
+//		PathKeys: restlidata.RawRecord{"collectionId": 1, "subcollectionId": 2},
+//	})
+//
+// Responses are decoded according to the same types, meaning the primitives they contain have the same Go type as
+// they would in generated bindings (e.g. int32 instead of float64).
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/codegen/resources"
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restli/batchkeyset"
+	"github.com/PapaCharlie/go-restli/v2/restli/patch"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+const (
+	FinderPrefix      = "finder:"
+	BatchFinderPrefix = "batch_finder:"
+	ActionPrefix      = "action:"
+
+	finderParam = "q"
+	actionParam = "action"
+)
+
+// ReadManifestFile reads the manifest at the given path, as written by the go-restli code generator
+func ReadManifestFile(filename string) (*cmd.GoRestliManifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return cmd.ReadManifest(data)
+}
+
+// Client calls the resources declared by a manifest using the underlying restli.Client, meaning all of its settings
+// (hostname resolution, query tunnelling, observers, etc.) apply to dynamic calls as well.
+type Client struct {
+	*restli.Client
+	schema    *schema
+	resources map[string]*resources.Resource
+}
+
+// NewClient returns a new Client for the resources of the given manifest
+func NewClient(c *restli.Client, manifest *cmd.GoRestliManifest) *Client {
+	client := &Client{
+		Client:    c,
+		schema:    newSchema(manifest),
+		resources: make(map[string]*resources.Resource, len(manifest.Resources)),
+	}
+	for _, r := range manifest.Resources {
+		client.resources[resourceName(r)] = r
+	}
+	return client
+}
+
+// resourceName returns the names of the resource's path segments joined with '/', e.g. "collection/subcollection"
+func resourceName(r *resources.Resource) string {
+	names := make([]string, len(r.ResourcePathSegments))
+	for i, s := range r.ResourcePathSegments {
+		names[i] = s.ResourceName
+	}
+	return strings.Join(names, "/")
+}
+
+// Resources returns the sorted names of all the resources declared by the manifest. Subresources are named after the
+// full path to the resource, e.g. "collection/subcollection".
+func (c *Client) Resources() (names []string) {
+	for name := range c.resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Methods returns the names of all the methods of the given resource, in the form accepted by Call
+func (c *Client) Methods(resource string) (names []string, err error) {
+	r, ok := c.resources[resource]
+	if !ok {
+		return nil, fmt.Errorf("go-restli: Unknown resource %q", resource)
+	}
+	for _, m := range r.Methods {
+		switch m := m.GetMethod(); m.MethodType {
+		case resources.FINDER:
+			names = append(names, FinderPrefix+m.Name)
+		case resources.BATCH_FINDER:
+			names = append(names, BatchFinderPrefix+m.Name)
+		case resources.ACTION:
+			names = append(names, ActionPrefix+m.Name)
+		default:
+			names = append(names, m.Name)
+		}
+	}
+	return names, nil
+}
+
+// method finds the given method of the given resource. Rest methods are named after their rest.li method (e.g.
+// "batch_get"), while finders, batch finders and actions are named after their declared names, optionally prefixed
+// with FinderPrefix, BatchFinderPrefix or ActionPrefix if several of them have the same name.
+func (c *Client) method(resource, name string) (*resources.Resource, *resources.Method, error) {
+	r, ok := c.resources[resource]
+	if !ok {
+		return nil, nil, fmt.Errorf("go-restli: Unknown resource %q", resource)
+	}
+
+	var candidates []*resources.Method
+	for _, impl := range r.Methods {
+		m := impl.GetMethod()
+		switch {
+		case m.MethodType == resources.FINDER && name == FinderPrefix+m.Name,
+			m.MethodType == resources.BATCH_FINDER && name == BatchFinderPrefix+m.Name,
+			m.MethodType == resources.ACTION && name == ActionPrefix+m.Name:
+			return r, m, nil
+		case m.Name == name:
+			candidates = append(candidates, m)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil, fmt.Errorf("go-restli: Unknown method %q of %q", name, resource)
+	case 1:
+		return r, candidates[0], nil
+	default:
+		for _, m := range candidates {
+			if m.MethodType == resources.REST_METHOD {
+				return r, m, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("go-restli: Ambiguous method %q of %q, use the %q or %q prefix",
+			name, resource, FinderPrefix, ActionPrefix)
+	}
+}
+
+// Request holds the inputs of a dynamic call. Only the fields relevant to the called method are used.
+type Request struct {
+	// PathKeys holds the keys of the resource's parent resources, indexed by the name of the key. Methods that operate
+	// on a single entity (get, update, partial_update, delete and entity-level actions) also require the key of the
+	// resource itself, either in PathKeys or in Key.
+	PathKeys restlidata.RawRecord
+	// Key is the key of the resource itself, for methods that operate on a single entity. For assocKey finders, it is
+	// the partial association key the finder is called on, e.g. {"src": 1}.
+	Key any
+	// Params holds the query parameters of rest methods and finders (including "start" and "count" for finders that
+	// support paging), or the parameters of actions. The criteria of batch finders are passed in their batch parameter,
+	// like any other parameter.
+	Params restlidata.RawRecord
+	// Entity is the entity of create and update requests. For partial_update requests, it is the patch of the entity,
+	// e.g. {"$set": {"field": 1}, "$delete": ["otherField"]}.
+	Entity restlidata.RawRecord
+	// BatchKeys are the keys of batch_get, batch_delete, batch_update and batch_partial_update requests
+	BatchKeys []any
+	// BatchEntities are the entities of batch_create requests, or the entities (or patches) of batch_update and
+	// batch_partial_update requests, in the same order as BatchKeys
+	BatchEntities []restlidata.RawRecord
+}
+
+// Response holds the outputs of a dynamic call. Only the fields relevant to the called method are set.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// Id is the key of the entity created by a create request
+	Id any
+	// Entity is the entity returned by get requests, or by create and partial_update requests that return the entity
+	Entity restlidata.RawRecord
+	// Elements, Paging and Metadata are returned by get_all and finder requests
+	Elements []restlidata.RawRecord
+	Paging   restlidata.RawRecord
+	Metadata any
+	// Results holds the result of each key of a batch request (in the same order as Request.BatchKeys), or of each
+	// created entity of a batch_create request
+	Results []*BatchResult
+	// Value is the value returned by an action, if any
+	Value any
+	// CriteriaResults holds the result of each criteria of a batch_finder request, in the same order as the criteria
+	CriteriaResults []*CriteriaResult
+}
+
+// CriteriaResult is the result of a single criteria of a batch_finder request. Error is set if the criteria failed.
+type CriteriaResult struct {
+	Elements []restlidata.RawRecord
+	Paging   restlidata.RawRecord
+	Metadata any
+	Error    *common.ErrorResponse
+}
+
+// BatchResult is the result of a single key of a batch request
+type BatchResult struct {
+	Key    any
+	Status int
+	// Entity is the entity returned by batch_get requests, or by batch_create requests that return the entities
+	Entity restlidata.RawRecord
+	Error  *common.ErrorResponse
+}
+
+// Call calls the given method of the given resource (see Resources and Methods for the accepted names). Errors returned
+// by the server are returned as *restli.Error, like generated bindings.
+func (c *Client) Call(ctx context.Context, resource, method string, req *Request) (*Response, error) {
+	if req == nil {
+		req = new(Request)
+	}
+	r, m, err := c.method(resource, method)
+	if err != nil {
+		return nil, err
+	}
+
+	rp, err := c.resourcePath(r, m, req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.MethodType {
+	case resources.FINDER:
+		return c.find(ctx, r, m, rp, req)
+	case resources.BATCH_FINDER:
+		return c.batchFind(ctx, r, m, rp, req)
+	case resources.ACTION:
+		return c.action(ctx, m, rp, req)
+	default:
+		return c.restMethod(ctx, r, m, rp, req)
+	}
+}
+
+func (c *Client) resourcePath(r *resources.Resource, m *resources.Method, req *Request) (restli.ResourcePathString, error) {
+	e := &encoder{schema: c.schema}
+	writer := restlicodec.NewRor2PathWriter()
+	for i, segment := range r.ResourcePathSegments {
+		writer.RawPathSegment("/" + segment.ResourceName)
+		isLast := i == len(r.ResourcePathSegments)-1
+		if isLast && len(m.AssocKeys) > 0 {
+			err := c.writeAssocKey(e, segment.PathKey, m, req.Key, writer)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		if segment.PathKey == nil || (isLast && !m.OnEntity) {
+			continue
+		}
+
+		key, ok := req.PathKeys[segment.PathKey.Name]
+		if isLast && req.Key != nil {
+			key, ok = req.Key, true
+		}
+		if !ok {
+			return "", fmt.Errorf("go-restli: Missing path key %q", segment.PathKey.Name)
+		}
+		writer.RawPathSegment("/")
+		err := e.write(path{segment.PathKey.Name}, &segment.PathKey.Type, key, writer)
+		if err != nil {
+			return "", fmt.Errorf("go-restli: Invalid path key: %w", err)
+		}
+	}
+	return restli.ResourcePathString(writer.Finalize()), nil
+}
+
+// writeAssocKey writes the partial association key that the given assocKey finder is called on, which is passed in the
+// request's Key
+func (c *Client) writeAssocKey(e *encoder, pk *resources.PathKey, m *resources.Method, key any, writer restlicodec.Ror2PathWriter) error {
+	var associationKey *types.AssociationKey
+	if pk != nil && pk.Type.Reference != nil {
+		associationKey, _ = c.schema.types[*pk.Type.Reference].(*types.AssociationKey)
+	}
+	if associationKey == nil {
+		return fmt.Errorf("go-restli: %q declares assocKeys but is not declared on an association", m.Name)
+	}
+	values, ok := asMap(key)
+	if !ok {
+		return fmt.Errorf("go-restli: Missing association key for finder %q", m.Name)
+	}
+
+	fields := associationKey.Subset(associationKey.Identifier, "", m.AssocKeys).Record().Fields
+	writer.RawPathSegment("/")
+	err := writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+		return e.writeFields(path{pk.Name}, fields, values, keyWriter)
+	})
+	if err != nil {
+		return fmt.Errorf("go-restli: Invalid association key: %w", err)
+	}
+	return nil
+}
+
+// query encodes the given method's query parameters, including the given encoded batch keys if any
+func (c *Client) query(m *resources.Method, params restlidata.RawRecord, batchKeys []string) (restli.QueryParamsEncoder, error) {
+	query, err := restlicodec.BuildQueryParams(func(paramWriter func(string) restlicodec.Writer) error {
+		switch m.MethodType {
+		case resources.FINDER:
+			paramWriter(finderParam).WriteString(m.Name)
+		case resources.BATCH_FINDER:
+			paramWriter(restli.BatchFinderNameParam).WriteString(m.Name)
+		case resources.ACTION:
+			// Action parameters are sent in the request's body
+			paramWriter(actionParam).WriteString(m.Name)
+			return nil
+		}
+
+		if batchKeys != nil {
+			err := paramWriter(batchkeyset.EntityIDsField).WriteArray(func(itemWriter func() restlicodec.Writer) error {
+				for _, k := range batchKeys {
+					itemWriter().WriteRawBytes([]byte(k))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		fields := m.Params
+		if m.IsPagingSupported {
+			fields = append(append([]types.Field(nil), fields...), resources.PagingContext.Fields...)
+		}
+		e := &encoder{schema: c.schema}
+		return e.writeFields(nil, fields, params, paramWriter)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("go-restli: Invalid params: %w", err)
+	}
+	if query == "" {
+		return nil, nil
+	}
+	return restli.QueryParamsString(query), nil
+}
+
+func (c *Client) action(ctx context.Context, m *resources.Method, rp restli.ResourcePath, req *Request) (*Response, error) {
+	query, err := c.query(m, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &encoder{schema: c.schema}
+	params := restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+		err := writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+			return e.writeFields(nil, m.Params, req.Params, keyWriter)
+		})
+		if err != nil {
+			return fmt.Errorf("go-restli: Invalid params: %w", err)
+		}
+		return nil
+	})
+
+	httpReq, err := restli.NewJsonRequest(c.Client, ctx, rp, query, http.MethodPost, restli.Method_action, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Return == nil {
+		return c.doAndIgnore(httpReq)
+	}
+	return c.doAndRead(httpReq, func(reader restlicodec.Reader, res *Response) error {
+		return reader.ReadMap(func(reader restlicodec.Reader, field string) (err error) {
+			if field == common.ValueField {
+				res.Value, err = c.schema.read(m.Return, reader)
+				return err
+			}
+			return reader.Skip()
+		})
+	})
+}
+
+func (c *Client) find(ctx context.Context, r *resources.Resource, m *resources.Method, rp restli.ResourcePath, req *Request) (*Response, error) {
+	query, err := c.query(m, req.Params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := restli.NewGetRequest(c.Client, ctx, rp, query, restli.Method_finder)
+	if err != nil {
+		return nil, err
+	}
+
+	elementType := r.ResourceSchema
+	if m.Return != nil {
+		elementType = m.Return
+	}
+	return c.doAndRead(httpReq, c.readCollection(elementType, m.Metadata))
+}
+
+func (c *Client) batchFind(ctx context.Context, r *resources.Resource, m *resources.Method, rp restli.ResourcePath, req *Request) (*Response, error) {
+	query, err := c.query(m, req.Params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := restli.NewGetRequest(c.Client, ctx, rp, query, restli.Method_batch_finder)
+	if err != nil {
+		return nil, err
+	}
+
+	elementType := r.ResourceSchema
+	if m.Return != nil {
+		elementType = m.Return
+	}
+	return c.doAndRead(httpReq, c.readCriteriaResults(elementType, m.Metadata))
+}
+
+func (c *Client) restMethod(ctx context.Context, r *resources.Resource, m *resources.Method, rp restli.ResourcePath, req *Request) (*Response, error) {
+	method, ok := restli.MethodNameMapping[m.Name]
+	if !ok {
+		return nil, fmt.Errorf("go-restli: Unknown rest.li method %q", m.Name)
+	}
+
+	readOnlyFields := restlicodec.NewPathSpec(r.ReadOnlyFields...)
+	createAndReadOnlyFields := restlicodec.NewPathSpec(append(append([]string(nil), r.ReadOnlyFields...), r.CreateOnlyFields...)...)
+
+	var batchKeys, entityKeys []string
+	switch method {
+	case restli.Method_batch_get, restli.Method_batch_delete, restli.Method_batch_update, restli.Method_batch_partial_update:
+		var err error
+		batchKeys, err = c.encodeKeys(r, req.BatchKeys, restlicodec.NewRestLiQueryParamsWriter)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(batchKeys)
+		entityKeys, err = c.encodeKeys(r, req.BatchKeys, restlicodec.NewRor2HeaderWriter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := c.query(m, req.Params, batchKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var httpReq *http.Request
+	switch method {
+	case restli.Method_get:
+		httpReq, err = restli.NewGetRequest(c.Client, ctx, rp, query, method)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndRead(httpReq, c.readEntity(r.ResourceSchema))
+
+	case restli.Method_get_all:
+		httpReq, err = restli.NewGetRequest(c.Client, ctx, rp, query, method)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndRead(httpReq, c.readCollection(r.ResourceSchema, m.Metadata))
+
+	case restli.Method_delete:
+		httpReq, err = restli.NewDeleteRequest(c.Client, ctx, rp, query, method)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndIgnore(httpReq)
+
+	case restli.Method_create:
+		e := &encoder{schema: c.schema, excluded: readOnlyFields}
+		httpReq, err = restli.NewCreateRequest(c.Client, ctx, rp, query, method, e.entity(r.ResourceSchema, req.Entity), readOnlyFields)
+		if err != nil {
+			return nil, err
+		}
+		var res *Response
+		if m.ReturnEntity {
+			res, err = c.doAndRead(httpReq, c.readEntity(r.ResourceSchema))
+		} else {
+			res, err = c.doAndIgnore(httpReq)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if id := res.Header.Get(restli.IDHeader); id != "" {
+			res.Id, err = c.readKey(r, id)
+		}
+		return res, err
+
+	case restli.Method_update:
+		e := &encoder{schema: c.schema, excluded: createAndReadOnlyFields}
+		httpReq, err = restli.NewJsonRequest(c.Client, ctx, rp, query, http.MethodPut, method,
+			e.entity(r.ResourceSchema, req.Entity), createAndReadOnlyFields)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndIgnore(httpReq)
+
+	case restli.Method_partial_update:
+		e := &encoder{schema: c.schema, excluded: createAndReadOnlyFields}
+		httpReq, err = restli.NewJsonRequest(c.Client, ctx, rp, query, http.MethodPost, method,
+			e.patch(r.ResourceSchema, req.Entity), createAndReadOnlyFields)
+		if err != nil {
+			return nil, err
+		}
+		if m.ReturnEntity {
+			return c.doAndRead(httpReq, c.readEntity(r.ResourceSchema))
+		}
+		return c.doAndIgnore(httpReq)
+
+	case restli.Method_batch_get:
+		httpReq, err = restli.NewGetRequest(c.Client, ctx, rp, query, method)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndRead(httpReq, c.readBatchResponse(r, req.BatchKeys, entityKeys, r.ResourceSchema))
+
+	case restli.Method_batch_delete:
+		httpReq, err = restli.NewDeleteRequest(c.Client, ctx, rp, query, method)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndRead(httpReq, c.readBatchResponse(r, req.BatchKeys, entityKeys, nil))
+
+	case restli.Method_batch_update, restli.Method_batch_partial_update:
+		if len(req.BatchEntities) != len(req.BatchKeys) {
+			return nil, fmt.Errorf("go-restli: Got %d keys but %d entities", len(req.BatchKeys), len(req.BatchEntities))
+		}
+		e := &encoder{schema: c.schema, excluded: createAndReadOnlyFields}
+		httpMethod, marshal := http.MethodPut, e.entity
+		if method == restli.Method_batch_partial_update {
+			httpMethod, marshal = http.MethodPost, e.patch
+		}
+		body := restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+			return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+				return keyWriter(common.EntitiesField).WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+					for i, k := range entityKeys {
+						err := marshal(r.ResourceSchema, req.BatchEntities[i]).MarshalRestLi(keyWriter(k).SetScope())
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			})
+		})
+		httpReq, err = restli.NewJsonRequest(c.Client, ctx, rp, query, httpMethod, method, body, createAndReadOnlyFields)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndRead(httpReq, c.readBatchResponse(r, req.BatchKeys, entityKeys, nil))
+
+	case restli.Method_batch_create:
+		e := &encoder{schema: c.schema, excluded: readOnlyFields}
+		body := restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+			return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+				return keyWriter(common.ElementsField).WriteArray(func(itemWriter func() restlicodec.Writer) error {
+					for _, entity := range req.BatchEntities {
+						err := e.entity(r.ResourceSchema, entity).MarshalRestLi(itemWriter().SetScope())
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			})
+		})
+		httpReq, err = restli.NewCreateRequest(c.Client, ctx, rp, query, method, body, readOnlyFields)
+		if err != nil {
+			return nil, err
+		}
+		return c.doAndRead(httpReq, c.readBatchCreateResponse(r))
+
+	default:
+		return nil, fmt.Errorf("go-restli: Unsupported rest.li method %q", m.Name)
+	}
+}
+
+func (e *encoder) entity(t *types.RestliType, entity restlidata.RawRecord) restlicodec.Marshaler {
+	return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+		if entity == nil {
+			return fmt.Errorf("go-restli: Missing entity")
+		}
+		err := e.write(nil, t, entity, writer)
+		if err != nil {
+			return fmt.Errorf("go-restli: Invalid entity: %w", err)
+		}
+		return nil
+	})
+}
+
+func (e *encoder) patch(t *types.RestliType, entity restlidata.RawRecord) restlicodec.Marshaler {
+	return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+		var fields []types.Field
+		isRecord := false
+		if ct, ok := e.types[*t.Reference]; ok {
+			var err error
+			fields, isRecord, err = e.fields(ct)
+			if err != nil {
+				return err
+			}
+		}
+		if !isRecord {
+			return fmt.Errorf("go-restli: Cannot partially update %q", *t.Reference)
+		}
+
+		return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+			err := e.writePatch(nil, t.Reference.FullName(), fields, entity, keyWriter(patch.PatchField).SetScope())
+			if err != nil {
+				return fmt.Errorf("go-restli: Invalid patch: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// encodeKeys validates and encodes the given keys of the given resource with the given writer
+func (c *Client) encodeKeys(r *resources.Resource, keys []any, newWriter func() restlicodec.Writer) ([]string, error) {
+	e := &encoder{schema: c.schema}
+	keyType := &r.LastSegment().PathKey.Type
+	encoded := make([]string, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		w := newWriter()
+		err := e.write(path{r.LastSegment().PathKey.Name}, keyType, k, w)
+		if err != nil {
+			return nil, fmt.Errorf("go-restli: Invalid key: %w", err)
+		}
+		encoded[i] = w.Finalize()
+		if seen[encoded[i]] {
+			return nil, fmt.Errorf("go-restli: Cannot specify key %v twice", k)
+		}
+		seen[encoded[i]] = true
+	}
+	return encoded, nil
+}
+
+// readKey reads the given ROR2-encoded key of the given resource
+func (c *Client) readKey(r *resources.Resource, rawKey string) (any, error) {
+	reader, err := restlicodec.NewRor2Reader(rawKey)
+	if err != nil {
+		return nil, err
+	}
+	return c.schema.read(&r.LastSegment().PathKey.Type, reader)
+}
+
+func (c *Client) doAndIgnore(req *http.Request) (*Response, error) {
+	res, err := restli.DoAndIgnore(c.Client, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: res.StatusCode, Header: res.Header}, nil
+}
+
+func (c *Client) doAndRead(req *http.Request, read func(restlicodec.Reader, *Response) error) (*Response, error) {
+	res, httpRes, err := restli.DoAndUnmarshal(c.Client, req, func(reader restlicodec.Reader) (*Response, error) {
+		res := new(Response)
+		return res, read(reader, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.StatusCode = httpRes.StatusCode
+	res.Header = httpRes.Header
+	return res, nil
+}
+
+func (c *Client) readRecord(t *types.RestliType, reader restlicodec.Reader) (restlidata.RawRecord, error) {
+	v, err := c.schema.read(t, reader)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := v.(restlidata.RawRecord)
+	if !ok {
+		return nil, fmt.Errorf("go-restli: Expected a record, got %T", v)
+	}
+	return record, nil
+}
+
+func (c *Client) readEntity(t *types.RestliType) func(restlicodec.Reader, *Response) error {
+	return func(reader restlicodec.Reader, res *Response) (err error) {
+		res.Entity, err = c.readRecord(t, reader)
+		return err
+	}
+}
+
+func (c *Client) readCollection(elementType, metadataType *types.RestliType) func(restlicodec.Reader, *Response) error {
+	return func(reader restlicodec.Reader, res *Response) error {
+		return reader.ReadMap(func(reader restlicodec.Reader, field string) error {
+			return c.readCollectionField(elementType, metadataType, reader, field, &res.Elements, &res.Paging, &res.Metadata)
+		})
+	}
+}
+
+func (c *Client) readCollectionField(
+	elementType, metadataType *types.RestliType,
+	reader restlicodec.Reader,
+	field string,
+	elements *[]restlidata.RawRecord,
+	paging *restlidata.RawRecord,
+	metadata *any,
+) (err error) {
+	switch field {
+	case common.ElementsField:
+		*elements = []restlidata.RawRecord{}
+		return reader.ReadArray(func(reader restlicodec.Reader) error {
+			element, err := c.readRecord(elementType, reader)
+			*elements = append(*elements, element)
+			return err
+		})
+	case common.PagingField:
+		*paging, err = c.readRecord(&types.RestliType{Reference: &collectionMetadataIdentifier}, reader)
+		return err
+	case common.MetadataField:
+		if metadataType != nil {
+			*metadata, err = c.schema.read(metadataType, reader)
+		} else {
+			*metadata, err = reader.ReadInterface()
+		}
+		return err
+	default:
+		return reader.Skip()
+	}
+}
+
+// readCriteriaResults reads the response of a batch_finder request, which holds one collection (or error) per criteria
+func (c *Client) readCriteriaResults(elementType, metadataType *types.RestliType) func(restlicodec.Reader, *Response) error {
+	return func(reader restlicodec.Reader, res *Response) error {
+		res.CriteriaResults = []*CriteriaResult{}
+		return reader.ReadMap(func(reader restlicodec.Reader, field string) error {
+			if field != common.ElementsField {
+				return reader.Skip()
+			}
+			return reader.ReadArray(func(reader restlicodec.Reader) error {
+				result := new(CriteriaResult)
+				res.CriteriaResults = append(res.CriteriaResults, result)
+				return reader.ReadMap(func(reader restlicodec.Reader, field string) error {
+					if field == common.ErrorField {
+						result.Error = new(common.ErrorResponse)
+						return result.Error.UnmarshalRestLi(reader)
+					}
+					return c.readCollectionField(elementType, metadataType, reader, field,
+						&result.Elements, &result.Paging, &result.Metadata)
+				})
+			})
+		})
+	}
+}
+
+func (c *Client) readBatchResponse(
+	r *resources.Resource,
+	keys []any,
+	encodedKeys []string,
+	entityType *types.RestliType,
+) func(restlicodec.Reader, *Response) error {
+	return func(reader restlicodec.Reader, res *Response) error {
+		results := make(map[string]*BatchResult, len(keys))
+		res.Results = make([]*BatchResult, len(keys))
+		for i, k := range keys {
+			res.Results[i] = &BatchResult{Key: k}
+			results[encodedKeys[i]] = res.Results[i]
+		}
+
+		return reader.ReadMap(func(reader restlicodec.Reader, field string) error {
+			switch field {
+			case common.ResultsField, common.StatusesField, common.ErrorsField:
+			default:
+				return reader.Skip()
+			}
+
+			return reader.ReadMap(func(reader restlicodec.Reader, rawKey string) (err error) {
+				key, err := c.readKey(r, rawKey)
+				if err != nil {
+					return err
+				}
+				encodedKey, err := c.encodeKeys(r, []any{key}, restlicodec.NewRor2HeaderWriter)
+				if err != nil {
+					return err
+				}
+				result, ok := results[encodedKey[0]]
+				if !ok {
+					return fmt.Errorf("go-restli: Unknown key returned by batch method: %q", rawKey)
+				}
+
+				switch field {
+				case common.ResultsField:
+					if entityType != nil {
+						result.Entity, err = c.readRecord(entityType, reader)
+						if result.Status == 0 {
+							result.Status = http.StatusOK
+						}
+						return err
+					}
+					status := new(common.BatchEntityUpdateResponse)
+					err = status.UnmarshalRestLi(reader)
+					if result.Status == 0 {
+						result.Status = status.Status
+					}
+					return err
+				case common.StatusesField:
+					result.Status, err = reader.ReadInt()
+					return err
+				default:
+					result.Error = new(common.ErrorResponse)
+					err = result.Error.UnmarshalRestLi(reader)
+					if result.Error.Status != nil {
+						result.Status = int(*result.Error.Status)
+					}
+					return err
+				}
+			})
+		})
+	}
+}
+
+func (c *Client) readBatchCreateResponse(r *resources.Resource) func(restlicodec.Reader, *Response) error {
+	return func(reader restlicodec.Reader, res *Response) error {
+		return reader.ReadMap(func(reader restlicodec.Reader, field string) error {
+			if field != common.ElementsField {
+				return reader.Skip()
+			}
+			res.Results = []*BatchResult{}
+			return reader.ReadArray(func(reader restlicodec.Reader) error {
+				result := new(BatchResult)
+				res.Results = append(res.Results, result)
+				return reader.ReadMap(func(reader restlicodec.Reader, field string) (err error) {
+					switch field {
+					case common.IdField:
+						var rawKey string
+						rawKey, err = reader.ReadString()
+						if err != nil {
+							return err
+						}
+						result.Key, err = c.readKey(r, rawKey)
+					case common.StatusField:
+						result.Status, err = reader.ReadInt()
+					case common.EntityField:
+						result.Entity, err = c.readRecord(r.ResourceSchema, reader)
+					case common.ErrorField:
+						result.Error = new(common.ErrorResponse)
+						err = result.Error.UnmarshalRestLi(reader)
+					default:
+						err = reader.Skip()
+					}
+					return err
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package dynamic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restli/patch"
+	"github.com/PapaCharlie/go-restli/v2/restlidata"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "packageRoot": "example.com/items",
+  "dependencyDataTypes": [],
+  "inputDataTypes": [
+    {"enum": {"name": "Color", "namespace": "test", "Symbols": ["RED", "BLUE"]}},
+    {"standaloneUnion": {"name": "Value", "namespace": "test", "Union": {"HasNull": false, "Members": [
+      {"Type": {"primitive": "int32"}, "Alias": "int"},
+      {"Type": {"primitive": "string"}, "Alias": "string"}
+    ]}}},
+    {"record": {"name": "ItemKey", "namespace": "test", "includes": [], "fields": [
+      {"name": "id", "type": {"primitive": "int32"}, "isOptional": false},
+      {"name": "name", "type": {"primitive": "string"}, "isOptional": false}
+    ]}},
+    {"record": {"name": "ItemParams", "namespace": "test", "includes": [], "fields": [
+      {"name": "version", "type": {"primitive": "int32"}, "isOptional": true}
+    ]}},
+    {"complexKey": {"name": "ItemComplexKey", "namespace": "test",
+      "Key": {"name": "ItemKey", "namespace": "test"},
+      "Params": {"name": "ItemParams", "namespace": "test"}}},
+    {"record": {"name": "ColorCriteria", "namespace": "test", "includes": [], "fields": [
+      {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false}
+    ]}},
+    {"record": {"name": "Item", "namespace": "test", "includes": [], "fields": [
+      {"name": "id", "type": {"primitive": "int32"}, "isOptional": false},
+      {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false},
+      {"name": "value", "type": {"reference": {"name": "Value", "namespace": "test"}}, "isOptional": false},
+      {"name": "tags", "type": {"array": {"primitive": "string"}}, "isOptional": true}
+    ]}},
+    {"associationKey": {"name": "Links_AssociationKey", "namespace": "test", "keys": [
+      {"name": "src", "type": {"primitive": "int32"}},
+      {"name": "dest", "type": {"reference": {"name": "Color", "namespace": "test"}}}
+    ]}}
+  ],
+  "resources": [{
+    "namespace": "test",
+    "resourcePathSegments": [{"resourceName": "items", "pathKey": {
+      "name": "itemsId", "type": {"reference": {"name": "ItemComplexKey", "namespace": "test"}}}}],
+    "resourceSchema": {"reference": {"name": "Item", "namespace": "test"}},
+    "readOnlyFields": ["id"],
+    "createOnlyFields": [],
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "REST_METHOD", "name": "batch_get", "onEntity": false, "params": []},
+      {"methodType": "REST_METHOD", "name": "create", "onEntity": false, "params": []},
+      {"methodType": "REST_METHOD", "name": "partial_update", "onEntity": true, "params": []},
+      {"methodType": "FINDER", "name": "byColor", "onEntity": false, "isPagingSupported": true, "params": [
+        {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false}
+      ]},
+      {"methodType": "BATCH_FINDER", "name": "byColors", "onEntity": false, "batchParam": "criteria", "params": [
+        {"name": "criteria", "type": {"array": {"reference": {"name": "ColorCriteria", "namespace": "test"}}},
+          "isOptional": false}
+      ]},
+      {"methodType": "ACTION", "name": "count", "onEntity": false, "params": [
+        {"name": "values", "type": {"array": {"reference": {"name": "Value", "namespace": "test"}}}, "isOptional": false}
+      ], "return": {"primitive": "int32"}}
+    ]
+  }, {
+    "namespace": "test",
+    "resourcePathSegments": [{"resourceName": "links", "pathKey": {
+      "name": "linksId", "type": {"reference": {"name": "Links_AssociationKey", "namespace": "test"}}}}],
+    "resourceSchema": {"reference": {"name": "Item", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "FINDER", "name": "bySrc", "onEntity": false, "assocKeys": ["src"], "params": []}
+    ]
+  }]
+}`
+
+type capturedRequest struct {
+	method string
+	uri    string
+	header http.Header
+	body   string
+}
+
+func newTestClient(t *testing.T, status int, header http.Header, body string) (*Client, *capturedRequest) {
+	captured := new(capturedRequest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*captured = capturedRequest{method: r.Method, uri: r.RequestURI, header: r.Header, body: string(b)}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.Header().Set(restli.ProtocolVersionHeader, restli.ProtocolVersion)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	manifest, err := cmd.ReadManifest([]byte(testManifest))
+	require.NoError(t, err)
+
+	serverUrl, _ := url.Parse(server.URL)
+	return NewClient(&restli.Client{
+		Client:           server.Client(),
+		HostnameResolver: &restli.SimpleHostnameResolver{Hostname: serverUrl},
+	}, manifest), captured
+}
+
+var testKey = restlidata.RawRecord{"id": 1, "name": "a b", "$params": restlidata.RawRecord{"version": 2}}
+
+func TestGet(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusOK, nil,
+		`{"id":1,"color":"RED","value":{"int":3},"tags":["x"],"extra":1.5}`)
+
+	res, err := c.Call(context.Background(), "items", "get", &Request{
+		PathKeys: restlidata.RawRecord{"itemsId": testKey},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodGet, captured.method)
+	require.Equal(t, "/items/($params:(version:2),id:1,name:a%20b)", captured.uri)
+
+	_, err = c.Call(context.Background(), "items", "get", &Request{Key: testKey})
+	require.NoError(t, err)
+	require.Equal(t, "/items/($params:(version:2),id:1,name:a%20b)", captured.uri)
+	require.Equal(t, "get", captured.header.Get(restli.MethodHeader))
+
+	require.Equal(t, restlidata.RawRecord{
+		"id":    int32(1),
+		"color": "RED",
+		"value": restlidata.RawRecord{"int": int32(3)},
+		"tags":  []any{"x"},
+		"extra": 1.5,
+	}, res.Entity)
+}
+
+func TestBatchGet(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusOK, nil, `{
+		"results": {"(id:2,name:b)": {"id":2,"color":"BLUE","value":{"string":"s"}}},
+		"errors": {"(id:1,name:a)": {"status":404,"message":"not found"}}
+	}`)
+
+	keys := []any{
+		restlidata.RawRecord{"id": 2, "name": "b"},
+		restlidata.RawRecord{"id": 1, "name": "a"},
+	}
+	res, err := c.Call(context.Background(), "items", "batch_get", &Request{BatchKeys: keys})
+	require.NoError(t, err)
+	require.Equal(t, "/items?ids=List((id:1,name:a),(id:2,name:b))", captured.uri)
+
+	require.Len(t, res.Results, 2)
+	require.Equal(t, keys[0], res.Results[0].Key)
+	require.Equal(t, http.StatusOK, res.Results[0].Status)
+	require.Equal(t, restlidata.RawRecord{
+		"id":    int32(2),
+		"color": "BLUE",
+		"value": restlidata.RawRecord{"string": "s"},
+	}, res.Results[0].Entity)
+	require.Equal(t, keys[1], res.Results[1].Key)
+	require.Equal(t, http.StatusNotFound, res.Results[1].Status)
+	require.Equal(t, "not found", *res.Results[1].Error.Message)
+}
+
+func TestCreate(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusCreated,
+		http.Header{restli.IDHeader: {"(id:3,name:c)"}}, "")
+
+	res, err := c.Call(context.Background(), "items", "create", &Request{
+		Entity: restlidata.RawRecord{"color": "BLUE", "value": restlidata.RawRecord{"int": 5}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/items", captured.uri)
+	require.JSONEq(t, `{"color":"BLUE","value":{"int":5}}`, captured.body)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	require.Equal(t, restlidata.RawRecord{"id": int32(3), "name": "c"}, res.Id)
+}
+
+func TestPartialUpdate(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusNoContent, nil, "")
+
+	_, err := c.Call(context.Background(), "items", "partial_update", &Request{
+		PathKeys: restlidata.RawRecord{"itemsId": testKey},
+		Entity: restlidata.RawRecord{
+			"$set":    restlidata.RawRecord{"color": "RED"},
+			"$delete": []any{"tags"},
+		},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"patch":{"$set":{"color":"RED"},"$delete":["tags"]}}`, captured.body)
+
+	_, err = c.Call(context.Background(), "items", "partial_update", &Request{
+		PathKeys: restlidata.RawRecord{"itemsId": testKey},
+		Entity:   restlidata.RawRecord{"$set": restlidata.RawRecord{"id": 2}},
+	})
+	illegal := new(patch.IllegalPartialUpdateError)
+	require.True(t, errors.As(err, &illegal), err)
+	require.Equal(t, "id", illegal.Field)
+}
+
+func TestFinder(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusOK, nil, `{
+		"elements": [{"id":1,"color":"RED","value":{"int":1}}],
+		"paging": {"start":10,"count":1,"total":11,"links":[]}
+	}`)
+
+	res, err := c.Call(context.Background(), "items", "byColor", &Request{
+		Params: restlidata.RawRecord{"color": "RED", "start": 10, "count": 1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/items?color=RED&count=1&q=byColor&start=10", captured.uri)
+	require.Equal(t, []restlidata.RawRecord{{"id": int32(1), "color": "RED", "value": restlidata.RawRecord{"int": int32(1)}}},
+		res.Elements)
+	require.Equal(t, restlidata.RawRecord{"start": int32(10), "count": int32(1), "total": int32(11), "links": []any{}},
+		res.Paging)
+}
+
+func TestAssociation(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusOK, nil, `{"elements": []}`)
+
+	_, err := c.Call(context.Background(), "links", "get", &Request{
+		Key: restlidata.RawRecord{"src": 1, "dest": "RED"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/links/(dest:RED,src:1)", captured.uri)
+
+	_, err = c.Call(context.Background(), "links", "get", &Request{
+		Key: restlidata.RawRecord{"src": 1},
+	})
+	require.Error(t, err)
+
+	_, err = c.Call(context.Background(), "links", "bySrc", &Request{
+		Key: restlidata.RawRecord{"src": 1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/links/(src:1)?q=bySrc", captured.uri)
+
+	_, err = c.Call(context.Background(), "links", "bySrc", &Request{
+		Key: restlidata.RawRecord{"src": 1, "dest": "RED"},
+	})
+	require.Error(t, err)
+}
+
+func TestBatchFinder(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusOK, nil, `{"elements": [
+		{"elements": [{"id":1,"color":"RED","value":{"int":1}}], "isError": false},
+		{"elements": [], "error": {"status":400,"message":"bad criteria"}, "isError": true}
+	]}`)
+
+	res, err := c.Call(context.Background(), "items", "batch_finder:byColors", &Request{
+		Params: restlidata.RawRecord{"criteria": []any{
+			restlidata.RawRecord{"color": "RED"},
+			restlidata.RawRecord{"color": "BLUE"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/items?bq=byColors&criteria=List((color:RED),(color:BLUE))", captured.uri)
+	require.Equal(t, "batch_finder", captured.header.Get(restli.MethodHeader))
+	require.Len(t, res.CriteriaResults, 2)
+	require.Equal(t, []restlidata.RawRecord{{"id": int32(1), "color": "RED", "value": restlidata.RawRecord{"int": int32(1)}}},
+		res.CriteriaResults[0].Elements)
+	require.Nil(t, res.CriteriaResults[0].Error)
+	require.Equal(t, []restlidata.RawRecord{}, res.CriteriaResults[1].Elements)
+	require.Equal(t, "bad criteria", *res.CriteriaResults[1].Error.Message)
+}
+
+func TestAction(t *testing.T) {
+	c, captured := newTestClient(t, http.StatusOK, nil, `{"value":2}`)
+
+	res, err := c.Call(context.Background(), "items", "action:count", &Request{
+		Params: restlidata.RawRecord{"values": []any{
+			restlidata.RawRecord{"int": 1},
+			map[string]any{"string": "a"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/items?action=count", captured.uri)
+	require.JSONEq(t, `{"values":[{"int":1},{"string":"a"}]}`, captured.body)
+	require.Equal(t, int32(2), res.Value)
+}
+
+func TestValidation(t *testing.T) {
+	c, _ := newTestClient(t, http.StatusOK, nil, "{}")
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		resource string
+		method   string
+		req      *Request
+		err      string
+	}{
+		{
+			name:     "unknown resource",
+			resource: "nope",
+			method:   "get",
+			req:      &Request{},
+			err:      `go-restli: Unknown resource "nope"`,
+		},
+		{
+			name:     "unknown method",
+			resource: "items",
+			method:   "delete",
+			req:      &Request{},
+			err:      `go-restli: Unknown method "delete" of "items"`,
+		},
+		{
+			name:     "missing path key",
+			resource: "items",
+			method:   "get",
+			req:      &Request{},
+			err:      `go-restli: Missing path key "itemsId"`,
+		},
+		{
+			name:     "int32 overflow",
+			resource: "items",
+			method:   "get",
+			req: &Request{PathKeys: restlidata.RawRecord{
+				"itemsId": restlidata.RawRecord{"id": int64(1) << 40, "name": "a"},
+			}},
+			err: `go-restli: Invalid path key: "itemsId.id": `,
+		},
+		{
+			name:     "missing required param",
+			resource: "items",
+			method:   "byColor",
+			req:      &Request{},
+			err:      `go-restli: Invalid params: "color": missing required field`,
+		},
+		{
+			name:     "unknown enum symbol",
+			resource: "items",
+			method:   "byColor",
+			req:      &Request{Params: restlidata.RawRecord{"color": "GREEN"}},
+			err:      `go-restli: Invalid params: "color": `,
+		},
+		{
+			name:     "union with two members",
+			resource: "items",
+			method:   "create",
+			req: &Request{Entity: restlidata.RawRecord{
+				"color": "RED",
+				"value": restlidata.RawRecord{"int": 1, "string": "a"},
+			}},
+			err: `go-restli: Invalid entity: "value": `,
+		},
+		{
+			name:     "unknown field",
+			resource: "items",
+			method:   "create",
+			req: &Request{Entity: restlidata.RawRecord{
+				"color": "RED",
+				"value": restlidata.RawRecord{"int": 1},
+				"other": 1,
+			}},
+			err: `go-restli: Invalid entity: "other": unknown field`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := c.Call(ctx, test.resource, test.method, test.req)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
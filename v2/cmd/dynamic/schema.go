@@ -0,0 +1,611 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/codegen/resources"
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/PapaCharlie/go-restli/v2/restli/patch"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata"
+)
+
+const (
+	setDirective    = "$set"
+	deleteDirective = "$delete"
+)
+
+var (
+	linkIdentifier = utils.Identifier{
+		Name:      "Link",
+		Namespace: "com.linkedin.restli.common",
+	}
+	linkRecord = &types.Record{
+		NamedType: types.NamedType{Identifier: linkIdentifier},
+		Fields: []types.Field{
+			{Name: "rel", Type: types.RestliType{Primitive: &types.StringPrimitive}},
+			{Name: "href", Type: types.RestliType{Primitive: &types.StringPrimitive}},
+			{Name: "type", Type: types.RestliType{Primitive: &types.StringPrimitive}},
+		},
+	}
+
+	collectionMetadataIdentifier = utils.Identifier{
+		Name:      "CollectionMetadata",
+		Namespace: "com.linkedin.restli.common",
+	}
+	collectionMetadataRecord = &types.Record{
+		NamedType: types.NamedType{Identifier: collectionMetadataIdentifier},
+		Fields: []types.Field{
+			{Name: "start", Type: types.RestliType{Primitive: &types.Int32Primitive}},
+			{Name: "count", Type: types.RestliType{Primitive: &types.Int32Primitive}},
+			{Name: "total", Type: types.RestliType{Primitive: &types.Int32Primitive}, IsOptional: true},
+			{Name: "links", Type: types.RestliType{Array: &types.RestliType{Reference: &linkIdentifier}}, IsOptional: true},
+		},
+	}
+)
+
+// schema resolves the data types declared by a manifest, and uses them to validate and encode inputs and to decode
+// responses
+type schema struct {
+	types map[utils.Identifier]utils.ComplexType
+}
+
+func newSchema(manifest *cmd.GoRestliManifest) *schema {
+	s := &schema{types: manifest.ComplexTypes()}
+	for id, t := range map[utils.Identifier]utils.ComplexType{
+		utils.PagingContextIdentifier: resources.PagingContext,
+		utils.EmptyRecordIdentifier:   &types.Record{NamedType: types.NamedType{Identifier: utils.EmptyRecordIdentifier}},
+		linkIdentifier:                linkRecord,
+		collectionMetadataIdentifier:  collectionMetadataRecord,
+	} {
+		if _, ok := s.types[id]; !ok {
+			s.types[id] = t
+		}
+	}
+	return s
+}
+
+// recordFields returns all the fields of the given record, including the fields of the records it includes
+func (s *schema) recordFields(r *types.Record) (fields []types.Field, err error) {
+	for _, include := range r.Includes {
+		included, ok := s.types[include].(*types.Record)
+		if !ok {
+			return nil, fmt.Errorf("go-restli: Unknown included record %q", include)
+		}
+		includedFields, err := s.recordFields(included)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, includedFields...)
+	}
+	return append(fields, r.Fields...), nil
+}
+
+// complexKeyFields returns the fields of the given complex key, namely the fields of its key record and the optional
+// $params field
+func (s *schema) complexKeyFields(ck *types.ComplexKey) ([]types.Field, error) {
+	key, ok := s.types[ck.Key].(*types.Record)
+	if !ok {
+		return nil, fmt.Errorf("go-restli: Unknown complex key record %q", ck.Key)
+	}
+	fields, err := s.recordFields(key)
+	if err != nil {
+		return nil, err
+	}
+	params := ck.Params
+	return append(fields, types.Field{
+		Name:       utils.ComplexKeyParams,
+		Type:       types.RestliType{Reference: &params},
+		IsOptional: true,
+	}), nil
+}
+
+// fields returns the fields of the given type if it is a record, complex key or association key
+func (s *schema) fields(t utils.ComplexType) ([]types.Field, bool, error) {
+	switch t := t.(type) {
+	case *types.Record:
+		fields, err := s.recordFields(t)
+		return fields, true, err
+	case *types.ComplexKey:
+		fields, err := s.complexKeyFields(t)
+		return fields, true, err
+	case *types.AssociationKey:
+		return t.Record().Fields, true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// encoder validates values against the schema's types while writing them. Fields matched by excluded (i.e. read-only
+// or create-only fields) are not required, and are dropped by the writer.
+type encoder struct {
+	*schema
+	excluded restlicodec.PathSpec
+}
+
+type path []string
+
+func (p path) String() string {
+	if len(p) == 0 {
+		return "value"
+	}
+	return strings.Join(p, ".")
+}
+
+func (p path) child(segment string) path {
+	return append(append(path(nil), p...), segment)
+}
+
+func (p path) errorf(format string, args ...any) error {
+	return fmt.Errorf("%q: "+format, append([]any{p.String()}, args...)...)
+}
+
+func (e *encoder) write(p path, t *types.RestliType, v any, writer restlicodec.Writer) error {
+	switch {
+	case t.Primitive != nil:
+		return writePrimitive(p, t.Primitive, v, writer)
+	case t.Array != nil:
+		items := reflect.ValueOf(v)
+		if items.Kind() != reflect.Slice {
+			return p.errorf("expected an array, got %T", v)
+		}
+		return writer.WriteArray(func(itemWriter func() restlicodec.Writer) error {
+			for i := 0; i < items.Len(); i++ {
+				err := e.write(p.child(restlicodec.WildCard), t.Array, items.Index(i).Interface(), itemWriter())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case t.Map != nil:
+		m, ok := asMap(v)
+		if !ok {
+			return p.errorf("expected a map, got %T", v)
+		}
+		return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+			for k, item := range m {
+				if err := e.write(p.child(k), t.Map, item, keyWriter(k)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case *t.Reference == utils.RawRecordIdentifier:
+		m, ok := asMap(v)
+		if !ok {
+			return p.errorf("expected a record, got %T", v)
+		}
+		return restlidata.RawRecord(m).MarshalRestLi(writer)
+	}
+
+	ct, ok := e.types[*t.Reference]
+	if !ok {
+		return p.errorf("unknown type %q", *t.Reference)
+	}
+
+	if fields, isRecord, err := e.fields(ct); isRecord {
+		if err != nil {
+			return err
+		}
+		m, ok := asMap(v)
+		if !ok {
+			return p.errorf("expected a %s, got %T", t.Reference.Name, v)
+		}
+		return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+			return e.writeFields(p, fields, m, keyWriter)
+		})
+	}
+
+	switch ct := ct.(type) {
+	case *types.Typeref:
+		return writePrimitive(p, ct.Type, v, writer)
+	case *types.Enum:
+		s, ok := v.(string)
+		if !ok {
+			return p.errorf("expected a %s symbol, got %T", ct.Name, v)
+		}
+		for _, symbol := range ct.Symbols {
+			if s == symbol {
+				writer.WriteString(s)
+				return nil
+			}
+		}
+		return p.errorf("unknown %s symbol %q (must be one of %v)", ct.Name, s, ct.Symbols)
+	case *types.Fixed:
+		b, ok := asBytes(v)
+		if !ok {
+			return p.errorf("expected bytes, got %T", v)
+		}
+		if len(b) != ct.Size {
+			return p.errorf("expected %d bytes for %s, got %d", ct.Size, ct.Name, len(b))
+		}
+		writer.WriteBytes(b)
+		return nil
+	case *types.StandaloneUnion:
+		m, ok := asMap(v)
+		if !ok {
+			return p.errorf("expected a union, got %T", v)
+		}
+		if len(m) > 1 || (len(m) == 0 && !ct.Union.HasNull) {
+			return p.errorf("must specify exactly one member of %s, got %d", ct.Name, len(m))
+		}
+		return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+			for alias, member := range m {
+				t := unionMember(ct, alias)
+				if t == nil {
+					return p.errorf("unknown member %q of %s", alias, ct.Name)
+				}
+				return e.write(p.child(alias), t, member, keyWriter(alias))
+			}
+			return nil
+		})
+	default:
+		return p.errorf("unsupported type %q", *t.Reference)
+	}
+}
+
+// writeFields writes the given values as the given fields, checking that every value corresponds to a field and that
+// all the required fields are present
+func (e *encoder) writeFields(
+	p path,
+	fields []types.Field,
+	values map[string]any,
+	keyWriter func(string) restlicodec.Writer,
+) error {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Name] = true
+		fieldPath := p.child(f.Name)
+
+		v, ok := values[f.Name]
+		if !ok || v == nil {
+			if !f.IsOptional && f.DefaultValue == nil && !e.excluded.Matches(fieldPath) {
+				return fieldPath.errorf("missing required field")
+			}
+			continue
+		}
+
+		err := e.write(fieldPath, &f.Type, v, keyWriter(f.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	for k := range values {
+		if !known[k] {
+			return p.child(k).errorf("unknown field")
+		}
+	}
+	return nil
+}
+
+func unionMember(u *types.StandaloneUnion, alias string) *types.RestliType {
+	for _, m := range u.Union.Members {
+		if m.Alias == alias {
+			t := m.Type
+			return &t
+		}
+	}
+	return nil
+}
+
+func writePrimitive(p path, t *types.PrimitiveType, v any, writer restlicodec.Writer) error {
+	switch t.Type {
+	case types.Int32Primitive.Type:
+		i, ok := asInt64(v)
+		if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+			return p.errorf("expected an int32, got %v (%T)", v, v)
+		}
+		writer.WriteInt32(int32(i))
+	case types.Int64Primitive.Type:
+		i, ok := asInt64(v)
+		if !ok {
+			return p.errorf("expected an int64, got %v (%T)", v, v)
+		}
+		writer.WriteInt64(i)
+	case types.Float32Primitive.Type:
+		f, ok := asFloat64(v)
+		if !ok {
+			return p.errorf("expected a float32, got %v (%T)", v, v)
+		}
+		writer.WriteFloat32(float32(f))
+	case types.Float64Primitive.Type:
+		f, ok := asFloat64(v)
+		if !ok {
+			return p.errorf("expected a float64, got %v (%T)", v, v)
+		}
+		writer.WriteFloat64(f)
+	case types.BoolPrimitive.Type:
+		b, ok := v.(bool)
+		if !ok {
+			return p.errorf("expected a bool, got %v (%T)", v, v)
+		}
+		writer.WriteBool(b)
+	case types.StringPrimitive.Type:
+		s, ok := v.(string)
+		if !ok {
+			return p.errorf("expected a string, got %v (%T)", v, v)
+		}
+		writer.WriteString(s)
+	case types.BytesPrimitive.Type:
+		b, ok := asBytes(v)
+		if !ok {
+			return p.errorf("expected bytes, got %T", v)
+		}
+		writer.WriteBytes(b)
+	default:
+		return p.errorf("unknown primitive type %q", t.Type)
+	}
+	return nil
+}
+
+func asMap(v any) (map[string]any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		return v, true
+	case restlidata.RawRecord:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+func asBytes(v any) ([]byte, bool) {
+	switch v := v.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	default:
+		return nil, false
+	}
+}
+
+func asInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case float64:
+		i := int64(v)
+		return i, float64(i) == v
+	case float32:
+		i := int64(v)
+		return i, float32(i) == v
+	default:
+		return 0, false
+	}
+}
+
+func asFloat64(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		i, ok := asInt64(v)
+		return float64(i), ok
+	}
+}
+
+// read reads a value of the given type. Records, complex keys and unions are read as restlidata.RawRecords, and
+// primitives are read as their corresponding Go type (e.g. int32 fields are read as int32 instead of float64). Values
+// whose type is not declared by the manifest, including unknown record fields, are read with ReadInterface.
+func (s *schema) read(t *types.RestliType, reader restlicodec.Reader) (any, error) {
+	switch {
+	case t.Primitive != nil:
+		return readPrimitive(t.Primitive, reader)
+	case t.Array != nil:
+		items := []any{}
+		err := reader.ReadArray(func(reader restlicodec.Reader) error {
+			item, err := s.read(t.Array, reader)
+			items = append(items, item)
+			return err
+		})
+		return items, err
+	case t.Map != nil:
+		m := map[string]any{}
+		err := reader.ReadMap(func(reader restlicodec.Reader, key string) (err error) {
+			m[key], err = s.read(t.Map, reader)
+			return err
+		})
+		return m, err
+	}
+
+	ct, ok := s.types[*t.Reference]
+	if !ok {
+		v, err := reader.ReadInterface()
+		if m, ok := v.(map[string]any); ok {
+			return restlidata.RawRecord(m), err
+		}
+		return v, err
+	}
+
+	if fields, isRecord, err := s.fields(ct); isRecord {
+		if err != nil {
+			return nil, err
+		}
+		return s.readFields(fields, reader)
+	}
+
+	switch ct := ct.(type) {
+	case *types.Typeref:
+		return readPrimitive(ct.Type, reader)
+	case *types.Enum:
+		return reader.ReadString()
+	case *types.Fixed:
+		return reader.ReadBytes()
+	case *types.StandaloneUnion:
+		union := restlidata.RawRecord{}
+		err := reader.ReadMap(func(reader restlicodec.Reader, alias string) (err error) {
+			if t := unionMember(ct, alias); t != nil {
+				union[alias], err = s.read(t, reader)
+			} else {
+				union[alias], err = reader.ReadInterface()
+			}
+			return err
+		})
+		return union, err
+	default:
+		return reader.ReadInterface()
+	}
+}
+
+func (s *schema) readFields(fields []types.Field, reader restlicodec.Reader) (restlidata.RawRecord, error) {
+	record := restlidata.RawRecord{}
+	err := reader.ReadMap(func(reader restlicodec.Reader, field string) (err error) {
+		for _, f := range fields {
+			if f.Name == field {
+				record[field], err = s.read(&f.Type, reader)
+				return err
+			}
+		}
+		record[field], err = reader.ReadInterface()
+		return err
+	})
+	return record, err
+}
+
+func readPrimitive(t *types.PrimitiveType, reader restlicodec.Reader) (any, error) {
+	switch t.Type {
+	case types.Int32Primitive.Type:
+		return reader.ReadInt32()
+	case types.Int64Primitive.Type:
+		return reader.ReadInt64()
+	case types.Float32Primitive.Type:
+		return reader.ReadFloat32()
+	case types.Float64Primitive.Type:
+		return reader.ReadFloat64()
+	case types.BoolPrimitive.Type:
+		return reader.ReadBool()
+	case types.StringPrimitive.Type:
+		return reader.ReadString()
+	case types.BytesPrimitive.Type:
+		return reader.ReadBytes()
+	default:
+		return reader.ReadInterface()
+	}
+}
+
+func findField(fields []types.Field, name string) *types.Field {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// writePatch validates and writes the given partial update of a record with the given fields, using the same
+// representation as the generated partial update structs (i.e. "$set" and "$delete" directives, along with nested
+// partial updates of record fields)
+func (e *encoder) writePatch(p path, recordType string, fields []types.Field, values map[string]any, writer restlicodec.Writer) error {
+	checkField := func(name string) (*types.Field, error) {
+		f := findField(fields, name)
+		if f == nil {
+			return nil, p.child(name).errorf("unknown field")
+		}
+		if e.excluded.Matches(p.child(name)) {
+			return nil, &patch.IllegalPartialUpdateError{
+				Message:    "Cannot delete/update/partial update read-only or create-ony",
+				Field:      name,
+				RecordType: recordType,
+			}
+		}
+		return f, nil
+	}
+
+	return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+		for directive, v := range values {
+			switch directive {
+			case setDirective:
+				set, ok := asMap(v)
+				if !ok {
+					return p.child(directive).errorf("expected a map, got %T", v)
+				}
+				err := keyWriter(directive).WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+					for name, value := range set {
+						f, err := checkField(name)
+						if err != nil {
+							return err
+						}
+						err = e.write(p.child(name), &f.Type, value, keyWriter(name))
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			case deleteDirective:
+				names, ok := v.([]any)
+				if !ok {
+					return p.child(directive).errorf("expected an array, got %T", v)
+				}
+				err := keyWriter(directive).WriteArray(func(itemWriter func() restlicodec.Writer) error {
+					for _, name := range names {
+						s, ok := name.(string)
+						if !ok {
+							return p.child(directive).errorf("expected field names, got %T", name)
+						}
+						f, err := checkField(s)
+						if err != nil {
+							return err
+						}
+						if !f.IsOptional {
+							return p.child(s).errorf("cannot delete required field")
+						}
+						itemWriter().WriteString(s)
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			default:
+				f, err := checkField(directive)
+				if err != nil {
+					return err
+				}
+				var nestedFields []types.Field
+				isRecord := false
+				if f.Type.Reference != nil {
+					if ct, ok := e.types[*f.Type.Reference]; ok {
+						nestedFields, isRecord, err = e.fields(ct)
+						if err != nil {
+							return err
+						}
+					}
+				}
+				nested, ok := asMap(v)
+				if !isRecord || !ok {
+					return p.child(directive).errorf("only record fields can be partially updated")
+				}
+				err = e.writePatch(p.child(directive), f.Type.Reference.FullName(), nestedFields, nested, keyWriter(directive))
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -99,6 +99,7 @@ type DataType struct {
 	Fixed           *types.Fixed           `json:"fixed,omitempty"`
 	Record          *types.Record          `json:"record,omitempty"`
 	ComplexKey      *types.ComplexKey      `json:"complexKey,omitempty"`
+	AssociationKey  *types.AssociationKey  `json:"associationKey,omitempty"`
 	StandaloneUnion *types.StandaloneUnion `json:"standaloneUnion,omitempty"`
 	Typeref         *types.Typeref         `json:"typeref,omitempty"`
 }
@@ -113,6 +114,8 @@ func (dt *DataType) GetComplexType() utils.ComplexType {
 		return dt.Record
 	case dt.ComplexKey != nil:
 		return dt.ComplexKey
+	case dt.AssociationKey != nil:
+		return dt.AssociationKey
 	case dt.StandaloneUnion != nil:
 		return dt.StandaloneUnion
 	case dt.Typeref != nil:
@@ -157,6 +160,19 @@ func (m *GoRestliManifest) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ComplexTypes returns all the data types declared by the manifest, including its dependencies, indexed by their
+// identifier
+func (m *GoRestliManifest) ComplexTypes() map[utils.Identifier]utils.ComplexType {
+	complexTypes := make(map[utils.Identifier]utils.ComplexType, len(m.DependencyDataTypes)+len(m.InputDataTypes))
+	for _, dataTypes := range [][]DataType{m.DependencyDataTypes, m.InputDataTypes} {
+		for i := range dataTypes {
+			t := dataTypes[i].GetComplexType()
+			complexTypes[t.GetIdentifier()] = t
+		}
+	}
+	return complexTypes
+}
+
 func (m *GoRestliManifest) GenerateResourceCode() (codeFiles []*utils.CodeFile) {
 	for _, r := range m.Resources {
 		codeFiles = append(codeFiles, r.GenerateCode()...)
@@ -0,0 +1,82 @@
+// Package openapi implements the "go-restli openapi" subcommand, which generates an OpenAPI 3.1 document from the
+// resources declared by a go-restli manifest.
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/codegen/openapi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Command returns the "openapi" subcommand
+func Command() *cobra.Command {
+	c := &cobra.Command{
+		Use:          "openapi",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+	}
+	c.Short = strings.TrimSpace(`
+Generates an OpenAPI 3.1 document describing the resources and data types
+declared in the manifest (go-restli-manifest.gr.json) written by the code
+generator, for tools that do not understand rest.li IDL.`)
+	c.Example = strings.TrimSpace(`
+go-restli openapi --manifest go-restli-manifest.gr.json --output openapi.json --title "Fruits API" --server https://api.example.com`)
+
+	var manifestFile string
+	const manifestFlag = "manifest"
+	c.Flags().StringVarP(&manifestFile, manifestFlag, "m", "", "The manifest that declares the resources")
+	_ = c.MarkFlagRequired(manifestFlag)
+
+	var output string
+	c.Flags().StringVarP(&output, "output", "o", "", "The file to write the document to, instead of stdout")
+
+	info := openapi.Info{}
+	c.Flags().StringVar(&info.Title, "title", "go-restli", "The title of the API")
+	c.Flags().StringVar(&info.Version, "api-version", "1.0.0", "The version of the API")
+	c.Flags().StringVar(&info.Description, "description", "", "The description of the API")
+
+	var servers []string
+	c.Flags().StringSliceVar(&servers, "server", nil, "The URLs of the servers that serve the API")
+
+	c.RunE = func(c *cobra.Command, _ []string) error {
+		data, err := os.ReadFile(manifestFile)
+		if err != nil {
+			return err
+		}
+		manifest, err := cmd.ReadManifest(data)
+		if err != nil {
+			return errors.Wrapf(err, "go-restli: Could not read manifest from %q", manifestFile)
+		}
+
+		doc, err := openapi.Generate(manifest, info)
+		if err != nil {
+			return err
+		}
+		for _, s := range servers {
+			doc.Servers = append(doc.Servers, openapi.Server{URL: s})
+		}
+
+		var out io.Writer = c.OutOrStdout()
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	}
+
+	return c
+}
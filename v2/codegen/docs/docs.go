@@ -0,0 +1,584 @@
+// Package docs renders static, human-browsable HTML documentation from go-restli manifests. The documentation has an
+// index page listing every resource and data type, one page per resource describing its methods, and one page per
+// data type. Requests are illustrated with example ROR2 URLs and JSON bodies, built from the default and sample values
+// of each type (see the samples package).
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/codegen/resources"
+	"github.com/PapaCharlie/go-restli/v2/codegen/samples"
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+)
+
+// IndexFile is the name of the documentation's entry point
+const IndexFile = "index.html"
+
+const (
+	resourcesDir = "resources/"
+	modelsDir    = "models/"
+)
+
+// Generate returns the documentation of all the resources and data types declared in the given manifest, indexed by
+// the path of each file relative to the documentation's root directory
+func Generate(manifest *cmd.GoRestliManifest, title string) (map[string][]byte, error) {
+	complexTypes := manifest.ComplexTypes()
+	g := &generator{
+		types:   complexTypes,
+		samples: samples.New(complexTypes),
+		title:   title,
+		files:   map[string][]byte{},
+	}
+
+	index := &indexPage{page: page{Title: title}}
+
+	for _, r := range manifest.Resources {
+		rp, err := g.resourcePage(r)
+		if err != nil {
+			return nil, err
+		}
+		err = g.render(rp.filename, "resource", rp)
+		if err != nil {
+			return nil, err
+		}
+		index.Resources = append(index.Resources, indexEntry{
+			Name: rp.Name,
+			Link: rp.filename,
+			Path: rp.Path,
+			Doc:  r.Doc,
+		})
+	}
+
+	var ids []utils.Identifier
+	for id, t := range g.types {
+		// Complex keys and association keys are not data types, their key and params records and their key parts are
+		// documented instead
+		switch t.(type) {
+		case *types.ComplexKey, *types.AssociationKey:
+		default:
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].FullName() < ids[j].FullName() })
+	for _, id := range ids {
+		mp, err := g.modelPage(g.types[id])
+		if err != nil {
+			return nil, err
+		}
+		err = g.render(modelFilename(id), "model", mp)
+		if err != nil {
+			return nil, err
+		}
+		index.Models = append(index.Models, indexEntry{
+			Name: id.FullName(),
+			Link: modelFilename(id),
+			Doc:  mp.Doc,
+		})
+	}
+
+	err := g.render(IndexFile, "index", index)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.files, nil
+}
+
+type generator struct {
+	types   map[utils.Identifier]utils.ComplexType
+	samples *samples.Samples
+	title   string
+	files   map[string][]byte
+}
+
+func (g *generator) render(filename, name string, data interface{ setRoot(title, root string) }) error {
+	root := strings.Repeat("../", strings.Count(filename, "/"))
+	data.setRoot(g.title, root)
+	buf := new(bytes.Buffer)
+	err := templates.ExecuteTemplate(buf, name, data)
+	if err != nil {
+		return fmt.Errorf("go-restli: Could not render %q: %w", filename, err)
+	}
+	g.files[filename] = buf.Bytes()
+	return nil
+}
+
+func modelFilename(id utils.Identifier) string {
+	return modelsDir + id.FullName() + ".html"
+}
+
+// typeHTML returns the name of the given type, linking to the pages of the data types it references. root is the
+// relative path from the page the link is on to the documentation's root.
+func (g *generator) typeHTML(root string, t *types.RestliType) template.HTML {
+	switch {
+	case t == nil:
+		return ""
+	case t.Primitive != nil:
+		return template.HTML(template.HTMLEscapeString(t.Primitive.PegasusType()))
+	case t.Array != nil:
+		return "array[" + g.typeHTML(root, t.Array) + "]"
+	case t.Map != nil:
+		return "map[string, " + g.typeHTML(root, t.Map) + "]"
+	case t.RawRecord || t.Reference == nil:
+		return "record"
+	default:
+		return g.identifierHTML(root, *t.Reference)
+	}
+}
+
+func (g *generator) identifierHTML(root string, id utils.Identifier) template.HTML {
+	switch t := g.types[id].(type) {
+	case nil:
+		// Types that are not declared in the manifest, such as the builtin rest.li types, don't have a page
+		return template.HTML(template.HTMLEscapeString(id.FullName()))
+	case *types.ComplexKey:
+		return g.identifierHTML(root, t.Key)
+	case *types.AssociationKey:
+		return g.assocKeysHTML(root, t.Keys)
+	default:
+		return template.HTML(fmt.Sprintf(`<a href="%s" title="%s">%s</a>`,
+			template.HTMLEscapeString(root+modelFilename(id)),
+			template.HTMLEscapeString(id.FullName()),
+			template.HTMLEscapeString(id.Name)))
+	}
+}
+
+// assocKeysHTML returns the key parts of an association key, e.g. (key1: string, key2: long)
+func (g *generator) assocKeysHTML(root string, keys []types.AssocKey) template.HTML {
+	var parts []string
+	for i := range keys {
+		parts = append(parts, template.HTMLEscapeString(keys[i].Name)+": "+string(g.typeHTML(root, &keys[i].Type)))
+	}
+	return template.HTML("(" + strings.Join(parts, ", ") + ")")
+}
+
+// prettyJSON returns the indented JSON encoding of the given sample
+func prettyJSON(v any) (string, error) {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+type page struct {
+	Title     string
+	PageTitle string
+	Root      string
+}
+
+func (p *page) setRoot(title, root string) {
+	p.PageTitle = title
+	p.Root = root
+}
+
+type indexEntry struct {
+	Name string
+	Link string
+	Path string
+	Doc  string
+}
+
+type indexPage struct {
+	page
+	Resources []indexEntry
+	Models    []indexEntry
+}
+
+type resourcePage struct {
+	page
+	filename string
+	Name     string
+	Path     string
+	Doc      string
+	Kind     string
+	Key      template.HTML
+	Params   template.HTML
+	Entity   template.HTML
+	Methods  []*methodDoc
+}
+
+type methodDoc struct {
+	Kind       string
+	Anchor     string
+	Name       string
+	Doc        string
+	HTTPMethod string
+	URL        string
+	Headers    []string
+	// AssocKeys are the key parts of an assocKey finder's partial association key
+	AssocKeys template.HTML
+	Params    []paramDoc
+	Body      string
+	Returns   template.HTML
+}
+
+type paramDoc struct {
+	Name     string
+	Type     template.HTML
+	Optional bool
+	Default  string
+	Doc      string
+}
+
+func (g *generator) resourcePage(r *resources.Resource) (*resourcePage, error) {
+	var names []string
+	for _, s := range r.ResourcePathSegments {
+		names = append(names, s.ResourceName)
+	}
+	rp := &resourcePage{
+		Name:     strings.Join(names, "/"),
+		filename: resourcesDir + strings.Join(names, ".") + ".html",
+		Doc:      r.Doc,
+	}
+	root := strings.Repeat("../", strings.Count(rp.filename, "/"))
+	rp.Title = rp.Name
+
+	rb := &resourceBuilder{generator: g, root: root}
+	for i, s := range r.ResourcePathSegments {
+		rb.path += "/" + s.ResourceName
+		rb.url += "/" + s.ResourceName
+		if s.PathKey == nil {
+			continue
+		}
+		keySample := g.samples.Value(&s.PathKey.Type)
+		if i == len(r.ResourcePathSegments)-1 {
+			rb.entityKey = "/{" + s.PathKey.Name + "}"
+			rb.keySample = keySample
+			rp.Key = template.HTML(template.HTMLEscapeString(s.PathKey.Name)+": ") + g.typeHTML(root, &s.PathKey.Type)
+			if s.PathKey.Type.Reference != nil {
+				switch k := g.types[*s.PathKey.Type.Reference].(type) {
+				case *types.ComplexKey:
+					rp.Params = g.identifierHTML(root, k.Params)
+				case *types.AssociationKey:
+					rb.association = k
+				}
+			}
+		} else {
+			rb.path += "/{" + s.PathKey.Name + "}"
+			rb.url += "/" + samples.ROR2(keySample)
+		}
+	}
+	rp.Path = rb.path + rb.entityKey
+
+	switch {
+	case rb.association != nil:
+		rp.Kind = "Association"
+	case rb.entityKey != "":
+		rp.Kind = "Collection"
+	case r.ResourceSchema == nil:
+		rp.Kind = "Actions set"
+	default:
+		rp.Kind = "Simple"
+	}
+
+	if r.ResourceSchema != nil {
+		rb.entity = r.ResourceSchema
+		rb.entitySample = g.samples.Value(r.ResourceSchema)
+		rp.Entity = g.typeHTML(root, r.ResourceSchema)
+	}
+
+	for _, m := range r.Methods {
+		var md *methodDoc
+		var err error
+		switch m := m.(type) {
+		case *resources.Finder:
+			md, err = rb.finder(m.Method)
+		case *resources.BatchFinder:
+			md, err = rb.batchFinder(m.Method)
+		case *resources.Action:
+			md, err = rb.action(m.Method)
+		default:
+			md, err = rb.restMethod(m.GetMethod())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("go-restli: Could not document %s of %q: %w", m.GetMethod().Name, rp.Name, err)
+		}
+		rp.Methods = append(rp.Methods, md)
+	}
+
+	return rp, nil
+}
+
+// resourceBuilder holds the state shared by all the methods of a resource
+type resourceBuilder struct {
+	*generator
+	root string
+	// path is the path template of the resource, e.g. /collection/{collectionId}/subcollection
+	path string
+	// url is the example URL of the resource, i.e. its path with the sample keys of its parents
+	url string
+	// entityKey is the path template of the resource's entity key, e.g. /{subcollectionId}, and is only set for
+	// collections
+	entityKey string
+	keySample any
+	// association is the resource's association key, and is only set for associations
+	association  *types.AssociationKey
+	entity       *types.RestliType
+	entitySample any
+}
+
+func (rb *resourceBuilder) newMethod(kind string, method *resources.Method, httpMethod string) *methodDoc {
+	md := &methodDoc{
+		Kind:       kind,
+		Anchor:     strings.ToLower(strings.Fields(kind)[0]) + "-" + method.Name,
+		Name:       method.Name,
+		Doc:        method.Doc,
+		HTTPMethod: httpMethod,
+		URL:        rb.url,
+	}
+	if method.OnEntity && rb.entityKey != "" {
+		md.URL += "/" + samples.ROR2(rb.keySample)
+	}
+	return md
+}
+
+// addParams documents the given method's query parameters and adds their sample values to the example URL, as well
+// as the paging parameters if supported. query holds the parameters that identify the method, e.g. q=finder.
+func (rb *resourceBuilder) addParams(md *methodDoc, method *resources.Method, query ...string) {
+	for _, p := range method.Params {
+		md.Params = append(md.Params, rb.param(p))
+		query = append(query, p.Name+"="+samples.ROR2(rb.samples.Value(&p.Type)))
+	}
+	if method.IsPagingSupported {
+		query = append(query, "start=0", "count=10")
+	}
+	if len(query) > 0 {
+		md.URL += "?" + strings.Join(query, "&")
+	}
+}
+
+func (rb *resourceBuilder) param(p types.Field) paramDoc {
+	pd := paramDoc{
+		Name:     p.Name,
+		Type:     rb.typeHTML(rb.root, &p.Type),
+		Optional: p.IsOptional,
+		Doc:      p.Doc,
+	}
+	if p.DefaultValue != nil {
+		pd.Default = *p.DefaultValue
+	}
+	return pd
+}
+
+func (rb *resourceBuilder) setBody(md *methodDoc, body any) (err error) {
+	md.Body, err = prettyJSON(body)
+	return err
+}
+
+func (rb *resourceBuilder) collectionReturns(method *resources.Method) template.HTML {
+	returns := "Collection of " + rb.typeHTML(rb.root, rb.entity)
+	if method.Metadata != nil {
+		returns += ", with metadata " + rb.typeHTML(rb.root, method.Metadata)
+	}
+	return returns
+}
+
+func (rb *resourceBuilder) finder(method *resources.Method) (*methodDoc, error) {
+	md := rb.newMethod("Finder", method, http.MethodGet)
+	if len(method.AssocKeys) > 0 && rb.association != nil {
+		// assocKey finders are called on the partial association key made of the finder's key parts
+		assocKey := rb.association.Subset(rb.association.Identifier, "", method.AssocKeys)
+		md.URL += "/" + samples.ROR2(rb.samples.AssocKeys(assocKey.Keys))
+		md.AssocKeys = rb.assocKeysHTML(rb.root, assocKey.Keys)
+	}
+	rb.addParams(md, method, "q="+method.Name)
+	md.Returns = rb.collectionReturns(method)
+	return md, nil
+}
+
+func (rb *resourceBuilder) batchFinder(method *resources.Method) (*methodDoc, error) {
+	md := rb.newMethod("Batch finder", method, http.MethodGet)
+	rb.addParams(md, method, restli.BatchFinderNameParam+"="+method.Name)
+	md.Returns = rb.collectionReturns(method) + " for each criteria, or the error that occurred for that criteria"
+	return md, nil
+}
+
+func (rb *resourceBuilder) action(method *resources.Method) (*methodDoc, error) {
+	md := rb.newMethod("Action", method, http.MethodPost)
+	md.URL += "?action=" + method.Name
+	if len(method.Params) > 0 {
+		body := map[string]any{}
+		for _, p := range method.Params {
+			md.Params = append(md.Params, rb.param(p))
+			body[p.Name] = rb.samples.Value(&p.Type)
+		}
+		err := rb.setBody(md, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if method.Return != nil {
+		md.Returns = rb.typeHTML(rb.root, method.Return)
+	}
+	return md, nil
+}
+
+func (rb *resourceBuilder) restMethod(method *resources.Method) (md *methodDoc, err error) {
+	entity := rb.typeHTML(rb.root, rb.entity)
+	patch := map[string]any{"patch": map[string]any{"$set": rb.entitySample}}
+	var query []string
+	ids := "ids=" + samples.ROR2([]any{rb.keySample})
+	// The keys of the entities in batch request bodies are ROR2-encoded
+	keyed := func(v any) map[string]any {
+		return map[string]any{samples.ROR2(rb.keySample): v}
+	}
+
+	switch method.Name {
+	case "get":
+		md = rb.newMethod("REST method", method, http.MethodGet)
+		md.Returns = entity
+	case "create":
+		md = rb.newMethod("REST method", method, http.MethodPost)
+		err = rb.setBody(md, rb.entitySample)
+		md.Returns = template.HTML("The key of the created entity, in the " + restli.IDHeader + " header")
+		if method.ReturnEntity {
+			md.Returns += ", and the created " + entity
+		}
+	case "update":
+		md = rb.newMethod("REST method", method, http.MethodPut)
+		err = rb.setBody(md, rb.entitySample)
+	case "partial_update":
+		md = rb.newMethod("REST method", method, http.MethodPost)
+		err = rb.setBody(md, patch)
+		if method.ReturnEntity {
+			md.Returns = "The updated " + entity
+		}
+	case "delete":
+		md = rb.newMethod("REST method", method, http.MethodDelete)
+	case "get_all":
+		md = rb.newMethod("REST method", method, http.MethodGet)
+		md.Returns = rb.collectionReturns(method)
+	case "batch_get":
+		md = rb.newMethod("REST method", method, http.MethodGet)
+		query = append(query, ids)
+		md.Returns = "The requested " + entity + " entities, indexed by their ROR2-encoded keys"
+	case "batch_create":
+		md = rb.newMethod("REST method", method, http.MethodPost)
+		err = rb.setBody(md, map[string]any{"elements": []any{rb.entitySample}})
+		md.Returns = "The key and status of each created entity"
+		if method.ReturnEntity {
+			md.Returns += ", and the created " + entity
+		}
+	case "batch_update":
+		md = rb.newMethod("REST method", method, http.MethodPut)
+		query = append(query, ids)
+		err = rb.setBody(md, map[string]any{"entities": keyed(rb.entitySample)})
+		md.Returns = "The status of each entity"
+	case "batch_partial_update":
+		md = rb.newMethod("REST method", method, http.MethodPost)
+		query = append(query, ids)
+		err = rb.setBody(md, map[string]any{"entities": keyed(patch)})
+		md.Returns = "The status of each entity"
+	case "batch_delete":
+		md = rb.newMethod("REST method", method, http.MethodDelete)
+		query = append(query, ids)
+		md.Returns = "The status of each entity"
+	default:
+		return nil, fmt.Errorf("unknown method %q", method.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if md.HTTPMethod == http.MethodPost {
+		md.Headers = append(md.Headers, restli.MethodHeader+": "+method.Name)
+	}
+	rb.addParams(md, method, query...)
+	return md, nil
+}
+
+type modelPage struct {
+	page
+	FullName string
+	Kind     string
+	Doc      string
+	Includes []template.HTML
+	Fields   []paramDoc
+	Symbols  []symbolDoc
+	Members  []memberDoc
+	Size     int
+	Type     template.HTML
+	Example  string
+}
+
+type symbolDoc struct {
+	Symbol string
+	Doc    string
+}
+
+type memberDoc struct {
+	Alias string
+	Type  template.HTML
+}
+
+func (g *generator) modelPage(t utils.ComplexType) (mp *modelPage, err error) {
+	id := t.GetIdentifier()
+	root := strings.Repeat("../", strings.Count(modelFilename(id), "/"))
+	mp = &modelPage{FullName: id.FullName()}
+	mp.Title = id.Name
+
+	switch t := t.(type) {
+	case *types.Record:
+		mp.Kind = "Record"
+		mp.Doc = t.Doc
+		for _, include := range t.Includes {
+			mp.Includes = append(mp.Includes, g.identifierHTML(root, include))
+		}
+		for _, f := range t.Fields {
+			fd := paramDoc{
+				Name:     f.Name,
+				Type:     g.typeHTML(root, &f.Type),
+				Optional: f.IsOptional,
+				Doc:      f.Doc,
+			}
+			if f.DefaultValue != nil {
+				fd.Default = *f.DefaultValue
+			}
+			mp.Fields = append(mp.Fields, fd)
+		}
+	case *types.Enum:
+		mp.Kind = "Enum"
+		mp.Doc = t.Doc
+		for _, s := range t.Symbols {
+			mp.Symbols = append(mp.Symbols, symbolDoc{Symbol: s, Doc: t.SymbolToDoc[s]})
+		}
+	case *types.Fixed:
+		mp.Kind = "Fixed"
+		mp.Doc = t.Doc
+		mp.Size = t.Size
+	case *types.Typeref:
+		mp.Kind = "Typeref"
+		mp.Doc = t.Doc
+		mp.Type = template.HTML(template.HTMLEscapeString(t.Type.PegasusType()))
+	case *types.StandaloneUnion:
+		mp.Kind = "Union"
+		mp.Doc = t.Doc
+		if t.Union.HasNull {
+			mp.Members = append(mp.Members, memberDoc{Alias: "null", Type: "null"})
+		}
+		for _, m := range t.Union.Members {
+			mp.Members = append(mp.Members, memberDoc{Alias: m.Alias, Type: g.typeHTML(root, &m.Type)})
+		}
+	}
+
+	mp.Example, err = prettyJSON(g.samples.Named(id))
+	if err != nil {
+		return nil, err
+	}
+	return mp, nil
+}
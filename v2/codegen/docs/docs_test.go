@@ -0,0 +1,168 @@
+package docs
+
+import (
+	"html"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "packageRoot": "example.com/items",
+  "dependencyDataTypes": [],
+  "inputDataTypes": [
+    {"enum": {"name": "Color", "namespace": "test", "doc": "A color", "Symbols": ["RED", "BLUE"],
+      "SymbolToDoc": {"RED": "The color red"}}},
+    {"record": {"name": "ItemKey", "namespace": "test", "includes": [], "fields": [
+      {"name": "id", "type": {"primitive": "int32"}, "isOptional": false},
+      {"name": "name", "type": {"primitive": "string"}, "isOptional": false}
+    ]}},
+    {"record": {"name": "ItemParams", "namespace": "test", "includes": [], "fields": []}},
+    {"complexKey": {"name": "ItemComplexKey", "namespace": "test",
+      "Key": {"name": "ItemKey", "namespace": "test"},
+      "Params": {"name": "ItemParams", "namespace": "test"}}},
+    {"record": {"name": "Item", "namespace": "test", "doc": "An item", "includes": [], "fields": [
+      {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false, "defaultValue": "\"BLUE\""},
+      {"name": "tags", "type": {"array": {"primitive": "string"}}, "isOptional": true, "doc": "The tags"}
+    ]}},
+    {"record": {"name": "Note", "namespace": "test", "includes": [], "fields": [
+      {"name": "text", "type": {"primitive": "string"}, "isOptional": false}
+    ]}}
+  ],
+  "resources": [{
+    "namespace": "test",
+    "doc": "The items",
+    "resourcePathSegments": [{"resourceName": "items", "pathKey": {
+      "name": "itemsId", "type": {"reference": {"name": "ItemComplexKey", "namespace": "test"}}}}],
+    "resourceSchema": {"reference": {"name": "Item", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "REST_METHOD", "name": "batch_get", "onEntity": false, "params": []},
+      {"methodType": "REST_METHOD", "name": "partial_update", "onEntity": true, "params": []},
+      {"methodType": "FINDER", "name": "byColor", "doc": "Finds items by color", "onEntity": false,
+        "isPagingSupported": true, "params": [
+        {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false}
+      ]},
+      {"methodType": "BATCH_FINDER", "name": "byKeys", "doc": "Finds items by keys", "onEntity": false,
+        "batchParam": "criteria", "params": [
+        {"name": "criteria", "type": {"array": {"reference": {"name": "ItemKey", "namespace": "test"}}}, "isOptional": false}
+      ]},
+      {"methodType": "ACTION", "name": "count", "onEntity": false, "params": [
+        {"name": "colors", "type": {"array": {"reference": {"name": "Color", "namespace": "test"}}}, "isOptional": false}
+      ], "return": {"primitive": "int32"}}
+    ]
+  }, {
+    "namespace": "test.items.notes",
+    "resourcePathSegments": [
+      {"resourceName": "items", "pathKey": {"name": "itemsId", "type": {"reference": {"name": "ItemComplexKey", "namespace": "test"}}}},
+      {"resourceName": "notes"}
+    ],
+    "resourceSchema": {"reference": {"name": "Note", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": false, "params": []}
+    ]
+  }]
+}`
+
+func TestGenerate(t *testing.T) {
+	manifest, err := cmd.ReadManifest([]byte(testManifest))
+	require.NoError(t, err)
+	files, err := Generate(manifest, "Items")
+	require.NoError(t, err)
+
+	var filenames []string
+	for f := range files {
+		filenames = append(filenames, f)
+	}
+	require.ElementsMatch(t, []string{
+		IndexFile,
+		"resources/items.html",
+		"resources/items.notes.html",
+		"models/test.Color.html",
+		"models/test.Item.html",
+		"models/test.ItemKey.html",
+		"models/test.ItemParams.html",
+		"models/test.Note.html",
+	}, filenames)
+
+	page := func(name string) string {
+		return html.UnescapeString(string(files[name]))
+	}
+
+	index := page(IndexFile)
+	require.Contains(t, index, `<a href="resources/items.html">items</a>`)
+	require.Contains(t, index, `<code>/items/{itemsId}/notes</code>`)
+	require.Contains(t, index, `<a href="models/test.Item.html">test.Item</a>`)
+	require.NotContains(t, index, "ItemComplexKey")
+
+	items := page("resources/items.html")
+	require.Contains(t, items, `<code>/items/{itemsId}</code>`)
+	require.Contains(t, items, "The items")
+	require.Contains(t, items, `itemsId: <a href="../models/test.ItemKey.html" title="test.ItemKey">ItemKey</a>`)
+	require.Contains(t, items, "GET /items/(id:1,name:string)</pre>")
+	require.Contains(t, items, "GET /items?ids=List((id:1,name:string))")
+	require.Contains(t, items, "POST /items/(id:1,name:string)\nX-RestLi-Method: partial_update")
+	require.Contains(t, items, `"$set": {`)
+	require.Contains(t, items, "GET /items?q=byColor&color=RED&start=0&count=10")
+	require.Contains(t, items, "Finds items by color")
+	require.Contains(t, items, `<a href="#finder-byColor">byColor</a>`)
+	require.Contains(t, items, "GET /items?bq=byKeys&criteria=List((id:1,name:string))")
+	require.Contains(t, items, `<a href="#batch-byKeys">byKeys</a>`)
+	require.Contains(t, items, "POST /items?action=count")
+	require.Contains(t, items, "\"colors\": [\n    \"RED\"\n  ]")
+
+	notes := page("resources/items.notes.html")
+	require.Contains(t, notes, "GET /items/(id:1,name:string)/notes")
+
+	item := page("models/test.Item.html")
+	require.Contains(t, item, "An item")
+	require.Contains(t, item, `<a href="../models/test.Color.html" title="test.Color">Color</a>`)
+	require.Contains(t, item, `<code>"BLUE"</code>`)
+	require.Contains(t, item, "\"color\": \"BLUE\"")
+
+	color := page("models/test.Color.html")
+	require.Contains(t, color, "The color red")
+}
+
+const testAssociationManifest = `{
+  "packageRoot": "example.com/links",
+  "dependencyDataTypes": [],
+  "inputDataTypes": [
+    {"record": {"name": "Link", "namespace": "test", "includes": [], "fields": []}},
+    {"associationKey": {"name": "Links_AssociationKey", "namespace": "test", "keys": [
+      {"name": "src", "type": {"primitive": "int64"}},
+      {"name": "dest", "type": {"primitive": "string"}}
+    ]}}
+  ],
+  "resources": [{
+    "namespace": "test",
+    "resourcePathSegments": [{"resourceName": "links", "pathKey": {
+      "name": "linksId", "type": {"reference": {"name": "Links_AssociationKey", "namespace": "test"}}}}],
+    "resourceSchema": {"reference": {"name": "Link", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "FINDER", "name": "bySrc", "onEntity": false, "assocKeys": ["src"], "params": [],
+        "return": {"reference": {"name": "Link", "namespace": "test"}}}
+    ]
+  }]
+}`
+
+func TestGenerateAssociation(t *testing.T) {
+	manifest, err := cmd.ReadManifest([]byte(testAssociationManifest))
+	require.NoError(t, err)
+	files, err := Generate(manifest, "Links")
+	require.NoError(t, err)
+
+	var filenames []string
+	for f := range files {
+		filenames = append(filenames, f)
+	}
+	require.ElementsMatch(t, []string{IndexFile, "resources/links.html", "models/test.Link.html"}, filenames)
+
+	links := html.UnescapeString(string(files["resources/links.html"]))
+	require.Contains(t, links, "Association")
+	require.Contains(t, links, "linksId: (src: long, dest: string)")
+	require.Contains(t, links, "GET /links/(dest:string,src:1)</pre>")
+	require.Contains(t, links, "GET /links/(src:1)?q=bySrc")
+}
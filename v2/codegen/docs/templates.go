@@ -0,0 +1,155 @@
+package docs
+
+import (
+	"html/template"
+)
+
+var templates = template.Must(template.New("docs").Parse(`
+{{- define "header" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if ne .Title .PageTitle}}{{.Title}} - {{end}}{{.PageTitle}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; color: #222; }
+code, pre { font-family: monospace; background: #f4f4f4; }
+pre { padding: 0.5em; overflow-x: auto; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+.method { border-top: 1px solid #ccc; margin-top: 1.5em; }
+.doc { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<nav><a href="{{.Root}}index.html">{{.PageTitle}}</a></nav>
+{{- end}}
+
+{{- define "footer"}}
+</body>
+</html>
+{{end}}
+
+{{- define "doc"}}{{if .}}
+<p class="doc">{{.}}</p>
+{{- end}}{{end}}
+
+{{- define "fields"}}
+<table>
+<tr><th>Name</th><th>Type</th><th>Optional</th><th>Default</th><th>Description</th></tr>
+{{- range .}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{if .Optional}}yes{{end}}</td><td>{{if .Default}}<code>{{.Default}}</code>{{end}}</td><td class="doc">{{.Doc}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- define "index"}}{{template "header" .}}
+<h1>{{.Title}}</h1>
+<h2>Resources</h2>
+<table>
+<tr><th>Resource</th><th>Path</th><th>Description</th></tr>
+{{- range .Resources}}
+<tr><td><a href="{{.Link}}">{{.Name}}</a></td><td><code>{{.Path}}</code></td><td class="doc">{{.Doc}}</td></tr>
+{{- end}}
+</table>
+<h2>Data models</h2>
+<table>
+<tr><th>Model</th><th>Description</th></tr>
+{{- range .Models}}
+<tr><td><a href="{{.Link}}">{{.Name}}</a></td><td class="doc">{{.Doc}}</td></tr>
+{{- end}}
+</table>
+{{- template "footer"}}{{end}}
+
+{{- define "resource"}}{{template "header" .}}
+<h1>{{.Name}}</h1>
+{{- template "doc" .Doc}}
+<table>
+<tr><th>Path</th><td><code>{{.Path}}</code></td></tr>
+<tr><th>Kind</th><td>{{.Kind}}</td></tr>
+{{- if .Key}}
+<tr><th>Key</th><td>{{.Key}}</td></tr>
+{{- end}}
+{{- if .Params}}
+<tr><th>Key params</th><td>{{.Params}}</td></tr>
+{{- end}}
+{{- if .Entity}}
+<tr><th>Entity</th><td>{{.Entity}}</td></tr>
+{{- end}}
+</table>
+<h2>Methods</h2>
+<ul>
+{{- range .Methods}}
+<li><a href="#{{.Anchor}}">{{.Name}}</a> ({{.Kind}})</li>
+{{- end}}
+</ul>
+{{- range .Methods}}
+<div class="method" id="{{.Anchor}}">
+<h3>{{.Name}} <small>{{.Kind}}</small></h3>
+{{- template "doc" .Doc}}
+<pre>{{.HTTPMethod}} {{.URL}}{{range .Headers}}
+{{.}}{{end}}</pre>
+{{- if .AssocKeys}}
+<h4>Association key</h4>
+<p>{{.AssocKeys}}</p>
+{{- end}}
+{{- if .Params}}
+<h4>Parameters</h4>
+{{- template "fields" .Params}}
+{{- end}}
+{{- if .Body}}
+<h4>Example body</h4>
+<pre>{{.Body}}</pre>
+{{- end}}
+{{- if .Returns}}
+<h4>Returns</h4>
+<p>{{.Returns}}</p>
+{{- end}}
+</div>
+{{- end}}
+{{- template "footer"}}{{end}}
+
+{{- define "model"}}{{template "header" .}}
+<h1>{{.Title}} <small>{{.Kind}}</small></h1>
+<p><code>{{.FullName}}</code></p>
+{{- template "doc" .Doc}}
+{{- if .Includes}}
+<h2>Includes</h2>
+<ul>
+{{- range .Includes}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Fields}}
+<h2>Fields</h2>
+{{- template "fields" .Fields}}
+{{- end}}
+{{- if .Symbols}}
+<h2>Symbols</h2>
+<table>
+<tr><th>Symbol</th><th>Description</th></tr>
+{{- range .Symbols}}
+<tr><td><code>{{.Symbol}}</code></td><td class="doc">{{.Doc}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Members}}
+<h2>Members</h2>
+<table>
+<tr><th>Alias</th><th>Type</th></tr>
+{{- range .Members}}
+<tr><td><code>{{.Alias}}</code></td><td>{{.Type}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Size}}
+<p>Size: {{.Size}} bytes</p>
+{{- end}}
+{{- if .Type}}
+<p>Type: {{.Type}}</p>
+{{- end}}
+<h2>Example</h2>
+<pre>{{.Example}}</pre>
+{{- template "footer"}}{{end}}
+`))
@@ -0,0 +1,876 @@
+// Package openapi generates OpenAPI 3.1 documents from go-restli manifests, for tools that do not understand rest.li
+// IDL such as API gateways.
+//
+// Resources, subresources, finders, actions and batch methods are mapped to paths and operations, and all the data
+// types of the manifest are declared as JSON schemas in the document's components. Keys are documented as
+// ROR2-encoded strings, with examples. Note that rest.li distinguishes some methods by their query parameters or their
+// X-RestLi-Method header rather than their path, while OpenAPI only allows one operation per path and HTTP method.
+// All the methods that share a path and HTTP method (e.g. GET_ALL, finders and BATCH_GET on GET /fruits) are therefore
+// declared as a single operation, in which:
+//   - the q, bq, action and ids query parameters, along with the X-RestLi-Method header, identify the method
+//   - the parameters that are specific to some of the methods are optional
+//   - the request bodies and responses of the methods are declared with oneOf
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/codegen/resources"
+	"github.com/PapaCharlie/go-restli/v2/codegen/samples"
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+)
+
+// Version is the version of the OpenAPI specification generated documents conform to
+const Version = "3.1.0"
+
+const (
+	jsonContentType      = "application/json"
+	protocolVersionParam = "ProtocolVersion"
+)
+
+// Schema is a JSON schema
+type Schema = map[string]any
+
+// Document is an OpenAPI document. Only the subset of the specification used by Generate is declared.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Schemas    map[string]Schema     `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters,omitempty"`
+}
+
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string `json:"$ref,omitempty"`
+	Name        string `json:"name,omitempty"`
+	In          string `json:"in,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema,omitempty"`
+	Example     any    `json:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema  Schema `json:"schema"`
+	Example any    `json:"example,omitempty"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string `json:"description,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+type generator struct {
+	types   map[utils.Identifier]utils.ComplexType
+	samples *samples.Samples
+	doc     *Document
+	// operations holds the operations of every rest.li method by path and HTTP method, until they are added to the
+	// document's paths by addPaths
+	operations map[string]map[string][]*methodOperation
+}
+
+// methodOperation is the operation of a single rest.li method
+type methodOperation struct {
+	*Operation
+	// resource is the prefix of the operation's ID, e.g. items_notes
+	resource string
+	// method identifies the rest.li method in the operation's ID, e.g. finder_search
+	method string
+	// label identifies the rest.li method in descriptions, e.g. "finder search"
+	label string
+}
+
+// Generate returns the OpenAPI document of all the resources and data types declared in the given manifest
+func Generate(manifest *cmd.GoRestliManifest, info Info) (*Document, error) {
+	complexTypes := manifest.ComplexTypes()
+	g := &generator{
+		types:      complexTypes,
+		samples:    samples.New(complexTypes),
+		operations: map[string]map[string][]*methodOperation{},
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   map[string]*PathItem{},
+			Components: Components{
+				Schemas: map[string]Schema{},
+				Parameters: map[string]*Parameter{
+					protocolVersionParam: {
+						Name:        restli.ProtocolVersionHeader,
+						In:          "header",
+						Description: "The version of the rest.li protocol",
+						Schema:      Schema{"type": "string", "const": restli.ProtocolVersion},
+					},
+				},
+			},
+		},
+	}
+
+	for id, t := range g.types {
+		s, err := g.namedSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		// Complex keys are not data types, and are documented as ROR2-encoded strings instead
+		if s != nil {
+			g.doc.Components.Schemas[id.FullName()] = s
+		}
+	}
+	for name, s := range builtinSchemas {
+		if _, ok := g.doc.Components.Schemas[name]; !ok {
+			g.doc.Components.Schemas[name] = s
+		}
+	}
+
+	for _, r := range manifest.Resources {
+		err := g.addResource(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	g.addPaths()
+
+	return g.doc, nil
+}
+
+// resourceOperations holds the state shared by all the operations of a resource
+type resourceOperations struct {
+	*generator
+	name string
+	// path is the path of the resource, e.g. /collection/{collectionId}/subcollection
+	path string
+	// entityPath is the path of the resource's entities, which is the same as path for non-collections
+	entityPath string
+	pathKeys   []*Parameter
+	// entityKey is the parameter for the key of the resource's entities, and is only set for collections
+	entityKey *Parameter
+	// association is the resource's association key, and is only set for associations
+	association *types.AssociationKey
+	entity      Schema
+	keySample   any
+}
+
+func (g *generator) addResource(r *resources.Resource) (err error) {
+	ro := &resourceOperations{generator: g}
+
+	var names []string
+	for i, s := range r.ResourcePathSegments {
+		names = append(names, s.ResourceName)
+		ro.path += "/" + s.ResourceName
+		if s.PathKey == nil {
+			continue
+		}
+
+		param := g.pathKeyParameter(s.PathKey)
+		if i == len(r.ResourcePathSegments)-1 {
+			ro.entityKey = param
+			ro.keySample = g.samples.Value(&s.PathKey.Type)
+			if s.PathKey.Type.Reference != nil {
+				ro.association, _ = g.types[*s.PathKey.Type.Reference].(*types.AssociationKey)
+			}
+		} else {
+			ro.path += "/{" + s.PathKey.Name + "}"
+			ro.pathKeys = append(ro.pathKeys, param)
+		}
+	}
+	ro.name = strings.Join(names, "/")
+	ro.entityPath = ro.path
+	if ro.entityKey != nil {
+		ro.entityPath += "/{" + ro.entityKey.Name + "}"
+	}
+
+	if r.ResourceSchema != nil {
+		ro.entity, err = g.typeSchema(r.ResourceSchema)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range r.Methods {
+		switch m := m.(type) {
+		case *resources.Finder:
+			err = ro.addFinder(m.Method)
+		case *resources.BatchFinder:
+			err = ro.addBatchFinder(m.Method)
+		case *resources.Action:
+			err = ro.addAction(m.Method)
+		default:
+			err = ro.addRestMethod(m.GetMethod())
+		}
+		if err != nil {
+			return fmt.Errorf("go-restli: Could not generate operation for %s of %q: %w", m.GetMethod().Name, ro.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) pathKeyParameter(pk *resources.PathKey) *Parameter {
+	keyType := pk.Type.RestSpecType()
+	if pk.Type.Reference != nil {
+		switch k := g.types[*pk.Type.Reference].(type) {
+		case *types.ComplexKey:
+			keyType = k.Key.FullName()
+		case *types.AssociationKey:
+			keyType = "association"
+		}
+	}
+	return &Parameter{
+		Name:        pk.Name,
+		In:          "path",
+		Description: "The ROR2-encoded " + keyType + " key",
+		Required:    true,
+		Schema:      Schema{"type": "string"},
+		Example:     samples.ROR2(g.samples.Value(&pk.Type)),
+	}
+}
+
+func (ro *resourceOperations) operation(path, httpMethod string, method *resources.Method, restLiMethod string) *Operation {
+	operations := ro.operations[path]
+	if operations == nil {
+		operations = map[string][]*methodOperation{}
+		ro.operations[path] = operations
+	}
+
+	resource := strings.ReplaceAll(ro.name, "/", "_")
+	op := &Operation{
+		OperationID: resource + "_" + restLiMethod,
+		Description: method.Doc,
+		Tags:        []string{ro.name},
+		Parameters:  append([]*Parameter(nil), ro.pathKeys...),
+		Responses: map[string]*Response{
+			"default": {
+				Description: "A rest.li error response",
+				Content:     jsonContent(ref(errorResponseName)),
+			},
+		},
+	}
+	if method.OnEntity && ro.entityKey != nil {
+		op.Parameters = append(op.Parameters, ro.entityKey)
+	}
+	op.Parameters = append(op.Parameters,
+		&Parameter{Ref: "#/components/parameters/" + protocolVersionParam},
+		&Parameter{
+			Name:        restli.MethodHeader,
+			In:          "header",
+			Description: "The rest.li method, which is required for POST requests",
+			Required:    httpMethod == http.MethodPost,
+			Schema:      Schema{"type": "string", "const": method.Name},
+		},
+	)
+
+	label := method.Name
+	if name := strings.TrimPrefix(restLiMethod, method.Name+"_"); name != restLiMethod {
+		label += " " + name
+	}
+	operations[httpMethod] = append(operations[httpMethod], &methodOperation{
+		Operation: op,
+		resource:  resource,
+		method:    restLiMethod,
+		label:     label,
+	})
+	return op
+}
+
+// addPaths adds the operations of all the rest.li methods to the document's paths, merging the operations of the
+// methods that share a path and HTTP method
+func (g *generator) addPaths() {
+	for path, operations := range g.operations {
+		item := new(PathItem)
+		for httpMethod, ops := range operations {
+			op := ops[0].Operation
+			if len(ops) > 1 {
+				op = mergeOperations(ops)
+			}
+			switch httpMethod {
+			case http.MethodGet:
+				item.Get = op
+			case http.MethodPut:
+				item.Put = op
+			case http.MethodPost:
+				item.Post = op
+			case http.MethodDelete:
+				item.Delete = op
+			}
+		}
+		g.doc.Paths[path] = item
+	}
+}
+
+// mergeOperations returns a single operation for all the given operations, which share the same path and HTTP method.
+// Parameters are only required if they are required by all the operations, and the request bodies and responses of
+// the operations are declared with oneOf.
+func mergeOperations(ops []*methodOperation) *Operation {
+	var methods []string
+	description := new(strings.Builder)
+	fmt.Fprintf(description, "This operation is shared by multiple rest.li methods, which are identified by the q, bq, "+
+		"action and ids query parameters and by the %s header:", restli.MethodHeader)
+	for _, o := range ops {
+		methods = append(methods, o.method)
+		fmt.Fprintf(description, "\n  - %s", o.label)
+		if o.Description != "" {
+			description.WriteString(": " + strings.Join(strings.Fields(o.Description), " "))
+		}
+	}
+	merged := &Operation{
+		OperationID: ops[0].resource + "_" + strings.Join(methods, "_or_"),
+		Description: description.String(),
+		Tags:        ops[0].Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	var paramKeys []string
+	params := map[string][]*Parameter{}
+	paramLabels := map[string][]string{}
+	for _, o := range ops {
+		for _, p := range o.Parameters {
+			key := p.Ref
+			if key == "" {
+				key = p.In + ":" + p.Name
+			}
+			if _, ok := params[key]; !ok {
+				paramKeys = append(paramKeys, key)
+			}
+			params[key] = append(params[key], p)
+			paramLabels[key] = append(paramLabels[key], o.label)
+		}
+	}
+	for _, key := range paramKeys {
+		p := *params[key][0]
+		if p.Ref == "" {
+			var schemas []Schema
+			var descriptions []string
+			for _, other := range params[key] {
+				p.Required = p.Required && other.Required
+				schemas = append(schemas, other.Schema)
+				if other.Description != "" && !contains(descriptions, other.Description) {
+					descriptions = append(descriptions, other.Description)
+				}
+			}
+			p.Schema = mergeSchemas(schemas)
+			p.Description = strings.Join(descriptions, "\n\n")
+			if len(params[key]) < len(ops) {
+				p.Required = false
+				p.Description = strings.TrimSpace(fmt.Sprintf("Only used by %s. %s",
+					strings.Join(paramLabels[key], ", "), p.Description))
+			}
+		}
+		merged.Parameters = append(merged.Parameters, &p)
+	}
+
+	var bodies []*RequestBody
+	for _, o := range ops {
+		if o.RequestBody != nil {
+			bodies = append(bodies, o.RequestBody)
+		}
+	}
+	if len(bodies) == 1 && len(ops) > 1 {
+		body := *bodies[0]
+		body.Required = false
+		merged.RequestBody = &body
+	} else if len(bodies) > 1 {
+		var schemas []Schema
+		required := len(bodies) == len(ops)
+		for _, b := range bodies {
+			schemas = append(schemas, b.Content[jsonContentType].Schema)
+			required = required && b.Required
+		}
+		merged.RequestBody = &RequestBody{Required: required, Content: jsonContent(mergeSchemas(schemas))}
+	}
+
+	type labeledResponse struct {
+		label string
+		*Response
+	}
+	responses := map[string][]labeledResponse{}
+	for _, o := range ops {
+		for code, res := range o.Responses {
+			responses[code] = append(responses[code], labeledResponse{o.label, res})
+		}
+	}
+	for code, rs := range responses {
+		var unique []labeledResponse
+		for _, r := range rs {
+			duplicate := false
+			for _, u := range unique {
+				duplicate = duplicate || reflect.DeepEqual(r.Response, u.Response)
+			}
+			if !duplicate {
+				unique = append(unique, r)
+			}
+		}
+		if len(unique) == 1 {
+			merged.Responses[code] = unique[0].Response
+			continue
+		}
+
+		res := new(Response)
+		var descriptions []string
+		var schemas []Schema
+		for _, r := range unique {
+			descriptions = append(descriptions, r.label+": "+r.Description)
+			for name, h := range r.Headers {
+				if res.Headers == nil {
+					res.Headers = map[string]*Header{}
+				}
+				res.Headers[name] = h
+			}
+			if c, ok := r.Content[jsonContentType]; ok {
+				schemas = append(schemas, c.Schema)
+			}
+		}
+		res.Description = strings.Join(descriptions, "\n")
+		if len(schemas) > 0 {
+			res.Content = jsonContent(mergeSchemas(schemas))
+		}
+		merged.Responses[code] = res
+	}
+
+	return merged
+}
+
+// mergeSchemas returns a schema that matches any of the given schemas. Identical schemas are only declared once, and
+// string constants (e.g. the names of finders) are merged into a single enum.
+func mergeSchemas(schemas []Schema) Schema {
+	var unique []Schema
+	for _, s := range schemas {
+		duplicate := false
+		for _, u := range unique {
+			duplicate = duplicate || reflect.DeepEqual(s, u)
+		}
+		if !duplicate {
+			unique = append(unique, s)
+		}
+	}
+	if len(unique) == 1 {
+		return unique[0]
+	}
+
+	var constants []string
+	for _, s := range unique {
+		if c, ok := s["const"].(string); ok && len(s) == 2 && s["type"] == "string" {
+			constants = append(constants, c)
+		}
+	}
+	if len(constants) == len(unique) {
+		sort.Strings(constants)
+		return Schema{"type": "string", "enum": constants}
+	}
+	return Schema{"oneOf": unique}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlainQueryParam returns true if the given type is not ROR2-encoded when used as a query parameter, i.e. it is a
+// primitive, an enum or a typeref
+func (g *generator) isPlainQueryParam(t *types.RestliType) bool {
+	if t.Primitive != nil {
+		return true
+	}
+	if t.Reference == nil {
+		return false
+	}
+	switch g.types[*t.Reference].(type) {
+	case *types.Enum, *types.Typeref:
+		return true
+	default:
+		return false
+	}
+}
+
+// addParams adds the given query parameters to the operation, as well as the paging parameters if supported
+func (ro *resourceOperations) addParams(op *Operation, method *resources.Method) error {
+	for _, p := range method.Params {
+		schema, err := ro.typeSchema(&p.Type)
+		if err != nil {
+			return err
+		}
+		param := &Parameter{
+			Name:        p.Name,
+			In:          "query",
+			Description: p.Doc,
+			Required:    !p.IsOptional && p.DefaultValue == nil,
+			Example:     samples.ROR2(ro.samples.Value(&p.Type)),
+		}
+		if ro.isPlainQueryParam(&p.Type) {
+			param.Schema = schema
+		} else {
+			param.Schema = Schema{"type": "string"}
+			param.Description = strings.TrimSpace("The ROR2-encoded " + p.Type.RestSpecType() + ". " + p.Doc)
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+	if method.IsPagingSupported {
+		for _, name := range []string{"start", "count"} {
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "query", Schema: int32Schema()})
+		}
+	}
+	return nil
+}
+
+func (ro *resourceOperations) addFinder(method *resources.Method) error {
+	path := ro.path
+	var assocKey *Parameter
+	if len(method.AssocKeys) > 0 && ro.association != nil {
+		// assocKey finders are called on the partial association key made of the finder's key parts, which takes the
+		// place of the entity key in the path
+		keys := ro.association.Subset(ro.association.Identifier, "", method.AssocKeys).Keys
+		assocKey = &Parameter{
+			Name: ro.entityKey.Name,
+			In:   "path",
+			Description: fmt.Sprintf("For the %s finder, the ROR2-encoded partial association key, made of %s",
+				method.Name, strings.Join(method.AssocKeys, ", ")),
+			Required: true,
+			Schema:   Schema{"type": "string"},
+			Example:  samples.ROR2(ro.samples.AssocKeys(keys)),
+		}
+		path = ro.entityPath
+	}
+	op := ro.operation(path, http.MethodGet, &resources.Method{
+		Name: "finder",
+		Doc:  method.Doc,
+	}, "finder_"+method.Name)
+	if assocKey != nil {
+		op.Parameters = append(op.Parameters, assocKey)
+	}
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        "q",
+		In:          "query",
+		Description: "The name of the finder",
+		Required:    true,
+		Schema:      Schema{"type": "string", "const": method.Name},
+	})
+	err := ro.addParams(op, method)
+	if err != nil {
+		return err
+	}
+	return ro.collectionResponse(op, method)
+}
+
+func (ro *resourceOperations) addBatchFinder(method *resources.Method) error {
+	op := ro.operation(ro.path, http.MethodGet, &resources.Method{
+		Name: "batch_finder",
+		Doc:  method.Doc,
+	}, "batch_finder_"+method.Name)
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        restli.BatchFinderNameParam,
+		In:          "query",
+		Description: "The name of the batch finder",
+		Required:    true,
+		Schema:      Schema{"type": "string", "const": method.Name},
+	})
+	err := ro.addParams(op, method)
+	if err != nil {
+		return err
+	}
+
+	result, err := ro.collectionSchema(method)
+	if err != nil {
+		return err
+	}
+	properties := result["properties"].(map[string]any)
+	properties["error"] = ref(errorResponseName)
+	properties["isError"] = Schema{"type": "boolean"}
+	op.Responses["200"] = &Response{
+		Description: "The elements found for each criteria, in the same order as the criteria",
+		Content: jsonContent(Schema{
+			"type":       "object",
+			"properties": map[string]any{"elements": Schema{"type": "array", "items": result}},
+			"required":   []string{"elements"},
+		}),
+	}
+	return nil
+}
+
+func (ro *resourceOperations) addAction(method *resources.Method) error {
+	path := ro.path
+	if method.OnEntity {
+		path = ro.entityPath
+	}
+	op := ro.operation(path, http.MethodPost, &resources.Method{
+		Name:     "action",
+		Doc:      method.Doc,
+		OnEntity: method.OnEntity,
+	}, "action_"+method.Name)
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        "action",
+		In:          "query",
+		Description: "The name of the action",
+		Required:    true,
+		Schema:      Schema{"type": "string", "const": method.Name},
+	})
+
+	if len(method.Params) > 0 {
+		properties := map[string]any{}
+		sample := map[string]any{}
+		var required []string
+		for _, p := range method.Params {
+			schema, err := ro.typeSchema(&p.Type)
+			if err != nil {
+				return err
+			}
+			if p.Doc != "" {
+				schema["description"] = p.Doc
+			}
+			properties[p.Name] = schema
+			sample[p.Name] = ro.samples.Value(&p.Type)
+			if !p.IsOptional && p.DefaultValue == nil {
+				required = append(required, p.Name)
+			}
+		}
+		body := Schema{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			body["required"] = required
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(body)}
+		op.RequestBody.Content[jsonContentType].Example = sample
+	}
+
+	if method.Return != nil {
+		value, err := ro.typeSchema(method.Return)
+		if err != nil {
+			return err
+		}
+		op.Responses["200"] = &Response{
+			Description: "The value returned by the action",
+			Content: jsonContent(Schema{
+				"type":       "object",
+				"properties": map[string]any{"value": value},
+			}),
+		}
+	} else {
+		op.Responses["200"] = &Response{Description: "The action succeeded"}
+	}
+	return nil
+}
+
+func (ro *resourceOperations) addRestMethod(method *resources.Method) error {
+	idsParam := &Parameter{
+		Name:        "ids",
+		In:          "query",
+		Description: "The ROR2-encoded list of keys",
+		Required:    true,
+		Schema:      Schema{"type": "string"},
+	}
+	if ro.keySample != nil {
+		idsParam.Example = samples.ROR2([]any{ro.keySample})
+	}
+	entityBody := &RequestBody{Required: true, Content: jsonContent(ro.entity)}
+	patch := Schema{
+		"type":        "object",
+		"description": "A rest.li partial update, e.g. {\"patch\": {\"$set\": {\"field\": \"value\"}}}",
+		"properties":  map[string]any{"patch": Schema{"type": "object"}},
+		"required":    []string{"patch"},
+	}
+	keyed := func(values Schema) Schema {
+		return Schema{"type": "object", "additionalProperties": values}
+	}
+	batchStatuses := &Response{
+		Description: "The status of each entity",
+		Content: jsonContent(Schema{
+			"type": "object",
+			"properties": map[string]any{
+				"results": keyed(Schema{"type": "object", "properties": map[string]any{"status": int32Schema()}}),
+				"errors":  keyed(ref(errorResponseName)),
+			},
+		}),
+	}
+	noContent := &Response{Description: "The method succeeded"}
+	entityResponse := &Response{Description: "The entity", Content: jsonContent(ro.entity)}
+	updateResponse := func(op *Operation) {
+		if method.ReturnEntity {
+			op.Responses["200"] = entityResponse
+		} else {
+			op.Responses["204"] = noContent
+		}
+	}
+
+	var op *Operation
+	switch method.Name {
+	case "get":
+		op = ro.operation(ro.entityPath, http.MethodGet, method, method.Name)
+		op.Responses["200"] = entityResponse
+	case "create":
+		op = ro.operation(ro.path, http.MethodPost, method, method.Name)
+		op.RequestBody = entityBody
+		created := &Response{
+			Description: "The entity was created",
+			Headers: map[string]*Header{
+				restli.IDHeader: {Description: "The ROR2-encoded key of the created entity", Schema: Schema{"type": "string"}},
+			},
+		}
+		if method.ReturnEntity {
+			created.Content = jsonContent(ro.entity)
+		}
+		op.Responses["201"] = created
+	case "update":
+		op = ro.operation(ro.entityPath, http.MethodPut, method, method.Name)
+		op.RequestBody = entityBody
+		op.Responses["204"] = noContent
+	case "partial_update":
+		op = ro.operation(ro.entityPath, http.MethodPost, method, method.Name)
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(patch)}
+		updateResponse(op)
+	case "delete":
+		op = ro.operation(ro.entityPath, http.MethodDelete, method, method.Name)
+		op.Responses["204"] = noContent
+	case "get_all":
+		op = ro.operation(ro.path, http.MethodGet, method, method.Name)
+		err := ro.collectionResponse(op, method)
+		if err != nil {
+			return err
+		}
+	case "batch_get":
+		op = ro.operation(ro.path, http.MethodGet, method, method.Name)
+		op.Parameters = append(op.Parameters, idsParam)
+		op.Responses["200"] = &Response{
+			Description: "The entities, indexed by their ROR2-encoded keys",
+			Content: jsonContent(Schema{
+				"type": "object",
+				"properties": map[string]any{
+					"results": keyed(ro.entity),
+					"errors":  keyed(ref(errorResponseName)),
+				},
+			}),
+		}
+	case "batch_create":
+		op = ro.operation(ro.path, http.MethodPost, method, method.Name)
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(Schema{
+			"type":       "object",
+			"properties": map[string]any{"elements": Schema{"type": "array", "items": ro.entity}},
+		})}
+		element := Schema{
+			"type": "object",
+			"properties": map[string]any{
+				"status": int32Schema(),
+				"id":     Schema{"type": "string", "description": "The ROR2-encoded key of the created entity"},
+				"error":  ref(errorResponseName),
+			},
+		}
+		if method.ReturnEntity {
+			element["properties"].(map[string]any)["entity"] = ro.entity
+		}
+		op.Responses["200"] = &Response{
+			Description: "The status of each created entity, in the same order as the request",
+			Content: jsonContent(Schema{
+				"type":       "object",
+				"properties": map[string]any{"elements": Schema{"type": "array", "items": element}},
+			}),
+		}
+	case "batch_update":
+		op = ro.operation(ro.path, http.MethodPut, method, method.Name)
+		op.Parameters = append(op.Parameters, idsParam)
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(Schema{
+			"type":       "object",
+			"properties": map[string]any{"entities": keyed(ro.entity)},
+		})}
+		op.Responses["200"] = batchStatuses
+	case "batch_partial_update":
+		op = ro.operation(ro.path, http.MethodPost, method, method.Name)
+		op.Parameters = append(op.Parameters, idsParam)
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(Schema{
+			"type":       "object",
+			"properties": map[string]any{"entities": keyed(patch)},
+		})}
+		op.Responses["200"] = batchStatuses
+	case "batch_delete":
+		op = ro.operation(ro.path, http.MethodDelete, method, method.Name)
+		op.Parameters = append(op.Parameters, idsParam)
+		op.Responses["200"] = batchStatuses
+	default:
+		return fmt.Errorf("unknown method %q", method.Name)
+	}
+
+	return ro.addParams(op, method)
+}
+
+// collectionResponse declares the response of finders and GET_ALL
+func (ro *resourceOperations) collectionResponse(op *Operation, method *resources.Method) error {
+	schema, err := ro.collectionSchema(method)
+	if err != nil {
+		return err
+	}
+	op.Responses["200"] = &Response{
+		Description: "The elements of the collection",
+		Content:     jsonContent(schema),
+	}
+	return nil
+}
+
+func (ro *resourceOperations) collectionSchema(method *resources.Method) (Schema, error) {
+	properties := map[string]any{
+		"elements": Schema{"type": "array", "items": ro.entity},
+		"paging":   ref(collectionMetadataName),
+	}
+	if method.Metadata != nil {
+		metadata, err := ro.typeSchema(method.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		properties["metadata"] = metadata
+	}
+	return Schema{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"elements"},
+	}, nil
+}
+
+func jsonContent(schema Schema) map[string]*MediaType {
+	return map[string]*MediaType{jsonContentType: {Schema: schema}}
+}
@@ -0,0 +1,314 @@
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `{
+  "packageRoot": "example.com/items",
+  "dependencyDataTypes": [],
+  "inputDataTypes": [
+    {"enum": {"name": "Color", "namespace": "test", "doc": "A color", "Symbols": ["RED", "BLUE"]}},
+    {"standaloneUnion": {"name": "Value", "namespace": "test", "Union": {"HasNull": true, "Members": [
+      {"Type": {"primitive": "int32"}, "Alias": "int"},
+      {"Type": {"primitive": "string"}, "Alias": "string"}
+    ]}}},
+    {"fixed": {"name": "Hash", "namespace": "test", "Size": 4}},
+    {"typeref": {"name": "Timestamp", "namespace": "test", "type": "int64"}},
+    {"record": {"name": "ItemKey", "namespace": "test", "includes": [], "fields": [
+      {"name": "id", "type": {"primitive": "int32"}, "isOptional": false},
+      {"name": "name", "type": {"primitive": "string"}, "isOptional": false}
+    ]}},
+    {"record": {"name": "ItemParams", "namespace": "test", "includes": [], "fields": [
+      {"name": "version", "type": {"primitive": "int32"}, "isOptional": true}
+    ]}},
+    {"complexKey": {"name": "ItemComplexKey", "namespace": "test",
+      "Key": {"name": "ItemKey", "namespace": "test"},
+      "Params": {"name": "ItemParams", "namespace": "test"}}},
+    {"record": {"name": "Item", "namespace": "test", "doc": "An item", "includes": [
+      {"name": "ItemKey", "namespace": "test"}
+    ], "fields": [
+      {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false, "defaultValue": "\"BLUE\""},
+      {"name": "value", "type": {"reference": {"name": "Value", "namespace": "test"}}, "isOptional": false},
+      {"name": "tags", "type": {"array": {"primitive": "string"}}, "isOptional": true, "doc": "The tags"}
+    ]}},
+    {"record": {"name": "Note", "namespace": "test", "includes": [], "fields": [
+      {"name": "text", "type": {"primitive": "string"}, "isOptional": false}
+    ]}}
+  ],
+  "resources": [{
+    "namespace": "test",
+    "resourcePathSegments": [{"resourceName": "items", "pathKey": {
+      "name": "itemsId", "type": {"reference": {"name": "ItemComplexKey", "namespace": "test"}}}}],
+    "resourceSchema": {"reference": {"name": "Item", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "REST_METHOD", "name": "batch_get", "onEntity": false, "params": []},
+      {"methodType": "REST_METHOD", "name": "create", "onEntity": false, "params": [], "returnEntity": true},
+      {"methodType": "REST_METHOD", "name": "batch_create", "onEntity": false, "params": []},
+      {"methodType": "REST_METHOD", "name": "partial_update", "onEntity": true, "params": []},
+      {"methodType": "FINDER", "name": "byColor", "doc": "Finds items by color", "onEntity": false,
+        "isPagingSupported": true, "params": [
+        {"name": "color", "type": {"reference": {"name": "Color", "namespace": "test"}}, "isOptional": false},
+        {"name": "names", "type": {"array": {"primitive": "string"}}, "isOptional": true}
+      ]},
+      {"methodType": "BATCH_FINDER", "name": "byKeys", "doc": "Finds items by keys", "onEntity": false,
+        "batchParam": "criteria", "params": [
+        {"name": "criteria", "type": {"array": {"reference": {"name": "ItemKey", "namespace": "test"}}}, "isOptional": false}
+      ]},
+      {"methodType": "ACTION", "name": "count", "onEntity": false, "params": [
+        {"name": "values", "type": {"array": {"reference": {"name": "Value", "namespace": "test"}}}, "isOptional": false}
+      ], "return": {"primitive": "int32"}}
+    ]
+  }, {
+    "namespace": "test.items.notes",
+    "resourcePathSegments": [
+      {"resourceName": "items", "pathKey": {"name": "itemsId", "type": {"reference": {"name": "ItemComplexKey", "namespace": "test"}}}},
+      {"resourceName": "notes"}
+    ],
+    "resourceSchema": {"reference": {"name": "Note", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": false, "params": []},
+      {"methodType": "ACTION", "name": "clear", "onEntity": false, "params": []}
+    ]
+  }]
+}`
+
+func generateTestDocument(t *testing.T) *Document {
+	manifest, err := cmd.ReadManifest([]byte(testManifest))
+	require.NoError(t, err)
+	doc, err := Generate(manifest, Info{Title: "Items", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	// The document must be serializable
+	_, err = json.Marshal(doc)
+	require.NoError(t, err)
+	return doc
+}
+
+func findParameter(t *testing.T, op *Operation, name string) *Parameter {
+	for _, p := range op.Parameters {
+		if p.Name == name {
+			return p
+		}
+	}
+	require.Failf(t, "Missing parameter", "%q not found", name)
+	return nil
+}
+
+func TestPaths(t *testing.T) {
+	doc := generateTestDocument(t)
+	require.Equal(t, Version, doc.OpenAPI)
+
+	var paths []string
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	require.Equal(t, []string{
+		"/items",
+		"/items/{itemsId}",
+		"/items/{itemsId}/notes",
+	}, paths)
+
+	require.NotNil(t, doc.Paths["/items"].Get)
+	require.NotNil(t, doc.Paths["/items"].Post)
+	require.NotNil(t, doc.Paths["/items/{itemsId}"].Get)
+	require.NotNil(t, doc.Paths["/items/{itemsId}"].Post)
+	require.NotNil(t, doc.Paths["/items/{itemsId}/notes"].Get)
+	require.NotNil(t, doc.Paths["/items/{itemsId}/notes"].Post)
+}
+
+func TestOperations(t *testing.T) {
+	doc := generateTestDocument(t)
+
+	get := doc.Paths["/items/{itemsId}"].Get
+	require.Equal(t, "items_get", get.OperationID)
+	key := findParameter(t, get, "itemsId")
+	require.Equal(t, "path", key.In)
+	require.True(t, key.Required)
+	require.Equal(t, Schema{"type": "string"}, key.Schema)
+	require.Equal(t, "(id:1,name:string)", key.Example)
+	require.Contains(t, get.Parameters, &Parameter{Ref: "#/components/parameters/" + protocolVersionParam})
+	method := findParameter(t, get, restli.MethodHeader)
+	require.Equal(t, "header", method.In)
+	require.False(t, method.Required)
+	require.Equal(t, "get", method.Schema["const"])
+	require.Equal(t, ref("test.Item"), get.Responses["200"].Content[jsonContentType].Schema)
+
+	notes := doc.Paths["/items/{itemsId}/notes"].Get
+	require.Equal(t, "items_notes_get", notes.OperationID)
+	require.Equal(t, []string{"items/notes"}, notes.Tags)
+	findParameter(t, notes, "itemsId")
+
+	clearNotes := doc.Paths["/items/{itemsId}/notes"].Post
+	require.Equal(t, "items_notes_action_clear", clearNotes.OperationID)
+	require.True(t, findParameter(t, clearNotes, "action").Required)
+}
+
+func TestSharedOperations(t *testing.T) {
+	doc := generateTestDocument(t)
+
+	// BATCH_GET, finders and batch finders are all GET requests on the resource's path
+	get := doc.Paths["/items"].Get
+	require.Equal(t, "items_batch_get_or_finder_byColor_or_batch_finder_byKeys", get.OperationID)
+	require.Contains(t, get.Description, "finder byColor: Finds items by color")
+	require.Equal(t, Schema{"type": "string", "enum": []string{"batch_finder", "batch_get", "finder"}},
+		findParameter(t, get, restli.MethodHeader).Schema)
+
+	ids := findParameter(t, get, "ids")
+	require.False(t, ids.Required)
+	require.Equal(t, "List((id:1,name:string))", ids.Example)
+
+	q := findParameter(t, get, "q")
+	require.False(t, q.Required)
+	require.Equal(t, Schema{"type": "string", "const": "byColor"}, q.Schema)
+	require.Equal(t, "Only used by finder byColor. The name of the finder", q.Description)
+	color := findParameter(t, get, "color")
+	require.False(t, color.Required)
+	require.Equal(t, ref("test.Color"), color.Schema)
+	names := findParameter(t, get, "names")
+	require.Equal(t, Schema{"type": "string"}, names.Schema)
+	require.Equal(t, "List(string)", names.Example)
+	findParameter(t, get, "start")
+	findParameter(t, get, "count")
+
+	require.Equal(t, Schema{"type": "string", "const": "byKeys"}, findParameter(t, get, "bq").Schema)
+	require.False(t, findParameter(t, get, "criteria").Required)
+
+	// The responses of all the methods are declared with oneOf
+	results := get.Responses["200"].Content[jsonContentType].Schema["oneOf"].([]Schema)
+	require.Len(t, results, 3)
+	require.Equal(t, "batch_get: The entities, indexed by their ROR2-encoded keys\n"+
+		"finder byColor: The elements of the collection\n"+
+		"batch_finder byKeys: The elements found for each criteria, in the same order as the criteria",
+		get.Responses["200"].Description)
+	criteriaResults := results[2]["properties"].(map[string]any)["elements"].(Schema)["items"].(Schema)
+	require.Equal(t, ref(errorResponseName), criteriaResults["properties"].(map[string]any)["error"])
+	require.Equal(t, ref(errorResponseName), get.Responses["default"].Content[jsonContentType].Schema)
+
+	// CREATE, BATCH_CREATE and actions are all POST requests on the resource's path
+	post := doc.Paths["/items"].Post
+	require.Equal(t, "items_create_or_batch_create_or_action_count", post.OperationID)
+	method := findParameter(t, post, restli.MethodHeader)
+	require.True(t, method.Required)
+	require.Equal(t, Schema{"type": "string", "enum": []string{"action", "batch_create", "create"}}, method.Schema)
+	action := findParameter(t, post, "action")
+	require.False(t, action.Required)
+	require.Equal(t, Schema{"type": "string", "const": "count"}, action.Schema)
+
+	require.True(t, post.RequestBody.Required)
+	bodies := post.RequestBody.Content[jsonContentType].Schema["oneOf"].([]Schema)
+	require.Len(t, bodies, 3)
+	require.Equal(t, ref("test.Item"), bodies[0])
+	require.Equal(t, Schema{
+		"type": "object",
+		"properties": map[string]any{
+			"values": Schema{"type": "array", "items": ref("test.Value")},
+		},
+		"required": []string{"values"},
+	}, bodies[2])
+
+	require.Contains(t, post.Responses["201"].Headers, restli.IDHeader)
+	require.Equal(t, ref("test.Item"), post.Responses["201"].Content[jsonContentType].Schema)
+	require.Len(t, post.Responses["200"].Content[jsonContentType].Schema["oneOf"], 2)
+
+	// PARTIAL_UPDATE is the only POST request on the entity path, so its operation is not shared
+	partialUpdate := doc.Paths["/items/{itemsId}"].Post
+	require.Equal(t, "items_partial_update", partialUpdate.OperationID)
+	require.Equal(t, "partial_update", findParameter(t, partialUpdate, restli.MethodHeader).Schema["const"])
+}
+
+func TestSchemas(t *testing.T) {
+	schemas := generateTestDocument(t).Components.Schemas
+
+	require.NotContains(t, schemas, "test.ItemComplexKey")
+	require.Contains(t, schemas, errorResponseName)
+	require.Contains(t, schemas, collectionMetadataName)
+
+	require.Equal(t, Schema{
+		"title":       "Item",
+		"description": "An item",
+		"allOf": []Schema{
+			ref("test.ItemKey"),
+			{
+				"type": "object",
+				"properties": map[string]any{
+					"color": Schema{"$ref": schemasRef + "test.Color", "default": "BLUE"},
+					"value": ref("test.Value"),
+					"tags":  Schema{"type": "array", "items": Schema{"type": "string"}, "description": "The tags"},
+				},
+				"required": []string{"value"},
+			},
+		},
+	}, schemas["test.Item"])
+	require.Equal(t, Schema{"title": "Color", "description": "A color", "type": "string", "enum": []string{"RED", "BLUE"}},
+		schemas["test.Color"])
+	require.Equal(t, Schema{"title": "Hash", "type": "string", "minLength": 4, "maxLength": 4}, schemas["test.Hash"])
+	require.Equal(t, Schema{"title": "Timestamp", "type": "integer", "format": "int64"}, schemas["test.Timestamp"])
+	require.Equal(t, Schema{
+		"title": "Value",
+		"oneOf": []Schema{
+			{"type": "null"},
+			{
+				"type":                 "object",
+				"properties":           map[string]any{"int": Schema{"type": "integer", "format": "int32"}},
+				"required":             []string{"int"},
+				"additionalProperties": false,
+			},
+			{
+				"type":                 "object",
+				"properties":           map[string]any{"string": Schema{"type": "string"}},
+				"required":             []string{"string"},
+				"additionalProperties": false,
+			},
+		},
+	}, schemas["test.Value"])
+}
+
+const testAssociationManifest = `{
+  "packageRoot": "example.com/links",
+  "dependencyDataTypes": [],
+  "inputDataTypes": [
+    {"record": {"name": "Link", "namespace": "test", "includes": [], "fields": []}},
+    {"associationKey": {"name": "Links_AssociationKey", "namespace": "test", "keys": [
+      {"name": "src", "type": {"primitive": "int64"}},
+      {"name": "dest", "type": {"primitive": "string"}}
+    ]}}
+  ],
+  "resources": [{
+    "namespace": "test",
+    "resourcePathSegments": [{"resourceName": "links", "pathKey": {
+      "name": "linksId", "type": {"reference": {"name": "Links_AssociationKey", "namespace": "test"}}}}],
+    "resourceSchema": {"reference": {"name": "Link", "namespace": "test"}},
+    "methods": [
+      {"methodType": "REST_METHOD", "name": "get", "onEntity": true, "params": []},
+      {"methodType": "FINDER", "name": "bySrc", "onEntity": false, "assocKeys": ["src"], "params": [],
+        "return": {"reference": {"name": "Link", "namespace": "test"}}}
+    ]
+  }]
+}`
+
+func TestAssociation(t *testing.T) {
+	manifest, err := cmd.ReadManifest([]byte(testAssociationManifest))
+	require.NoError(t, err)
+	doc, err := Generate(manifest, Info{Title: "Links", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	// assocKey finders are called on the same path as GET, since the partial association key replaces the entity key
+	op := doc.Paths["/links/{linksId}"].Get
+	require.Equal(t, "links_get_or_finder_bySrc", op.OperationID)
+	key := findParameter(t, op, "linksId")
+	require.Equal(t, "path", key.In)
+	require.True(t, key.Required)
+	require.Equal(t, "The ROR2-encoded association key\n\n"+
+		"For the bySrc finder, the ROR2-encoded partial association key, made of src", key.Description)
+	require.Equal(t, "(dest:string,src:1)", key.Example)
+	require.Equal(t, Schema{"type": "string", "const": "bySrc"}, findParameter(t, op, "q").Schema)
+}
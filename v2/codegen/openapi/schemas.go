@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+)
+
+const schemasRef = "#/components/schemas/"
+
+var (
+	errorResponseName      = "com.linkedin.restli.common.ErrorResponse"
+	collectionMetadataName = "com.linkedin.restli.common.CollectionMetadata"
+	linkName               = "com.linkedin.restli.common.Link"
+
+	// builtinSchemas are the schemas of the rest.li types used by the protocol itself, which are only added to the
+	// document if the manifest does not declare them
+	builtinSchemas = map[string]Schema{
+		errorResponseName: {
+			"type":        "object",
+			"description": "The body of all rest.li error responses",
+			"properties": map[string]any{
+				"status":           int32Schema(),
+				"code":             Schema{"type": "string"},
+				"message":          Schema{"type": "string"},
+				"serviceErrorCode": int32Schema(),
+				"exceptionClass":   Schema{"type": "string"},
+				"stackTrace":       Schema{"type": "string"},
+				"errorDetailType":  Schema{"type": "string"},
+				"errorDetails":     Schema{"type": "object"},
+			},
+		},
+		collectionMetadataName: {
+			"type":     "object",
+			"required": []string{"start", "count"},
+			"properties": map[string]any{
+				"start": int32Schema(),
+				"count": int32Schema(),
+				"total": int32Schema(),
+				"links": Schema{"type": "array", "items": ref(linkName)},
+			},
+		},
+		linkName: {
+			"type":     "object",
+			"required": []string{"rel", "href", "type"},
+			"properties": map[string]any{
+				"rel":  Schema{"type": "string"},
+				"href": Schema{"type": "string"},
+				"type": Schema{"type": "string"},
+			},
+		},
+	}
+)
+
+func int32Schema() Schema {
+	return Schema{"type": "integer", "format": "int32"}
+}
+
+func ref(name string) Schema {
+	return Schema{"$ref": schemasRef + name}
+}
+
+func primitiveSchema(p *types.PrimitiveType) Schema {
+	switch p.PegasusType() {
+	case "int":
+		return int32Schema()
+	case "long":
+		return Schema{"type": "integer", "format": "int64"}
+	case "float":
+		return Schema{"type": "number", "format": "float"}
+	case "double":
+		return Schema{"type": "number", "format": "double"}
+	case "boolean":
+		return Schema{"type": "boolean"}
+	default:
+		// Bytes are encoded as strings where each character is a byte (i.e. in the U+0000 to U+00FF range), which
+		// does not correspond to any of the standard formats
+		return Schema{"type": "string"}
+	}
+}
+
+// typeSchema returns the JSON schema of the given type. Named types are referenced from the components.
+func (g *generator) typeSchema(t *types.RestliType) (Schema, error) {
+	switch {
+	case t.Primitive != nil:
+		return primitiveSchema(t.Primitive), nil
+	case t.Array != nil:
+		items, err := g.typeSchema(t.Array)
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "array", "items": items}, nil
+	case t.Map != nil:
+		values, err := g.typeSchema(t.Map)
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "object", "additionalProperties": values}, nil
+	case t.RawRecord:
+		return Schema{"type": "object"}, nil
+	case t.Reference != nil:
+		if _, ok := g.types[*t.Reference]; !ok {
+			if _, ok = builtinSchemas[t.Reference.FullName()]; !ok {
+				return nil, fmt.Errorf("go-restli: Unknown type %q", t.Reference.FullName())
+			}
+		}
+		return ref(t.Reference.FullName()), nil
+	default:
+		return nil, fmt.Errorf("go-restli: Illegal type %+v", t)
+	}
+}
+
+// namedSchema returns the JSON schema of the given named type, as declared in the document's components
+func (g *generator) namedSchema(t utils.ComplexType) (s Schema, err error) {
+	switch t := t.(type) {
+	case *types.Record:
+		s = Schema{"type": "object"}
+		properties := map[string]any{}
+		var required []string
+		for _, f := range t.Fields {
+			var fs Schema
+			fs, err = g.typeSchema(&f.Type)
+			if err != nil {
+				return nil, err
+			}
+			if f.Doc != "" {
+				fs["description"] = f.Doc
+			}
+			if f.DefaultValue != nil {
+				var defaultValue any
+				err = json.Unmarshal([]byte(*f.DefaultValue), &defaultValue)
+				if err != nil {
+					return nil, fmt.Errorf("go-restli: Invalid default value for %s.%s: %w", t.FullName(), f.Name, err)
+				}
+				fs["default"] = defaultValue
+			}
+			if !f.IsOptional && f.DefaultValue == nil {
+				required = append(required, f.Name)
+			}
+			properties[f.Name] = fs
+		}
+		s["properties"] = properties
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		if len(t.Includes) > 0 {
+			var allOf []Schema
+			for _, include := range t.Includes {
+				allOf = append(allOf, ref(include.FullName()))
+			}
+			s = Schema{"allOf": append(allOf, s)}
+		}
+		s["description"] = t.Doc
+	case *types.Enum:
+		s = Schema{"type": "string", "enum": t.Symbols, "description": t.Doc}
+	case *types.Fixed:
+		s = Schema{"type": "string", "minLength": t.Size, "maxLength": t.Size, "description": t.Doc}
+	case *types.Typeref:
+		s = primitiveSchema(t.Type)
+		s["description"] = t.Doc
+	case *types.StandaloneUnion:
+		var oneOf []Schema
+		if t.Union.HasNull {
+			oneOf = append(oneOf, Schema{"type": "null"})
+		}
+		for _, m := range t.Union.Members {
+			var ms Schema
+			ms, err = g.typeSchema(&m.Type)
+			if err != nil {
+				return nil, err
+			}
+			oneOf = append(oneOf, Schema{
+				"type":                 "object",
+				"properties":           map[string]any{m.Alias: ms},
+				"required":             []string{m.Alias},
+				"additionalProperties": false,
+			})
+		}
+		s = Schema{"oneOf": oneOf, "description": t.Doc}
+	default:
+		return nil, nil
+	}
+
+	if s["description"] == "" {
+		delete(s, "description")
+	}
+	s["title"] = t.GetIdentifier().Name
+	return s, nil
+}
@@ -0,0 +1,162 @@
+package resources
+
+import (
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	. "github.com/dave/jennifer/jen"
+)
+
+// altKeyMethods are the methods that can address entities by one of the resource's alternative keys
+var altKeyMethods = map[restli.Method]bool{
+	restli.Method_get:            true,
+	restli.Method_batch_get:      true,
+	restli.Method_update:         true,
+	restli.Method_delete:         true,
+	restli.Method_partial_update: true,
+}
+
+// AltKeyMethod is the variant of a get, batch_get, update, delete or partial_update method that addresses entities by
+// one of the resource's alternative keys. It is only generated on the client, since servers coerce alternative keys to
+// primary keys before calling the resource (see restli.RegisterKeyCoercer).
+type AltKeyMethod struct {
+	*RestMethod
+	AltKey *AlternativeKey
+}
+
+// altKeyMethods returns the AltKeyMethod variants of the resource's methods, for each of its alternative keys
+func (r *Resource) altKeyMethods() (methods []*AltKeyMethod) {
+	for i := range r.AlternativeKeys {
+		for _, m := range r.Methods {
+			if rM, ok := m.(*RestMethod); ok && altKeyMethods[rM.restLiMethod()] {
+				methods = append(methods, &AltKeyMethod{RestMethod: rM, AltKey: &r.AlternativeKeys[i]})
+			}
+		}
+	}
+	return methods
+}
+
+// clientMethods returns all the methods of the resource's client, i.e. the resource's methods followed by their
+// AltKeyMethod variants
+func (r *Resource) clientMethods() []MethodImplementation {
+	methods := append([]MethodImplementation(nil), r.Methods...)
+	for _, m := range r.altKeyMethods() {
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+func (k *AlternativeKey) pathKey() *PathKey {
+	return &PathKey{Name: k.Name, Type: k.Type}
+}
+
+func (k *AlternativeKey) resourcePathStructType() string {
+	return ResourceEntityPath + "By" + utils.ExportedIdentifier(k.Name)
+}
+
+func (a *AltKeyMethod) FuncName() string {
+	return a.RestMethod.FuncName() + "By" + utils.ExportedIdentifier(a.AltKey.Name)
+}
+
+func (a *AltKeyMethod) GetPathKeys() (keys []*PathKey) {
+	for _, segment := range a.Resource.ParentSegments() {
+		if segment.PathKey != nil {
+			keys = append(keys, segment.PathKey)
+		}
+	}
+	if a.OnEntity {
+		keys = append(keys, a.AltKey.pathKey())
+	}
+	return keys
+}
+
+func (a *AltKeyMethod) FuncParamTypes() []Code {
+	params := a.RestMethod.FuncParamTypes()
+	if a.isBatchGet() {
+		params[0] = Index().Add(a.AltKey.Type.ReferencedType())
+	}
+	return params
+}
+
+func (a *AltKeyMethod) NonErrorFuncReturnParam() Code {
+	if a.isBatchGet() {
+		return Add(Results).Add(a.batchReturnType())
+	}
+	return a.RestMethod.NonErrorFuncReturnParam()
+}
+
+func (a *AltKeyMethod) isBatchGet() bool {
+	return a.restLiMethod() == restli.Method_batch_get
+}
+
+// batchReturnType returns the type of batch_get responses, which are keyed by the alternative key
+func (a *AltKeyMethod) batchReturnType() Code {
+	return Op("*").Qual(utils.RestLiCommonPackage, BatchResponse).Index(List(a.AltKey.Type.ReferencedType(), a.EntityType()))
+}
+
+func (a *AltKeyMethod) GenerateCode() *utils.CodeFile {
+	c := a.Resource.NewCodeFile(a.Name + "By" + utils.ExportedIdentifier(a.AltKey.Name))
+
+	a.Resource.addClientFuncDeclarations(c.Code, ClientType, a, func(def *Group) {
+		genericParams := a.GenericParams()
+		batchReturnType := a.RestMethod.batchReturnType()
+		queryParamsType := Qual(utils.RestLiPackage, "AltKeyQueryParams")
+		if a.isBatchGet() {
+			genericParams = List(a.AltKey.Type.ReferencedType(), a.EntityType())
+			batchReturnType = a.batchReturnType()
+			queryParamsType = Qual(utils.RestLiPackage, "AltKeyBatchQueryParams").Index(a.AltKey.Type.ReferencedType())
+		}
+
+		a.generateClientMethod(def, a, genericParams, batchReturnType, func(queryParams Code) Code {
+			return Op("&").Add(queryParamsType).Values(DictFunc(func(d Dict) {
+				d[Id("AltKey")] = Lit(a.AltKey.Name)
+				if queryParams != nil {
+					d[Id("Params")] = queryParams
+				}
+			}))
+		})
+	})
+
+	return c
+}
+
+// generateKeyCoercers declares the KeyCoercers struct, which holds the restli.KeyCoercer of each of the resource's
+// alternative keys
+func (r *Resource) generateKeyCoercers() Code {
+	def := Empty()
+	utils.AddWordWrappedComment(def, KeyCoercers+" holds the coercers of the resource's alternative keys, which are "+
+		"passed to "+RegisterKeyCoercers+". Requests made with an alternative key whose coercer is not set are "+
+		"rejected.").Line()
+	def.Type().Id(KeyCoercers).StructFunc(func(def *Group) {
+		keyType := r.LastSegment().PathKey.GoType()
+		for _, k := range r.AlternativeKeys {
+			if k.Doc != "" {
+				utils.AddWordWrappedComment(def.Empty(), k.Doc)
+			}
+			def.Id(utils.ExportedIdentifier(k.Name)).Qual(utils.RestLiPackage, "KeyCoercer").
+				Index(List(k.Type.ReferencedType(), keyType))
+		}
+	}).Line().Line()
+	return def
+}
+
+// generateRegisterKeyCoercers declares the RegisterKeyCoercers func, which registers the coercers of the resource's
+// alternative keys that are set. It is kept separate from RegisterResource such that all resources share the same
+// RegisterResource signature.
+func (r *Resource) generateRegisterKeyCoercers(server, segments Code) Code {
+	keyCoercers := Code(Id("keyCoercers"))
+	def := Empty()
+	utils.AddWordWrappedComment(def, RegisterKeyCoercers+" registers the given coercers of the resource's alternative "+
+		"keys, and is called alongside RegisterResource before the server starts serving requests.").Line()
+	def.Func().Id(RegisterKeyCoercers).
+		Params(Add(server).Qual(utils.RestLiPackage, "Server"), Add(keyCoercers).Id(KeyCoercers)).
+		BlockFunc(func(def *Group) {
+			r.declareResourcePathSegments(def, segments)
+			for _, k := range r.AlternativeKeys {
+				coercer := Add(keyCoercers).Dot(utils.ExportedIdentifier(k.Name))
+				def.If(Add(coercer).Op("!=").Nil()).Block(
+					Qual(utils.RestLiPackage, "RegisterKeyCoercer").Call(server, segments, Lit(k.Name), coercer),
+				)
+			}
+		})
+	return def
+}
@@ -0,0 +1,136 @@
+package resources
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	. "github.com/dave/jennifer/jen"
+)
+
+type BatchFinder struct{ methodImplementation }
+
+func (f *BatchFinder) FuncName() string {
+	return BatchFindBy + utils.ExportedIdentifier(f.Name)
+}
+
+func (f *BatchFinder) FuncParamNames() []Code {
+	names := []Code{Criteria}
+	if f.hasParams() {
+		names = append(names, QueryParams)
+	}
+	return names
+}
+
+func (f *BatchFinder) FuncParamTypes() []Code {
+	paramTypes := []Code{Index().Add(f.criteriaType().ReferencedType())}
+	if f.hasParams() {
+		paramTypes = append(paramTypes, Op("*").Qual(f.Resource.PackagePath(), f.paramsStructType()))
+	}
+	return paramTypes
+}
+
+func (f *BatchFinder) NonErrorFuncReturnParam() Code {
+	results := Add(Results).Index().Op("*")
+	if f.Metadata != nil {
+		results.Add(f.Resource.LocalType(f.returnTypeAliasName()))
+	} else {
+		results.Add(f.Resource.LocalType(BatchFinderCriteriaResult))
+	}
+	return results
+}
+
+// criteriaType returns the type of the batch finder's criteria, i.e. the item type of its batch parameter
+func (f *BatchFinder) criteriaType() *types.RestliType {
+	for _, p := range f.Params {
+		if p.Name == f.BatchParam {
+			if p.Type.Array == nil {
+				log.Panicf("go-restli: Batch parameter %q of batch finder %q on %q is not an array",
+					f.BatchParam, f.Name, f.Resource.Namespace)
+			}
+			return p.Type.Array
+		}
+	}
+	log.Panicf("go-restli: Batch finder %q on %q does not declare its batch parameter %q",
+		f.Name, f.Resource.Namespace, f.BatchParam)
+	return nil
+}
+
+func (f *BatchFinder) returnTypeAliasName() string {
+	return f.FuncName() + "CriteriaResult"
+}
+
+func (f *BatchFinder) paramsStructType() string {
+	return f.FuncName() + "Params"
+}
+
+func (f *BatchFinder) GenerateCode() *utils.CodeFile {
+	c := f.Resource.NewCodeFile("batchFindBy" + utils.ExportedIdentifier(f.Name))
+
+	if f.hasParams() {
+		params := &types.Record{
+			NamedType: types.NamedType{
+				Identifier: utils.Identifier{
+					Name:      f.paramsStructType(),
+					Namespace: f.Resource.Namespace,
+				},
+				Doc: fmt.Sprintf("%s provides the parameters to the %s batch finder, other than its criteria",
+					f.paramsStructType(), f.Name),
+			},
+			Fields:   f.queryParams(),
+			Includes: f.includes(),
+		}
+		c.Code.Add(params.GenerateStruct()).Line().Line().
+			Add(params.GenerateQueryParamMarshaler(nil, nil)).Line().Line().
+			Add(params.GenerateQueryParamUnmarshaler(nil)).Line().Line().
+			Add(params.GeneratePopulateDefaultValues()).Line().Line()
+	}
+
+	if f.Metadata != nil {
+		c.Code.Type().Id(f.returnTypeAliasName()).Op("=").
+			Qual(utils.RestLiCommonPackage, "BatchFinderCriteriaResultWithMetadata").
+			Index(List(f.Return.ReferencedType(), f.Metadata.ReferencedType())).Line().Line()
+	}
+
+	f.Resource.addClientFuncDeclarations(c.Code, ClientType, f, func(def *Group) {
+		declareRpStruct(f, def)
+
+		name := "BatchFind"
+		genericParams := []Code{f.criteriaType().ReferencedType(), f.Return.ReferencedType()}
+		if f.Metadata != nil {
+			name += "WithMetadata"
+			genericParams = append(genericParams, f.Metadata.ReferencedType())
+		}
+
+		var qp Code
+		if f.hasParams() {
+			def.If(Add(QueryParams).Op("==").Nil()).Block(Return(Nil(), Qual(utils.RestLiPackage, "NilQueryParams")))
+			qp = QueryParams
+		} else {
+			qp = Nil()
+		}
+
+		def.Return(Qual(utils.RestLiPackage, name).Index(List(genericParams...)).
+			Call(RestLiClientReceiver, Ctx, Rp, Lit(f.Name), Lit(f.BatchParam), Criteria, qp))
+	})
+
+	return c
+}
+
+func (f *BatchFinder) RegisterMethod(server, resource, segments Code) Code {
+	name := "RegisterBatchFinder"
+	if f.Metadata != nil {
+		name += "WithMetadata"
+	}
+
+	return Qual(utils.RestLiPackage, name).Call(
+		Add(server), Add(segments), Lit(f.Name), Lit(f.BatchParam),
+		Line().Func().
+			Params(registerParams(f)...).
+			Params(methodReturnParams(f)...).
+			BlockFunc(func(def *Group) {
+				def.Return(resource).Dot(f.FuncName()).Call(splatRpAndParams(f)...)
+			}),
+	)
+}
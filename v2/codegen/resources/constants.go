@@ -8,9 +8,11 @@ import (
 const (
 	ResourcePath       = "ResourcePath"
 	ResourceEntityPath = "ResourceEntityPath"
+	AssocKeyField      = "AssocKey"
 
 	WithContext = "WithContext"
 	FindBy      = "FindBy"
+	BatchFindBy = "BatchFindBy"
 
 	RestLiClient        = "Client"
 	ClientReceiver      = "c"
@@ -18,12 +20,16 @@ const (
 	ClientInterfaceType = "Client"
 
 	ResourceInterfaceType = "Resource"
-
-	CreatedEntity            = "CreatedEntity"
-	CreatedAndReturnedEntity = "CreatedAndReturnedEntity"
-	Elements                 = "Elements"
-	BatchEntities            = "BatchEntities"
-	BatchResponse            = "BatchResponse"
+	ResourceSchema        = "ResourceSchema"
+	KeyCoercers           = "KeyCoercers"
+	RegisterKeyCoercers   = "RegisterKeyCoercers"
+
+	CreatedEntity             = "CreatedEntity"
+	CreatedAndReturnedEntity  = "CreatedAndReturnedEntity"
+	Elements                  = "Elements"
+	BatchEntities             = "BatchEntities"
+	BatchResponse             = "BatchResponse"
+	BatchFinderCriteriaResult = "BatchFinderCriteriaResult"
 )
 
 var (
@@ -42,7 +48,9 @@ var (
 	Entities     = Code(Id("entities"))
 	Results      = Code(Id("results"))
 	Keys         = Code(Id("keys"))
+	Criteria     = Code(Id("criteria"))
 	QueryParams  = Code(Id("queryParams"))
+	AssocKey     = Code(Id("assocKey"))
 	ActionParams = Code(Id("actionParams"))
 
 	NoExcludedFields        = Code(Qual(utils.RestLiCodecPackage, "NoExcludedFields"))
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/PapaCharlie/go-restli/v2/codegen/types"
 	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
@@ -14,20 +15,56 @@ func (f *Finder) FuncName() string {
 	return FindBy + utils.ExportedIdentifier(f.Name)
 }
 
-func (f *Finder) FuncParamNames() []Code {
+func (f *Finder) FuncParamNames() (names []Code) {
+	if f.isAssocKeyFinder() {
+		names = append(names, AssocKey)
+	}
 	if f.hasParams() {
-		return []Code{QueryParams}
-	} else {
-		return nil
+		names = append(names, QueryParams)
 	}
+	return names
 }
 
-func (f *Finder) FuncParamTypes() []Code {
+func (f *Finder) FuncParamTypes() (paramTypes []Code) {
+	if f.isAssocKeyFinder() {
+		paramTypes = append(paramTypes, Op("*").Qual(f.Resource.PackagePath(), f.assocKeyStructType()))
+	}
 	if f.hasParams() {
-		return []Code{Op("*").Qual(f.Resource.PackagePath(), f.paramsStructType())}
-	} else {
-		return nil
+		paramTypes = append(paramTypes, Op("*").Qual(f.Resource.PackagePath(), f.paramsStructType()))
+	}
+	return paramTypes
+}
+
+// isAssocKeyFinder returns true if this finder is called on a partial association key, which is passed as the
+// assocKey parameter of the generated functions
+func (f *Finder) isAssocKeyFinder() bool {
+	return len(f.AssocKeys) > 0
+}
+
+func (f *Finder) assocKeyStructType() string {
+	return f.FuncName() + "AssocKey"
+}
+
+func (f *Finder) resourcePathStructType() string {
+	return f.FuncName() + ResourcePath
+}
+
+// assocKey returns the partial association key of this assocKey finder
+func (f *Finder) assocKey() *types.AssociationKey {
+	var associationKey *types.AssociationKey
+	if pk := f.Resource.LastSegment().PathKey; pk != nil {
+		associationKey = pk.Type.AssociationKey()
+	}
+	if associationKey == nil {
+		log.Panicf("go-restli: %q declares assocKeys but %q is not an association", f.Name, f.Resource.Namespace)
 	}
+
+	name := f.assocKeyStructType()
+	return associationKey.Subset(
+		utils.Identifier{Name: name, Namespace: f.Resource.Namespace},
+		fmt.Sprintf("%s is the partial association key the %s finder is called on", name, f.Name),
+		f.AssocKeys,
+	)
 }
 
 func (f *Finder) NonErrorFuncReturnParam() Code {
@@ -72,6 +109,16 @@ func (f *Finder) GenerateCode() *utils.CodeFile {
 			Call(Lit("q=" + f.Name)).Line()
 	}
 
+	if f.isAssocKeyFinder() {
+		assocKey := f.assocKey()
+		c.Code.Add(assocKey.GenerateCode()).Line().Line()
+		utils.AddWordWrappedComment(c.Code, fmt.Sprintf("%s is the resource path of the %s finder, which is called on "+
+			"a partial association key", f.resourcePathStructType(), f.Name)).Line()
+		segments := append([]ResourcePathSegment(nil), f.Resource.ParentSegments()...)
+		segments = append(segments, ResourcePathSegment{ResourceName: f.Resource.LastSegment().ResourceName})
+		f.Resource.addResourcePathStruct(c.Code, f.resourcePathStructType(), segments, assocKey)
+	}
+
 	if f.Metadata != nil {
 		c.Code.Type().Id(f.returnTypeAliasName()).Op("=").
 			Add(ElementsWithMetadata).Index(List(f.Return.ReferencedType(), f.Metadata.ReferencedType())).Line().Line()
@@ -0,0 +1,247 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	. "github.com/dave/jennifer/jen"
+)
+
+// generateResourceSchema declares the ResourceSchema var, which describes the resource as it was declared in its
+// original restspec, such that the IDL can be regenerated from a restli.Server (see restli.GenerateIDL)
+func (r *Resource) generateResourceSchema() *utils.CodeFile {
+	c := r.NewCodeFile("resource_schema")
+
+	utils.AddWordWrappedComment(c.Code, ResourceSchema+" describes this resource as declared by its restspec, and is "+
+		"declared by RegisterResource").Line()
+	c.Code.Var().Id(ResourceSchema).Op("=").Op("&").Qual(utils.RestLiPackage, "ResourceSchema").Values(DictFunc(func(d Dict) {
+		d[Id("Namespace")] = Lit(r.restSpecNamespace())
+		if r.Doc != "" {
+			d[Id("Doc")] = Lit(r.Doc)
+		}
+		if r.ResourceSchema != nil {
+			d[Id("Schema")] = Lit(r.ResourceSchema.RestSpecType())
+		}
+		if pk := r.LastSegment().PathKey; pk != nil {
+			d[Id("Key")] = Op("&").Qual(utils.RestLiPackage, "KeySchema").Values(DictFunc(func(d Dict) {
+				d[Id("Name")] = Lit(pk.Name)
+				var k utils.ComplexType
+				if pk.Type.Reference != nil {
+					k = pk.Type.Reference.Resolve()
+				}
+				switch k := k.(type) {
+				case *types.ComplexKey:
+					d[Id("Type")] = Lit(k.Key.FullName())
+					d[Id("Params")] = Lit(k.Params.FullName())
+				case *types.AssociationKey:
+					d[Id("AssocKeys")] = Index().Qual(utils.RestLiPackage, "AssocKeySchema").ValuesFunc(func(def *Group) {
+						for _, ak := range k.Keys {
+							def.Line().Values(Dict{
+								Id("Name"): Lit(ak.Name),
+								Id("Type"): Lit(ak.Type.RestSpecType()),
+							})
+						}
+						def.Line()
+					})
+				default:
+					d[Id("Type")] = Lit(pk.Type.RestSpecType())
+				}
+			}))
+		}
+		if len(r.AlternativeKeys) > 0 {
+			d[Id("AlternativeKeys")] = Index().Qual(utils.RestLiPackage, "AlternativeKeySchema").ValuesFunc(func(def *Group) {
+				for _, k := range r.AlternativeKeys {
+					def.Line().Values(DictFunc(func(d Dict) {
+						d[Id("Name")] = Lit(k.Name)
+						if k.Doc != "" {
+							d[Id("Doc")] = Lit(k.Doc)
+						}
+						d[Id("Type")] = Lit(k.Type.RestSpecType())
+						d[Id("KeyCoercer")] = Lit(k.KeyCoercer)
+					}))
+				}
+				def.Line()
+			})
+		}
+		if len(r.ReadOnlyFields) > 0 {
+			d[Id("ReadOnlyFields")] = Index().String().ValuesFunc(func(def *Group) {
+				for _, f := range r.ReadOnlyFields {
+					def.Lit(f)
+				}
+			})
+		}
+		if len(r.CreateOnlyFields) > 0 {
+			d[Id("CreateOnlyFields")] = Index().String().ValuesFunc(func(def *Group) {
+				for _, f := range r.CreateOnlyFields {
+					def.Lit(f)
+				}
+			})
+		}
+		if len(r.ServiceErrors) > 0 {
+			d[Id("ServiceErrors")] = serviceErrorDefinitions(r.ServiceErrors)
+		}
+		d[Id("Methods")] = Index().Qual(utils.RestLiPackage, "MethodSchema").ValuesFunc(func(def *Group) {
+			for _, m := range r.Methods {
+				def.Line().Add(methodSchema(m))
+			}
+			def.Line()
+		})
+		dataSchemas := func(schema func(t utils.ComplexType) (string, bool)) Code {
+			return Map(String()).String().ValuesFunc(func(def *Group) {
+				r.dataSchemas().Range(func(id utils.Identifier) {
+					if s, ok := schema(id.Resolve()); ok {
+						def.Line().Lit(id.FullName()).Op(":").Lit(s)
+					}
+				})
+				def.Line()
+			})
+		}
+		d[Id("DataSchemas")] = dataSchemas(types.PDL)
+		d[Id("JSONDataSchemas")] = dataSchemas(types.JSONSchema)
+	})).Line().Line()
+
+	return c
+}
+
+// restSpecNamespace returns the namespace of the resource's restspec. The namespace of the resource is suffixed with
+// the name of every resource in its path, such that subresources are generated in their own package.
+func (r *Resource) restSpecNamespace() string {
+	var names []string
+	for _, rps := range r.ResourcePathSegments {
+		names = append(names, rps.ResourceName)
+	}
+	return strings.TrimSuffix(r.Namespace, "."+strings.Join(names, "."))
+}
+
+func methodSchema(m MethodImplementation) Code {
+	method := m.GetMethod()
+	return Values(DictFunc(func(d Dict) {
+		switch m.(type) {
+		case *Finder:
+			d[Id("Method")] = Qual(utils.RestLiPackage, "Method_finder")
+			d[Id("Name")] = Lit(method.Name)
+			if len(method.AssocKeys) > 0 {
+				d[Id("AssocKeys")] = Index().String().ValuesFunc(func(def *Group) {
+					for _, k := range method.AssocKeys {
+						def.Lit(k)
+					}
+				})
+			}
+		case *BatchFinder:
+			d[Id("Method")] = Qual(utils.RestLiPackage, "Method_batch_finder")
+			d[Id("Name")] = Lit(method.Name)
+			d[Id("BatchParam")] = Lit(method.BatchParam)
+		case *Action:
+			d[Id("Method")] = Qual(utils.RestLiPackage, "Method_action")
+			d[Id("Name")] = Lit(method.Name)
+			if method.OnEntity {
+				d[Id("OnEntity")] = True()
+			}
+		default:
+			d[Id("Method")] = Qual(utils.RestLiPackage, "Method_"+method.Name)
+		}
+		if method.Doc != "" {
+			d[Id("Doc")] = Lit(method.Doc)
+		}
+		if len(method.Params) > 0 {
+			d[Id("Params")] = Index().Qual(utils.RestLiPackage, "ParameterSchema").ValuesFunc(func(def *Group) {
+				for _, p := range method.Params {
+					def.Line().Values(DictFunc(func(d Dict) {
+						d[Id("Name")] = Lit(p.Name)
+						d[Id("Type")] = Lit(p.Type.RestSpecType())
+						if p.Doc != "" {
+							d[Id("Doc")] = Lit(p.Doc)
+						}
+						if p.IsOptional {
+							d[Id("Optional")] = True()
+						}
+						if p.DefaultValue != nil {
+							d[Id("Default")] = Qual(utils.RestLiPackage, "StringPointer").Call(Lit(*p.DefaultValue))
+						}
+					}))
+				}
+				def.Line()
+			})
+		}
+		if method.IsPagingSupported {
+			d[Id("PagingSupported")] = True()
+		}
+		if _, ok := m.(*Action); ok && method.Return != nil {
+			d[Id("Returns")] = Lit(method.Return.RestSpecType())
+		}
+		if method.Metadata != nil {
+			d[Id("Metadata")] = Lit(method.Metadata.RestSpecType())
+		}
+		if method.ReturnEntity {
+			d[Id("ReturnEntity")] = True()
+		}
+		if method.MaxBatchSize != nil {
+			d[Id("MaxBatchSize")] = Op("&").Qual(utils.RestLiPackage, "MaxBatchSize").Values(Dict{
+				Id("Value"):    Lit(method.MaxBatchSize.Value),
+				Id("Validate"): Lit(method.MaxBatchSize.Validate),
+			})
+		}
+		if len(method.ServiceErrors) > 0 {
+			d[Id("ServiceErrors")] = serviceErrorDefinitions(method.ServiceErrors)
+		}
+	}))
+}
+
+func serviceErrorDefinitions(serviceErrors []ServiceError) Code {
+	return Index().Qual(utils.RestLiPackage, "ServiceErrorDefinition").ValuesFunc(func(def *Group) {
+		for _, e := range serviceErrors {
+			def.Id(serviceErrorName(e.Code))
+		}
+	})
+}
+
+// dataSchemas returns all the named types transitively referenced by the resource. Types that are not pegasus types,
+// such as complex keys, are not themselves included, but the types they reference are.
+func (r *Resource) dataSchemas() utils.IdentifierSet {
+	set := utils.IdentifierSet{}
+	var add func(t *types.RestliType)
+	var addId func(id utils.Identifier)
+	addId = func(id utils.Identifier) {
+		// RawRecord and PagingContext are not pegasus types
+		if id.Namespace == utils.RawRecordIdentifier.Namespace || set[id] {
+			return
+		}
+		set.Add(id)
+		for referenced := range id.Resolve().ReferencedTypes() {
+			addId(referenced)
+		}
+	}
+	add = func(t *types.RestliType) {
+		if t == nil || t.RawRecord {
+			return
+		}
+		for id := range t.InnerTypes() {
+			addId(id)
+		}
+	}
+
+	for _, rps := range r.ResourcePathSegments {
+		if rps.PathKey != nil {
+			add(&rps.PathKey.Type)
+		}
+	}
+	add(r.ResourceSchema)
+	addErrors := func(serviceErrors []ServiceError) {
+		for _, e := range serviceErrors {
+			add(e.ErrorDetailType)
+		}
+	}
+	addErrors(r.ServiceErrors)
+	for _, m := range r.Methods {
+		method := m.GetMethod()
+		for i := range method.Params {
+			add(&method.Params[i].Type)
+		}
+		add(method.Return)
+		add(method.Metadata)
+		addErrors(method.ServiceErrors)
+	}
+
+	return set
+}
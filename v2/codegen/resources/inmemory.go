@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	. "github.com/dave/jennifer/jen"
+)
+
+const (
+	NewInMemoryResource   = "NewInMemoryResource"
+	inMemoryCollectionArg = "collection"
+)
+
+// restMethods returns all the rest methods of the resource
+func (r *Resource) restMethods() (methods []*RestMethod) {
+	for _, m := range r.Methods {
+		if rM, ok := m.(*RestMethod); ok {
+			methods = append(methods, rM)
+		}
+	}
+	return methods
+}
+
+// hasInMemoryResource returns true if the resource is a collection with at least one rest method, and whose key can
+// be used as an inmemory.InMemoryCollection key (i.e. it is comparable)
+func (r *Resource) hasInMemoryResource() bool {
+	if r.ResourceSchema == nil || r.LastSegment().PathKey == nil || len(r.restMethods()) == 0 {
+		return false
+	}
+
+	keyType := r.LastSegment().PathKey.Type
+	switch {
+	case keyType.Primitive != nil:
+		return !keyType.Primitive.IsBytes()
+	case keyType.IsMapOrArray():
+		return false
+	case keyType.IsCustomTyperef():
+		// Custom typerefs can be any Go type, which may not be comparable
+		return false
+	case keyType.Typeref() != nil:
+		return !keyType.Typeref().Type.IsBytes()
+	default:
+		return true
+	}
+}
+
+// generateInMemoryResource generates a function that returns a MockResource whose rest methods are all backed by an
+// inmemory.InMemoryCollection
+func (r *Resource) generateInMemoryResource(resourceStruct string) Code {
+	if !r.hasInMemoryResource() {
+		return nil
+	}
+
+	collection := Code(Id(inMemoryCollectionArg))
+	collectionType := Op("*").Qual(utils.InMemoryPackage, "InMemoryCollection").
+		Index(List(r.LastSegment().PathKey.GoType(), r.ResourceSchema.ReferencedType()))
+
+	def := Empty()
+	comment := NewInMemoryResource + " returns a " + resourceStruct + " whose rest methods are backed by the given\n" +
+		"InMemoryCollection. Query parameters are ignored, and finders and actions are left unset."
+	if len(r.ParentSegments()) > 0 {
+		comment += "\nThe keys of parent resources are also ignored."
+	}
+	utils.AddWordWrappedComment(def, comment).Line()
+	def.Func().Id(NewInMemoryResource).
+		Params(Add(collection).Add(collectionType)).
+		Op("*").Id(resourceStruct).
+		Block(Return(Op("&").Id(resourceStruct).Values(DictFunc(func(d Dict) {
+			for _, m := range r.restMethods() {
+				d[Id("Mock"+m.FuncName())] = Func().Params(methodParams(m, resourceContext)...).
+					Params(methodReturnParams(m)...).
+					Block(Return(m.inMemoryCall(collection)))
+			}
+		}))))
+
+	return def
+}
+
+// inMemoryCall returns the call to the inmemory.InMemoryCollection method that implements this rest method
+func (r *RestMethod) inMemoryCall(collection Code) Code {
+	args := []Code{Ctx}
+	if r.OnEntity {
+		args = append(args, Id(r.Resource.LastSegment().PathKey.Name))
+	}
+	params := r.FuncParamNames()
+	if r.hasParams() {
+		// The query params are always the last parameter
+		params = params[:len(params)-1]
+	}
+	args = append(args, params...)
+
+	if r.restLiMethod() == restli.Method_batch_partial_update {
+		return Qual(utils.InMemoryPackage, "BatchPartialUpdate").Call(append([]Code{collection}, args...)...)
+	}
+
+	name := r.FuncName()
+	if r.ReturnEntity {
+		name += "WithReturnEntity"
+	}
+	return Add(collection).Dot(name).Call(args...)
+}
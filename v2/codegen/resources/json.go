@@ -42,6 +42,17 @@ type Resource struct {
 	Methods              []MethodImplementation `json:"-"`
 	ReadOnlyFields       []string               `json:"readOnlyFields"`
 	CreateOnlyFields     []string               `json:"createOnlyFields"`
+	ServiceErrors        []ServiceError         `json:"serviceErrors"`
+	AlternativeKeys      []AlternativeKey       `json:"alternativeKeys"`
+}
+
+// AlternativeKey is an alternative key of a collection or association, which its entities can also be addressed with.
+// KeyCoercer is the fully qualified name of the Java class that coerces it to the resource's primary key.
+type AlternativeKey struct {
+	Name       string           `json:"name"`
+	Doc        string           `json:"doc"`
+	Type       types.RestliType `json:"type"`
+	KeyCoercer string           `json:"keyCoercer"`
 }
 
 func (r *Resource) UnmarshalJSON(data []byte) (err error) {
@@ -73,6 +84,8 @@ func (r *Resource) UnmarshalJSON(data []byte) (err error) {
 			impl = &Action{mI}
 		case FINDER:
 			impl = &Finder{mI}
+		case BATCH_FINDER:
+			impl = &BatchFinder{mI}
 		default:
 			return errors.Errorf("unknown method type: %s", m.MethodType)
 		}
@@ -85,9 +98,10 @@ func (r *Resource) UnmarshalJSON(data []byte) (err error) {
 type MethodType string
 
 const (
-	REST_METHOD MethodType = "REST_METHOD"
-	ACTION      MethodType = "ACTION"
-	FINDER      MethodType = "FINDER"
+	REST_METHOD  MethodType = "REST_METHOD"
+	ACTION       MethodType = "ACTION"
+	FINDER       MethodType = "FINDER"
+	BATCH_FINDER MethodType = "BATCH_FINDER"
 )
 
 type Method struct {
@@ -100,10 +114,47 @@ type Method struct {
 	Return            *types.RestliType `json:"return"`
 	Metadata          *types.RestliType `json:"metadata"`
 	ReturnEntity      bool              `json:"returnEntity"`
+	MaxBatchSize      *MaxBatchSize     `json:"maxBatchSize"`
+	ServiceErrors     []ServiceError    `json:"serviceErrors"`
+	BatchParam        string            `json:"batchParam"`
+	// AssocKeys are the association key parts that assocKey finders are called on, and are only set for the finders
+	// of associations
+	AssocKeys []string `json:"assocKeys"`
+}
+
+// MaxBatchSize is the maximum number of keys or entities a batch method accepts, as declared by its @MaxBatchSize
+// annotation. Clients always split larger batches, but servers only reject them if Validate is true.
+type MaxBatchSize struct {
+	Value    int  `json:"value"`
+	Validate bool `json:"validate"`
+}
+
+// ServiceError is a service error declared by a resource or method's @ServiceErrors annotation. ErrorDetailType is nil
+// if the error does not declare any details.
+type ServiceError struct {
+	Code            string            `json:"code"`
+	Status          int               `json:"status"`
+	Message         string            `json:"message"`
+	ErrorDetailType *types.RestliType `json:"errorDetailType"`
 }
 
 func (m *Method) hasParams() bool {
-	return len(m.Params) > 0 || m.IsPagingSupported
+	return len(m.queryParams()) > 0 || m.IsPagingSupported
+}
+
+// queryParams returns the method's parameters, excluding the batch finder's batch parameter (if any) since the criteria
+// are passed separately
+func (m *Method) queryParams() []types.Field {
+	if m.BatchParam == "" {
+		return m.Params
+	}
+	var params []types.Field
+	for _, p := range m.Params {
+		if p.Name != m.BatchParam {
+			params = append(params, p)
+		}
+	}
+	return params
 }
 
 func (m *Method) includes() []utils.Identifier {
@@ -37,19 +37,39 @@ func (m *methodImplementation) GetPathKeys() (keys []*PathKey) {
 	return keys
 }
 
-func declareRpStruct(m MethodImplementation, def *Group) {
-	rp := def.Add(Rp).Op(":=").Op("&")
-	if m.GetMethod().OnEntity {
-		rp.Id(ResourceEntityPath)
+// resourcePathStruct returns the name of the resource path struct of the given method. assocKey finders have their own
+// resource path struct, which holds the partial association key they are called on, as do the entity methods called
+// with an alternative key.
+func resourcePathStruct(m MethodImplementation) string {
+	if f, ok := m.(*Finder); ok && f.isAssocKeyFinder() {
+		return f.resourcePathStructType()
+	} else if a, ok := m.(*AltKeyMethod); ok && a.OnEntity {
+		return a.AltKey.resourcePathStructType()
+	} else if m.GetMethod().OnEntity {
+		return ResourceEntityPath
 	} else {
-		rp.Id(ResourcePath)
+		return ResourcePath
 	}
+}
+
+// nonPathFuncParamNames returns the parameters of the given method that are not held by its resource path struct
+func nonPathFuncParamNames(m MethodImplementation) []Code {
+	names := m.FuncParamNames()
+	if f, ok := m.(*Finder); ok && f.isAssocKeyFinder() {
+		names = names[1:]
+	}
+	return names
+}
 
+func declareRpStruct(m MethodImplementation, def *Group) {
 	pathParamNames := entityParamNames(m)
-	rp.Add(utils.OrderedValues(func(add func(key Code, value Code)) {
+	def.Add(Rp).Op(":=").Op("&").Id(resourcePathStruct(m)).Add(utils.OrderedValues(func(add func(key Code, value Code)) {
 		for _, name := range pathParamNames {
 			add(name, name)
 		}
+		if f, ok := m.(*Finder); ok && f.isAssocKeyFinder() {
+			add(Id(AssocKeyField), AssocKey)
+		}
 	}))
 }
 
@@ -58,7 +78,10 @@ func splatRpAndParams(m MethodImplementation) []Code {
 	for _, pk := range m.GetPathKeys() {
 		params = append(params, Add(Rp).Dot(pk.Name))
 	}
-	params = append(params, m.FuncParamNames()...)
+	if f, ok := m.(*Finder); ok && f.isAssocKeyFinder() {
+		params = append(params, Add(Rp).Dot(AssocKeyField))
+	}
+	params = append(params, nonPathFuncParamNames(m)...)
 	return params
 }
 
@@ -111,15 +134,10 @@ func methodReturnParams(m MethodImplementation) []Code {
 
 func registerParams(m MethodImplementation) []Code {
 	params := []Code{RequestContextParam}
-	rp := Add(Rp).Op("*")
-	if m.GetMethod().OnEntity {
-		rp.Id(ResourceEntityPath)
-	} else {
-		rp.Id(ResourcePath)
-	}
-	params = append(params, rp)
+	params = append(params, Add(Rp).Op("*").Id(resourcePathStruct(m)))
 
-	names, types := m.FuncParamNames(), m.FuncParamTypes()
+	names, types := nonPathFuncParamNames(m), m.FuncParamTypes()
+	types = types[len(types)-len(names):]
 	for i, name := range names {
 		params = append(params, Add(name).Add(types[i]))
 	}
@@ -53,6 +53,10 @@ func (r *Resource) GenerateCode() []*utils.CodeFile {
 		}
 	}
 
+	for i := range r.AlternativeKeys {
+		r.addAltKeyResourcePathStruct(resource.Code, &r.AlternativeKeys[i])
+	}
+
 	newPathSpec := func(directives []string) Code {
 		return Qual(utils.RestLiCodecPackage, "NewPathSpec").CallFunc(func(def *Group) {
 			for _, d := range directives {
@@ -86,6 +90,10 @@ func (r *Resource) GenerateCode() []*utils.CodeFile {
 		resource.Code.Type().DefsFunc(func(def *Group) {
 			entityType := r.ResourceSchema.ReferencedType()
 			def.Id(Elements).Op("=").Qual(utils.RestLiCommonPackage, Elements).Index(entityType)
+			if r.hasBatchFinders() {
+				def.Id(BatchFinderCriteriaResult).Op("=").
+					Qual(utils.RestLiCommonPackage, BatchFinderCriteriaResult).Index(entityType)
+			}
 			if r.LastSegment().PathKey != nil {
 				keyType := r.LastSegment().PathKey.Type.ReferencedType()
 				def.Id(CreatedEntity).Op("=").Qual(utils.RestLiCommonPackage, CreatedEntity).Index(keyType)
@@ -101,10 +109,15 @@ func (r *Resource) GenerateCode() []*utils.CodeFile {
 	resource.Code.Add(r.generateClientCode(), r.generateResourceCode())
 	codeFiles := []*utils.CodeFile{resource}
 
-	for _, m := range r.Methods {
+	for _, m := range r.clientMethods() {
 		codeFiles = append(codeFiles, m.GenerateCode())
 	}
 
+	if serviceErrors := r.generateServiceErrors(); serviceErrors != nil {
+		codeFiles = append(codeFiles, serviceErrors)
+	}
+
+	codeFiles = append(codeFiles, r.generateResourceSchema())
 	codeFiles = append(codeFiles, r.generateTestCode())
 
 	return codeFiles
@@ -115,7 +128,7 @@ func (r *Resource) generateClientCode() Code {
 
 	utils.AddWordWrappedComment(def, r.Doc).Line()
 	def.Type().Id(ClientInterfaceType).InterfaceFunc(func(def *Group) {
-		for _, m := range r.Methods {
+		for _, m := range r.clientMethods() {
 			if m.GetMethod().MethodType != REST_METHOD {
 				utils.AddWordWrappedComment(def.Empty(), m.GetMethod().Doc)
 			}
@@ -145,28 +158,42 @@ func (r *Resource) generateResourceCode() Code {
 		}
 	}).Line().Line()
 
-	var server, resource Code = Id("server"), Id("resource")
+	var server, resource, segments Code = Id("server"), Id("resource"), Id("segments")
 	def.Func().Id("RegisterResource").
 		Params(Add(server).Qual(utils.RestLiPackage, "Server"), Add(resource).Id(ResourceInterfaceType)).
 		BlockFunc(func(def *Group) {
-			segments := Code(Id("segments"))
-			def.Add(segments).Op(":=").Index().Qual(utils.RestLiPackage, "ResourcePathSegment").
-				ValuesFunc(func(def *Group) {
-					for _, rps := range r.ResourcePathSegments {
-						def.Line().Qual(utils.RestLiPackage, "NewResourcePathSegment").Call(Lit(rps.ResourceName), Lit(rps.PathKey != nil))
-					}
-					def.Line()
-				})
-
+			r.declareResourcePathSegments(def, segments)
 			for _, m := range r.Methods {
 				def.Add(m.RegisterMethod(server, resource, segments))
+				if declare := r.declareServiceErrors(m, server, segments); declare != nil {
+					def.Add(declare)
+				}
 			}
+			def.Qual(utils.RestLiPackage, "DeclareResourceSchema").Call(server, segments, Id(ResourceSchema))
 
 		})
 
+	if len(r.AlternativeKeys) > 0 {
+		def.Line().Line()
+		def.Add(r.generateKeyCoercers())
+		def.Add(r.generateRegisterKeyCoercers(server, segments))
+	}
+
 	return def
 }
 
+// declareResourcePathSegments declares the segments variable, which holds the restli.ResourcePathSegment of each of
+// the resource's path segments
+func (r *Resource) declareResourcePathSegments(def *Group, segments Code) {
+	def.Add(segments).Op(":=").Index().Qual(utils.RestLiPackage, "ResourcePathSegment").
+		ValuesFunc(func(def *Group) {
+			for _, rps := range r.ResourcePathSegments {
+				def.Line().Qual(utils.RestLiPackage, "NewResourcePathSegment").Call(Lit(rps.ResourceName), Lit(rps.PathKey != nil))
+			}
+			def.Line()
+		})
+}
+
 func (r *Resource) addResourcePathStructs(def *Statement, onEntity bool) {
 	var structName string
 	if onEntity {
@@ -181,12 +208,38 @@ func (r *Resource) addResourcePathStructs(def *Statement, onEntity bool) {
 		segments[len(segments)-1].PathKey = r.LastSegment().PathKey
 	}
 
+	r.addResourcePathStruct(def, structName, segments, nil)
+}
+
+// addAltKeyResourcePathStruct declares the resource path struct of the entity methods called with the given
+// alternative key, if any
+func (r *Resource) addAltKeyResourcePathStruct(def *Statement, altKey *AlternativeKey) {
+	for _, m := range r.altKeyMethods() {
+		if m.AltKey == altKey && m.OnEntity {
+			segments := append([]ResourcePathSegment(nil), r.ParentSegments()...)
+			segments = append(segments, ResourcePathSegment{
+				ResourceName: r.LastSegment().ResourceName,
+				PathKey:      altKey.pathKey(),
+			})
+			r.addResourcePathStruct(def, altKey.resourcePathStructType(), segments, nil)
+			return
+		}
+	}
+}
+
+// addResourcePathStruct declares a resource path struct with the given name and segments. assocKey is only set for
+// assocKey finders, in which case the last segment is keyed by the finder's partial association key, held in the
+// AssocKey field.
+func (r *Resource) addResourcePathStruct(def *Statement, structName string, segments []ResourcePathSegment, assocKey *types.AssociationKey) {
 	def.Type().Id(structName).StructFunc(func(def *Group) {
 		for _, rps := range segments {
 			if rps.PathKey != nil {
 				def.Id(rps.PathKey.Name).Add(rps.PathKey.GoType())
 			}
 		}
+		if assocKey != nil {
+			def.Id(AssocKeyField).Op("*").Id(assocKey.TypeName())
+		}
 	}).Line().Line()
 
 	const rp = "rp"
@@ -198,15 +251,20 @@ func (r *Resource) addResourcePathStructs(def *Statement, onEntity bool) {
 		BlockFunc(func(def *Group) {
 			def.Add(types.Writer).Op(":=").Qual(utils.RestLiCodecPackage, "NewRor2PathWriter").Call()
 
-			for _, rps := range segments {
+			for i, rps := range segments {
+				isAssocKey := assocKey != nil && i == len(segments)-1
 				path := "/" + rps.ResourceName
-				if rps.PathKey != nil {
+				if rps.PathKey != nil || isAssocKey {
 					path += "/"
 				}
 				def.Add(types.Writer).Dot("RawPathSegment").Call(Lit(path))
 				if rps.PathKey != nil {
 					def.Add(types.Writer.Write(rps.PathKey.Type, types.Writer, Add(Rp).Dot(rps.PathKey.Name), Lit(""), Err()))
 				}
+				if isAssocKey {
+					def.Err().Op("=").Add(Rp).Dot(AssocKeyField).Dot(utils.MarshalRestLi).Call(types.Writer)
+					def.Add(utils.IfErrReturn(Lit(""), Err()))
+				}
 			}
 
 			def.Line()
@@ -233,11 +291,30 @@ func (r *Resource) addResourcePathStructs(def *Statement, onEntity bool) {
 					def.Add(types.Reader.Read(rps.PathKey.Type, Add(segmentsSlice).Index(Lit(i)), accessor))
 					def.Add(utils.IfErrReturn(Err())).Line()
 				}
+				if assocKey != nil && i == len(segments)-1 {
+					accessor := Add(Rp).Dot(AssocKeyField)
+					def.Add(accessor).Op("=").New(Id(assocKey.TypeName()))
+					def.Err().Op("=").Add(accessor).Dot(utils.UnmarshalRestLi).Call(Add(segmentsSlice).Index(Lit(i)))
+					def.Add(utils.IfErrReturn(Err())).Line()
+				}
 			}
 			def.Line()
 
 			def.Return(Nil())
 		}).Line().Line()
+
+	if assocKey != nil {
+		utils.AddFuncOnReceiver(def, rp, structName, "AssocKeys", types.RecordShouldUsePointer).
+			Params().
+			Index().String().
+			BlockFunc(func(def *Group) {
+				def.Return(Index().String().ValuesFunc(func(def *Group) {
+					for _, k := range assocKey.Keys {
+						def.Lit(k.Name)
+					}
+				}))
+			}).Line().Line()
+	}
 }
 
 func (r *Resource) generateTestCode() *utils.CodeFile {
@@ -250,7 +327,7 @@ func (r *Resource) generateTestCode() *utils.CodeFile {
 	var clientStructFields []Code
 	clientFuncs := Empty()
 
-	for _, m := range r.Methods {
+	for _, m := range r.clientMethods() {
 		clientStructFields = append(clientStructFields,
 			Id(mock+m.FuncName()).Func().Params(methodParams(m, clientContext)...).Params(methodReturnParams(m)...),
 		)
@@ -289,6 +366,10 @@ func (r *Resource) generateTestCode() *utils.CodeFile {
 		Type().Id(resourceStruct).Struct(resourceStructFields...).Line().Line().
 		Add(resourceFuncs)
 
+	if inMemoryResource := r.generateInMemoryResource(resourceStruct); inMemoryResource != nil {
+		clientTest.Code.Line().Line().Add(inMemoryResource)
+	}
+
 	return clientTest
 }
 
@@ -338,6 +419,15 @@ func (r *Resource) createAndReadOnlyFields() Code {
 	}
 }
 
+func (r *Resource) hasBatchFinders() bool {
+	for _, m := range r.Methods {
+		if _, ok := m.(*BatchFinder); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Resource) ParentSegments() []ResourcePathSegment {
 	return r.ResourcePathSegments[:len(r.ResourcePathSegments)-1]
 }
@@ -32,6 +32,15 @@ var batchQueryParamMethods = map[restli.Method]bool{
 	restli.Method_batch_partial_update: true,
 }
 
+// batchSplitters are the functions used to split batch requests that exceed the method's max batch size
+var batchSplitters = map[restli.Method]string{
+	restli.Method_batch_get:            "SplitBatchKeys",
+	restli.Method_batch_delete:         "SplitBatchKeys",
+	restli.Method_batch_update:         "SplitBatchEntities",
+	restli.Method_batch_partial_update: "SplitBatchEntities",
+	restli.Method_batch_create:         "SplitBatchCreate",
+}
+
 var takesReadOnlyFields = map[restli.Method]bool{
 	restli.Method_create:       true,
 	restli.Method_batch_create: true,
@@ -104,22 +113,12 @@ func (r *RestMethod) NonErrorFuncReturnParam() Code {
 		return Add(Entity).Add(r.EntityType())
 	case restli.Method_get_all:
 		return Add(Results).Op("*").Add(r.Resource.LocalType(Elements))
-	case restli.Method_create, restli.Method_batch_create:
-		returns := Op("*")
-		if r.ReturnEntity {
-			returns.Add(r.Resource.LocalType(CreatedAndReturnedEntity))
-		} else {
-			returns.Add(r.Resource.LocalType(CreatedEntity))
-		}
-		if r.restLiMethod() == restli.Method_batch_create {
-			return Id("createdEntities").Index().Add(returns)
-		} else {
-			return Id("createdEntity").Add(returns)
-		}
-	case restli.Method_batch_get:
-		return Add(Results).Op("*").Add(r.Resource.LocalType(BatchEntities))
-	case restli.Method_batch_delete, restli.Method_batch_update, restli.Method_batch_partial_update:
-		return Add(Results).Op("*").Add(r.Resource.LocalType(BatchResponse))
+	case restli.Method_create:
+		return Id("createdEntity").Add(r.createdEntityType())
+	case restli.Method_batch_create:
+		return Id("createdEntities").Add(r.batchReturnType())
+	case restli.Method_batch_get, restli.Method_batch_delete, restli.Method_batch_update, restli.Method_batch_partial_update:
+		return Add(Results).Add(r.batchReturnType())
 	case restli.Method_partial_update:
 		if r.ReturnEntity {
 			return Id("updatedEntity").Add(r.EntityType())
@@ -131,6 +130,27 @@ func (r *RestMethod) NonErrorFuncReturnParam() Code {
 	}
 }
 
+func (r *RestMethod) createdEntityType() Code {
+	if r.ReturnEntity {
+		return Op("*").Add(r.Resource.LocalType(CreatedAndReturnedEntity))
+	} else {
+		return Op("*").Add(r.Resource.LocalType(CreatedEntity))
+	}
+}
+
+func (r *RestMethod) batchReturnType() Code {
+	switch r.restLiMethod() {
+	case restli.Method_batch_create:
+		return Index().Add(r.createdEntityType())
+	case restli.Method_batch_get:
+		return Op("*").Add(r.Resource.LocalType(BatchEntities))
+	case restli.Method_batch_delete, restli.Method_batch_update, restli.Method_batch_partial_update:
+		return Op("*").Add(r.Resource.LocalType(BatchResponse))
+	default:
+		return nil
+	}
+}
+
 func (r *RestMethod) GenericParams() Code {
 	switch r.restLiMethod() {
 	case restli.Method_get, restli.Method_update, restli.Method_get_all:
@@ -173,18 +193,37 @@ func (r *RestMethod) queryParamsStructName() string {
 }
 
 func (r *RestMethod) clientMethodGenerator(def *Group) {
-	params := r.FuncParamNames()
+	r.generateClientMethod(def, r, r.GenericParams(), r.batchReturnType(), func(queryParams Code) Code {
+		if queryParams == nil {
+			return Nil()
+		}
+		return queryParams
+	})
+}
 
+// generateClientMethod generates the body of the client func of m, which is either r itself or one of its AltKeyMethod
+// variants. query returns the query params passed to the underlying restli func, given the method's query params (nil
+// if the method has none).
+func (r *RestMethod) generateClientMethod(
+	def *Group,
+	m MethodImplementation,
+	genericParams, batchReturnType Code,
+	query func(queryParams Code) Code,
+) {
+	params := m.FuncParamNames()
+
+	var queryParams Code
 	if r.hasParams() {
 		var errReturns []Code
-		if r.NonErrorFuncReturnParam() != nil {
+		if m.NonErrorFuncReturnParam() != nil {
 			errReturns = append(errReturns, Nil())
 		}
 		errReturns = append(errReturns, Qual(utils.RestLiPackage, "NilQueryParams"))
 		def.If(Add(QueryParams).Op("==").Nil()).Block(Return(errReturns...))
-	} else {
-		params = append(params, Nil())
+		params = params[:len(params)-1]
+		queryParams = QueryParams
 	}
+	params = append(params, query(queryParams))
 
 	f := r.FuncName()
 	if r.ReturnEntity {
@@ -198,14 +237,26 @@ func (r *RestMethod) clientMethodGenerator(def *Group) {
 	}
 
 	call := Qual(utils.RestLiPackage, f)
-	if p := r.GenericParams(); p != nil {
-		call.Index(p)
+	if genericParams != nil {
+		call.Index(genericParams)
 	}
 
-	declareRpStruct(r, def)
-	def.Return(call.Call(
-		append([]Code{RestLiClientReceiver, Ctx, Rp}, params...)...,
-	))
+	call.Call(append([]Code{RestLiClientReceiver, Ctx, Rp}, params...)...)
+
+	declareRpStruct(m, def)
+	if splitter, ok := batchSplitters[r.restLiMethod()]; ok && r.MaxBatchSize != nil {
+		// The batch is always the first parameter, and is shadowed by each chunk in the closure
+		batch, batchType := m.FuncParamNames()[0], m.FuncParamTypes()[0]
+		def.Return(Qual(utils.RestLiPackage, splitter).Call(
+			RestLiClientReceiver, Ctx, batch, Lit(r.MaxBatchSize.Value),
+			Func().
+				Params(Add(Ctx).Add(Context), Add(batch).Add(batchType)).
+				Params(batchReturnType, Error()).
+				Block(Return(call)),
+		))
+	} else {
+		def.Return(call)
+	}
 }
 
 func (r *RestMethod) RegisterMethod(server, resource, segments Code) Code {
@@ -214,7 +265,7 @@ func (r *RestMethod) RegisterMethod(server, resource, segments Code) Code {
 		name += "WithReturnEntity"
 	}
 
-	return Qual(utils.RestLiPackage, name).CallFunc(func(def *Group) {
+	register := Qual(utils.RestLiPackage, name).CallFunc(func(def *Group) {
 		def.Add(server)
 		def.Add(segments)
 
@@ -229,6 +280,16 @@ func (r *RestMethod) RegisterMethod(server, resource, segments Code) Code {
 				def.Return(resource).Dot(r.FuncName()).Call(splatRpAndParams(r)...)
 			})
 	})
+
+	if r.MaxBatchSize != nil && r.MaxBatchSize.Validate {
+		register.Line().Qual(utils.RestLiPackage, "DeclareMaxBatchSize").Call(
+			server,
+			segments,
+			Qual(utils.RestLiPackage, "Method_"+r.Name),
+			Lit(r.MaxBatchSize.Value),
+		)
+	}
+	return register
 }
 
 // https://linkedin.github.io/rest.li/user_guide/restli_server#resource-methods
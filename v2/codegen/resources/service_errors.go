@@ -0,0 +1,140 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	. "github.com/dave/jennifer/jen"
+	"github.com/iancoleman/strcase"
+)
+
+// serviceErrorName returns the Go name for the given service error code, e.g. INVALID_ID becomes InvalidIdError
+func serviceErrorName(code string) string {
+	return utils.ExportedIdentifier(strcase.ToCamel(strings.ToLower(code))) + "Error"
+}
+
+// hasServiceErrors returns true if the resource, or any of its methods, declares service errors. If so, every method of
+// the resource must declare the service errors it can return, since the server rejects any undeclared ones.
+func (r *Resource) hasServiceErrors() bool {
+	if len(r.ServiceErrors) > 0 {
+		return true
+	}
+	for _, m := range r.Methods {
+		if len(m.GetMethod().ServiceErrors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// allServiceErrors returns all the distinct service errors declared by the resource and its methods
+func (r *Resource) allServiceErrors() (all []ServiceError) {
+	added := make(map[string]bool)
+	add := func(serviceErrors []ServiceError) {
+		for _, e := range serviceErrors {
+			if !added[e.Code] {
+				added[e.Code] = true
+				all = append(all, e)
+			}
+		}
+	}
+
+	add(r.ServiceErrors)
+	for _, m := range r.Methods {
+		add(m.GetMethod().ServiceErrors)
+	}
+	return all
+}
+
+func (r *Resource) generateServiceErrors() *utils.CodeFile {
+	if !r.hasServiceErrors() {
+		return nil
+	}
+
+	c := r.NewCodeFile("service_errors")
+	for _, e := range r.allServiceErrors() {
+		name := serviceErrorName(e.Code)
+		definition := Id(name)
+
+		comment := name + " is the " + e.Code + " service error"
+		if e.Message != "" {
+			comment += ": " + e.Message
+		}
+		utils.AddWordWrappedComment(c.Code, comment).Line()
+		c.Code.Var().Add(definition).Op("=").Qual(utils.RestLiPackage, "ServiceErrorDefinition").Values(DictFunc(func(d Dict) {
+			d[Id("Code")] = Lit(e.Code)
+			d[Id("Status")] = Lit(e.Status)
+			if e.Message != "" {
+				d[Id("Message")] = Lit(e.Message)
+			}
+			if e.ErrorDetailType != nil {
+				d[Id("ErrorDetailType")] = Lit(e.ErrorDetailType.Reference.FullName())
+			}
+		})).Line().Line()
+
+		errParam := Id("err")
+		if e.ErrorDetailType != nil {
+			details := Id("details")
+			detailsType := e.ErrorDetailType.ReferencedType()
+
+			utils.AddWordWrappedComment(c.Code, "New"+name+" returns a new "+e.Code+" service error with the given details").Line()
+			c.Code.Func().Id("New"+name).Params(Add(details).Add(detailsType)).
+				Op("*").Qual(utils.RestLiPackage, "ServiceError").Index(detailsType).
+				Block(Return(Qual(utils.RestLiPackage, "NewServiceError").Call(definition, details))).
+				Line().Line()
+
+			utils.AddWordWrappedComment(c.Code, "Is"+name+" returns the error's details if the given error is the "+e.Code+
+				" service error").Line()
+			c.Code.Func().Id("Is"+name).Params(Add(errParam).Error()).Params(detailsType, Bool()).
+				Block(Return(Qual(utils.RestLiPackage, "IsServiceErrorWithDetails").Index(detailsType).
+					Call(errParam, Add(definition).Dot("Code")))).
+				Line().Line()
+		} else {
+			utils.AddWordWrappedComment(c.Code, "New"+name+" returns a new "+e.Code+" service error").Line()
+			c.Code.Func().Id("New"+name).Params().Op("*").Qual(utils.RestLiCommonPackage, "ErrorResponse").
+				Block(Return(Qual(utils.RestLiPackage, "NewServiceErrorResponse").Call(definition))).
+				Line().Line()
+
+			utils.AddWordWrappedComment(c.Code, "Is"+name+" returns true if the given error is the "+e.Code+" service error").Line()
+			c.Code.Func().Id("Is" + name).Params(Add(errParam).Error()).Bool().
+				Block(Return(Qual(utils.RestLiPackage, "IsServiceError").Call(errParam, Add(definition).Dot("Code")))).
+				Line().Line()
+		}
+	}
+
+	return c
+}
+
+// declareServiceErrors declares the service errors the given method can return, which are the errors declared on the
+// method itself and the ones declared on the resource
+func (r *Resource) declareServiceErrors(m MethodImplementation, server, segments Code) Code {
+	if !r.hasServiceErrors() {
+		return nil
+	}
+
+	var method, name Code
+	switch m.(type) {
+	case *Finder:
+		method, name = Qual(utils.RestLiPackage, "Method_finder"), Lit(m.GetMethod().Name)
+	case *BatchFinder:
+		method, name = Qual(utils.RestLiPackage, "Method_batch_finder"), Lit(m.GetMethod().Name)
+	case *Action:
+		method, name = Qual(utils.RestLiPackage, "Method_action"), Lit(m.GetMethod().Name)
+	default:
+		method, name = Qual(utils.RestLiPackage, "Method_"+m.GetMethod().Name), Lit("")
+	}
+
+	return Qual(utils.RestLiPackage, "DeclareServiceErrors").CallFunc(func(def *Group) {
+		def.Add(server)
+		def.Add(segments)
+		def.Add(method)
+		def.Add(name)
+		added := make(map[string]bool)
+		for _, e := range append(append([]ServiceError(nil), r.ServiceErrors...), m.GetMethod().ServiceErrors...) {
+			if !added[e.Code] {
+				added[e.Code] = true
+				def.Id(serviceErrorName(e.Code)).Dot("Code")
+			}
+		}
+	})
+}
@@ -0,0 +1,162 @@
+// Package samples generates sample values of the data types declared by a go-restli manifest, which are used as
+// examples in generated documentation.
+package samples
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/types"
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+// Samples generates sample values of the given types. Fields with default values use their default value, all other
+// values are arbitrary. Values are represented the same way they are in a restlidata.RawRecord, i.e. records, maps
+// and unions are map[string]any, arrays are []any, and enums and fixed types are strings.
+type Samples struct {
+	types map[utils.Identifier]utils.ComplexType
+}
+
+// New returns a new Samples for the given types, typically the result of cmd.GoRestliManifest.ComplexTypes
+func New(types map[utils.Identifier]utils.ComplexType) *Samples {
+	return &Samples{types: types}
+}
+
+// Value returns a sample value of the given type
+func (s *Samples) Value(t *types.RestliType) any {
+	return s.value(t, map[utils.Identifier]bool{})
+}
+
+// Named returns a sample value of the given named type. The sample of a complex key is the sample of its key record.
+func (s *Samples) Named(id utils.Identifier) any {
+	return s.named(id, map[utils.Identifier]bool{})
+}
+
+func (s *Samples) value(t *types.RestliType, visiting map[utils.Identifier]bool) any {
+	switch {
+	case t.Primitive != nil:
+		return primitive(t.Primitive)
+	case t.Array != nil:
+		return []any{s.value(t.Array, visiting)}
+	case t.Map != nil:
+		return map[string]any{"key": s.value(t.Map, visiting)}
+	case t.Reference != nil && !t.RawRecord:
+		return s.named(*t.Reference, visiting)
+	default:
+		return map[string]any{}
+	}
+}
+
+func (s *Samples) named(id utils.Identifier, visiting map[utils.Identifier]bool) any {
+	visiting[id] = true
+	defer delete(visiting, id)
+
+	switch t := s.types[id].(type) {
+	case *types.Record:
+		sample := map[string]any{}
+		for _, include := range t.Includes {
+			if included, ok := s.named(include, visiting).(map[string]any); ok {
+				for k, v := range included {
+					sample[k] = v
+				}
+			}
+		}
+		for _, f := range t.Fields {
+			var defaultValue any
+			switch {
+			case f.DefaultValue != nil && json.Unmarshal([]byte(*f.DefaultValue), &defaultValue) == nil:
+				sample[f.Name] = defaultValue
+			case f.IsOptional && f.Type.Reference != nil && visiting[*f.Type.Reference]:
+				// Skip optional fields of cyclic records
+			default:
+				sample[f.Name] = s.value(&f.Type, visiting)
+			}
+		}
+		return sample
+	case *types.ComplexKey:
+		return s.named(t.Key, visiting)
+	case *types.AssociationKey:
+		return s.assocKeys(t.Keys, visiting)
+	case *types.Enum:
+		if len(t.Symbols) > 0 {
+			return t.Symbols[0]
+		}
+		return ""
+	case *types.Fixed:
+		return strings.Repeat("a", t.Size)
+	case *types.Typeref:
+		return primitive(t.Type)
+	case *types.StandaloneUnion:
+		if len(t.Union.Members) == 0 {
+			return nil
+		}
+		m := t.Union.Members[0]
+		return map[string]any{m.Alias: s.value(&m.Type, visiting)}
+	default:
+		return map[string]any{}
+	}
+}
+
+// AssocKeys returns a sample association key that only has the given key parts, such as the partial keys of assocKey
+// finders
+func (s *Samples) AssocKeys(keys []types.AssocKey) map[string]any {
+	return s.assocKeys(keys, map[utils.Identifier]bool{})
+}
+
+func (s *Samples) assocKeys(keys []types.AssocKey, visiting map[utils.Identifier]bool) map[string]any {
+	sample := map[string]any{}
+	for i := range keys {
+		sample[keys[i].Name] = s.value(&keys[i].Type, visiting)
+	}
+	return sample
+}
+
+func primitive(p *types.PrimitiveType) any {
+	switch p.PegasusType() {
+	case "int", "long":
+		return 1
+	case "float", "double":
+		return 1.5
+	case "boolean":
+		return true
+	case "bytes":
+		return "bytes"
+	default:
+		return "string"
+	}
+}
+
+// ROR2 encodes the given sample value as it appears in rest.li URLs
+func ROR2(v any) string {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]string, len(keys))
+		for i, k := range keys {
+			fields[i] = ROR2(k) + ":" + ROR2(v[k])
+		}
+		return "(" + strings.Join(fields, ",") + ")"
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = ROR2(item)
+		}
+		return "List(" + strings.Join(items, ",") + ")"
+	case string:
+		if v == "" {
+			return "''"
+		}
+		return restlicodec.Ror2PathEscape(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
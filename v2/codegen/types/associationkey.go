@@ -0,0 +1,74 @@
+package types
+
+import (
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	. "github.com/dave/jennifer/jen"
+)
+
+// AssociationKey is the compound key of an association resource. It is represented as a record whose fields are the
+// association's key parts, all of which are required. This means it is encoded as (key1:a,key2:b) in both paths and
+// query parameters.
+type AssociationKey struct {
+	NamedType
+	Keys []AssocKey `json:"keys"`
+}
+
+type AssocKey struct {
+	Name string     `json:"name"`
+	Type RestliType `json:"type"`
+}
+
+func (ak *AssociationKey) ReferencedTypes() utils.IdentifierSet {
+	innerTypes := utils.IdentifierSet{}
+	for _, k := range ak.Keys {
+		innerTypes.AddAll(k.Type.InnerTypes())
+	}
+	return innerTypes
+}
+
+func (ak *AssociationKey) ShouldReference() utils.ShouldUsePointer {
+	return RecordShouldUsePointer
+}
+
+// Record returns the equivalent record for this association key, which is used to generate its code
+func (ak *AssociationKey) Record() *Record {
+	record := &Record{NamedType: ak.NamedType}
+	for _, k := range ak.Keys {
+		record.Fields = append(record.Fields, Field{
+			Name: k.Name,
+			Type: k.Type,
+		})
+	}
+	return record
+}
+
+// Subset returns a new association key with the given identifier that only contains the given key parts, in the
+// original declaration order. It is used to generate the keys of assocKey finders.
+func (ak *AssociationKey) Subset(id utils.Identifier, doc string, names []string) *AssociationKey {
+	subset := &AssociationKey{
+		NamedType: NamedType{
+			Identifier: id,
+			SourceFile: ak.SourceFile,
+			Doc:        doc,
+		},
+	}
+	for _, k := range ak.Keys {
+		for _, n := range names {
+			if k.Name == n {
+				subset.Keys = append(subset.Keys, k)
+				break
+			}
+		}
+	}
+	return subset
+}
+
+func (ak *AssociationKey) GenerateCode() *Statement {
+	record := ak.Record()
+	return Empty().
+		Add(record.GenerateStruct()).Line().Line().
+		Add(record.GenerateEquals()).Line().Line().
+		Add(record.GenerateComputeHash()).Line().Line().
+		Add(record.GenerateMarshalRestLi()).Line().Line().
+		Add(record.GenerateUnmarshalRestLi()).Line().Line()
+}
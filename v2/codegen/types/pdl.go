@@ -0,0 +1,312 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+)
+
+// pegasusPrimitives maps the go-restli primitive types to their pegasus names
+var pegasusPrimitives = map[string]string{
+	Int32Primitive.Type:   "int",
+	Int64Primitive.Type:   "long",
+	Float32Primitive.Type: "float",
+	Float64Primitive.Type: "double",
+	BoolPrimitive.Type:    "boolean",
+	StringPrimitive.Type:  "string",
+	BytesPrimitive.Type:   "bytes",
+}
+
+// PegasusType returns the name of this primitive in pegasus schemas, e.g. "long" for int64
+func (p *PrimitiveType) PegasusType() string {
+	return pegasusPrimitives[p.Type]
+}
+
+func (t *RestliType) namedType() utils.Identifier {
+	if t.RawRecord {
+		return utils.RawRecordIdentifier
+	}
+	return *t.Reference
+}
+
+// RestSpecType returns this type as it is represented in a restspec: the name of the primitive or the fully qualified
+// name of the named type, or the JSON-encoded schema of arrays and maps, e.g. {"type":"array","items":"string"}.
+func (t *RestliType) RestSpecType() string {
+	s := t.jsonSchema()
+	if name, ok := s.(string); ok {
+		return name
+	}
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func (t *RestliType) jsonSchema() any {
+	switch {
+	case t.Primitive != nil:
+		return t.Primitive.PegasusType()
+	case t.Array != nil:
+		return map[string]any{"type": "array", "items": t.Array.jsonSchema()}
+	case t.Map != nil:
+		return map[string]any{"type": "map", "values": t.Map.jsonSchema()}
+	default:
+		return t.namedType().FullName()
+	}
+}
+
+// unionMemberKey returns the key of this type when it is an unaliased union member: the fully qualified name of named
+// types or the name of the primitive, or "array" and "map" for arrays and maps
+func (t *RestliType) unionMemberKey() string {
+	switch {
+	case t.Array != nil:
+		return "array"
+	case t.Map != nil:
+		return "map"
+	default:
+		return t.RestSpecType()
+	}
+}
+
+// isAliased returns true if any of the members of this union is aliased, i.e. if its alias is not its unionMemberKey.
+// Since pegasus does not allow aliased and unaliased members to be mixed, either all the members of a union are aliased
+// or none of them are.
+func (u *UnionType) isAliased() bool {
+	for _, m := range u.Members {
+		if m.Alias != m.Type.unionMemberKey() {
+			return true
+		}
+	}
+	return false
+}
+
+// PDL returns this type as it is written in a .pdl file in the given namespace
+func (t *RestliType) PDL(namespace string) string {
+	switch {
+	case t.Primitive != nil:
+		return t.Primitive.PegasusType()
+	case t.Array != nil:
+		return "array[" + t.Array.PDL(namespace) + "]"
+	case t.Map != nil:
+		return "map[string, " + t.Map.PDL(namespace) + "]"
+	default:
+		id := t.namedType()
+		if id.Namespace == namespace {
+			return id.Name
+		}
+		return id.FullName()
+	}
+}
+
+// PDL returns the contents of the .pdl file that declares the given type. Since all unions are standalone unions,
+// unions declared inline in the original schema are declared as typerefs to the union instead, which does not change
+// their wire representation. Complex keys are not pegasus types, and their key and params records should be used
+// instead. Association keys are not pegasus types either, and are skipped.
+func PDL(t utils.ComplexType) (string, bool) {
+	id := t.GetIdentifier()
+	ns := id.Namespace
+
+	pdl := new(strings.Builder)
+	if ns != "" {
+		fmt.Fprintf(pdl, "namespace %s\n\n", ns)
+	}
+
+	switch t := t.(type) {
+	case *Record:
+		writePdlDoc(pdl, "", t.Doc)
+		fmt.Fprintf(pdl, "record %s ", id.Name)
+		if len(t.Includes) > 0 {
+			pdl.WriteString("includes ")
+			for i := range t.Includes {
+				if i > 0 {
+					pdl.WriteString(", ")
+				}
+				pdl.WriteString((&RestliType{Reference: &t.Includes[i]}).PDL(ns))
+			}
+			pdl.WriteString(" ")
+		}
+		pdl.WriteString("{\n")
+		for _, f := range t.Fields {
+			writePdlDoc(pdl, "  ", f.Doc)
+			writePdlValidators(pdl, "  ", f.Validators)
+			fmt.Fprintf(pdl, "  %s: ", f.Name)
+			if f.IsOptional {
+				pdl.WriteString("optional ")
+			}
+			pdl.WriteString(f.Type.PDL(ns))
+			if f.DefaultValue != nil {
+				pdl.WriteString(" = " + *f.DefaultValue)
+			}
+			pdl.WriteString("\n")
+		}
+		pdl.WriteString("}\n")
+	case *Enum:
+		writePdlDoc(pdl, "", t.Doc)
+		fmt.Fprintf(pdl, "enum %s {\n", id.Name)
+		for _, s := range t.Symbols {
+			writePdlDoc(pdl, "  ", t.SymbolToDoc[s])
+			fmt.Fprintf(pdl, "  %s\n", s)
+		}
+		pdl.WriteString("}\n")
+	case *Fixed:
+		writePdlDoc(pdl, "", t.Doc)
+		fmt.Fprintf(pdl, "fixed %s %d\n", id.Name, t.Size)
+	case *Typeref:
+		writePdlDoc(pdl, "", t.Doc)
+		fmt.Fprintf(pdl, "typeref %s = %s\n", id.Name, t.Type.PegasusType())
+	case *StandaloneUnion:
+		writePdlDoc(pdl, "", t.Doc)
+		fmt.Fprintf(pdl, "typeref %s = union[", id.Name)
+		var members []string
+		if t.Union.HasNull {
+			members = append(members, "null")
+		}
+		aliased := t.Union.isAliased()
+		for _, m := range t.Union.Members {
+			member := m.Type.PDL(ns)
+			if aliased {
+				member = m.Alias + ": " + member
+			}
+			members = append(members, member)
+		}
+		pdl.WriteString(strings.Join(members, ", "))
+		pdl.WriteString("]\n")
+	default:
+		return "", false
+	}
+
+	return pdl.String(), true
+}
+
+func writePdlDoc(pdl *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(pdl, "%s/** %s */\n", indent, doc)
+		return
+	}
+	fmt.Fprintf(pdl, "%s/**\n", indent)
+	for _, l := range lines {
+		fmt.Fprintln(pdl, strings.TrimRight(indent+" * "+l, " "))
+	}
+	fmt.Fprintf(pdl, "%s */\n", indent)
+}
+
+// writePdlValidators writes the given @validate annotations in order of validator name, e.g.
+// @validate.strlen = {"min":1}
+func writePdlValidators(pdl *strings.Builder, indent string, validators map[string]json.RawMessage) {
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		config := new(bytes.Buffer)
+		if err := json.Compact(config, validators[name]); err != nil {
+			config.Reset()
+			config.Write(validators[name])
+		}
+		fmt.Fprintf(pdl, "%s@validate.%s = %s\n", indent, name, config)
+	}
+}
+
+// JSONSchema returns the JSON (.pdsc) schema of the given type, as returned in the models of rest.li OPTIONS
+// responses. Like PDL, complex keys and association keys are not pegasus types and are skipped.
+func JSONSchema(t utils.ComplexType) (string, bool) {
+	id := t.GetIdentifier()
+	schema := map[string]any{
+		"name": id.Name,
+	}
+	if id.Namespace != "" {
+		schema["namespace"] = id.Namespace
+	}
+	setDoc := func(doc string) {
+		if doc != "" {
+			schema["doc"] = doc
+		}
+	}
+
+	switch t := t.(type) {
+	case *Record:
+		schema["type"] = "record"
+		setDoc(t.Doc)
+		if len(t.Includes) > 0 {
+			var includes []string
+			for _, i := range t.Includes {
+				includes = append(includes, i.FullName())
+			}
+			schema["include"] = includes
+		}
+		fields := []map[string]any{}
+		for _, f := range t.Fields {
+			field := map[string]any{
+				"name": f.Name,
+				"type": f.Type.jsonSchema(),
+			}
+			if f.Doc != "" {
+				field["doc"] = f.Doc
+			}
+			if f.IsOptional {
+				field["optional"] = true
+			}
+			if f.DefaultValue != nil {
+				field["default"] = json.RawMessage(*f.DefaultValue)
+			}
+			if len(f.Validators) > 0 {
+				field["validate"] = f.Validators
+			}
+			fields = append(fields, field)
+		}
+		schema["fields"] = fields
+	case *Enum:
+		schema["type"] = "enum"
+		setDoc(t.Doc)
+		schema["symbols"] = t.Symbols
+		symbolDocs := map[string]string{}
+		for s, doc := range t.SymbolToDoc {
+			if doc != "" {
+				symbolDocs[s] = doc
+			}
+		}
+		if len(symbolDocs) > 0 {
+			schema["symbolDocs"] = symbolDocs
+		}
+	case *Fixed:
+		schema["type"] = "fixed"
+		setDoc(t.Doc)
+		schema["size"] = t.Size
+	case *Typeref:
+		schema["type"] = "typeref"
+		setDoc(t.Doc)
+		schema["ref"] = t.Type.PegasusType()
+	case *StandaloneUnion:
+		schema["type"] = "typeref"
+		setDoc(t.Doc)
+		members := []any{}
+		if t.Union.HasNull {
+			members = append(members, "null")
+		}
+		aliased := t.Union.isAliased()
+		for _, m := range t.Union.Members {
+			var member any = m.Type.jsonSchema()
+			if aliased {
+				member = map[string]any{"alias": m.Alias, "type": member}
+			}
+			members = append(members, member)
+		}
+		schema["ref"] = members
+	default:
+		return "", false
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUnion(members ...UnionMember) *StandaloneUnion {
+	return &StandaloneUnion{
+		NamedType: NamedType{Identifier: utils.Identifier{Name: "TestUnion", Namespace: "com.example"}},
+		Union:     UnionType{Members: members},
+	}
+}
+
+var (
+	testRecordIdentifier = utils.Identifier{Name: "Record", Namespace: "com.example"}
+	testOtherIdentifier  = utils.Identifier{Name: "Other", Namespace: "com.example.other"}
+)
+
+func TestUnionPDL(t *testing.T) {
+	t.Run("unaliased", func(t *testing.T) {
+		pdl, ok := PDL(newTestUnion(
+			UnionMember{Type: RestliType{Primitive: &StringPrimitive}, Alias: "string"},
+			UnionMember{Type: RestliType{Reference: &testOtherIdentifier}, Alias: testOtherIdentifier.FullName()},
+		))
+		require.True(t, ok)
+		require.Equal(t, "namespace com.example\n\ntyperef TestUnion = union[string, com.example.other.Other]\n", pdl)
+	})
+
+	t.Run("aliased", func(t *testing.T) {
+		// The "string" member's alias is the same as its unaliased key, but it must still be aliased since the other
+		// member is
+		pdl, ok := PDL(newTestUnion(
+			UnionMember{Type: RestliType{Primitive: &StringPrimitive}, Alias: "string"},
+			UnionMember{Type: RestliType{Reference: &testRecordIdentifier}, Alias: "record"},
+		))
+		require.True(t, ok)
+		require.Equal(t, "namespace com.example\n\ntyperef TestUnion = union[string: string, record: Record]\n", pdl)
+	})
+}
+
+func TestUnionJSONSchema(t *testing.T) {
+	schema, ok := JSONSchema(newTestUnion(
+		UnionMember{Type: RestliType{Primitive: &StringPrimitive}, Alias: "string"},
+		UnionMember{Type: RestliType{Reference: &testOtherIdentifier}, Alias: testOtherIdentifier.FullName()},
+	))
+	require.True(t, ok)
+	require.JSONEq(t, `{
+		"name": "TestUnion",
+		"namespace": "com.example",
+		"type": "typeref",
+		"ref": ["string", "com.example.other.Other"]
+	}`, schema)
+
+	schema, ok = JSONSchema(newTestUnion(
+		UnionMember{Type: RestliType{Primitive: &StringPrimitive}, Alias: "string"},
+		UnionMember{Type: RestliType{Reference: &testRecordIdentifier}, Alias: "record"},
+	))
+	require.True(t, ok)
+	require.JSONEq(t, `{
+		"name": "TestUnion",
+		"namespace": "com.example",
+		"type": "typeref",
+		"ref": [
+			{"alias": "string", "type": "string"},
+			{"alias": "record", "type": "com.example.Record"}
+		]
+	}`, schema)
+}
+
+func newTestValidatedRecord() *Record {
+	return &Record{
+		NamedType: NamedType{Identifier: testRecordIdentifier},
+		Fields: []Field{
+			{
+				Name: "name",
+				Doc:  "The name",
+				Type: RestliType{Primitive: &StringPrimitive},
+				Validators: map[string]json.RawMessage{
+					"strlen": json.RawMessage(`{"min": 1, "max": 10}`),
+					"regex":  json.RawMessage(`{"regex": "^[a-z]+$"}`),
+				},
+			},
+			{Name: "count", Type: RestliType{Primitive: &Int32Primitive}, IsOptional: true},
+		},
+	}
+}
+
+func TestRecordValidatorsPDL(t *testing.T) {
+	pdl, ok := PDL(newTestValidatedRecord())
+	require.True(t, ok)
+	require.Equal(t, `namespace com.example
+
+record Record {
+  /** The name */
+  @validate.regex = {"regex":"^[a-z]+$"}
+  @validate.strlen = {"min":1,"max":10}
+  name: string
+  count: optional int
+}
+`, pdl)
+}
+
+func TestRecordValidatorsJSONSchema(t *testing.T) {
+	schema, ok := JSONSchema(newTestValidatedRecord())
+	require.True(t, ok)
+	require.JSONEq(t, `{
+		"name": "Record",
+		"namespace": "com.example",
+		"type": "record",
+		"fields": [
+			{
+				"name": "name",
+				"type": "string",
+				"doc": "The name",
+				"validate": {"strlen": {"min": 1, "max": 10}, "regex": {"regex": "^[a-z]+$"}}
+			},
+			{"name": "count", "type": "int", "optional": true}
+		]
+	}`, schema)
+}
@@ -62,11 +62,13 @@ func (r *Record) PartialUpdateStruct() *Statement {
 }
 
 type Field struct {
-	Name               string     `json:"name"`
-	Doc                string     `json:"doc"`
-	Type               RestliType `json:"type"`
-	IsOptional         bool       `json:"isOptional"`
-	DefaultValue       *string    `json:"defaultValue,omitempty"`
+	Name         string     `json:"name"`
+	Doc          string     `json:"doc"`
+	Type         RestliType `json:"type"`
+	IsOptional   bool       `json:"isOptional"`
+	DefaultValue *string    `json:"defaultValue,omitempty"`
+	// Validators are the field's @validate annotations, indexed by validator name
+	Validators         map[string]json.RawMessage `json:"validators,omitempty"`
 	isComplexKeyParams bool
 }
 
@@ -110,6 +112,7 @@ func (r *Record) GenerateCode() *Statement {
 		Add(r.GeneratePopulateDefaultValues()).Line().Line().
 		Add(r.GenerateEquals()).Line().Line().
 		Add(r.GenerateComputeHash()).Line().Line().
+		Add(r.GenerateValidate()).Line().Line().
 		Add(r.GenerateMarshalRestLi()).Line().Line().
 		Add(r.GenerateUnmarshalRestLi()).Line().Line().
 		Add(r.generatePartialUpdateStruct()).Line()
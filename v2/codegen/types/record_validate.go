@@ -0,0 +1,240 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/PapaCharlie/go-restli/v2/codegen/utils"
+	. "github.com/dave/jennifer/jen"
+)
+
+const (
+	collector = "collector"
+	item      = "item"
+)
+
+func AddValidate(def *Statement, receiver, typeName string, pointer utils.ShouldUsePointer, f func(c Code, def *Group)) *Statement {
+	c := Id(collector)
+
+	def.Commentf("%s returns a *validation.Error listing all the violations of the constraints declared by the @validate "+
+		"annotations of %s's fields, or nil if there are none.", utils.Validate, typeName).Line()
+	utils.AddFuncOnReceiver(def, receiver, typeName, utils.Validate, pointer).
+		Params().Error().
+		Block(Return(validationFunc("Validate").Call(Id(receiver)))).
+		Line().Line()
+
+	return utils.AddFuncOnReceiver(def, receiver, typeName, utils.CollectViolations, pointer).
+		Params(Add(c).Op("*").Qual(utils.ValidationPackage, "Collector")).
+		BlockFunc(func(def *Group) {
+			f(c, def)
+		}).Line().Line()
+}
+
+func (r *Record) GenerateValidate() Code {
+	return AddValidate(Empty(), r.Receiver(), r.TypeName(), RecordShouldUsePointer, func(c Code, def *Group) {
+		for _, i := range r.Includes {
+			if i.IsEmptyRecord() {
+				continue
+			}
+			def.Add(validationFunc("Nested").Call(c, Lit(""), Op("&").Id(r.Receiver()).Dot(i.TypeName())))
+		}
+
+		for _, f := range r.Fields {
+			validateField(def, c, r.Identifier, f.Name, f.Type, f.IsOptionalOrDefault(), r.fieldAccessor(f), f.Validators)
+		}
+	})
+}
+
+// validateField adds the validation of the given field, which is the validation declared by its @validate annotations,
+// followed by the validation of any record or union it contains. Optional fields are only validated if present.
+func validateField(def *Group, c Code, id utils.Identifier, name string, t RestliType, isPointer bool, accessor Code, validators map[string]json.RawMessage) {
+	var value, reference Code
+	switch {
+	case t.Reference != nil && t.ShouldReference():
+		value = accessor
+		if !isPointer {
+			value = Op("&").Add(accessor)
+		}
+		reference = value
+	case isPointer:
+		value = Op("*").Add(accessor)
+	default:
+		value = accessor
+	}
+
+	var statements []Code
+	for _, v := range sortedValidators(validators) {
+		statements = append(statements, annotationValidation(c, id, name, t, value, v.name, v.config)...)
+	}
+	if needsNestedValidation(t) {
+		if reference == nil {
+			reference = value
+		}
+		statements = append(statements, nestedValidation(c, Lit(name), t, reference))
+	}
+
+	if len(statements) == 0 {
+		return
+	}
+	if isPointer {
+		def.If(Add(accessor).Op("!=").Nil()).Block(statements...)
+	} else {
+		for _, s := range statements {
+			def.Add(s)
+		}
+	}
+}
+
+type validator struct {
+	name   string
+	config json.RawMessage
+}
+
+func sortedValidators(validators map[string]json.RawMessage) (sorted []validator) {
+	for name, config := range validators {
+		sorted = append(sorted, validator{name: name, config: config})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	return sorted
+}
+
+// validationPrimitive returns the primitive type the built-in validators apply to, i.e. the type of primitive fields
+// and the underlying type of non-custom typerefs
+func validationPrimitive(t RestliType) *PrimitiveType {
+	if t.Primitive != nil {
+		return t.Primitive
+	}
+	if typeref := t.Typeref(); typeref != nil && !typeref.IsCustomTyperef() {
+		return typeref.Type
+	}
+	return nil
+}
+
+// annotationValidation returns the statements that implement the given @validate annotation. The built-in strlen,
+// regex and range validators are implemented by the validation package, and are ignored on fields they do not apply
+// to (as they are in Java). All other validators are custom validators, which must be registered at runtime.
+func annotationValidation(c Code, id utils.Identifier, name string, t RestliType, value Code, validatorName string, config json.RawMessage) []Code {
+	field := Lit(name)
+	custom := func() []Code {
+		compact := new(bytes.Buffer)
+		if err := json.Compact(compact, config); err != nil {
+			log.Panicf("go-restli: Illegal @validate.%s on %s.%s: %+v", validatorName, id.FullName(), name, err)
+		}
+		return []Code{validationFunc("Custom").Call(c, field, Lit(validatorName), value, Lit(compact.String()))}
+	}
+
+	p := validationPrimitive(t)
+	isString := p != nil && p.Type == StringPrimitive.Type
+	isNumber := false
+	if p != nil {
+		switch p.Type {
+		case Int32Primitive.Type, Int64Primitive.Type, Float32Primitive.Type, Float64Primitive.Type:
+			isNumber = true
+		}
+	}
+
+	switch validatorName {
+	case "strlen":
+		if !isString {
+			return nil
+		}
+		var strlen struct {
+			Min *int `json:"min"`
+			Max *int `json:"max"`
+		}
+		if err := json.Unmarshal(config, &strlen); err != nil {
+			log.Panicf("go-restli: Illegal @validate.strlen on %s.%s: %+v", id.FullName(), name, err)
+		}
+		min, max := 0, -1
+		if strlen.Min != nil {
+			min = *strlen.Min
+		}
+		if strlen.Max != nil {
+			max = *strlen.Max
+		}
+		return []Code{validationFunc("Strlen").Call(c, field, value, Lit(min), Lit(max))}
+	case "regex":
+		if !isString {
+			return nil
+		}
+		var regex struct {
+			Regex string `json:"regex"`
+		}
+		if err := json.Unmarshal(config, &regex); err != nil {
+			log.Panicf("go-restli: Illegal @validate.regex on %s.%s: %+v", id.FullName(), name, err)
+		}
+		if _, err := regexp.Compile(regex.Regex); err != nil {
+			// Java supports some constructs RE2 does not (such as lookarounds). Such patterns can still be enforced
+			// by registering a custom validator named "regex".
+			log.Printf("WARNING: @validate.regex pattern %q on %s.%s is not supported (%s), it will be treated as a "+
+				"custom validator", regex.Regex, id.FullName(), name, err)
+			return custom()
+		}
+		return []Code{validationFunc("Regex").Call(c, field, value, Lit(regex.Regex))}
+	case "integerRange", "longRange", "floatRange", "doubleRange":
+		if !isNumber {
+			return nil
+		}
+		var bounds struct {
+			Min *json.Number `json:"min"`
+			Max *json.Number `json:"max"`
+		}
+		if err := json.Unmarshal(config, &bounds); err != nil {
+			log.Panicf("go-restli: Illegal @validate.%s on %s.%s: %+v", validatorName, id.FullName(), name, err)
+		}
+		if t.Primitive == nil {
+			// Convert typerefs to their underlying type to match the type of the bound
+			value = p.Cast(Add(value))
+		}
+		var statements []Code
+		for _, b := range []struct {
+			f     string
+			bound *json.Number
+		}{{"Min", bounds.Min}, {"Max", bounds.Max}} {
+			if b.bound != nil {
+				statements = append(statements, validationFunc(b.f).Call(c, field, value, p.getLit(b.bound.String())))
+			}
+		}
+		return statements
+	default:
+		return custom()
+	}
+}
+
+// needsNestedValidation returns true if the given type is or contains a record or a union
+func needsNestedValidation(t RestliType) bool {
+	switch {
+	case t.Array != nil:
+		return needsNestedValidation(*t.Array)
+	case t.Map != nil:
+		return needsNestedValidation(*t.Map)
+	case t.Reference != nil && !t.RawRecord:
+		return t.Record() != nil || t.StandaloneUnion() != nil
+	default:
+		return false
+	}
+}
+
+// nestedValidation returns the statement that validates the records and unions contained in the given value, which
+// is a pointer for records and unions
+func nestedValidation(c Code, field Code, t RestliType, value Code) Code {
+	if t.IsMapOrArray() {
+		innerT, word := t.InnerMapOrArray()
+		if word == "Array" {
+			word = "Slice"
+		}
+		return validationFunc(word).Call(c, field, value,
+			Func().
+				Params(Add(c).Op("*").Qual(utils.ValidationPackage, "Collector"), Id(item).Add(innerT.ReferencedType())).
+				Block(nestedValidation(c, Lit(""), innerT, Id(item))),
+		)
+	}
+	return validationFunc("Nested").Call(c, field, value)
+}
+
+func validationFunc(name string) *Statement {
+	return Qual(utils.ValidationPackage, name)
+}
@@ -114,6 +114,15 @@ func (t *RestliType) ComplexKey() *ComplexKey {
 	return complexKey
 }
 
+func (t *RestliType) AssociationKey() *AssociationKey {
+	if t.Reference == nil {
+		return nil
+	}
+
+	associationKey, _ := t.Reference.Resolve().(*AssociationKey)
+	return associationKey
+}
+
 func (t *RestliType) StandaloneUnion() *StandaloneUnion {
 	if t.Reference == nil {
 		return nil
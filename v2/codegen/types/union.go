@@ -45,6 +45,12 @@ func (u *StandaloneUnion) GenerateCode() *Statement {
 		}
 	})
 
+	AddValidate(def, unionReceiver, u.TypeName(), UnionShouldUsePointer, func(c Code, def *Group) {
+		for _, m := range u.Union.Members {
+			validateField(def, c, u.Identifier, m.Alias, m.Type, true, Id(unionReceiver).Dot(m.name()), nil)
+		}
+	})
+
 	utils.AddFuncOnReceiver(def, unionReceiver, u.TypeName(), utils.ValidateUnionFields, UnionShouldUsePointer).
 		Params().
 		Params(Error()).
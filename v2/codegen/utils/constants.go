@@ -12,6 +12,8 @@ const (
 	Equals                     = "Equals"
 	ComputeHash                = "ComputeHash"
 	ValidateUnionFields        = "ValidateUnionFields"
+	Validate                   = "Validate"
+	CollectViolations          = "CollectViolations"
 	ComplexKeyParamsField      = "Params"
 	ComplexKeyParams           = "$params"
 	FinderNameParam            = "q"
@@ -25,6 +27,8 @@ const (
 	RestLiCommonPackage = RestLiDataPackage + "/generated/com/linkedin/restli/common"
 	BatchKeySetPackage  = RestLiPackage + "/batchkeyset"
 	EqualsPackage       = RestLiPackage + "/equals"
+	ValidationPackage   = RestLiPackage + "/validation"
+	InMemoryPackage     = RestLiPackage + "/inmemory"
 )
 
 var (
@@ -21,6 +21,9 @@ type Client struct {
 	// A positive value will be used as-is, a 0 value will default to DefaultInitialUriWatchTimeout and a negative value
 	// disables the timeout altogether
 	InitialZkWatchTimeout time.Duration
+	// If non-nil, the Observer will be notified of every host chosen by the Client, and of every change to the hosts
+	// of the clusters it watches.
+	Observer Observer
 
 	// TODO: Support sslSessionValidationStrings. This can be done by opening a connection to the host, pulling its cert
 	//  and calling this function. This should happen when the update is received from D2 so that hosts are only added
@@ -176,6 +179,7 @@ func (c *Client) handleUriUpdate(watcher *serviceUris, event TreeCacheEvent) *se
 	if event.Data == nil {
 		watcher = watcher.copy()
 		delete(watcher.uris, path)
+		c.observeHostSetChanged(watcher)
 		return watcher
 	}
 
@@ -195,6 +199,7 @@ func (c *Client) handleUriUpdate(watcher *serviceUris, event TreeCacheEvent) *se
 
 	watcher = watcher.copy()
 	watcher.uris[path] = uri
+	c.observeHostSetChanged(watcher)
 	return watcher
 }
 
@@ -217,6 +222,20 @@ func (c *SingleServiceClient) ResolveHostnameAndContextForQuery(_ string, query
 	return c.c.ResolveHostnameAndContextForQuery(c.serviceName, query)
 }
 
+func (c *SingleServiceClient) ResolveProtocolVersion(string) (string, error) {
+	return c.c.ResolveProtocolVersion(c.serviceName)
+}
+
+// ResolveProtocolVersion implements restli.ProtocolVersionResolver by returning the rest.li protocol version announced by
+// the given service (see Service.RestLiProtocolVersion).
+func (c *Client) ResolveProtocolVersion(rootResource string) (string, error) {
+	service, _, err := c.getServiceUris(rootResource)
+	if err != nil {
+		return "", err
+	}
+	return service.RestLiProtocolVersion(), nil
+}
+
 func (c *Client) ResolveHostnameAndContextForQuery(rootResource string, _ *url.URL) (*url.URL, error) {
 	service, uris, err := c.getServiceUris(rootResource)
 	if err != nil {
@@ -227,5 +246,6 @@ func (c *Client) ResolveHostnameAndContextForQuery(rootResource string, _ *url.U
 	if chosenHost == nil {
 		return nil, errors.Errorf("Could not find a host for %q", rootResource)
 	}
+	c.observeHostChosen(rootResource, chosenHost)
 	return chosenHost, nil
 }
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/PapaCharlie/go-restli/v2/restli"
 	"github.com/stretchr/testify/require"
 )
 
@@ -220,3 +221,18 @@ func hostRatios(t *testing.T, c *Client) map[url.URL]float64 {
 
 	return ratios
 }
+
+func TestService_RestLiProtocolVersion(t *testing.T) {
+	for announced, expected := range map[string]string{
+		"":      "",
+		"1.0.0": restli.ProtocolVersion1,
+		"2.0.0": restli.ProtocolVersion,
+		"3.0.0": restli.ProtocolVersion,
+	} {
+		s := &Service{}
+		if announced != "" {
+			s.ServiceMetadataProperties = map[string]interface{}{RestLiProtocolVersionProperty: announced}
+		}
+		require.Equal(t, expected, s.RestLiProtocolVersion(), announced)
+	}
+}
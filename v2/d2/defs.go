@@ -3,6 +3,9 @@ package d2
 import (
 	"encoding/json"
 	"net/url"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
 )
 
 type Cluster struct {
@@ -21,6 +24,28 @@ type Service struct {
 	ClusterName                 string   `json:"clusterName"`
 	PrioritizedSchemes          []string `json:"prioritizedSchemes"`
 	SslSessionValidationStrings []string `json:"sslSessionValidationStrings"`
+	// ServiceMetadataProperties holds arbitrary properties announced by the service, such as the rest.li protocol
+	// version it supports (see RestLiProtocolVersionProperty).
+	ServiceMetadataProperties map[string]interface{} `json:"serviceMetadataProperties"`
+}
+
+// RestLiProtocolVersionProperty is the service metadata property under which services announce the rest.li protocol
+// version they support.
+const RestLiProtocolVersionProperty = "restli.protocolVersion"
+
+// RestLiProtocolVersion returns the rest.li protocol version announced by this service under
+// RestLiProtocolVersionProperty, i.e. either restli.ProtocolVersion or restli.ProtocolVersion1, or an empty string if
+// the service does not announce a version. Services that announce a version older than 2.0.0 only support 1.0.0.
+func (s *Service) RestLiProtocolVersion() string {
+	v, _ := s.ServiceMetadataProperties[RestLiProtocolVersionProperty].(string)
+	switch {
+	case v == "":
+		return ""
+	case strings.HasPrefix(v, "0.") || strings.HasPrefix(v, "1."):
+		return restli.ProtocolVersion1
+	default:
+		return restli.ProtocolVersion
+	}
 }
 
 type UriProperty struct {
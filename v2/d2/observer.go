@@ -0,0 +1,43 @@
+package d2
+
+import (
+	"net/url"
+	"path"
+
+	"github.com/go-zookeeper/zk"
+)
+
+// Observer receives events from a Client, for example to export metrics. Note that restli.Observer is a superset of
+// this interface, therefore the same observer can be used for both the restli.Client and the d2.Client.
+type Observer interface {
+	// D2HostChosen is called every time a host is chosen for the given service.
+	D2HostChosen(service string, host *url.URL)
+	// D2HostSetChanged is called whenever a host is announced or removed from the given cluster, with the new number of
+	// hosts in the cluster.
+	D2HostSetChanged(cluster string, hosts int)
+	// D2ZkStateChanged is called whenever the state of the ZK session changes (see Client.ZkEventCallback).
+	D2ZkStateChanged(state string)
+}
+
+// ZkEventCallback reports the ZK session state changes to the Client's Observer. It should be passed to zk.Connect via
+// zk.WithEventCallback, e.g.:
+//
+//	c := &d2.Client{Observer: observer}
+//	c.Conn, _, err = zk.Connect(servers, sessionTimeout, zk.WithEventCallback(c.ZkEventCallback))
+func (c *Client) ZkEventCallback(event zk.Event) {
+	if event.Type == zk.EventSession && c.Observer != nil {
+		c.Observer.D2ZkStateChanged(event.State.String())
+	}
+}
+
+func (c *Client) observeHostChosen(service string, host *url.URL) {
+	if c.Observer != nil {
+		c.Observer.D2HostChosen(service, host)
+	}
+}
+
+func (c *Client) observeHostSetChanged(watcher *serviceUris) {
+	if c.Observer != nil {
+		c.Observer.D2HostSetChanged(path.Base(watcher.zkPath), len(watcher.uris))
+	}
+}
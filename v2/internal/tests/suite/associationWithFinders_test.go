@@ -0,0 +1,81 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/associationWithFinders"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/associationWithFinders_test"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restlidata"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func (o *Operation) AssociationWithFindersGet(t *testing.T, c Client) func(*testing.T) *MockResource {
+	key := &AssociationWithFinders_AssociationKey{Dest: "string:with:colons", Src: 1}
+	expected := &extras.SinglePrimitiveField{String: key.Dest}
+	actual, err := c.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockGet: func(ctx *restli.RequestContext, associationWithFindersId *AssociationWithFinders_AssociationKey) (entity *extras.SinglePrimitiveField, err error) {
+				require.Equal(t, key, associationWithFindersId)
+				return expected, nil
+			},
+		}
+	}
+}
+
+func (o *Operation) AssociationWithFindersBatchGet(t *testing.T, c Client) func(*testing.T) *MockResource {
+	keys := []*AssociationWithFinders_AssociationKey{
+		{Dest: "a", Src: 1},
+		{Dest: "b", Src: 2},
+	}
+	expected := new(BatchEntities)
+	for _, k := range keys {
+		expected.AddResult(k, &extras.SinglePrimitiveField{String: k.Dest})
+	}
+	res, err := c.BatchGet(keys)
+	require.NoError(t, err)
+	require.Len(t, res.Results, len(keys))
+	for k, v := range res.Results {
+		require.Equal(t, &extras.SinglePrimitiveField{String: k.Dest}, v)
+	}
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockBatchGet: func(ctx *restli.RequestContext, actualKeys []*AssociationWithFinders_AssociationKey) (results *BatchEntities, err error) {
+				require.Equal(t, keys, actualKeys)
+				return expected, nil
+			},
+		}
+	}
+}
+
+func (o *Operation) AssociationWithFindersFindBySrc(t *testing.T, c Client) func(*testing.T) *MockResource {
+	assocKey := &FindBySrcAssocKey{Src: 1}
+	params := &FindBySrcParams{
+		PagingContext: restlidata.NewPagingContext(0, 10),
+		Keyword:       restli.StringPointer("test"),
+	}
+	expected := &Elements{
+		Elements: []*extras.SinglePrimitiveField{{String: "a"}, {String: "b"}},
+		Paging:   &common.CollectionMetadata{Count: 10, Total: restli.Int32Pointer(2)},
+	}
+	res, err := c.FindBySrc(assocKey, params)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockFindBySrc: func(ctx *restli.RequestContext, actualAssocKey *FindBySrcAssocKey, queryParams *FindBySrcParams) (results *Elements, err error) {
+				require.Equal(t, assocKey, actualAssocKey)
+				require.Equal(t, params, queryParams)
+				return expected, nil
+			},
+		}
+	}
+}
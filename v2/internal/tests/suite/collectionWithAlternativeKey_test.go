@@ -0,0 +1,70 @@
+package suite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithAlternativeKey"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithAlternativeKey_test"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+const itemUrnPrefix = "urn:li:item:"
+
+// itemUrnCoercer translates the urn alternative keys of collectionWithAlternativeKey to and from their primary keys
+type itemUrnCoercer struct{}
+
+func (itemUrnCoercer) CoerceToKey(urn string) (int64, error) {
+	if !strings.HasPrefix(urn, itemUrnPrefix) {
+		return 0, fmt.Errorf("invalid item urn: %q", urn)
+	}
+	return strconv.ParseInt(strings.TrimPrefix(urn, itemUrnPrefix), 10, 64)
+}
+
+func (itemUrnCoercer) CoerceFromKey(id int64) (string, error) {
+	return itemUrnPrefix + strconv.FormatInt(id, 10), nil
+}
+
+func (o *Operation) CollectionWithAlternativeKeyGetByUrn(t *testing.T, c Client) func(*testing.T) *MockResource {
+	expected := &extras.SinglePrimitiveField{String: "a"}
+	actual, err := c.GetByUrn("urn:li:item:1")
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockGet: func(ctx *restli.RequestContext, id int64) (entity *extras.SinglePrimitiveField, err error) {
+				require.Equal(t, int64(1), id)
+				return expected, nil
+			},
+		}
+	}
+}
+
+func (o *Operation) CollectionWithAlternativeKeyBatchGetByUrn(t *testing.T, c Client) func(*testing.T) *MockResource {
+	expected := map[string]*extras.SinglePrimitiveField{
+		"urn:li:item:1": {String: "a"},
+		"urn:li:item:2": {String: "b"},
+	}
+	res, err := c.BatchGetByUrn([]string{"urn:li:item:1", "urn:li:item:2"})
+	require.NoError(t, err)
+	requireMapEquals(t, expected, res.Results)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockBatchGet: func(ctx *restli.RequestContext, keys []int64) (results *BatchEntities, err error) {
+				require.Equal(t, []int64{1, 2}, keys)
+				return &BatchEntities{
+					Results: map[int64]*extras.SinglePrimitiveField{
+						1: {String: "a"},
+						2: {String: "b"},
+					},
+				}, nil
+			},
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package suite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithBatchFinder"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithBatchFinder_test"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restlidata"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func (o *Operation) CollectionWithBatchFinderBatchFindBySearch(t *testing.T, c Client) func(*testing.T) *MockResource {
+	criteria := []*extras.SimpleRecord{{Foo: "a"}, {Foo: "b"}}
+	params := &BatchFindBySearchParams{
+		PagingContext: restlidata.NewPagingContext(0, 10),
+		Keyword:       restli.StringPointer("test"),
+	}
+	expected := []*BatchFinderCriteriaResult{
+		{
+			Elements: []*extras.SinglePrimitiveField{{String: "a"}},
+			Paging:   &common.CollectionMetadata{Count: 10, Total: restli.Int32Pointer(1)},
+		},
+		{
+			Error: &common.ErrorResponse{
+				Status:  restli.Int32Pointer(http.StatusNotFound),
+				Message: restli.StringPointer("No elements found for b"),
+			},
+		},
+	}
+	res, err := c.BatchFindBySearch(criteria, params)
+	require.NoError(t, err)
+	require.Len(t, res, len(criteria))
+	require.Equal(t, expected[0], res[0])
+	require.NotNil(t, res[1].Error)
+	require.Equal(t, expected[1].Error.Status, res[1].Error.Status)
+	require.Equal(t, expected[1].Error.Message, res[1].Error.Message)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockBatchFindBySearch: func(ctx *restli.RequestContext, actualCriteria []*extras.SimpleRecord, queryParams *BatchFindBySearchParams) (results []*BatchFinderCriteriaResult, err error) {
+				require.Equal(t, criteria, actualCriteria)
+				require.Equal(t, params, queryParams)
+				return expected, nil
+			},
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package suite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithServiceErrors"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithServiceErrors_test"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+func (o *Operation) CollectionWithServiceErrorsCreateWithDetails(t *testing.T, c Client) func(*testing.T) *MockResource {
+	create := &extras.SinglePrimitiveField{String: "a"}
+	expectedDetails := &extras.SimpleRecord{Foo: "bar"}
+	_, err := c.Create(create)
+	details, ok := IsInvalidInputError(err)
+	require.True(t, ok, err)
+	require.Equal(t, expectedDetails, details)
+	require.False(t, IsEntityNotFoundError(err))
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockCreate: func(ctx *restli.RequestContext, entity *extras.SinglePrimitiveField) (createdEntity *CreatedEntity, err error) {
+				require.Equal(t, create, entity)
+				return nil, NewInvalidInputError(expectedDetails)
+			},
+		}
+	}
+}
+
+func (o *Operation) CollectionWithServiceErrorsGetWithoutDetails(t *testing.T, c Client) func(*testing.T) *MockResource {
+	_, err := c.Get(1)
+	require.True(t, IsEntityNotFoundError(err), err)
+	status, _ := restli.ErrorStatus(err)
+	require.Equal(t, http.StatusNotFound, status)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockGet: func(ctx *restli.RequestContext, id int64) (entity *extras.SinglePrimitiveField, err error) {
+				require.Equal(t, int64(1), id)
+				return nil, NewEntityNotFoundError()
+			},
+		}
+	}
+}
+
+func (o *Operation) CollectionWithServiceErrorsCreateUndeclared(t *testing.T, c Client) func(*testing.T) *MockResource {
+	_, err := c.Create(&extras.SinglePrimitiveField{String: "a"})
+	require.False(t, IsEntityNotFoundError(err), err)
+	status, _ := restli.ErrorStatus(err)
+	require.Equal(t, http.StatusInternalServerError, status)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockCreate: func(ctx *restli.RequestContext, entity *extras.SinglePrimitiveField) (createdEntity *CreatedEntity, err error) {
+				// ENTITY_NOT_FOUND is only declared by get, so the server replaces it with a 500
+				return nil, NewEntityNotFoundError()
+			},
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package suite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithValidation"
+	. "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithValidation_test"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+func (o *Operation) CollectionWithValidationCreate(t *testing.T, c Client) func(*testing.T) *MockResource {
+	create := &extras.ValidatedRecord{Name: "valid"}
+	require.NoError(t, create.Validate())
+	id, err := c.Create(create)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id.Id)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockCreate: func(ctx *restli.RequestContext, entity *extras.ValidatedRecord) (createdEntity *CreatedEntity, err error) {
+				require.Equal(t, create, entity)
+				return &CreatedEntity{Id: 1}, nil
+			},
+		}
+	}
+}
+
+func (o *Operation) CollectionWithValidationCreateInvalid(t *testing.T, c Client) func(*testing.T) *MockResource {
+	create := &extras.ValidatedRecord{Name: ""}
+	require.Error(t, create.Validate())
+	_, err := c.Create(create)
+	status, _ := restli.ErrorStatus(err)
+	require.Equal(t, http.StatusUnprocessableEntity, status)
+
+	return func(t *testing.T) *MockResource {
+		return &MockResource{
+			MockCreate: func(ctx *restli.RequestContext, entity *extras.ValidatedRecord) (createdEntity *CreatedEntity, err error) {
+				require.FailNow(t, "Invalid entities should be rejected before reaching the resource")
+				return nil, nil
+			},
+		}
+	}
+}
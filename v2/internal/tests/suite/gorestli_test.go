@@ -23,8 +23,13 @@ import (
 	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated/testsuite/params"
 	"github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated/testsuite/simple"
 	collectiontyperef "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated/testsuite/typerefs/collectionTyperef"
+	associationwithfinders "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/associationWithFinders"
+	collectionwithalternativekey "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithAlternativeKey"
 	collectionwithannotations "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithAnnotations"
+	collectionwithbatchfinder "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithBatchFinder"
+	collectionwithserviceerrors "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithServiceErrors"
 	collectionwithtyperefkey "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithTyperefKey"
+	collectionwithvalidation "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/collectionWithValidation"
 	simplecomplexkey "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/simpleComplexKey"
 	simplewithpartialupdate "github.com/PapaCharlie/go-restli/v2/internal/tests/testdata/generated_extras/extras/simpleWithPartialUpdate"
 	"github.com/PapaCharlie/go-restli/v2/restli"
@@ -82,6 +87,9 @@ func TestGoRestli(t *testing.T) {
 					}
 					server := restli.NewServer()
 					o.getResource().register.Call([]reflect.Value{reflect.ValueOf(server), resource})
+					if setup := o.getResource().setup; setup != nil {
+						setup(server)
+					}
 
 					res := httptest.NewRecorder()
 					server.(http.Handler).ServeHTTP(res, o.Request(t))
@@ -129,6 +137,8 @@ func requireComplexKeyMapEquals[K ckey[K], V any](t *testing.T, left, right map[
 type supportedResource struct {
 	client   reflect.Value
 	register reflect.Value
+	// setup, if set, is called on the server after the resource is registered
+	setup func(server restli.Server)
 }
 
 type roundTripper func(*http.Request) (*http.Response, error)
@@ -230,6 +240,34 @@ var resourceRegistry = map[reflect.Type]*supportedResource{
 		client:   reflect.ValueOf(simplecomplexkey.NewClient),
 		register: reflect.ValueOf(simplecomplexkey.RegisterResource),
 	},
+	typeOf[associationwithfinders.Client](): {
+		client:   reflect.ValueOf(associationwithfinders.NewClient),
+		register: reflect.ValueOf(associationwithfinders.RegisterResource),
+	},
+	typeOf[collectionwithserviceerrors.Client](): {
+		client:   reflect.ValueOf(collectionwithserviceerrors.NewClient),
+		register: reflect.ValueOf(collectionwithserviceerrors.RegisterResource),
+	},
+	typeOf[collectionwithvalidation.Client](): {
+		client:   reflect.ValueOf(collectionwithvalidation.NewClient),
+		register: reflect.ValueOf(collectionwithvalidation.RegisterResource),
+		setup: func(server restli.Server) {
+			restli.SetEntityValidation(server, http.StatusUnprocessableEntity)
+		},
+	},
+	typeOf[collectionwithbatchfinder.Client](): {
+		client:   reflect.ValueOf(collectionwithbatchfinder.NewClient),
+		register: reflect.ValueOf(collectionwithbatchfinder.RegisterResource),
+	},
+	typeOf[collectionwithalternativekey.Client](): {
+		client:   reflect.ValueOf(collectionwithalternativekey.NewClient),
+		register: reflect.ValueOf(collectionwithalternativekey.RegisterResource),
+		setup: func(server restli.Server) {
+			collectionwithalternativekey.RegisterKeyCoercers(server, collectionwithalternativekey.KeyCoercers{
+				Urn: itemUrnCoercer{},
+			})
+		},
+	},
 }
 
 func niceHeaders(h http.Header) string {
@@ -119,13 +119,7 @@ func ReadTestManifest() *TestManifest {
 		}
 		aggregateManifest.JsonTestData = append(aggregateManifest.JsonTestData, m.JsonTestData...)
 		aggregateManifest.SchemaTestData = append(aggregateManifest.SchemaTestData, m.SchemaTestData...)
-		for _, wd := range m.WireProtocolTestData {
-			// Association resources will likely never be supported so skip loading them altogether
-			if wd.Name == "association" || wd.Name == "associationTyperef" {
-				continue
-			}
-			aggregateManifest.WireProtocolTestData = append(aggregateManifest.WireProtocolTestData, wd)
-		}
+		aggregateManifest.WireProtocolTestData = append(aggregateManifest.WireProtocolTestData, m.WireProtocolTestData...)
 	}
 	return &aggregateManifest
 }
@@ -0,0 +1,17 @@
+HTTP/1.1 200 OK
+Content-Length: 166
+Content-Type: application/json
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "statuses" : { },
+  "results" : {
+    "(dest:a,src:1)" : {
+      "string" : "a"
+    },
+    "(dest:b,src:2)" : {
+      "string" : "b"
+    }
+  },
+  "errors" : { }
+}
@@ -0,0 +1,18 @@
+HTTP/1.1 200 OK
+Content-Length: 163
+Content-Type: application/json
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "elements" : [ {
+    "string" : "a"
+  }, {
+    "string" : "b"
+  } ],
+  "paging" : {
+    "total" : 2,
+    "count" : 10,
+    "start" : 0,
+    "links" : [ ]
+  }
+}
@@ -0,0 +1,8 @@
+HTTP/1.1 200 OK
+Content-Length: 37
+Content-Type: application/json
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "string" : "string:with:colons"
+}
@@ -0,0 +1,17 @@
+HTTP/1.1 200 OK
+Content-Length: 176
+Content-Type: application/json
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "statuses" : { },
+  "results" : {
+    "urn%3Ali%3Aitem%3A1" : {
+      "string" : "a"
+    },
+    "urn%3Ali%3Aitem%3A2" : {
+      "string" : "b"
+    }
+  },
+  "errors" : { }
+}
@@ -0,0 +1,26 @@
+HTTP/1.1 200 OK
+Content-Length: 342
+Content-Type: application/json
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "elements" : [ {
+    "elements" : [ {
+      "string" : "a"
+    } ],
+    "paging" : {
+      "total" : 1,
+      "count" : 10,
+      "start" : 0,
+      "links" : [ ]
+    },
+    "isError" : false
+  }, {
+    "error" : {
+      "status" : 404,
+      "message" : "No elements found for b"
+    },
+    "isError" : true,
+    "elements" : [ ]
+  } ]
+}
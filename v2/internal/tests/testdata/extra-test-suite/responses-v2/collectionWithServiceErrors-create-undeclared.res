@@ -0,0 +1,10 @@
+HTTP/1.1 500 Internal Server Error
+Content-Length: 84
+Content-Type: application/json
+X-RestLi-Error-Response: true
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "status" : 500,
+  "message" : "Undeclared service error: \"ENTITY_NOT_FOUND\""
+}
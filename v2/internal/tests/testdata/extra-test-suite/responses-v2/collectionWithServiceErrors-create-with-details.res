@@ -0,0 +1,15 @@
+HTTP/1.1 422 Unprocessable Entity
+Content-Length: 176
+Content-Type: application/json
+X-RestLi-Error-Response: true
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "status" : 422,
+  "code" : "INVALID_INPUT",
+  "message" : "The input was invalid",
+  "errorDetailType" : "extras.SimpleRecord",
+  "errorDetails" : {
+    "foo" : "bar"
+  }
+}
@@ -0,0 +1,10 @@
+HTTP/1.1 404 Not Found
+Content-Length: 51
+Content-Type: application/json
+X-RestLi-Error-Response: true
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "status" : 404,
+  "code" : "ENTITY_NOT_FOUND"
+}
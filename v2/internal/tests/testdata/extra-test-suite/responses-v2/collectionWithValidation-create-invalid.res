@@ -0,0 +1,10 @@
+HTTP/1.1 422 Unprocessable Entity
+Content-Length: 149
+Content-Type: application/json
+X-RestLi-Error-Response: true
+X-RestLi-Protocol-Version: 2.0.0
+
+{
+  "status" : 422,
+  "message" : "Invalid request body for \"create\": go-restli: Validation failed: name: length 0 is less than the minimum of 1"
+}
@@ -0,0 +1,7 @@
+HTTP/1.1 201 Created
+Content-Length: 0
+Location: /collectionWithValidation/1
+X-RestLi-Id: 1
+X-RestLi-Protocol-Version: 2.0.0
+
+
@@ -5,6 +5,9 @@ import (
 	"log"
 
 	"github.com/PapaCharlie/go-restli/v2/cmd"
+	"github.com/PapaCharlie/go-restli/v2/cmd/call"
+	"github.com/PapaCharlie/go-restli/v2/cmd/docs"
+	"github.com/PapaCharlie/go-restli/v2/cmd/openapi"
 )
 
 // jar is embedded in the main package to ensure the 25MB jar isn't accidentally bundled in downstream builds via an
@@ -15,7 +18,9 @@ var jar []byte
 
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile | log.Lmsgprefix)
-	if err := cmd.CodeGenerator(jar).Execute(); err != nil {
+	command := cmd.CodeGenerator(jar)
+	command.AddCommand(call.Command(), docs.Command(), openapi.Command())
+	if err := command.Execute(); err != nil {
 		log.Fatalf("%+v", err)
 	}
 }
@@ -130,18 +130,20 @@ func registerAction[RP ResourcePathUnmarshaler[RP], P, R any](
 			}
 		}
 
-		results, err := h(ctx, rp, params)
-		if err != nil {
-			return newErrorResponsef(err, http.StatusBadRequest, "Action %q failed: %s", name)
-		}
-		if resultsMarshaler != nil {
-			return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
-				return writer.WriteMap(func(keyWriter func(key string) restlicodec.Writer) (err error) {
-					return resultsMarshaler(results, keyWriter(common.ValueField))
-				})
-			}), nil
-		} else {
-			return nil, nil
-		}
+		return filterRequest(ctx, newDecodedRequest(rp, nil, params), func() (restlicodec.Marshaler, error) {
+			results, err := h(ctx, rp, params)
+			if err != nil {
+				return ctx.errorPolicy.wrap(err, http.StatusBadRequest, "Action %q failed: %s", name)
+			}
+			if resultsMarshaler != nil {
+				return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+					return writer.WriteMap(func(keyWriter func(key string) restlicodec.Writer) (err error) {
+						return resultsMarshaler(results, keyWriter(common.ValueField))
+					})
+				}), nil
+			} else {
+				return nil, nil
+			}
+		})
 	}
 }
@@ -0,0 +1,252 @@
+package restli
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/restli/batchkeyset"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+// AltKeyParam is the query parameter that selects the alternative key in which a request's keys are expressed
+const AltKeyParam = "altkey"
+
+// KeyCoercer translates an alternative key A of a collection to its primary key K, and back. It is the equivalent of
+// rest.li's KeyCoercer, and is registered alongside the resource with RegisterKeyCoercer. Errors returned by
+// CoerceToKey are reported to the client as invalid requests.
+type KeyCoercer[A, K any] interface {
+	CoerceToKey(altKey A) (K, error)
+	CoerceFromKey(key K) (A, error)
+}
+
+// AltKeyQueryParams encodes the query of a request whose key is an alternative key, i.e. the altkey parameter along
+// with the method's other parameters (if any)
+type AltKeyQueryParams struct {
+	AltKey string
+	Params QueryParamsFields
+}
+
+func (q *AltKeyQueryParams) EncodeQueryParams() (string, error) {
+	return restlicodec.BuildQueryParams(func(paramNameWriter func(string) restlicodec.Writer) error {
+		paramNameWriter(AltKeyParam).WriteString(q.AltKey)
+		if q.Params != nil {
+			return q.Params.MarshalFields(paramNameWriter)
+		}
+		return nil
+	})
+}
+
+// AltKeyBatchQueryParams is the batch equivalent of AltKeyQueryParams, and also encodes the request's alternative
+// keys
+type AltKeyBatchQueryParams[A any] struct {
+	AltKey string
+	Params QueryParamsFields
+}
+
+func (q *AltKeyBatchQueryParams[A]) EncodeQueryParams(keys batchkeyset.BatchKeySet[A]) (string, error) {
+	return restlicodec.BuildQueryParams(func(paramNameWriter func(string) restlicodec.Writer) error {
+		paramNameWriter(AltKeyParam).WriteString(q.AltKey)
+		err := keys.Encode(paramNameWriter)
+		if err != nil {
+			return err
+		}
+		if q.Params != nil {
+			return q.Params.MarshalFields(paramNameWriter)
+		}
+		return nil
+	})
+}
+
+// RegisterKeyCoercer registers the KeyCoercer of the given alternative key of a collection. The keys of requests that
+// select an alternative key are coerced to primary keys before being handed to the resource, and the keys of batch_get
+// responses are coerced back to alternative keys. Requests that select an alternative key without a registered
+// KeyCoercer are rejected.
+func RegisterKeyCoercer[A, K any](
+	s Server,
+	segments []ResourcePathSegment,
+	name string,
+	coercer KeyCoercer[A, K],
+) {
+	p := s.subNode(segments)
+	if !p.isCollection {
+		log.Panicf("go-restli: Cannot register key coercer %q on %v since it is not a collection", name, segments)
+	}
+	if _, ok := p.keyCoercers[name]; ok {
+		log.Panicf("go-restli: Cannot register key coercer %q twice for %v", name, segments)
+	}
+	p.keyCoercers[name] = &keyCoercer[A, K]{coercer}
+}
+
+// altKeyCoercer is the type-erased form of a KeyCoercer, which reads and writes encoded keys
+type altKeyCoercer interface {
+	coerceToKey(altKey restlicodec.Reader) (key restlicodec.Marshaler, err error)
+	coerceFromKey(key restlicodec.Reader) (altKey restlicodec.Marshaler, err error)
+}
+
+type keyCoercer[A, K any] struct {
+	KeyCoercer[A, K]
+}
+
+func (c *keyCoercer[A, K]) coerceToKey(altKey restlicodec.Reader) (restlicodec.Marshaler, error) {
+	return coerceKey(altKey, c.CoerceToKey)
+}
+
+func (c *keyCoercer[A, K]) coerceFromKey(key restlicodec.Reader) (restlicodec.Marshaler, error) {
+	return coerceKey(key, c.CoerceFromKey)
+}
+
+func coerceKey[From, To any](reader restlicodec.Reader, coerce func(From) (To, error)) (restlicodec.Marshaler, error) {
+	from, err := restlicodec.UnmarshalRestLi[From](reader)
+	if err != nil {
+		return nil, err
+	}
+	to, err := coerce(from)
+	if err != nil {
+		return nil, err
+	}
+	return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+		return restlicodec.MarshalRestLi(to, writer)
+	}), nil
+}
+
+// coerceAltKeys coerces the alternative keys of the request to their primary keys, such that resources only ever see
+// primary keys. The entity segment of get, update, delete and partial_update requests is replaced, while the ids of
+// batch_get requests are rewritten in the request's query. In both cases, the altkey parameter is removed from the
+// query.
+func (p *pathNode) coerceAltKeys(
+	ctx *RequestContext,
+	method Method,
+	altKey string,
+	entitySegments []validatedRor2String,
+) ([]validatedRor2String, error) {
+	c, ok := p.keyCoercers[altKey]
+	if !ok {
+		_, err := newErrorResponsef(nil, http.StatusBadRequest, "Unknown alternative key %q for %q", altKey, p.name)
+		return nil, err
+	}
+
+	var ids string
+	switch method {
+	case Method_get, Method_update, Method_delete, Method_partial_update:
+		last := len(entitySegments) - 1
+		reader, _ := restlicodec.NewRor2Reader(string(entitySegments[last]))
+		key, err := c.coerceToKey(reader)
+		if err != nil {
+			_, err = newErrorResponsef(err, http.StatusBadRequest, "Invalid alternative key %q: %s", altKey)
+			return nil, err
+		}
+
+		w := restlicodec.NewRor2PathWriter()
+		err = key.MarshalRestLi(w)
+		if err != nil {
+			_, err = newErrorResponsef(err, http.StatusInternalServerError, "Could not serialize coerced key: %s")
+			return nil, err
+		}
+		entitySegments = append(append([]validatedRor2String(nil), entitySegments[:last]...),
+			validatedRor2String(w.Finalize()))
+	case Method_batch_get:
+		params, err := restlicodec.ParseQueryParams(ctx.Request.URL.RawQuery)
+		if err != nil {
+			return nil, err
+		}
+		reader, ok := params[batchkeyset.EntityIDsField]
+		if !ok {
+			_, err = newErrorResponsef(nil, http.StatusBadRequest, "No ids provided for %q", method)
+			return nil, err
+		}
+		keys, err := restlicodec.ReadArray(reader, c.coerceToKey)
+		if err != nil {
+			_, err = newErrorResponsef(err, http.StatusBadRequest, "Invalid alternative keys %q: %s", altKey)
+			return nil, err
+		}
+		ids, err = restlicodec.BuildQueryParams(func(paramNameWriter func(string) restlicodec.Writer) error {
+			return restlicodec.WriteArray(paramNameWriter(batchkeyset.EntityIDsField), keys, restlicodec.Marshaler.MarshalRestLi)
+		})
+		if err != nil {
+			_, err = newErrorResponsef(err, http.StatusInternalServerError, "Could not serialize coerced keys: %s")
+			return nil, err
+		}
+		ctx.altKeyCoercer = c
+	default:
+		_, err := newErrorResponsef(nil, http.StatusBadRequest, "Alternative keys are not supported for %q", method)
+		return nil, err
+	}
+
+	var query []string
+	for _, param := range strings.Split(ctx.Request.URL.RawQuery, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		if name == AltKeyParam || (ids != "" && name == batchkeyset.EntityIDsField) {
+			continue
+		}
+		query = append(query, param)
+	}
+	if ids != "" {
+		query = append(query, ids)
+	}
+	ctx.Request.URL.RawQuery = strings.Join(query, "&")
+
+	return entitySegments, nil
+}
+
+// encodedKey is a key that was already encoded, and is written as-is
+type encodedKey string
+
+func (k encodedKey) MarshalRestLi(writer restlicodec.Writer) error {
+	writer.WriteRawBytes([]byte(k))
+	return nil
+}
+
+// coerceBatchResponseKeys coerces the primary keys of the given batch response back to the alternative keys the
+// request was made with
+func coerceBatchResponseKeys[K comparable, V restlicodec.Marshaler](
+	c altKeyCoercer,
+	res *common.BatchResponse[K, V],
+) (coerced *common.BatchResponse[encodedKey, V], err error) {
+	coerce := func(k K) (encodedKey, error) {
+		w := restlicodec.NewRor2HeaderWriter()
+		err := restlicodec.MarshalRestLi(k, w)
+		if err != nil {
+			return "", err
+		}
+		reader, err := restlicodec.NewRor2Reader(w.Finalize())
+		if err != nil {
+			return "", err
+		}
+		altKey, err := c.coerceFromKey(reader)
+		if err != nil {
+			return "", err
+		}
+		w = restlicodec.NewRor2HeaderWriter()
+		err = altKey.MarshalRestLi(w)
+		if err != nil {
+			return "", err
+		}
+		return encodedKey(w.Finalize()), nil
+	}
+
+	coerced = new(common.BatchResponse[encodedKey, V])
+	for k, v := range res.Results {
+		altKey, err := coerce(k)
+		if err != nil {
+			return nil, err
+		}
+		coerced.AddResult(altKey, v)
+	}
+	for k, v := range res.Statuses {
+		altKey, err := coerce(k)
+		if err != nil {
+			return nil, err
+		}
+		coerced.AddStatus(altKey, v)
+	}
+	for k, v := range res.Errors {
+		altKey, err := coerce(k)
+		if err != nil {
+			return nil, err
+		}
+		coerced.AddError(altKey, v)
+	}
+	return coerced, nil
+}
@@ -0,0 +1,104 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+// urnCoercer coerces urns of the form "urn:li:item:<id>" to their ID
+type urnCoercer struct{}
+
+const testUrnPrefix = "urn:li:item:"
+
+func (urnCoercer) CoerceToKey(urn string) (id int64, err error) {
+	if !strings.HasPrefix(urn, testUrnPrefix) {
+		return 0, fmt.Errorf("not an item urn: %q", urn)
+	}
+	_, err = fmt.Sscan(strings.TrimPrefix(urn, testUrnPrefix), &id)
+	return id, err
+}
+
+func (urnCoercer) CoerceFromKey(id int64) (string, error) {
+	return fmt.Sprint(testUrnPrefix, id), nil
+}
+
+func newAltKeyTestClient(t *testing.T) *Client {
+	s := NewServer()
+	RegisterGet(s, testCollectionSegments,
+		func(_ *RequestContext, rp *testEntityResourcePath, _ common.EmptyRecord) (validatedName, error) {
+			return validatedName(fmt.Sprint(rp.Id)), nil
+		})
+	RegisterBatchGet(s, testCollectionSegments,
+		func(_ *RequestContext, _ testResourcePath, keys []int64, _ *SliceBatchQueryParams[int64]) (*common.BatchResponse[int64, validatedName], error) {
+			res := new(common.BatchResponse[int64, validatedName])
+			for _, k := range keys {
+				res.AddResult(k, validatedName(fmt.Sprint(k)))
+			}
+			return res, nil
+		})
+	RegisterDelete(s, testCollectionSegments,
+		func(_ *RequestContext, rp *testEntityResourcePath, _ common.EmptyRecord) error {
+			require.Equal(t, int64(3), rp.Id)
+			return nil
+		})
+	RegisterGetAll(s, testCollectionSegments,
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Elements[validatedName], error) {
+			return &common.Elements[validatedName]{}, nil
+		})
+	RegisterKeyCoercer[string, int64](s, testCollectionSegments, "urn", urnCoercer{})
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+	}
+}
+
+func TestAlternativeKeys(t *testing.T) {
+	c := newAltKeyTestClient(t)
+	ctx := context.Background()
+	entityPath := func(urn string) ResourcePath {
+		return ResourcePathString("/collection/" + restlicodec.Ror2PathEscape(urn))
+	}
+
+	name, err := Get[validatedName](c, ctx, entityPath("urn:li:item:1"), &AltKeyQueryParams{AltKey: "urn"})
+	require.NoError(t, err)
+	require.Equal(t, validatedName("1"), name)
+
+	err = Delete(c, ctx, entityPath("urn:li:item:3"), &AltKeyQueryParams{AltKey: "urn"})
+	require.NoError(t, err)
+
+	// The keys of the response are coerced back to the alternative keys
+	res, err := BatchGet[string, validatedName](c, ctx, ResourcePathString("/collection"),
+		[]string{"urn:li:item:1", "urn:li:item:2"}, &AltKeyBatchQueryParams[string]{AltKey: "urn"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]validatedName{"urn:li:item:1": "1", "urn:li:item:2": "2"}, res.Results)
+
+	requireErrorStatus := func(err error, status int, message string) {
+		var restLiErr *Error
+		require.True(t, errors.As(err, &restLiErr), "%+v", err)
+		require.Equal(t, status, restLiErr.Response.StatusCode)
+		require.Contains(t, *restLiErr.Message, message)
+	}
+
+	_, err = Get[validatedName](c, ctx, entityPath("foo"), &AltKeyQueryParams{AltKey: "urn"})
+	requireErrorStatus(err, http.StatusBadRequest, "Invalid alternative key")
+
+	_, err = Get[validatedName](c, ctx, entityPath("urn:li:item:1"), &AltKeyQueryParams{AltKey: "email"})
+	requireErrorStatus(err, http.StatusBadRequest, "Unknown alternative key")
+
+	_, err = GetAll[validatedName](c, ctx, ResourcePathString("/collection"), &AltKeyQueryParams{AltKey: "urn"})
+	requireErrorStatus(err, http.StatusBadRequest, "Alternative keys are not supported")
+}
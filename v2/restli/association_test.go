@@ -0,0 +1,108 @@
+package restli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testAssocKeyResourcePath is the resource path of an assocKey finder on the src key part of an association
+type testAssocKeyResourcePath struct {
+	Src string
+}
+
+func (t *testAssocKeyResourcePath) NewInstance() *testAssocKeyResourcePath {
+	return new(testAssocKeyResourcePath)
+}
+
+func (t *testAssocKeyResourcePath) UnmarshalResourcePath(segments []restlicodec.Reader) error {
+	return segments[0].ReadRecord(restlicodec.NewRequiredFields().Add("src"), func(reader restlicodec.Reader, field string) (err error) {
+		if field != "src" {
+			return reader.Skip()
+		}
+		t.Src, err = reader.ReadString()
+		return err
+	})
+}
+
+func (t *testAssocKeyResourcePath) ResourcePath() (string, error) {
+	return "/collection/(src:" + t.Src + ")", nil
+}
+
+func (t *testAssocKeyResourcePath) RootResource() string {
+	return "collection"
+}
+
+func (t *testAssocKeyResourcePath) AssocKeys() []string {
+	return []string{"src"}
+}
+
+func newAssociationTestClient(t *testing.T) *Client {
+	s := NewServer()
+	RegisterFinder(s, testCollectionSegments, "bySrc",
+		func(ctx *RequestContext, rp *testAssocKeyResourcePath, _ common.EmptyRecord) (*common.Elements[validatedName], error) {
+			require.Equal(t, Method_finder, GetMethodFromContext(ctx.Request.Context()))
+			return &common.Elements[validatedName]{Elements: []validatedName{validatedName(rp.Src)}}, nil
+		})
+	RegisterFinder(s, testCollectionSegments, "all",
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Elements[validatedName], error) {
+			return &common.Elements[validatedName]{}, nil
+		})
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+	}
+}
+
+func TestAssocKeyFinder(t *testing.T) {
+	c := newAssociationTestClient(t)
+
+	results, err := Find[validatedName](c, context.Background(), &testAssocKeyResourcePath{Src: "a"},
+		QueryParamsString("q=bySrc"))
+	require.NoError(t, err)
+	require.Equal(t, []validatedName{"a"}, results.Elements)
+
+	u, err := c.HostnameResolver.ResolveHostnameAndContextForQuery("collection", nil)
+	require.NoError(t, err)
+	get := func(path string, header string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, u.String()+path, nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set(MethodHeader, header)
+		}
+		res, err := c.Client.Do(req)
+		require.NoError(t, err)
+		body := new(strings.Builder)
+		_, _ = io.Copy(body, res.Body)
+		return res.StatusCode, body.String()
+	}
+
+	// The method header is optional, the finder is identified by its q parameter even though an entity is provided
+	status, body := get("/collection/(src:b)?q=bySrc", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"elements":["b"]}`, body)
+
+	// assocKey finders must be called on a partial association key
+	status, body = get("/collection?q=bySrc", "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, body, "No association key provided")
+
+	// Other finders cannot be called on an entity
+	status, body = get("/collection/(src:b)?q=all", Method_finder.String())
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, body, "Cannot provide an entity")
+	status, _ = get("/collection?q=all", "")
+	require.Equal(t, http.StatusOK, status)
+}
@@ -0,0 +1,277 @@
+package restli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+// Attachment is an unstructured data attachment, sent alongside the JSON body of a request or response using the
+// rest.li attachment wire format: the body is sent as a multipart/related message whose first part is the JSON body,
+// followed by one part per attachment, identified by its Content-ID header.
+type Attachment struct {
+	// ID is the attachment's unique identifier, sent as the Content-ID header of the attachment's part
+	ID string
+	// ContentType is the optional Content-Type of the attachment's data
+	ContentType string
+	// Body is the attachment's data. When sending an attachment, Body is read to EOF and closed if it implements
+	// io.Closer. When receiving an attachment, Body streams the attachment's data directly from the connection and is
+	// only valid until the function it was passed to returns.
+	Body io.Reader
+}
+
+func (a *Attachment) header() textproto.MIMEHeader {
+	h := textproto.MIMEHeader{ContentIDHeader: {a.ID}}
+	if a.ContentType != "" {
+		h.Set(ContentTypeHeader, a.ContentType)
+	}
+	return h
+}
+
+// WithRequestAttachments returns a context.Context to be passed into any generated client method that sends a request
+// body (such as a create, an update or an action). The given attachments will be sent alongside the request's body.
+// Note that requests with attachments are never tunnelled (see Client.QueryTunnellingThreshold).
+func WithRequestAttachments(ctx context.Context, attachments ...*Attachment) context.Context {
+	return context.WithValue(ctx, requestAttachmentsCtxKey, attachments)
+}
+
+// AcceptResponseAttachments returns a context.Context to be passed into any generated client method, which signals to
+// the server that the response may contain attachments. If it does, the given function will be called for each of the
+// response's attachments, in order, after the response's body is read. Any error it returns is returned by the client
+// method.
+func AcceptResponseAttachments(ctx context.Context, f func(a *Attachment) error) context.Context {
+	return context.WithValue(ctx, responseAttachmentsCtxKey, f)
+}
+
+// ReadRequestAttachments calls the given function for each of the attachments sent alongside the request's body, in
+// order. Attachments are streamed directly from the connection, meaning they can only be read once, and that each
+// attachment's Body is only valid until the function returns. Any error returned by the function is returned
+// immediately.
+func (c *RequestContext) ReadRequestAttachments(f func(a *Attachment) error) error {
+	if c.requestAttachments == nil {
+		return nil
+	}
+	r := c.requestAttachments
+	c.requestAttachments = nil
+	return readAttachments(r, f)
+}
+
+// AddResponseAttachment adds the given attachment to the response, which is sent after the response's body. Clients
+// must explicitly accept attachments (see AcceptResponseAttachments and ResponseAttachmentsAccepted), otherwise the
+// request fails with a 406.
+func (c *RequestContext) AddResponseAttachment(a *Attachment) {
+	c.responseAttachments = append(c.responseAttachments, a)
+}
+
+// ResponseAttachmentsAccepted returns true if the client accepts attachments in the response, i.e. if the request's
+// Accept header includes multipart/related.
+func (c *RequestContext) ResponseAttachmentsAccepted() bool {
+	return acceptsMultipartRelated(c.Request.Header)
+}
+
+func acceptsMultipartRelated(h http.Header) bool {
+	for _, accept := range h.Values(AcceptHeader) {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, _, _ := mime.ParseMediaType(mediaRange)
+			if mediaType == MultipartRelatedContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// multipartRelatedBody returns a reader that streams the given JSON body followed by the given attachments as a
+// multipart/related message with the given boundary, using the same encoding as multipart.Writer. Closing it closes
+// all the attachments.
+func multipartRelatedBody(boundary string, body []byte, attachments []*Attachment) io.ReadCloser {
+	readers := []io.Reader{
+		partHeader(boundary, true, textproto.MIMEHeader{ContentTypeHeader: {ApplicationJsonContentType}}),
+		bytes.NewReader(body),
+	}
+	for _, a := range attachments {
+		readers = append(readers, partHeader(boundary, false, a.header()))
+		if a.Body != nil {
+			readers = append(readers, a.Body)
+		}
+	}
+	readers = append(readers, strings.NewReader("\r\n--"+boundary+"--\r\n"))
+	return &attachmentsReader{Reader: io.MultiReader(readers...), attachments: attachments}
+}
+
+func multipartRelatedContentType(boundary string) string {
+	return mime.FormatMediaType(MultipartRelatedContentType, map[string]string{
+		MultipartBoundary: boundary,
+		"type":            ApplicationJsonContentType,
+	})
+}
+
+func partHeader(boundary string, first bool, header textproto.MIMEHeader) io.Reader {
+	b := new(strings.Builder)
+	if !first {
+		b.WriteString("\r\n")
+	}
+	b.WriteString("--" + boundary + "\r\n")
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			b.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	b.WriteString("\r\n")
+	return strings.NewReader(b.String())
+}
+
+type attachmentsReader struct {
+	io.Reader
+	attachments []*Attachment
+}
+
+func (r *attachmentsReader) Close() (err error) {
+	for _, a := range r.attachments {
+		if c, ok := a.Body.(io.Closer); ok {
+			if closeErr := c.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	return err
+}
+
+// readMultipartRelated reads the root part of the given multipart/related body, which must be at most maxSize bytes,
+// returning a multipart.Reader positioned on the first attachment.
+func readMultipartRelated(body io.Reader, boundary string, maxSize int64) ([]byte, *multipart.Reader, error) {
+	r := multipart.NewReader(body, boundary)
+	root, err := r.NextPart()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("go-restli: Empty %s body", MultipartRelatedContentType)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := readAtMost(root, maxSize, "Request body")
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, r, nil
+}
+
+func readAttachments(r *multipart.Reader, f func(a *Attachment) error) error {
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		id := part.Header.Get(ContentIDHeader)
+		if id == "" {
+			return fmt.Errorf("go-restli: Attachment does not specify a %s", ContentIDHeader)
+		}
+		err = f(&Attachment{
+			ID:          id,
+			ContentType: part.Header.Get(ContentTypeHeader),
+			Body:        part,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readRequestBody reads the request's body. If the request has attachments, only its first part (the JSON body) is
+// read, and the remaining parts can be read with RequestContext.ReadRequestAttachments.
+func readRequestBody(ctx *RequestContext) ([]byte, error) {
+	mediaType, params, _ := mime.ParseMediaType(ctx.Request.Header.Get(ContentTypeHeader))
+	if mediaType != MultipartRelatedContentType || ctx.Request.Body == nil {
+		return readBody(ctx.Request, ctx.limits.MaxRequestBodySize)
+	}
+
+	body, r, err := readMultipartRelated(ctx.Request.Body, params[MultipartBoundary], ctx.limits.MaxRequestBodySize)
+	if err != nil {
+		if _, ok := asErrorResponse(err); ok {
+			return nil, err
+		}
+		_, err = newErrorResponsef(err, http.StatusBadRequest, "Invalid %s body: %s", MultipartRelatedContentType)
+		return nil, err
+	}
+	ctx.requestAttachments = r
+	return body, nil
+}
+
+// writeResponseWithAttachments writes the response body followed by the response's attachments as a multipart/related
+// message. The body is serialized before anything is written so that serialization errors can still be returned as
+// regular rest.li errors, but the attachments are streamed. Should reading an attachment fail, the connection is
+// aborted since the response was already partially written.
+func writeResponseWithAttachments(res http.ResponseWriter, ctx *RequestContext, responseBody restlicodec.Marshaler) {
+	var body []byte
+	if responseBody != nil {
+		w := restlicodec.NewCompactJsonWriter()
+		err := responseBody.MarshalRestLi(w)
+		if err != nil {
+			ctx.responseAttachments = nil
+			_, err = newErrorResponsef(err, http.StatusInternalServerError, "Failed to serialize response: %s")
+			errRes, _ := asErrorResponse(err)
+			writeResponse(res, ctx, prepareErrorResponse(res, ctx, errRes))
+			return
+		}
+		body = []byte(w.Finalize())
+	}
+
+	boundary := multipart.NewWriter(nil).Boundary()
+	res.Header().Set(ContentTypeHeader, multipartRelatedContentType(boundary))
+	r := multipartRelatedBody(boundary, body, ctx.responseAttachments)
+	defer r.Close()
+
+	status := ctx.ResponseStatus
+	if status == http.StatusNoContent {
+		// Methods that have no response body (such as updates) default to a 204, which cannot carry the attachments
+		status = http.StatusOK
+	}
+	stream := &responseStream{ResponseWriter: res, status: status}
+	_, err := io.Copy(stream, r)
+	if err != nil {
+		if stream.err == nil {
+			log.Printf("go-restli: Failed to write response attachments for %q: %s", ctx.Request.URL, err)
+		}
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// readResponseBody reads the response's body. If the response has attachments, only its first part (the JSON body) is
+// read, and the returned multipart.Reader is positioned on the first attachment.
+func readResponseBody(res *http.Response) ([]byte, *multipart.Reader, error) {
+	mediaType, params, _ := mime.ParseMediaType(res.Header.Get(ContentTypeHeader))
+	if mediaType != MultipartRelatedContentType {
+		data, err := io.ReadAll(res.Body)
+		return data, nil, err
+	}
+	return readMultipartRelated(res.Body, params[MultipartBoundary], 0)
+}
+
+// readResponseAttachments passes the response's attachments to the function given to AcceptResponseAttachments. If
+// there is no such function, the attachments are discarded.
+func readResponseAttachments(r *multipart.Reader, f func(a *Attachment) error) error {
+	if f == nil {
+		f = func(a *Attachment) error {
+			_, err := io.Copy(io.Discard, a.Body)
+			return err
+		}
+	}
+	return readAttachments(r, f)
+}
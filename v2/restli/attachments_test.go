@@ -0,0 +1,98 @@
+package restli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttachmentsTestClient(t *testing.T) *Client {
+	s := NewServer()
+	RegisterUpdate(s, testSimpleSegments, nil,
+		func(ctx *RequestContext, _ testResourcePath, _ common.EmptyRecord, _ common.EmptyRecord) error {
+			var echo []string
+			err := ctx.ReadRequestAttachments(func(a *Attachment) error {
+				data, err := io.ReadAll(a.Body)
+				echo = append(echo, a.ID+"("+a.ContentType+")="+string(data))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if len(echo) > 0 {
+				ctx.AddResponseAttachment(&Attachment{ID: "echo", Body: strings.NewReader(strings.Join(echo, ","))})
+			}
+			return nil
+		})
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAttachments(t *testing.T) {
+	c := newAttachmentsTestClient(t)
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		bin := &closeRecorder{Reader: strings.NewReader("\x00\x01")}
+		ctx := WithRequestAttachments(context.Background(),
+			&Attachment{ID: "text", ContentType: "text/plain", Body: strings.NewReader("hello")},
+			&Attachment{ID: "bin", Body: bin},
+		)
+		var received []string
+		ctx = AcceptResponseAttachments(ctx, func(a *Attachment) error {
+			data, err := io.ReadAll(a.Body)
+			received = append(received, a.ID+"="+string(data))
+			return err
+		})
+
+		err := Update(c, ctx, simpleResourcePath{}, new(common.EmptyRecord), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"echo=text(text/plain)=hello,bin()=\x00\x01"}, received)
+		require.True(t, bin.closed)
+	})
+
+	t.Run("NotAccepted", func(t *testing.T) {
+		ctx := WithRequestAttachments(context.Background(), &Attachment{ID: "text", Body: strings.NewReader("hello")})
+		err := Update(c, ctx, simpleResourcePath{}, new(common.EmptyRecord), nil, nil)
+		status, ok := ErrorStatus(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusNotAcceptable, status)
+	})
+
+	t.Run("NoAttachments", func(t *testing.T) {
+		ctx := AcceptResponseAttachments(context.Background(), func(a *Attachment) error {
+			require.Fail(t, "Unexpected attachment", a.ID)
+			return nil
+		})
+		err := Update(c, ctx, simpleResourcePath{}, new(common.EmptyRecord), nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("NoRequestBody", func(t *testing.T) {
+		ctx := WithRequestAttachments(context.Background(), &Attachment{ID: "text", Body: strings.NewReader("hello")})
+		err := Delete(c, ctx, simpleResourcePath{}, nil)
+		require.Error(t, err)
+	})
+}
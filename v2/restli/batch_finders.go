@@ -0,0 +1,176 @@
+package restli
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+// BatchFinderNameParam is the query parameter that holds the name of the batch finder being called
+const BatchFinderNameParam = "bq"
+
+// QueryParamsFields is implemented by generated query parameter structs, and writes each parameter with the given
+// function. Unlike QueryParamsEncoder, it allows the parameters to be combined with other parameters.
+type QueryParamsFields interface {
+	MarshalFields(paramNameWriter func(string) restlicodec.Writer) error
+}
+
+// batchFinderQuery encodes the query of a batch finder, i.e. the finder's name, its criteria and its other parameters
+type batchFinderQuery[C restlicodec.Marshaler] struct {
+	name       string
+	batchParam string
+	criteria   []C
+	params     QueryParamsFields
+}
+
+func (q *batchFinderQuery[C]) EncodeQueryParams() (string, error) {
+	return restlicodec.BuildQueryParams(func(paramNameWriter func(string) restlicodec.Writer) (err error) {
+		paramNameWriter(BatchFinderNameParam).WriteString(q.name)
+		err = restlicodec.WriteArray(paramNameWriter(q.batchParam), q.criteria, C.MarshalRestLi)
+		if err != nil {
+			return err
+		}
+		if q.params != nil {
+			return q.params.MarshalFields(paramNameWriter)
+		}
+		return nil
+	})
+}
+
+// BatchFind executes a rest.li batch find request. The given criteria are sent in the batchParam query parameter,
+// alongside the given params (if any), and the results are returned in the same order as the criteria.
+func BatchFind[C, V restlicodec.Marshaler](
+	c *Client,
+	ctx context.Context,
+	rp ResourcePath,
+	name, batchParam string,
+	criteria []C,
+	params QueryParamsFields,
+) (results []*common.BatchFinderCriteriaResult[V], err error) {
+	return batchFind[C, *common.BatchFinderCriteriaResult[V]](c, ctx, rp, name, batchParam, criteria, params)
+}
+
+// BatchFindWithMetadata executes a rest.li batch find request for batch finders that declare metadata
+func BatchFindWithMetadata[C, V, M restlicodec.Marshaler](
+	c *Client,
+	ctx context.Context,
+	rp ResourcePath,
+	name, batchParam string,
+	criteria []C,
+	params QueryParamsFields,
+) (results []*common.BatchFinderCriteriaResultWithMetadata[V, M], err error) {
+	return batchFind[C, *common.BatchFinderCriteriaResultWithMetadata[V, M]](c, ctx, rp, name, batchParam, criteria, params)
+}
+
+func batchFind[C, R restlicodec.Marshaler](
+	c *Client,
+	ctx context.Context,
+	rp ResourcePath,
+	name, batchParam string,
+	criteria []C,
+	params QueryParamsFields,
+) (results []R, err error) {
+	query := &batchFinderQuery[C]{name: name, batchParam: batchParam, criteria: criteria, params: params}
+	req, err := NewGetRequest(c, ctx, rp, query, Method_batch_finder)
+	if err != nil {
+		return nil, err
+	}
+
+	res, _, err := DoAndUnmarshal(c, req, restlicodec.UnmarshalRestLi[*common.Elements[R]])
+	if err != nil {
+		return nil, err
+	}
+	return res.Elements, nil
+}
+
+// RegisterBatchFinder registers the batch finder with the given name. The criteria are read from the batchParam query
+// parameter, and the other parameters are decoded into QP. The batch finder must return exactly one result per
+// criteria, in the same order as the criteria.
+func RegisterBatchFinder[RP ResourcePathUnmarshaler[RP], QP restlicodec.QueryParamsDecoder[QP], C, V restlicodec.Marshaler](
+	s Server,
+	segments []ResourcePathSegment,
+	name, batchParam string,
+	find func(*RequestContext, RP, []C, QP) ([]*common.BatchFinderCriteriaResult[V], error),
+) {
+	registerBatchFinder(s, segments, name, batchParam, find)
+}
+
+// RegisterBatchFinderWithMetadata registers the batch finder with the given name, for batch finders that declare
+// metadata (see RegisterBatchFinder)
+func RegisterBatchFinderWithMetadata[RP ResourcePathUnmarshaler[RP], QP restlicodec.QueryParamsDecoder[QP], C, V, M restlicodec.Marshaler](
+	s Server,
+	segments []ResourcePathSegment,
+	name, batchParam string,
+	find func(*RequestContext, RP, []C, QP) ([]*common.BatchFinderCriteriaResultWithMetadata[V, M], error),
+) {
+	registerBatchFinder(s, segments, name, batchParam, find)
+}
+
+func registerBatchFinder[RP ResourcePathUnmarshaler[RP], QP restlicodec.QueryParamsDecoder[QP], C, R restlicodec.Marshaler](
+	s Server,
+	segments []ResourcePathSegment,
+	name, batchParam string,
+	find func(*RequestContext, RP, []C, QP) ([]R, error),
+) {
+	p := s.subNode(segments)
+	if _, ok := p.batchFinders[name]; ok {
+		log.Panicf("go-restli: Cannot register batch finder %q twice for %v", name, segments)
+	}
+
+	p.batchFinders[name] = func(
+		ctx *RequestContext,
+		segments []restlicodec.Reader,
+		body []byte,
+	) (responseBody restlicodec.Marshaler, err error) {
+		rp, err := UnmarshalResourcePath[RP](segments)
+		if err != nil {
+			return newErrorResponsef(err, http.StatusBadRequest, "Invalid path for batch finder %q: %s", name)
+		}
+
+		queryParams, err := restlicodec.UnmarshalQueryParamsDecoder[QP](ctx.Request.URL.RawQuery)
+		if err != nil {
+			return newErrorResponsef(err, http.StatusBadRequest, "Invalid query params for batch finder %q: %s", name)
+		}
+
+		criteria, err := readBatchFinderCriteria[C](ctx.Request.URL.RawQuery, batchParam)
+		if err != nil {
+			return newErrorResponsef(err, http.StatusBadRequest, "Invalid criteria for batch finder %q: %s", name)
+		}
+
+		err = checkBatchSize(ctx, Method_batch_finder, len(criteria))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(body) != 0 {
+			return newErrorResponsef(nil, http.StatusBadRequest, "Batch finders do not accept request bodies")
+		}
+
+		return filterRequest(ctx, newDecodedRequest(rp, queryParams, criteria), func() (restlicodec.Marshaler, error) {
+			results, err := find(ctx, rp, criteria, queryParams)
+			if err != nil {
+				return ctx.errorPolicy.wrap(err, http.StatusInternalServerError, "Batch finder %q failed: %s", name)
+			}
+			if len(results) != len(criteria) {
+				return newErrorResponsef(nil, http.StatusInternalServerError,
+					"Batch finder %q returned %d results for %d criteria", name, len(results), len(criteria))
+			}
+			return &common.Elements[R]{Elements: results}, nil
+		})
+	}
+}
+
+func readBatchFinderCriteria[C restlicodec.Marshaler](rawQuery, batchParam string) ([]C, error) {
+	params, err := restlicodec.ParseQueryParams(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := params[batchParam]
+	if !ok {
+		return nil, &restlicodec.MissingRequiredFieldsError{Fields: []string{batchParam}}
+	}
+	return restlicodec.ReadArray(reader, restlicodec.UnmarshalRestLi[C])
+}
@@ -0,0 +1,88 @@
+package restli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchFinderTestClient(t *testing.T) *Client {
+	s := NewServer()
+	RegisterBatchFinder(s, testCollectionSegments, "prefixes", "criteria",
+		func(ctx *RequestContext, _ testResourcePath, criteria []validatedName, _ common.EmptyRecord) ([]*common.BatchFinderCriteriaResult[validatedName], error) {
+			require.Equal(t, Method_batch_finder, GetMethodFromContext(ctx.Request.Context()))
+			require.Equal(t, "prefixes", GetFinderNameFromContext(ctx.Request.Context()))
+
+			results := make([]*common.BatchFinderCriteriaResult[validatedName], len(criteria))
+			for i, c := range criteria {
+				if c == "" {
+					results[i] = &common.BatchFinderCriteriaResult[validatedName]{Error: &common.ErrorResponse{
+						Status:  Int32Pointer(http.StatusBadRequest),
+						Message: StringPointer("empty criteria"),
+					}}
+					continue
+				}
+				results[i] = &common.BatchFinderCriteriaResult[validatedName]{
+					Elements: []validatedName{c + "1", c + "2"},
+				}
+			}
+			return results, nil
+		})
+	SetMethodLimits(s, testCollectionSegments, Method_batch_finder, Limits{MaxBatchSize: 3})
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+	}
+}
+
+func TestBatchFinder(t *testing.T) {
+	c := newBatchFinderTestClient(t)
+	rp := ResourcePathString("/collection")
+
+	results, err := BatchFind[validatedName, validatedName](c, context.Background(), rp,
+		"prefixes", "criteria", []validatedName{"a", "", "b"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*common.BatchFinderCriteriaResult[validatedName]{
+		{Elements: []validatedName{"a1", "a2"}},
+		{Error: &common.ErrorResponse{
+			Status:  Int32Pointer(http.StatusBadRequest),
+			Message: StringPointer("empty criteria"),
+		}},
+		{Elements: []validatedName{"b1", "b2"}},
+	}, results)
+
+	_, err = BatchFind[validatedName, validatedName](c, context.Background(), rp,
+		"prefixes", "criteria", []validatedName{"a", "b", "c", "d"}, nil)
+	status, _ := ErrorStatus(err)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	_, err = BatchFind[validatedName, validatedName](c, context.Background(), rp,
+		"unknown", "criteria", []validatedName{"a"}, nil)
+	status, _ = ErrorStatus(err)
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestBatchFinderRouting(t *testing.T) {
+	c := newBatchFinderTestClient(t)
+	u, err := c.HostnameResolver.ResolveHostnameAndContextForQuery("collection", nil)
+	require.NoError(t, err)
+
+	// The method header is optional for batch finders, since they can be identified by their bq parameter
+	res, err := c.Client.Get(u.String() + "/collection?bq=prefixes&criteria=List(x)")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body := new(strings.Builder)
+	_, _ = io.Copy(body, res.Body)
+	require.JSONEq(t, `{"elements":[{"elements":["x1","x2"],"isError":false}]}`, body.String())
+}
@@ -0,0 +1,189 @@
+package restli
+
+import (
+	"context"
+	"sync"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+// DefaultMaxBatchConcurrency is the number of concurrent requests used to execute a split batch request if
+// Client.MaxBatchConcurrency is not set.
+const DefaultMaxBatchConcurrency = 4
+
+func (c *Client) maxBatchConcurrency() int {
+	if c.MaxBatchConcurrency > 0 {
+		return c.MaxBatchConcurrency
+	}
+	return DefaultMaxBatchConcurrency
+}
+
+// SplitBatchKeys splits the given keys into chunks of at most maxBatchSize keys, and calls the given batch function
+// once for each chunk, executing at most Client.MaxBatchConcurrency calls concurrently. The resulting
+// common.BatchResponse values are merged into a single common.BatchResponse. If any of the calls fail, the context
+// passed to the remaining calls is canceled and the first error is returned. If maxBatchSize is not positive, the batch
+// function is called once with all the keys.
+func SplitBatchKeys[K comparable, V restlicodec.Marshaler](
+	c *Client,
+	ctx context.Context,
+	keys []K,
+	maxBatchSize int,
+	batch func(ctx context.Context, keys []K) (*common.BatchResponse[K, V], error),
+) (*common.BatchResponse[K, V], error) {
+	if maxBatchSize <= 0 || len(keys) <= maxBatchSize {
+		return batch(ctx, keys)
+	}
+
+	var chunks [][]K
+	for len(keys) > maxBatchSize {
+		chunks = append(chunks, keys[:maxBatchSize])
+		keys = keys[maxBatchSize:]
+	}
+	chunks = append(chunks, keys)
+
+	results, err := runChunks(c, ctx, chunks, batch)
+	if err != nil {
+		return nil, err
+	}
+	return mergeBatchResponses(results), nil
+}
+
+// SplitBatchEntities is the same as SplitBatchKeys, except it splits a map of entities (e.g. for batch_update and
+// batch_partial_update) into chunks of at most maxBatchSize entities.
+func SplitBatchEntities[K comparable, V, R restlicodec.Marshaler](
+	c *Client,
+	ctx context.Context,
+	entities map[K]V,
+	maxBatchSize int,
+	batch func(ctx context.Context, entities map[K]V) (*common.BatchResponse[K, R], error),
+) (*common.BatchResponse[K, R], error) {
+	if maxBatchSize <= 0 || len(entities) <= maxBatchSize {
+		return batch(ctx, entities)
+	}
+
+	var chunks []map[K]V
+	chunk := make(map[K]V, maxBatchSize)
+	for k, v := range entities {
+		if len(chunk) == maxBatchSize {
+			chunks = append(chunks, chunk)
+			chunk = make(map[K]V, maxBatchSize)
+		}
+		chunk[k] = v
+	}
+	chunks = append(chunks, chunk)
+
+	results, err := runChunks(c, ctx, chunks, batch)
+	if err != nil {
+		return nil, err
+	}
+	return mergeBatchResponses(results), nil
+}
+
+// SplitBatchCreate is the same as SplitBatchKeys, except it splits a slice of entities to be created into chunks of at
+// most maxBatchSize entities. The created entities are returned in the same order as the given entities.
+func SplitBatchCreate[V, R any](
+	c *Client,
+	ctx context.Context,
+	entities []V,
+	maxBatchSize int,
+	batchCreate func(ctx context.Context, entities []V) ([]R, error),
+) ([]R, error) {
+	if maxBatchSize <= 0 || len(entities) <= maxBatchSize {
+		return batchCreate(ctx, entities)
+	}
+
+	var chunks [][]V
+	for len(entities) > maxBatchSize {
+		chunks = append(chunks, entities[:maxBatchSize])
+		entities = entities[maxBatchSize:]
+	}
+	chunks = append(chunks, entities)
+
+	results, err := runChunks(c, ctx, chunks, batchCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []R
+	for _, r := range results {
+		created = append(created, r...)
+	}
+	return created, nil
+}
+
+// runChunks calls f for each chunk with at most c.maxBatchConcurrency() concurrent calls. The results are returned in
+// the same order as the chunks.
+func runChunks[C, R any](
+	c *Client,
+	ctx context.Context,
+	chunks []C,
+	f func(ctx context.Context, chunk C) (R, error),
+) ([]R, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(chunks))
+	semaphore := make(chan struct{}, c.maxBatchConcurrency())
+	wg := new(sync.WaitGroup)
+	errOnce := new(sync.Once)
+	var firstErr error
+
+	for i, chunk := range chunks {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, chunk C) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			r, err := f(ctx, chunk)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = r
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func mergeBatchResponses[K comparable, V restlicodec.Marshaler](
+	responses []*common.BatchResponse[K, V],
+) *common.BatchResponse[K, V] {
+	merged := new(common.BatchResponse[K, V])
+	for _, res := range responses {
+		if res == nil {
+			continue
+		}
+		for k, v := range res.Statuses {
+			merged.AddStatus(k, v)
+		}
+		for k, v := range res.Results {
+			merged.AddResult(k, v)
+		}
+		for k, v := range res.Errors {
+			merged.AddError(k, v)
+		}
+	}
+	return merged
+}
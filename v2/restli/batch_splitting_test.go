@@ -0,0 +1,108 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBatchKeys(t *testing.T) {
+	c := &Client{MaxBatchConcurrency: 2}
+
+	var keys []int64
+	for i := int64(0); i < 25; i++ {
+		keys = append(keys, i)
+	}
+
+	var calls, running, maxRunning int32
+	res, err := SplitBatchKeys(c, context.Background(), keys, 10,
+		func(ctx context.Context, keys []int64) (*common.BatchResponse[int64, *common.BatchEntityUpdateResponse], error) {
+			atomic.AddInt32(&calls, 1)
+			r := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if r <= m || atomic.CompareAndSwapInt32(&maxRunning, m, r) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			require.LessOrEqual(t, len(keys), 10)
+			res := new(common.BatchResponse[int64, *common.BatchEntityUpdateResponse])
+			for _, k := range keys {
+				res.AddResult(k, &common.BatchEntityUpdateResponse{Status: http.StatusNoContent})
+			}
+			return res, nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls)
+	require.LessOrEqual(t, maxRunning, int32(2))
+	require.Len(t, res.Results, len(keys))
+}
+
+func TestSplitBatchKeysError(t *testing.T) {
+	c := &Client{MaxBatchConcurrency: 1}
+	expectedErr := errors.New("failed")
+
+	var calls int32
+	_, err := SplitBatchKeys(c, context.Background(), []int64{1, 2, 3, 4}, 1,
+		func(ctx context.Context, keys []int64) (*common.BatchResponse[int64, *common.BatchEntityUpdateResponse], error) {
+			if atomic.AddInt32(&calls, 1) == 2 {
+				return nil, expectedErr
+			}
+			return new(common.BatchResponse[int64, *common.BatchEntityUpdateResponse]), nil
+		})
+	require.ErrorIs(t, err, expectedErr)
+	// The remaining chunks should not be executed after the first failure
+	require.Equal(t, int32(2), calls)
+}
+
+func TestSplitBatchEntities(t *testing.T) {
+	entities := map[int64]*common.EmptyRecord{}
+	for i := int64(0); i < 7; i++ {
+		entities[i] = new(common.EmptyRecord)
+	}
+
+	lock := new(sync.Mutex)
+	seen := map[int64]bool{}
+	res, err := SplitBatchEntities(new(Client), context.Background(), entities, 3,
+		func(ctx context.Context, chunk map[int64]*common.EmptyRecord) (*common.BatchResponse[int64, *common.BatchEntityUpdateResponse], error) {
+			require.LessOrEqual(t, len(chunk), 3)
+			res := new(common.BatchResponse[int64, *common.BatchEntityUpdateResponse])
+			lock.Lock()
+			defer lock.Unlock()
+			for k := range chunk {
+				require.False(t, seen[k])
+				seen[k] = true
+				res.AddStatus(k, http.StatusNoContent)
+			}
+			return res, nil
+		})
+	require.NoError(t, err)
+	require.Len(t, seen, len(entities))
+	require.Len(t, res.Statuses, len(entities))
+}
+
+func TestSplitBatchCreate(t *testing.T) {
+	var entities []int
+	for i := 0; i < 10; i++ {
+		entities = append(entities, i)
+	}
+
+	created, err := SplitBatchCreate(new(Client), context.Background(), entities, 3,
+		func(ctx context.Context, entities []int) ([]int, error) {
+			// Delay the earlier chunks to check that the order is preserved
+			time.Sleep(time.Duration(10-entities[0]) * time.Millisecond)
+			return entities, nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, entities, created)
+}
@@ -0,0 +1,68 @@
+package batchkeyset
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+type protocol1BatchKeySet[T any] struct {
+	BatchKeySet[T]
+	complexKeys bool
+}
+
+// NewProtocol1BatchKeySet wraps the given BatchKeySet such that its keys are encoded using the rest.li protocol 1.0.0,
+// and such that the keys of batch responses are expected to be encoded using the same protocol. Primitive keys are
+// encoded as repeated parameters (e.g. "ids=1&ids=2"), association keys as repeated compound keys (e.g.
+// "ids=k1%3Dv1%26k2%3Dv2") and complex keys as indexed parameters (e.g. "ids[0].k1=v1&ids[0].$params.p1=v2").
+func NewProtocol1BatchKeySet[T any](set BatchKeySet[T]) BatchKeySet[T] {
+	var t T
+	_, complexKeys := any(t).(ComplexKey[T])
+	return &protocol1BatchKeySet[T]{BatchKeySet: set, complexKeys: complexKeys}
+}
+
+func (s *protocol1BatchKeySet[T]) LocateOriginalKeyFromReader(keyReader restlicodec.Reader) (originalKey T, err error) {
+	key, err := restlicodec.DecodeProtocol1Key(keyReader.String())
+	if err != nil {
+		return originalKey, err
+	}
+
+	keyReader, err = restlicodec.NewRor2Reader(key)
+	if err != nil {
+		return originalKey, err
+	}
+
+	return s.BatchKeySet.LocateOriginalKeyFromReader(keyReader)
+}
+
+func (s *protocol1BatchKeySet[T]) Encode(paramNameWriter func(string) restlicodec.Writer) error {
+	encodedKeys, err := s.encodeKeys()
+	if err != nil {
+		return err
+	}
+	sort.Strings(encodedKeys)
+
+	writeParam := func(name, value string) {
+		paramNameWriter(name).WriteRawBytes([]byte(value))
+	}
+	for i, k := range encodedKeys {
+		if s.complexKeys {
+			err = restlicodec.EncodeProtocol1Param(EntityIDsField+"["+strconv.Itoa(i)+"]", k, writeParam)
+		} else {
+			var key string
+			key, err = restlicodec.EncodeProtocol1Key(k)
+			if err == nil {
+				writeParam(EntityIDsField, restlicodec.Protocol1Escape(key))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *protocol1BatchKeySet[T]) EncodeQueryParams() (params string, err error) {
+	return restlicodec.BuildQueryParams(s.Encode)
+}
@@ -3,6 +3,8 @@ package batchkeyset
 import (
 	"testing"
 
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,3 +22,18 @@ func TestPrimitiveBatchKeySetAddTwice(t *testing.T) {
 	require.NoError(t, set.AddKey(k))
 	require.Error(t, set.AddKey(k))
 }
+
+func TestProtocol1BatchKeySet(t *testing.T) {
+	set := NewProtocol1BatchKeySet(NewPrimitiveKeySet[string]())
+	require.NoError(t, AddAllKeys(set, "b c", "a&b"))
+
+	query, err := set.EncodeQueryParams()
+	require.NoError(t, err)
+	require.Equal(t, "ids=a%26b&ids=b%20c", query)
+
+	reader, err := restlicodec.NewRor2Reader("b c")
+	require.NoError(t, err)
+	k, err := set.LocateOriginalKeyFromReader(reader)
+	require.NoError(t, err)
+	require.Equal(t, "b c", k)
+}
@@ -62,7 +62,17 @@ func CreateWithReturnEntity[K any, V restlicodec.Marshaler](
 }
 
 func unmarshalReturnEntityKey[K any](c *Client, res *http.Response) (result *common.CreatedEntity[K], err error) {
-	if h := res.Header.Get(IDHeader); len(h) > 0 {
+	h := res.Header.Get(IDHeader)
+	if h == "" {
+		// Fall back to the header used by rest.li protocol 1.0.0 services
+		if h = res.Header.Get(LegacyIDHeader); h != "" {
+			h, err = restlicodec.DecodeProtocol1Key(h)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(h) > 0 {
 		var reader restlicodec.Reader
 		reader, err = restlicodec.NewRor2Reader(h)
 		if err != nil {
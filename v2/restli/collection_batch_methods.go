@@ -53,6 +53,10 @@ func (b *wrappedBatchQueryParamsDecoder[T, QP]) NewInstance() *wrappedBatchQuery
 	return new(wrappedBatchQueryParamsDecoder[T, QP])
 }
 
+func (b *wrappedBatchQueryParamsDecoder[T, QP]) unwrap() (keys any, qp any) {
+	return b.keys, b.qp
+}
+
 func (b *wrappedBatchQueryParamsDecoder[T, QP]) DecodeQueryParams(reader restlicodec.QueryParamsReader) (err error) {
 	b.qp = b.qp.NewInstance()
 	b.keys, err = b.qp.DecodeQueryParams(reader)
@@ -123,8 +127,11 @@ func BatchDelete[K comparable](
 	keys []K,
 	query batchQueryParamsEncoder[K],
 ) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
-	keySet := batchkeyset.NewBatchKeySet[K]()
-	err := batchkeyset.AddAllKeys(keySet, keys...)
+	keySet, _, err := newBatchKeySet[K](c, rp)
+	if err != nil {
+		return nil, err
+	}
+	err = batchkeyset.AddAllKeys(keySet, keys...)
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +151,11 @@ func BatchGet[K comparable, V restlicodec.Marshaler](
 	keys []K,
 	query batchQueryParamsEncoder[K],
 ) (*common.BatchResponse[K, V], error) {
-	keySet := batchkeyset.NewBatchKeySet[K]()
-	err := batchkeyset.AddAllKeys(keySet, keys...)
+	keySet, _, err := newBatchKeySet[K](c, rp)
+	if err != nil {
+		return nil, err
+	}
+	err = batchkeyset.AddAllKeys(keySet, keys...)
 	if err != nil {
 		return nil, err
 	}
@@ -166,8 +176,11 @@ func BatchUpdate[K comparable, V restlicodec.Marshaler](
 	query batchQueryParamsEncoder[K],
 	createAndReadOnlyFields restlicodec.PathSpec,
 ) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
-	keys := batchkeyset.NewBatchKeySet[K]()
-	err := batchkeyset.AddAllMapKeys(keys, entities)
+	keys, protocol1, err := newBatchKeySet[K](c, rp)
+	if err != nil {
+		return nil, err
+	}
+	err = batchkeyset.AddAllMapKeys(keys, entities)
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +192,7 @@ func BatchUpdate[K comparable, V restlicodec.Marshaler](
 		batchQueryParams(keys, query),
 		http.MethodPut,
 		Method_batch_update,
-		batchEntities[K, V](entities),
+		encodeBatchEntities(batchEntities[K, V](entities), protocol1),
 		createAndReadOnlyFields,
 	)
 	if err != nil {
@@ -197,8 +210,11 @@ func BatchPartialUpdate[K comparable, PV restlicodec.Marshaler](
 	query batchQueryParamsEncoder[K],
 	createAndReadOnlyFields restlicodec.PathSpec,
 ) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
-	keys := batchkeyset.NewBatchKeySet[K]()
-	err := batchkeyset.AddAllMapKeys(keys, entities)
+	keys, protocol1, err := newBatchKeySet[K](c, rp)
+	if err != nil {
+		return nil, err
+	}
+	err = batchkeyset.AddAllMapKeys(keys, entities)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +226,7 @@ func BatchPartialUpdate[K comparable, PV restlicodec.Marshaler](
 		batchQueryParams(keys, query),
 		http.MethodPost,
 		Method_batch_partial_update,
-		batchEntities[K, PV](entities),
+		encodeBatchEntities(batchEntities[K, PV](entities), protocol1),
 		createAndReadOnlyFields,
 	)
 	if err != nil {
@@ -0,0 +1,310 @@
+package restli
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PriorityHeader is the request header from which the default PriorityClassifier derives the Priority of a request.
+// Its value is one of "critical", "normal" or "sheddable" (case-insensitive).
+const PriorityHeader = "X-RestLi-Priority"
+
+// Priority is the class of a request, which determines how much of a ConcurrencyLimit it can use. Lower priority
+// requests are shed first as a resource approaches its limit, such that capacity is left for higher priority requests.
+type Priority int
+
+const (
+	PriorityCritical = Priority(iota)
+	PriorityNormal
+	PrioritySheddable
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityNormal:
+		return "normal"
+	case PrioritySheddable:
+		return "sheddable"
+	default:
+		return "Priority(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// PriorityClassifier derives the Priority of an incoming request.
+type PriorityClassifier func(req *http.Request) Priority
+
+// HeaderPriorityClassifier is the default PriorityClassifier, which reads the request's Priority from PriorityHeader.
+// Requests without the header, or with an unknown value, are PriorityNormal.
+func HeaderPriorityClassifier(req *http.Request) Priority {
+	switch strings.ToLower(req.Header.Get(PriorityHeader)) {
+	case "critical":
+		return PriorityCritical
+	case "sheddable":
+		return PrioritySheddable
+	default:
+		return PriorityNormal
+	}
+}
+
+// SetPriorityClassifier sets the PriorityClassifier used by the given Server to classify requests against its
+// ConcurrencyLimits, replacing HeaderPriorityClassifier.
+func SetPriorityClassifier(s Server, classifier PriorityClassifier) {
+	s.subNode(nil).rootNode.priorityClassifier = classifier
+}
+
+// DefaultPriorityShares is the fraction of a ConcurrencyLimit each Priority can use when ConcurrencyLimit.PriorityShares
+// is not set.
+var DefaultPriorityShares = map[Priority]float64{
+	PriorityCritical:  1,
+	PriorityNormal:    0.9,
+	PrioritySheddable: 0.5,
+}
+
+// DefaultRetryAfter is the delay advertised in the Retry-After header of shed requests when
+// ConcurrencyLimit.RetryAfter is not set.
+const DefaultRetryAfter = time.Second
+
+// ConcurrencyLimit bounds the number of requests a resource or method handles concurrently, such that a slow method
+// cannot tie up every goroutine of the Server. Requests that would exceed the limit are shed immediately instead of
+// being queued: if the limit is reached, they are rejected with a 503 (Service Unavailable), and if only their
+// Priority's share of the limit is exhausted (i.e. capacity is being held for higher priority requests), they are
+// rejected with a 429 (Too Many Requests). In both cases, the Retry-After header tells the client when to try again.
+type ConcurrencyLimit struct {
+	// MaxConcurrency is the maximum number of requests handled concurrently. If Adaptive is set, it is the upper bound
+	// of the adaptive limit.
+	MaxConcurrency int
+	// Adaptive, if set, continuously adjusts the limit according to the observed latency of requests.
+	Adaptive *AIMDLimit
+	// PriorityShares is the fraction (between 0 and 1) of the limit requests of each Priority can use. Priorities
+	// missing from the map can use the entire limit. Defaults to DefaultPriorityShares.
+	PriorityShares map[Priority]float64
+	// RetryAfter is the delay advertised in the Retry-After header of shed requests, rounded up to the second. Defaults
+	// to DefaultRetryAfter.
+	RetryAfter time.Duration
+}
+
+// AIMDLimit adjusts a ConcurrencyLimit using additive-increase/multiplicative-decrease, similarly to TCP's congestion
+// control: every request that completes within LatencyThreshold grows the limit by 1/limit (i.e. the limit grows by
+// about 1 once a full limit's worth of requests succeeds), while every request that exceeds it or fails with a 503
+// (Service Unavailable) or 504 (Gateway Timeout) multiplies the limit by BackoffRatio.
+type AIMDLimit struct {
+	// MinConcurrency is the lower bound of the limit. Defaults to 1.
+	MinConcurrency int
+	// InitialConcurrency is the limit before any request completes. Defaults to ConcurrencyLimit.MaxConcurrency.
+	InitialConcurrency int
+	// LatencyThreshold is the latency above which requests are considered a sign of overload.
+	LatencyThreshold time.Duration
+	// BackoffRatio is the factor by which the limit is multiplied on overload, between 0 and 1. Defaults to 0.9.
+	BackoffRatio float64
+}
+
+// ConcurrencyStats is a snapshot of the state of a ConcurrencyLimit, as returned by GetConcurrencyStats and
+// GetMethodConcurrencyStats.
+type ConcurrencyStats struct {
+	// Limit is the current limit, which is always ConcurrencyLimit.MaxConcurrency unless the limit is adaptive.
+	Limit int
+	// InFlight is the number of requests currently being handled.
+	InFlight int
+	// Admitted is the total number of requests that were admitted.
+	Admitted uint64
+	// Shed is the total number of requests that were shed, by Priority.
+	Shed map[Priority]uint64
+}
+
+// SetResourceConcurrencyLimit sets the ConcurrencyLimit of the resource identified by the given segments, which is
+// shared by all of its methods. Requests to its sub-resources do not count against it.
+func SetResourceConcurrencyLimit(s Server, segments []ResourcePathSegment, limit ConcurrencyLimit) {
+	s.subNode(segments).concurrencyLimiter = newConcurrencyLimiter(segments, limit)
+}
+
+// SetMethodConcurrencyLimit sets the ConcurrencyLimit of a single method of the resource identified by the given
+// segments. Requests to that method must fit under both the method's and the resource's limit (if any).
+func SetMethodConcurrencyLimit(s Server, segments []ResourcePathSegment, method Method, limit ConcurrencyLimit) {
+	p := s.subNode(segments)
+	if p.methodConcurrencyLimiters == nil {
+		p.methodConcurrencyLimiters = make(map[Method]*concurrencyLimiter)
+	}
+	p.methodConcurrencyLimiters[method] = newConcurrencyLimiter(segments, limit)
+}
+
+// GetConcurrencyStats returns the ConcurrencyStats of the resource identified by the given segments, or false if it
+// has no ConcurrencyLimit. The stats are shared by all the http.Handlers created from the given Server.
+func GetConcurrencyStats(s Server, segments []ResourcePathSegment) (ConcurrencyStats, bool) {
+	return s.subNode(segments).concurrencyLimiter.stats()
+}
+
+// GetMethodConcurrencyStats returns the ConcurrencyStats of the given method of the resource identified by the given
+// segments, or false if it has no ConcurrencyLimit.
+func GetMethodConcurrencyStats(s Server, segments []ResourcePathSegment, method Method) (ConcurrencyStats, bool) {
+	return s.subNode(segments).methodConcurrencyLimiters[method].stats()
+}
+
+// concurrencyLimiter enforces a ConcurrencyLimit. Note that it is shared by all the copies of the pathNode it is set
+// on, i.e. all the http.Handlers created from the same Server count against the same limit.
+type concurrencyLimiter struct {
+	ConcurrencyLimit
+	lock     sync.Mutex
+	limit    float64
+	inFlight int
+	admitted uint64
+	shed     map[Priority]uint64
+}
+
+func newConcurrencyLimiter(segments []ResourcePathSegment, l ConcurrencyLimit) *concurrencyLimiter {
+	if l.MaxConcurrency <= 0 {
+		log.Panicf("go-restli: MaxConcurrency of %v must be positive (got %d)", segments, l.MaxConcurrency)
+	}
+	if l.PriorityShares == nil {
+		l.PriorityShares = DefaultPriorityShares
+	}
+	if l.RetryAfter <= 0 {
+		l.RetryAfter = DefaultRetryAfter
+	}
+
+	c := &concurrencyLimiter{
+		limit: float64(l.MaxConcurrency),
+		shed:  make(map[Priority]uint64),
+	}
+	if a := l.Adaptive; a != nil {
+		aCopy := *a
+		if aCopy.MinConcurrency <= 0 {
+			aCopy.MinConcurrency = 1
+		}
+		if aCopy.MinConcurrency > l.MaxConcurrency {
+			log.Panicf("go-restli: MinConcurrency of %v cannot exceed MaxConcurrency (%d > %d)",
+				segments, aCopy.MinConcurrency, l.MaxConcurrency)
+		}
+		if aCopy.InitialConcurrency > 0 {
+			c.limit = math.Max(math.Min(float64(aCopy.InitialConcurrency), c.limit), float64(aCopy.MinConcurrency))
+		}
+		if aCopy.BackoffRatio <= 0 || aCopy.BackoffRatio >= 1 {
+			aCopy.BackoffRatio = 0.9
+		}
+		if aCopy.LatencyThreshold <= 0 {
+			log.Panicf("go-restli: LatencyThreshold of %v must be positive", segments)
+		}
+		l.Adaptive = &aCopy
+	}
+	c.ConcurrencyLimit = l
+	return c
+}
+
+// acquire attempts to admit a request of the given Priority, returning the status with which the request should be
+// shed if it cannot be admitted.
+func (c *concurrencyLimiter) acquire(priority Priority) (status int, ok bool) {
+	if c == nil {
+		return 0, true
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	limit := int(c.limit)
+	if c.inFlight >= limit {
+		c.shed[priority]++
+		return http.StatusServiceUnavailable, false
+	}
+	if share, ok := c.PriorityShares[priority]; ok && float64(c.inFlight) >= share*float64(limit) {
+		c.shed[priority]++
+		return http.StatusTooManyRequests, false
+	}
+
+	c.inFlight++
+	c.admitted++
+	return 0, true
+}
+
+// cancel reverts a successful call to acquire, for requests that end up being shed by another limiter
+func (c *concurrencyLimiter) cancel() {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.inFlight--
+	c.admitted--
+}
+
+// release marks an admitted request as done, adjusting the limit according to its outcome if it is adaptive
+func (c *concurrencyLimiter) release(latency time.Duration, status int) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.inFlight--
+
+	a := c.Adaptive
+	if a == nil {
+		return
+	}
+	if latency > a.LatencyThreshold || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout {
+		c.limit = math.Max(c.limit*a.BackoffRatio, float64(a.MinConcurrency))
+	} else {
+		c.limit = math.Min(c.limit+1/c.limit, float64(c.MaxConcurrency))
+	}
+}
+
+func (c *concurrencyLimiter) stats() (ConcurrencyStats, bool) {
+	if c == nil {
+		return ConcurrencyStats{}, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return ConcurrencyStats{
+		Limit:    int(c.limit),
+		InFlight: c.inFlight,
+		Admitted: c.admitted,
+		Shed:     copyMap(c.shed),
+	}, true
+}
+
+// admit admits the given request against the resource's and the method's ConcurrencyLimit (if any). The returned
+// function must be called once the request has been handled.
+func (p *pathNode) admit(ctx *RequestContext, method Method) (release func(err error), err error) {
+	resourceLimiter, methodLimiter := p.concurrencyLimiter, p.methodConcurrencyLimiters[method]
+	if resourceLimiter == nil && methodLimiter == nil {
+		return func(error) {}, nil
+	}
+
+	classifier := p.rootNode.priorityClassifier
+	if classifier == nil {
+		classifier = HeaderPriorityClassifier
+	}
+	priority := classifier(ctx.Request)
+
+	shed := func(c *concurrencyLimiter, status int) error {
+		ctx.ResponseHeaders.Set(RetryAfterHeader, strconv.Itoa(int(math.Ceil(c.RetryAfter.Seconds()))))
+		_, err := newErrorResponsef(nil, status, "Too many concurrent requests to %q (priority: %s)", p.name, priority)
+		return err
+	}
+
+	if status, ok := resourceLimiter.acquire(priority); !ok {
+		return nil, shed(resourceLimiter, status)
+	}
+	if status, ok := methodLimiter.acquire(priority); !ok {
+		resourceLimiter.cancel()
+		return nil, shed(methodLimiter, status)
+	}
+
+	start := time.Now()
+	return func(err error) {
+		latency := time.Since(start)
+		status, _ := ErrorStatus(err)
+		methodLimiter.release(latency, status)
+		resourceLimiter.release(latency, status)
+	}, nil
+}
@@ -0,0 +1,165 @@
+package restli
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+// newConcurrencyTestServer returns a Server whose get_all method blocks until the given channel is closed, and whose
+// delete method returns immediately
+func newConcurrencyTestServer(block chan struct{}) Server {
+	s := NewServer()
+	RegisterGetAll(s, testCollectionSegments,
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Elements[validatedName], error) {
+			<-block
+			return &common.Elements[validatedName]{}, nil
+		})
+	RegisterDelete(s, testCollectionSegments,
+		func(*RequestContext, *testEntityResourcePath, common.EmptyRecord) error {
+			return nil
+		})
+	return s
+}
+
+// newConcurrencyTestRequest returns a get_all request with the given priority, or a delete request if httpMethod is
+// DELETE
+func newConcurrencyTestRequest(httpMethod string, priority Priority) *http.Request {
+	req := newTestRequest(http.MethodGet, "/collection", Method_get_all, "")
+	if httpMethod == http.MethodDelete {
+		req = newTestRequest(http.MethodDelete, "/collection/1", Method_delete, "")
+	}
+	req.Header.Set(PriorityHeader, priority.String())
+	return req
+}
+
+func TestConcurrencyLimits(t *testing.T) {
+	block := make(chan struct{})
+	s := newConcurrencyTestServer(block)
+	SetResourceConcurrencyLimit(s, testCollectionSegments, ConcurrencyLimit{
+		MaxConcurrency: 4,
+		RetryAfter:     1500 * time.Millisecond,
+	})
+	SetMethodConcurrencyLimit(s, testCollectionSegments, Method_get_all, ConcurrencyLimit{
+		MaxConcurrency: 2,
+		PriorityShares: map[Priority]float64{PrioritySheddable: 0.5},
+	})
+	h := s.Handler()
+
+	requireInFlight := func(resource, method int) {
+		require.Eventually(t, func() bool {
+			r, _ := GetConcurrencyStats(s, testCollectionSegments)
+			m, _ := GetMethodConcurrencyStats(s, testCollectionSegments, Method_get_all)
+			return r.InFlight == resource && m.InFlight == method
+		}, time.Second, time.Millisecond)
+	}
+
+	done := make(chan int)
+	go func() { done <- serveTestRequest(h, newConcurrencyTestRequest(http.MethodGet, PriorityNormal)).Code }()
+	requireInFlight(1, 1)
+
+	// Half of the method's limit is already used, so sheddable requests are throttled
+	res := serveTestRequest(h, newConcurrencyTestRequest(http.MethodGet, PrioritySheddable))
+	require.Equal(t, http.StatusTooManyRequests, res.Code)
+	require.Equal(t, "1", res.Header().Get(RetryAfterHeader))
+
+	go func() { done <- serveTestRequest(h, newConcurrencyTestRequest(http.MethodGet, PriorityCritical)).Code }()
+	requireInFlight(2, 2)
+
+	// The method's limit is reached, so even critical requests are shed
+	res = serveTestRequest(h, newConcurrencyTestRequest(http.MethodGet, PriorityCritical))
+	require.Equal(t, http.StatusServiceUnavailable, res.Code)
+	require.Equal(t, "1", res.Header().Get(RetryAfterHeader))
+
+	// Other methods only count against the resource's limit, which is not reached yet
+	res = serveTestRequest(h, newConcurrencyTestRequest(http.MethodDelete, PriorityNormal))
+	require.Equal(t, http.StatusNoContent, res.Code)
+
+	close(block)
+	require.Equal(t, http.StatusOK, <-done)
+	require.Equal(t, http.StatusOK, <-done)
+	requireInFlight(0, 0)
+
+	stats, ok := GetMethodConcurrencyStats(s, testCollectionSegments, Method_get_all)
+	require.True(t, ok)
+	require.Equal(t, ConcurrencyStats{
+		Limit:    2,
+		Admitted: 2,
+		Shed:     map[Priority]uint64{PrioritySheddable: 1, PriorityCritical: 1},
+	}, stats)
+
+	stats, ok = GetConcurrencyStats(s, testCollectionSegments)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), stats.Admitted)
+
+	_, ok = GetMethodConcurrencyStats(s, testCollectionSegments, Method_delete)
+	require.False(t, ok)
+}
+
+func TestPriorityClassifier(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	s := newConcurrencyTestServer(block)
+	SetResourceConcurrencyLimit(s, testCollectionSegments, ConcurrencyLimit{MaxConcurrency: 2})
+	SetPriorityClassifier(s, func(*http.Request) Priority { return PrioritySheddable })
+	h := s.Handler()
+
+	go serveTestRequest(h, newConcurrencyTestRequest(http.MethodGet, PriorityCritical))
+	require.Eventually(t, func() bool {
+		stats, _ := GetConcurrencyStats(s, testCollectionSegments)
+		return stats.InFlight == 1
+	}, time.Second, time.Millisecond)
+
+	// The header is ignored, and sheddable requests can only use half of the limit
+	res := serveTestRequest(h, newConcurrencyTestRequest(http.MethodDelete, PriorityCritical))
+	require.Equal(t, http.StatusTooManyRequests, res.Code)
+	stats, _ := GetConcurrencyStats(s, testCollectionSegments)
+	require.Equal(t, map[Priority]uint64{PrioritySheddable: 1}, stats.Shed)
+}
+
+func TestAIMDLimit(t *testing.T) {
+	c := newConcurrencyLimiter(testCollectionSegments, ConcurrencyLimit{
+		MaxConcurrency: 10,
+		Adaptive: &AIMDLimit{
+			MinConcurrency:     2,
+			InitialConcurrency: 5,
+			LatencyThreshold:   100 * time.Millisecond,
+			BackoffRatio:       0.5,
+		},
+	})
+	limit := func() int {
+		stats, _ := c.stats()
+		return stats.Limit
+	}
+	require.Equal(t, 5, limit())
+
+	// Roughly a full limit's worth of fast requests grows the limit by 1
+	for i := 0; i < 6; i++ {
+		_, ok := c.acquire(PriorityNormal)
+		require.True(t, ok)
+		c.release(time.Millisecond, 0)
+	}
+	require.Equal(t, 6, limit())
+
+	// Slow requests and unavailable responses halve it, down to MinConcurrency
+	c.acquire(PriorityNormal)
+	c.release(time.Second, 0)
+	require.Equal(t, 3, limit())
+	c.acquire(PriorityNormal)
+	c.release(time.Millisecond, http.StatusServiceUnavailable)
+	require.Equal(t, 2, limit())
+
+	// The limit never exceeds MaxConcurrency
+	for i := 0; i < 1000; i++ {
+		c.acquire(PriorityNormal)
+		c.release(time.Millisecond, 0)
+	}
+	require.Equal(t, 10, limit())
+
+	require.Panics(t, func() {
+		SetResourceConcurrencyLimit(NewServer(), testCollectionSegments, ConcurrencyLimit{})
+	})
+}
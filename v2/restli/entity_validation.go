@@ -0,0 +1,21 @@
+package restli
+
+import (
+	"log"
+	"net/http"
+)
+
+// SetEntityValidation enables the validation of the entities in the request bodies received by the given Server (e.g.
+// the entity of a CREATE or the entities of a BATCH_UPDATE) against the constraints declared by their @validate
+// annotations. Requests with invalid entities are rejected before reaching the resource with an ErrorResponse that
+// lists all the violations, and whose status is the given status, which must be either http.StatusBadRequest or
+// http.StatusUnprocessableEntity. A status of 0 disables validation, which is the default.
+func SetEntityValidation(s Server, status int) {
+	switch status {
+	case 0, http.StatusBadRequest, http.StatusUnprocessableEntity:
+		s.subNode(nil).rootNode.validationStatus = status
+	default:
+		log.Panicf("go-restli: Illegal entity validation status %d (must be %d or %d)",
+			status, http.StatusBadRequest, http.StatusUnprocessableEntity)
+	}
+}
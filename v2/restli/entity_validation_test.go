@@ -0,0 +1,79 @@
+package restli
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restli/validation"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedName string
+
+func (n validatedName) MarshalRestLi(writer restlicodec.Writer) error {
+	writer.WriteString(string(n))
+	return nil
+}
+
+func (n *validatedName) UnmarshalRestLi(reader restlicodec.Reader) (err error) {
+	s, err := reader.ReadString()
+	*n = validatedName(s)
+	return err
+}
+
+func (n validatedName) Validate() error {
+	return validation.Validate(n)
+}
+
+func (n validatedName) CollectViolations(c *validation.Collector) {
+	validation.Strlen(c, "name", n, 1, 3)
+}
+
+func newEntityValidationTestServer() Server {
+	s := NewServer()
+	RegisterCreate[int64](s, testCollectionSegments, nil,
+		func(*RequestContext, testResourcePath, validatedName, common.EmptyRecord) (*common.CreatedEntity[int64], error) {
+			return &common.CreatedEntity[int64]{Id: 1}, nil
+		})
+	RegisterBatchCreate[int64](s, testCollectionSegments, nil,
+		func(_ *RequestContext, _ testResourcePath, names []validatedName, _ common.EmptyRecord) ([]*common.CreatedEntity[int64], error) {
+			created := make([]*common.CreatedEntity[int64], len(names))
+			for i := range created {
+				created[i] = &common.CreatedEntity[int64]{Id: int64(i), Status: http.StatusCreated}
+			}
+			return created, nil
+		})
+	return s
+}
+
+func TestEntityValidation(t *testing.T) {
+	s := newEntityValidationTestServer()
+	create := func(body string) *http.Request {
+		return newTestRequest(http.MethodPost, "/collection", Method_create, body)
+	}
+
+	// Validation is disabled by default
+	status, _ := serveTestErrorRequest(t, s.Handler(), create(`"toolong"`))
+	require.Equal(t, http.StatusCreated, status)
+
+	SetEntityValidation(s, http.StatusUnprocessableEntity)
+
+	status, _ = serveTestErrorRequest(t, s.Handler(), create(`"ok"`))
+	require.Equal(t, http.StatusCreated, status)
+
+	status, errRes := serveTestErrorRequest(t, s.Handler(), create(`"toolong"`))
+	require.Equal(t, http.StatusUnprocessableEntity, status)
+	require.Contains(t, *errRes.Message, "name: length 7 is greater than the maximum of 3")
+
+	SetEntityValidation(s, http.StatusBadRequest)
+
+	status, errRes = serveTestErrorRequest(t, s.Handler(),
+		newTestRequest(http.MethodPost, "/collection", Method_batch_create, `{"elements":["ok","","toolong"]}`))
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, *errRes.Message, "elements[1].name: length 0 is less than the minimum of 1; "+
+		"elements[2].name: length 7 is greater than the maximum of 3")
+
+	require.Panics(t, func() { SetEntityValidation(s, http.StatusInternalServerError) })
+}
@@ -0,0 +1,170 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+// ErrorCategory is a broad classification of the errors returned by a Client, meant to be used by retry and alerting
+// logic. All the errors declared in this package implement CategorizedError, and can therefore be matched against an
+// ErrorCategory with errors.Is. Use CategoryOf to also classify errors from the standard library and the restlicodec
+// package, such as *url.Error and *restlicodec.MissingRequiredFieldsError.
+type ErrorCategory struct {
+	name string
+}
+
+func (c *ErrorCategory) Error() string {
+	return "go-restli: " + c.name
+}
+
+func (c *ErrorCategory) String() string {
+	return c.name
+}
+
+var (
+	// ErrNotFound is the category of errors caused by a 404 response
+	ErrNotFound = &ErrorCategory{"not found"}
+	// ErrConflict is the category of errors caused by a 409 or 412 response
+	ErrConflict = &ErrorCategory{"conflict"}
+	// ErrThrottled is the category of errors caused by a 429 response
+	ErrThrottled = &ErrorCategory{"throttled"}
+	// ErrUnavailable is the category of errors caused by a 502 or 503 response
+	ErrUnavailable = &ErrorCategory{"unavailable"}
+	// ErrTimeout is the category of errors caused by a 408 or 504 response, or by the request timing out
+	ErrTimeout = &ErrorCategory{"timeout"}
+	// ErrBadRequest is the category of errors caused by a 400 or 422 response, or by an invalid request that could not
+	// be sent
+	ErrBadRequest = &ErrorCategory{"bad request"}
+	// ErrDeserialization is the category of errors caused by a response that could not be deserialized
+	ErrDeserialization = &ErrorCategory{"deserialization"}
+	// ErrTransport is the category of errors caused by a failure to send a request or receive its response
+	ErrTransport = &ErrorCategory{"transport"}
+)
+
+// CategorizedError is implemented by all the errors in this package.
+type CategorizedError interface {
+	error
+	// Category returns the ErrorCategory this error belongs to, or nil if it does not belong to any category.
+	Category() *ErrorCategory
+	// Is returns true if the given target is this error's category, such that errors.Is can be used to check the
+	// category of an error.
+	Is(target error) bool
+}
+
+func isCategory(err CategorizedError, target error) bool {
+	category := err.Category()
+	return category != nil && error(category) == target
+}
+
+func statusCategory(status int) *ErrorCategory {
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrThrottled
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ErrUnavailable
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrTimeout
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrBadRequest
+	default:
+		return nil
+	}
+}
+
+// CategoryOf returns the ErrorCategory of the given error, or nil if the error does not belong to any category. Unlike
+// errors.Is, it also classifies errors that do not implement CategorizedError:
+//   - A *common.ErrorResponse is classified according to its status.
+//   - A *url.Error (which is returned for all network errors) is either ErrTimeout or ErrTransport.
+//   - context.DeadlineExceeded is ErrTimeout.
+//   - The restlicodec deserialization errors are ErrDeserialization.
+func CategoryOf(err error) *ErrorCategory {
+	var categorized CategorizedError
+	if errors.As(err, &categorized) {
+		if category := categorized.Category(); category != nil {
+			return category
+		}
+	}
+
+	if res, ok := findErrorResponse(err); ok && res.Status != nil {
+		if category := statusCategory(int(*res.Status)); category != nil {
+			return category
+		}
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if urlErr.Timeout() {
+			return ErrTimeout
+		}
+		return ErrTransport
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+
+	var (
+		deserializationErr *restlicodec.DeserializationError
+		missingFieldsErr   *restlicodec.MissingRequiredFieldsError
+		invalidTypeErr     *restlicodec.InvalidTypeError
+		excludedFieldErr   restlicodec.ExcludedFieldError
+	)
+	if errors.As(err, &deserializationErr) ||
+		errors.As(err, &missingFieldsErr) ||
+		errors.As(err, &invalidTypeErr) ||
+		errors.As(err, &excludedFieldErr) {
+		return ErrDeserialization
+	}
+
+	return nil
+}
+
+// ErrorStatus returns the HTTP status code of the response that caused the given error, if any.
+func ErrorStatus(err error) (int, bool) {
+	if res := responseOf(err); res != nil {
+		return res.StatusCode, true
+	}
+
+	if res, ok := findErrorResponse(err); ok && res.Status != nil {
+		return int(*res.Status), true
+	}
+
+	return 0, false
+}
+
+// ErrorServiceCode returns the service error code of the given error (the Code field of the ErrorResponse), if any.
+// See also IsServiceError.
+func ErrorServiceCode(err error) (string, bool) {
+	if res, ok := findErrorResponse(err); ok && res.Code != nil {
+		return *res.Code, true
+	}
+	return "", false
+}
+
+// ErrorRequest returns the http.Request that caused the given error, if any.
+func ErrorRequest(err error) *http.Request {
+	if res := responseOf(err); res != nil {
+		return res.Request
+	}
+	return nil
+}
+
+type responseError interface {
+	response() *http.Response
+}
+
+func responseOf(err error) *http.Response {
+	var re responseError
+	if errors.As(err, &re) {
+		return re.response()
+	}
+	return nil
+}
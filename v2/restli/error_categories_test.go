@@ -0,0 +1,196 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestErrorResponse(t *testing.T, status int, restLiError bool, body string) error {
+	res := httptest.NewRecorder()
+	if restLiError {
+		res.Header().Set(ErrorResponseHeader, "true")
+	}
+	res.WriteHeader(status)
+	_, _ = res.WriteString(body)
+
+	r := res.Result()
+	r.Request = httptest.NewRequest(http.MethodGet, "/collection/1", nil)
+	err := IsErrorResponse(r)
+	require.Error(t, err)
+	return err
+}
+
+func TestErrorCategories(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Err      error
+		Category *ErrorCategory
+	}{
+		{
+			Name:     "NotFound",
+			Err:      newTestErrorResponse(t, http.StatusNotFound, true, `{"status":404}`),
+			Category: ErrNotFound,
+		},
+		{
+			Name:     "Conflict",
+			Err:      NewServiceErrorResponse(ServiceErrorDefinition{Code: "CONFLICT", Status: http.StatusConflict}),
+			Category: ErrConflict,
+		},
+		{
+			Name:     "ServiceErrorConflict",
+			Err:      NewServiceError[*common.Link](ServiceErrorDefinition{Code: "CONFLICT", Status: http.StatusConflict}, nil),
+			Category: ErrConflict,
+		},
+		{
+			Name:     "Throttled",
+			Err:      newTestErrorResponse(t, http.StatusTooManyRequests, true, `{}`),
+			Category: ErrThrottled,
+		},
+		{
+			Name:     "Unavailable",
+			Err:      newTestErrorResponse(t, http.StatusServiceUnavailable, false, `unavailable`),
+			Category: ErrUnavailable,
+		},
+		{
+			Name:     "BadRequest",
+			Err:      &IllegalEnumConstant{Enum: "Enum", Constant: 42},
+			Category: ErrBadRequest,
+		},
+		{
+			Name:     "InternalServerError",
+			Err:      newTestErrorResponse(t, http.StatusInternalServerError, true, `{"status":500}`),
+			Category: nil,
+		},
+		{
+			Name:     "Timeout",
+			Err:      &url.Error{Op: "Get", URL: "/", Err: timeoutError{}},
+			Category: ErrTimeout,
+		},
+		{
+			Name:     "Transport",
+			Err:      &url.Error{Op: "Get", URL: "/", Err: errors.New("connection refused")},
+			Category: ErrTransport,
+		},
+		{
+			Name:     "TransportErrorTimeout",
+			Err:      &TransportError{Err: &url.Error{Op: "Get", URL: "/", Err: context.DeadlineExceeded}},
+			Category: ErrTimeout,
+		},
+		{
+			Name:     "TransportError",
+			Err:      &TransportError{Err: &url.Error{Op: "Get", URL: "/", Err: errors.New("connection refused")}},
+			Category: ErrTransport,
+		},
+		{
+			Name:     "NilQueryParams",
+			Err:      NilQueryParams,
+			Category: ErrBadRequest,
+		},
+		{
+			Name:     "Deserialization",
+			Err:      fmt.Errorf("wrapped: %w", &restlicodec.DeserializationError{Scope: "foo", Err: errors.New("bad")}),
+			Category: ErrDeserialization,
+		},
+		{
+			Name:     "UnknownEnumValue",
+			Err:      fmt.Errorf("wrapped: %w", &UnknownEnumValue{Enum: "Enum", Value: "FOO"}),
+			Category: ErrDeserialization,
+		},
+	}
+
+	categories := []*ErrorCategory{ErrNotFound, ErrConflict, ErrThrottled, ErrUnavailable, ErrTimeout, ErrBadRequest,
+		ErrDeserialization, ErrTransport}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			require.Equal(t, test.Category, CategoryOf(test.Err))
+
+			var categorized CategorizedError
+			if !errors.As(test.Err, &categorized) {
+				return
+			}
+			for _, c := range categories {
+				require.Equal(t, c == test.Category, errors.Is(test.Err, c), c.String())
+			}
+		})
+	}
+}
+
+func TestClientTransportErrors(t *testing.T) {
+	c := &Client{
+		Client: &http.Client{Transport: failingTransport{}},
+		HostnameResolver: &SimpleHostnameResolver{Hostname: &url.URL{
+			Scheme: "http",
+			Host:   "localhost",
+		}},
+	}
+
+	req, err := NewGetRequest(c, context.Background(), ResourcePathString("/collection"), nil, Method_get)
+	require.NoError(t, err)
+	_, err = DoAndIgnore(c, req)
+	require.ErrorIs(t, err, ErrTransport)
+	require.NotErrorIs(t, err, ErrTimeout)
+	var urlErr *url.Error
+	require.ErrorAs(t, err, &urlErr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	req, err = NewGetRequest(c, ctx, ResourcePathString("/collection"), nil, Method_get)
+	require.NoError(t, err)
+	_, err = DoAndIgnore(c, req)
+	require.ErrorIs(t, err, ErrTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotErrorIs(t, err, ErrTransport)
+}
+
+// failingTransport fails all requests, or returns the error of the request's context if it is done
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("connection refused")
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestErrorAccessors(t *testing.T) {
+	err := newTestErrorResponse(t, http.StatusNotFound, true, `{"status":404,"code":"NO_SUCH_ENTITY"}`)
+	err = fmt.Errorf("wrapped: %w", err)
+
+	status, ok := ErrorStatus(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusNotFound, status)
+
+	code, ok := ErrorServiceCode(err)
+	require.True(t, ok)
+	require.Equal(t, "NO_SUCH_ENTITY", code)
+
+	req := ErrorRequest(err)
+	require.NotNil(t, req)
+	require.Equal(t, "/collection/1", req.URL.Path)
+
+	err = newTestErrorResponse(t, http.StatusBadGateway, false, ``)
+	status, ok = ErrorStatus(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadGateway, status)
+	_, ok = ErrorServiceCode(err)
+	require.False(t, ok)
+
+	_, ok = ErrorStatus(errors.New("not a rest.li error"))
+	require.False(t, ok)
+	require.Nil(t, ErrorRequest(errors.New("not a rest.li error")))
+}
@@ -0,0 +1,150 @@
+package restli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+// ErrorPolicy controls how a Server turns errors into the rest.li error responses sent to its callers. By default,
+// errors returned by resources that are not rest.li errors (i.e. not a *common.ErrorResponse or a *ServiceError) are
+// sent as 500s whose message includes the error's text, and recovered panics are sent along with the full stack trace
+// of the panic. An ErrorPolicy applies uniformly to all the methods, finders, batch finders and actions of a Server,
+// as well as to the errors returned by its Filters.
+type ErrorPolicy struct {
+	// RedactStackTraces removes the StackTrace of all error responses, including those of recovered panics.
+	RedactStackTraces bool
+	// RedactExceptionClasses removes the ExceptionClass of all error responses.
+	RedactExceptionClasses bool
+	// Mappings are checked in order against errors that are not rest.li errors, and the first ErrorMapping that
+	// matches determines the status and service error code of the error response. Errors that match none of the
+	// mappings are sent with their default status, which is usually a 500.
+	Mappings []ErrorMapping
+	// SanitizeMessage, if non-nil, is called with the message of every error response generated from an error that is
+	// not a rest.li error (including recovered panics) and whose ErrorMapping, if any, does not declare a message. The
+	// returned message is sent instead, e.g. to avoid leaking the internals of the server to external callers.
+	SanitizeMessage func(err error, message string) string
+	// CorrelationID, if non-nil, is called once for every request that fails, and the returned ID is sent as the
+	// RequestId of the error response (unless the resource already set it). It is also logged alongside recovered
+	// panics, such that errors seen by callers can be correlated with the server's logs.
+	CorrelationID func(ctx *RequestContext) string
+}
+
+// ErrorMapping maps errors returned by resources to a status and service error code.
+type ErrorMapping struct {
+	// Matches returns true if the given error should be mapped to Definition.
+	Matches func(err error) bool
+	// Definition determines the status, service error code and message of the error response. If Definition.Code is
+	// empty, the error response does not have a service error code. If Definition.Message is empty, the error's
+	// message (sanitized by ErrorPolicy.SanitizeMessage if set) is sent instead. Note that if the resource declares its
+	// service errors (see DeclareServiceErrors), Definition.Code must be declared as well.
+	Definition ServiceErrorDefinition
+}
+
+// MapError returns an ErrorMapping that matches all errors that are target according to errors.Is, e.g. sentinel
+// errors like sql.ErrNoRows.
+func MapError(target error, definition ServiceErrorDefinition) ErrorMapping {
+	return ErrorMapping{
+		Matches: func(err error) bool {
+			return errors.Is(err, target)
+		},
+		Definition: definition,
+	}
+}
+
+// MapErrorType returns an ErrorMapping that matches all errors that can be converted to E according to errors.As.
+func MapErrorType[E error](definition ServiceErrorDefinition) ErrorMapping {
+	return ErrorMapping{
+		Matches: func(err error) bool {
+			var target E
+			return errors.As(err, &target)
+		},
+		Definition: definition,
+	}
+}
+
+// SetErrorPolicy sets the ErrorPolicy of the given Server. A nil policy restores the default behavior.
+func SetErrorPolicy(s Server, policy *ErrorPolicy) {
+	s.subNode(nil).rootNode.errorPolicy = policy
+}
+
+// wrap is equivalent to newErrorResponsef, except the resulting error response is subject to the policy's mappings
+// and message sanitization. The policy may be nil, in which case it is exactly equivalent to newErrorResponsef.
+func (p *ErrorPolicy) wrap(cause error, status int, format string, a ...any) (restlicodec.Marshaler, error) {
+	if _, ok := asErrorResponse(cause); ok || cause == nil || p == nil {
+		return newErrorResponsef(cause, status, format, a...)
+	}
+
+	for _, m := range p.Mappings {
+		if !m.Matches(cause) {
+			continue
+		}
+		res := NewServiceErrorResponse(m.Definition)
+		if res.Message == nil {
+			res.Message = p.sanitize(cause, fmt.Sprintf(format, append(a, cause)...))
+		}
+		return nil, res
+	}
+
+	_, err := newErrorResponsef(cause, status, format, a...)
+	res := err.(*common.ErrorResponse)
+	res.Message = p.sanitize(cause, *res.Message)
+	return nil, res
+}
+
+// recovered returns the error response sent for the given recovered panic
+func (p *ErrorPolicy) recovered(r any, stack string) *common.ErrorResponse {
+	message := fmt.Sprint(r)
+	res := &common.ErrorResponse{
+		Status:     Int32Pointer(http.StatusInternalServerError),
+		Message:    StringPointer(message),
+		StackTrace: &stack,
+	}
+	if p != nil {
+		cause, ok := r.(error)
+		if !ok {
+			cause = errors.New(message)
+		}
+		res.Message = p.sanitize(cause, message)
+	}
+	return res
+}
+
+func (p *ErrorPolicy) sanitize(cause error, message string) *string {
+	if p.SanitizeMessage != nil {
+		message = p.SanitizeMessage(cause, message)
+	}
+	return &message
+}
+
+// apply redacts the given error response and attaches the request's correlation ID to it, according to the policy
+func (p *ErrorPolicy) apply(ctx *RequestContext, res *common.ErrorResponse) {
+	if p == nil {
+		return
+	}
+	if p.RedactStackTraces {
+		res.StackTrace = nil
+	}
+	if p.RedactExceptionClasses {
+		res.ExceptionClass = nil
+	}
+	if id := ctx.correlationID(); id != "" && res.RequestId == nil {
+		res.RequestId = &id
+	}
+}
+
+// correlationID returns the ID generated by ErrorPolicy.CorrelationID for this request, if any. The ID is only
+// generated once per request.
+func (c *RequestContext) correlationID() string {
+	if c.errorPolicy == nil || c.errorPolicy.CorrelationID == nil {
+		return ""
+	}
+	if c.errorCorrelationID == nil {
+		id := c.errorPolicy.CorrelationID(c)
+		c.errorCorrelationID = &id
+	}
+	return *c.errorCorrelationID
+}
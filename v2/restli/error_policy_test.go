@@ -0,0 +1,151 @@
+package restli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestNotFound = errors.New("row not found in table secrets")
+
+type testQuotaError struct{}
+
+func (testQuotaError) Error() string {
+	return "quota exceeded for tenant 42"
+}
+
+var (
+	testNotFoundDefinition = ServiceErrorDefinition{Code: "NOT_FOUND", Status: http.StatusNotFound, Message: "Not found"}
+	testQuotaDefinition    = ServiceErrorDefinition{Code: "QUOTA", Status: http.StatusTooManyRequests}
+)
+
+// newErrorPolicyTestServer returns a Server whose get, finder and action all return the error returned by f, or panic
+// if f panics
+func newErrorPolicyTestServer(policy *ErrorPolicy, f func() error) Server {
+	s := NewServer()
+	RegisterGet(s, testCollectionSegments,
+		func(*RequestContext, *testEntityResourcePath, common.EmptyRecord) (validatedName, error) {
+			return "", f()
+		})
+	RegisterFinder(s, testCollectionSegments, "all",
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Elements[validatedName], error) {
+			return nil, f()
+		})
+	RegisterAction(s, testCollectionSegments, "do",
+		func(*RequestContext, testResourcePath, common.EmptyRecord) error {
+			return f()
+		})
+	SetErrorPolicy(s, policy)
+	return s
+}
+
+func serveErrorPolicyTestRequests(t *testing.T, s Server) (errs []*Error) {
+	h := s.Handler()
+	for _, r := range []struct {
+		method, target string
+		restLiMethod   Method
+	}{
+		{http.MethodGet, "/collection/1", Method_get},
+		{http.MethodGet, "/collection?q=all", Method_finder},
+		{http.MethodPost, "/collection?action=do", Method_action},
+	} {
+		res := serveTestRequest(h, newTestRequest(r.method, r.target, r.restLiMethod, ""))
+		var restLiErr *Error
+		require.True(t, errors.As(IsErrorResponse(res.Result()), &restLiErr), r.target)
+		errs = append(errs, restLiErr)
+	}
+	return errs
+}
+
+func TestErrorPolicy(t *testing.T) {
+	policy := &ErrorPolicy{
+		RedactStackTraces: true,
+		Mappings: []ErrorMapping{
+			MapError(errTestNotFound, testNotFoundDefinition),
+			MapErrorType[testQuotaError](testQuotaDefinition),
+		},
+		SanitizeMessage: func(error, string) string {
+			return "Internal error"
+		},
+		CorrelationID: func(*RequestContext) string {
+			return "correlation-id"
+		},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(nil, func() error {
+			return errTestNotFound
+		})) {
+			require.Contains(t, *err.Message, errTestNotFound.Error())
+			require.Nil(t, err.RequestId)
+		}
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(nil, func() error {
+			panic("secret")
+		})) {
+			require.Equal(t, http.StatusInternalServerError, err.Response.StatusCode)
+			require.Equal(t, "secret", *err.Message)
+			require.NotNil(t, err.StackTrace)
+		}
+	})
+
+	t.Run("sentinel", func(t *testing.T) {
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(policy, func() error {
+			return fmt.Errorf("lookup failed: %w", errTestNotFound)
+		})) {
+			require.Equal(t, http.StatusNotFound, err.Response.StatusCode)
+			require.True(t, IsServiceError(err, testNotFoundDefinition.Code))
+			require.Equal(t, testNotFoundDefinition.Message, *err.Message)
+			require.Equal(t, "correlation-id", *err.RequestId)
+		}
+	})
+
+	t.Run("type", func(t *testing.T) {
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(policy, func() error {
+			return testQuotaError{}
+		})) {
+			require.Equal(t, http.StatusTooManyRequests, err.Response.StatusCode)
+			require.True(t, IsServiceError(err, testQuotaDefinition.Code))
+			require.Equal(t, "Internal error", *err.Message)
+		}
+	})
+
+	t.Run("unmapped", func(t *testing.T) {
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(policy, func() error {
+			return errors.New("connection refused to 10.0.0.1")
+		})) {
+			require.Equal(t, "Internal error", *err.Message)
+			require.Nil(t, err.Code)
+			require.Equal(t, "correlation-id", *err.RequestId)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(policy, func() error {
+			panic("secret")
+		})) {
+			require.Equal(t, http.StatusInternalServerError, err.Response.StatusCode)
+			require.Equal(t, "Internal error", *err.Message)
+			require.Nil(t, err.StackTrace)
+			require.Equal(t, "correlation-id", *err.RequestId)
+		}
+	})
+
+	t.Run("rest.li errors", func(t *testing.T) {
+		// rest.li errors are not mapped nor sanitized, but are still redacted
+		for _, err := range serveErrorPolicyTestRequests(t, newErrorPolicyTestServer(policy, func() error {
+			return &common.ErrorResponse{
+				Status:     Int32Pointer(http.StatusConflict),
+				Message:    StringPointer("Conflict"),
+				StackTrace: StringPointer("stack"),
+			}
+		})) {
+			require.Equal(t, http.StatusConflict, err.Response.StatusCode)
+			require.Equal(t, "Conflict", *err.Message)
+			require.Nil(t, err.StackTrace)
+		}
+	})
+}
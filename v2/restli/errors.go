@@ -1,6 +1,8 @@
 package restli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -21,6 +23,30 @@ type Error struct {
 	ResponseBody []byte         `json:"-"`
 }
 
+// Category returns the ErrorCategory corresponding to the error's status.
+func (e *Error) Category() *ErrorCategory {
+	if e.Status != nil {
+		return statusCategory(int(*e.Status))
+	}
+	if e.Response != nil {
+		return statusCategory(e.Response.StatusCode)
+	}
+	return nil
+}
+
+func (e *Error) Is(target error) bool {
+	return isCategory(e, target)
+}
+
+// Unwrap returns the DeserializationError, if any.
+func (e *Error) Unwrap() error {
+	return e.DeserializationError
+}
+
+func (e *Error) response() *http.Response {
+	return e.Response
+}
+
 // UnexpectedStatusCodeError is returned by the Do* methods when the target rest.li service responded with non-2xx code
 // but did not set the expected X-RestLi-Error-Response header.
 type UnexpectedStatusCodeError struct {
@@ -34,6 +60,23 @@ func (u *UnexpectedStatusCodeError) Error() string {
 	return fmt.Sprintf("unexpected response code from %s: %s", u.Response.Request.URL, u.Response.Status)
 }
 
+// Category returns the ErrorCategory corresponding to the response's status code.
+func (u *UnexpectedStatusCodeError) Category() *ErrorCategory {
+	return statusCategory(u.Response.StatusCode)
+}
+
+func (u *UnexpectedStatusCodeError) Is(target error) bool {
+	return isCategory(u, target)
+}
+
+func (u *UnexpectedStatusCodeError) Unwrap() error {
+	return nil
+}
+
+func (u *UnexpectedStatusCodeError) response() *http.Response {
+	return u.Response
+}
+
 // IsErrorResponse checks the contents of the given http.Response and if the X-RestLi-Error-Response is set to `true`,
 // parses the body of the response into a Error. If the header is not set, but the status code isn't a 2xx code,
 // an UnexpectedStatusCodeError will be returned instead. Note that an UnexpectedStatusCodeError contains the
@@ -77,14 +120,34 @@ func IsErrorResponse(res *http.Response) error {
 }
 
 // UnsupportedRestLiProtocolVersion is returned when the server returns a version other than the requested one, which is
-// always ProtocolVersion.
+// ProtocolVersion unless the service is configured to use ProtocolVersion1 (see Client.ProtocolVersions).
 type UnsupportedRestLiProtocolVersion struct {
-	ReturnedVersion string
+	ReturnedVersion  string
+	RequestedVersion string
+	// The raw response that returned the unsupported version. Note that its body has already been closed
+	Response *http.Response
 }
 
 func (u *UnsupportedRestLiProtocolVersion) Error() string {
-	return fmt.Sprintf("go-restli: Unsupported rest.li protocol version: %s (the only supported version is %s)",
-		u.ReturnedVersion, ProtocolVersion)
+	return fmt.Sprintf("go-restli: Unsupported rest.li protocol version: %s (the requested version is %s)",
+		u.ReturnedVersion, u.RequestedVersion)
+}
+
+// Category always returns ErrTransport, since the server cannot be talked to.
+func (u *UnsupportedRestLiProtocolVersion) Category() *ErrorCategory {
+	return ErrTransport
+}
+
+func (u *UnsupportedRestLiProtocolVersion) Is(target error) bool {
+	return isCategory(u, target)
+}
+
+func (u *UnsupportedRestLiProtocolVersion) Unwrap() error {
+	return nil
+}
+
+func (u *UnsupportedRestLiProtocolVersion) response() *http.Response {
+	return u.Response
 }
 
 // CreateResponseHasNoEntityHeaderError is used specifically when a Create request succeeds but the resource
@@ -98,7 +161,80 @@ func (c CreateResponseHasNoEntityHeaderError) Error() string {
 	return "go-restli: response from CREATE request did not specify a " + IDHeader + " header"
 }
 
-var NilQueryParams = fmt.Errorf("go-restli: Query params cannot be nil")
+// Category always returns ErrDeserialization, since the response is incomplete.
+func (c CreateResponseHasNoEntityHeaderError) Category() *ErrorCategory {
+	return ErrDeserialization
+}
+
+func (c CreateResponseHasNoEntityHeaderError) Is(target error) bool {
+	return isCategory(c, target)
+}
+
+func (c CreateResponseHasNoEntityHeaderError) Unwrap() error {
+	return nil
+}
+
+func (c CreateResponseHasNoEntityHeaderError) response() *http.Response {
+	return c.Response
+}
+
+// TransportError is returned by the Do* methods when the request could not be sent or its response could not be read,
+// e.g. because the connection was refused or the request's context expired. Err is usually a *url.Error.
+type TransportError struct {
+	Err error
+}
+
+// newTransportError wraps the given error in a TransportError, unless it is already categorized
+func newTransportError(err error) error {
+	var categorized CategorizedError
+	if errors.As(err, &categorized) {
+		return err
+	}
+	return &TransportError{Err: err}
+}
+
+func (t *TransportError) Error() string {
+	return t.Err.Error()
+}
+
+// Category returns ErrTimeout if the request timed out, and ErrTransport otherwise.
+func (t *TransportError) Category() *ErrorCategory {
+	var timeout interface{ Timeout() bool }
+	if errors.Is(t.Err, context.DeadlineExceeded) || (errors.As(t.Err, &timeout) && timeout.Timeout()) {
+		return ErrTimeout
+	}
+	return ErrTransport
+}
+
+func (t *TransportError) Is(target error) bool {
+	return isCategory(t, target)
+}
+
+func (t *TransportError) Unwrap() error {
+	return t.Err
+}
+
+type nilQueryParamsError struct{}
+
+func (nilQueryParamsError) Error() string {
+	return "go-restli: Query params cannot be nil"
+}
+
+// Category always returns ErrBadRequest, since the request cannot be sent.
+func (n nilQueryParamsError) Category() *ErrorCategory {
+	return ErrBadRequest
+}
+
+func (n nilQueryParamsError) Is(target error) bool {
+	return isCategory(n, target)
+}
+
+func (nilQueryParamsError) Unwrap() error {
+	return nil
+}
+
+// NilQueryParams is returned by the generated clients when nil query params are given to a finder or batch finder.
+var NilQueryParams error = nilQueryParamsError{}
 
 type IllegalEnumConstant struct {
 	Enum     string
@@ -109,6 +245,19 @@ func (i *IllegalEnumConstant) Error() string {
 	return fmt.Sprintf("go-restli: Illegal constant for %q enum: %d", i.Enum, i.Constant)
 }
 
+// Category always returns ErrBadRequest, since an illegal constant cannot be sent.
+func (i *IllegalEnumConstant) Category() *ErrorCategory {
+	return ErrBadRequest
+}
+
+func (i *IllegalEnumConstant) Is(target error) bool {
+	return isCategory(i, target)
+}
+
+func (i *IllegalEnumConstant) Unwrap() error {
+	return nil
+}
+
 type UnknownEnumValue struct {
 	Enum  string
 	Value string
@@ -117,3 +266,16 @@ type UnknownEnumValue struct {
 func (u *UnknownEnumValue) Error() string {
 	return fmt.Sprintf("go-restli: Unknown enum value for %q: %q", u.Enum, u.Value)
 }
+
+// Category always returns ErrDeserialization, since unknown values are only encountered when deserializing.
+func (u *UnknownEnumValue) Category() *ErrorCategory {
+	return ErrDeserialization
+}
+
+func (u *UnknownEnumValue) Is(target error) bool {
+	return isCategory(u, target)
+}
+
+func (u *UnknownEnumValue) Unwrap() error {
+	return nil
+}
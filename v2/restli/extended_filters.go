@@ -0,0 +1,127 @@
+package restli
+
+import (
+	"net/http"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+// ExtendedFilter is a Filter that is also called with the decoded inputs of the request before the resource is called,
+// and with its outcome once the resource returns. Filters passed to NewServer that implement ExtendedFilter are
+// detected automatically. FilterRequest is called in the same order as PreRequest, and FilterResponse in the same order
+// as PostRequest (i.e. the inverse order), before PostRequest is called.
+type ExtendedFilter interface {
+	Filter
+	// FilterRequest is called once the request's inputs are decoded, right before the resource is called. It is only
+	// called if the PreRequest method of all the filters succeeded, and the decoded inputs can be modified in place. If
+	// it returns a non-nil FilterResult, the request is short-circuited: the resource and the remaining filters are not
+	// called, and the result is returned as-is (through the FilterResponse method of the previous filters). Otherwise,
+	// the request proceeds as usual.
+	FilterRequest(ctx *RequestContext, req *DecodedRequest) *FilterResult
+	// FilterResponse is called with the outcome of every request whose PreRequest method was called, unless the request
+	// was short-circuited by this filter or a filter that precedes it. The given DecodedRequest is nil if the request
+	// failed before its inputs could be decoded. The result can be modified in place, e.g. to replace the response body
+	// or to map the error to a different one.
+	FilterResponse(ctx *RequestContext, req *DecodedRequest, res *FilterResult)
+}
+
+// DecodedRequest holds the decoded inputs of a request, as passed to ExtendedFilter. The method of the request and the
+// name of the finder, batch finder or action (if any) can be read from the request's context with
+// GetMethodFromContext, GetFinderNameFromContext and GetActionNameFromContext.
+type DecodedRequest struct {
+	// ResourcePath is the decoded resource path of the request, i.e. the RP type parameter of the function the method
+	// was registered with.
+	ResourcePath any
+	// QueryParams are the decoded query parameters of the request, i.e. the QP type parameter of the function the method
+	// was registered with. It is nil for actions.
+	QueryParams any
+	// Keys are the keys of batch requests, as a slice of the resource's key type. It is nil for all other requests.
+	Keys any
+	// Entity is the decoded body of the request, if any: the entity or entities of create, update and partial_update
+	// methods (and their batch variants), the parameters of an action, or the criteria of a batch finder.
+	Entity any
+}
+
+// FilterResult is the outcome of a request, as seen by ExtendedFilter.
+type FilterResult struct {
+	// Status is the status of the response. If Err is non-nil, it is the status of the error (or 500 if the error does
+	// not specify a status) and is ignored, since the status of an error response is always that of the error. Filters
+	// that clear Err should therefore also set Status. Otherwise, it defaults to RequestContext.ResponseStatus.
+	Status int
+	// Body is the response body, which is ignored if Err is non-nil.
+	Body restlicodec.Marshaler
+	// Err is the error returned by the request, if any. Errors that are not a *common.ErrorResponse (or do not wrap
+	// one) are returned as a 500 (Internal Server Error).
+	Err error
+}
+
+// batchKeysAndQueryParams is implemented by the wrapper of the query params of batch methods, which also holds the
+// request's keys
+type batchKeysAndQueryParams interface {
+	unwrap() (keys any, qp any)
+}
+
+func newDecodedRequest(rp, qp, entity any) *DecodedRequest {
+	req := &DecodedRequest{ResourcePath: rp, QueryParams: qp, Entity: entity}
+	if b, ok := qp.(batchKeysAndQueryParams); ok {
+		req.Keys, req.QueryParams = b.unwrap()
+	}
+	return req
+}
+
+// filterRequest calls the FilterRequest method of the Server's ExtendedFilters with the given decoded inputs, then
+// calls the resource with h unless one of the filters short-circuits the request
+func filterRequest(
+	ctx *RequestContext,
+	req *DecodedRequest,
+	h func() (responseBody restlicodec.Marshaler, err error),
+) (responseBody restlicodec.Marshaler, err error) {
+	ctx.decodedRequest = req
+	for i, f := range ctx.filters[:ctx.filtered] {
+		ef, ok := f.(ExtendedFilter)
+		if !ok {
+			continue
+		}
+		if res := ef.FilterRequest(ctx, req); res != nil {
+			// Only the filters preceding this one see the response
+			ctx.filtered = i
+			if res.Status != 0 {
+				ctx.ResponseStatus = res.Status
+			}
+			return res.Body, res.Err
+		}
+	}
+	return h()
+}
+
+// filterResponse calls the FilterResponse method of the Server's ExtendedFilters with the outcome of the request, in
+// the inverse order
+func filterResponse(
+	ctx *RequestContext,
+	responseBody restlicodec.Marshaler,
+	err error,
+) (restlicodec.Marshaler, error) {
+	for i := ctx.filtered - 1; i >= 0; i-- {
+		ef, ok := ctx.filters[i].(ExtendedFilter)
+		if !ok {
+			continue
+		}
+
+		res := &FilterResult{Status: ctx.ResponseStatus, Body: responseBody, Err: err}
+		if err != nil {
+			if status, ok := ErrorStatus(err); ok {
+				res.Status = status
+			} else {
+				res.Status = http.StatusInternalServerError
+			}
+		}
+
+		ef.FilterResponse(ctx, ctx.decodedRequest, res)
+
+		responseBody, err = res.Body, res.Err
+		if err == nil {
+			ctx.ResponseStatus = res.Status
+		}
+	}
+	return responseBody, err
+}
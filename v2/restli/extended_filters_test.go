@@ -0,0 +1,191 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingFilter is an ExtendedFilter that records the calls made to it, and whose FilterRequest and FilterResponse
+// methods can be overridden
+type recordingFilter struct {
+	name           string
+	calls          *[]string
+	filterRequest  func(*RequestContext, *DecodedRequest) *FilterResult
+	filterResponse func(*RequestContext, *DecodedRequest, *FilterResult)
+}
+
+func (f *recordingFilter) PreRequest(*http.Request) (context.Context, error) {
+	*f.calls = append(*f.calls, f.name+".PreRequest")
+	return nil, nil
+}
+
+func (f *recordingFilter) PostRequest(context.Context, http.Header) error {
+	*f.calls = append(*f.calls, f.name+".PostRequest")
+	return nil
+}
+
+func (f *recordingFilter) FilterRequest(ctx *RequestContext, req *DecodedRequest) *FilterResult {
+	*f.calls = append(*f.calls, f.name+".FilterRequest")
+	if f.filterRequest != nil {
+		return f.filterRequest(ctx, req)
+	}
+	return nil
+}
+
+func (f *recordingFilter) FilterResponse(ctx *RequestContext, req *DecodedRequest, res *FilterResult) {
+	*f.calls = append(*f.calls, f.name+".FilterResponse")
+	if f.filterResponse != nil {
+		f.filterResponse(ctx, req, res)
+	}
+}
+
+var errExtendedFilterTest = errors.New("not found")
+
+func newExtendedFilterTestServer(filters ...Filter) Server {
+	s := NewServer(filters...)
+	RegisterGet(s, testCollectionSegments,
+		func(_ *RequestContext, rp *testEntityResourcePath, _ common.EmptyRecord) (validatedName, error) {
+			if rp.Id == 404 {
+				return "", errExtendedFilterTest
+			}
+			return "entity", nil
+		})
+	RegisterBatchGet(s, testCollectionSegments,
+		func(_ *RequestContext, _ testResourcePath, keys []int64, _ *SliceBatchQueryParams[int64]) (*common.BatchResponse[int64, validatedName], error) {
+			res := new(common.BatchResponse[int64, validatedName])
+			for _, k := range keys {
+				res.AddResult(k, "entity")
+			}
+			return res, nil
+		})
+	return s
+}
+
+func TestExtendedFilters(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		var calls []string
+		var decoded *DecodedRequest
+		s := newExtendedFilterTestServer(
+			&recordingFilter{name: "1", calls: &calls},
+			&recordingFilter{name: "2", calls: &calls,
+				filterResponse: func(ctx *RequestContext, req *DecodedRequest, res *FilterResult) {
+					decoded = req
+					require.Equal(t, http.StatusOK, res.Status)
+					require.Equal(t, validatedName("entity"), res.Body)
+					require.NoError(t, res.Err)
+				}},
+		)
+
+		res := serveTestRequest(s.Handler(), newTestRequest(http.MethodGet, "/collection/1", Method_get, ""))
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []string{
+			"1.PreRequest", "2.PreRequest",
+			"1.FilterRequest", "2.FilterRequest",
+			"2.FilterResponse", "1.FilterResponse",
+			"2.PostRequest", "1.PostRequest",
+		}, calls)
+		require.Equal(t, &DecodedRequest{
+			ResourcePath: &testEntityResourcePath{Id: 1},
+			QueryParams:  common.EmptyRecord{},
+		}, decoded)
+	})
+
+	t.Run("batchKeys", func(t *testing.T) {
+		var calls []string
+		var decoded *DecodedRequest
+		s := newExtendedFilterTestServer(&recordingFilter{name: "1", calls: &calls,
+			filterRequest: func(_ *RequestContext, req *DecodedRequest) *FilterResult {
+				decoded = req
+				return nil
+			}})
+
+		req := newTestRequest(http.MethodGet, "/collection?ids=List(1,2)", Method_batch_get, "")
+		res := serveTestRequest(s.Handler(), req)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []int64{1, 2}, decoded.Keys)
+		require.Equal(t, &SliceBatchQueryParams[int64]{}, decoded.QueryParams)
+	})
+
+	t.Run("shortCircuit", func(t *testing.T) {
+		var calls []string
+		s := newExtendedFilterTestServer(
+			&recordingFilter{name: "1", calls: &calls,
+				filterResponse: func(_ *RequestContext, _ *DecodedRequest, res *FilterResult) {
+					require.Equal(t, http.StatusAccepted, res.Status)
+					require.Equal(t, validatedName("cached"), res.Body)
+				}},
+			&recordingFilter{name: "2", calls: &calls,
+				filterRequest: func(*RequestContext, *DecodedRequest) *FilterResult {
+					return &FilterResult{Status: http.StatusAccepted, Body: validatedName("cached")}
+				}},
+			&recordingFilter{name: "3", calls: &calls},
+		)
+
+		res := serveTestRequest(s.Handler(), newTestRequest(http.MethodGet, "/collection/404", Method_get, ""))
+		require.Equal(t, http.StatusAccepted, res.Code)
+		require.Equal(t, `"cached"`, res.Body.String())
+		require.Equal(t, []string{
+			"1.PreRequest", "2.PreRequest", "3.PreRequest",
+			"1.FilterRequest", "2.FilterRequest",
+			"1.FilterResponse",
+			"3.PostRequest", "2.PostRequest", "1.PostRequest",
+		}, calls)
+	})
+
+	t.Run("errorMapping", func(t *testing.T) {
+		var calls []string
+		s := newExtendedFilterTestServer(&recordingFilter{name: "1", calls: &calls,
+			filterResponse: func(_ *RequestContext, _ *DecodedRequest, res *FilterResult) {
+				// Hide the details of internal errors from callers
+				if res.Status == http.StatusInternalServerError {
+					res.Err = &common.ErrorResponse{
+						Status:  Int32Pointer(http.StatusServiceUnavailable),
+						Message: StringPointer("Try again later"),
+					}
+				}
+			}})
+
+		res := serveTestRequest(s.Handler(), newTestRequest(http.MethodGet, "/collection/404", Method_get, ""))
+		require.Equal(t, http.StatusServiceUnavailable, res.Code)
+		require.Contains(t, res.Body.String(), "Try again later")
+		require.NotContains(t, res.Body.String(), errExtendedFilterTest.Error())
+	})
+
+	t.Run("responseRewriting", func(t *testing.T) {
+		var calls []string
+		s := newExtendedFilterTestServer(&recordingFilter{name: "1", calls: &calls,
+			filterResponse: func(_ *RequestContext, _ *DecodedRequest, res *FilterResult) {
+				res.Err = nil
+				res.Status = http.StatusOK
+				res.Body = restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+					writer.WriteString("rewritten")
+					return nil
+				})
+			}})
+
+		res := serveTestRequest(s.Handler(), newTestRequest(http.MethodGet, "/collection/404", Method_get, ""))
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, `"rewritten"`, res.Body.String())
+	})
+
+	t.Run("undecodedRequest", func(t *testing.T) {
+		var calls []string
+		var decoded = new(DecodedRequest)
+		s := newExtendedFilterTestServer(&recordingFilter{name: "1", calls: &calls,
+			filterResponse: func(_ *RequestContext, req *DecodedRequest, res *FilterResult) {
+				decoded = req
+				require.Equal(t, http.StatusBadRequest, res.Status)
+			}})
+
+		res := serveTestRequest(s.Handler(), newTestRequest(http.MethodGet, "/collection/foo", Method_get, ""))
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.Nil(t, decoded)
+		require.Equal(t, []string{"1.PreRequest", "1.FilterResponse"}, calls)
+	})
+}
@@ -75,6 +75,12 @@ func registerFinder[RP ResourcePathUnmarshaler[RP], QP restlicodec.QueryParamsDe
 	if _, ok := p.finders[name]; ok {
 		log.Panicf("go-restli: Cannot register finder %q twice for %v", name, segments)
 	}
+	if _, ok := any(*new(RP)).(AssocKeyResourcePath); ok {
+		if !p.isCollection {
+			log.Panicf("go-restli: Cannot register assocKey finder %q on %v since it is not an association", name, segments)
+		}
+		p.assocKeyFinders[name] = true
+	}
 
 	p.finders[name] = func(
 		ctx *RequestContext,
@@ -95,9 +101,11 @@ func registerFinder[RP ResourcePathUnmarshaler[RP], QP restlicodec.QueryParamsDe
 			return newErrorResponsef(nil, http.StatusBadRequest, "Finders do not accept request bodies")
 		}
 
-		results, err := h(ctx, rp, queryParams)
+		results, err := filterRequest(ctx, newDecodedRequest(rp, queryParams, nil), func() (restlicodec.Marshaler, error) {
+			return h(ctx, rp, queryParams)
+		})
 		if err != nil {
-			return newErrorResponsef(err, http.StatusInternalServerError, "Finder %q failed: %s", name)
+			return ctx.errorPolicy.wrap(err, http.StatusInternalServerError, "Finder %q failed: %s", name)
 		}
 		return results, nil
 	}
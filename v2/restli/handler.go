@@ -2,15 +2,15 @@ package restli
 
 import (
 	"context"
-	"fmt"
-	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 
-	"github.com/PapaCharlie/go-restli/v2/restli/batchkeyset"
+	"github.com/PapaCharlie/go-restli/v2/restli/validation"
 	"github.com/PapaCharlie/go-restli/v2/restlicodec"
 	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
 )
@@ -23,8 +23,19 @@ type handler func(
 
 type rootNode struct {
 	*pathNode
-	prefix  string
-	filters []Filter
+	prefix   string
+	filters  []Filter
+	observer Observer
+	tracer   Tracer
+	// priorityClassifier classifies requests against ConcurrencyLimits, defaulting to HeaderPriorityClassifier if nil
+	priorityClassifier PriorityClassifier
+	// validationStatus is the status returned for request bodies that fail validation, or 0 if they are not validated
+	validationStatus int
+	errorPolicy      *ErrorPolicy
+	// routes are the precompiled routes of the root resources. They are built by Handler, or by the first call to
+	// ServeHTTP when the Server is used directly as an http.Handler.
+	routes     map[string]*route
+	routesOnce sync.Once
 }
 
 type pathNode struct {
@@ -34,6 +45,24 @@ type pathNode struct {
 	finders  map[string]handler
 	actions  map[string]handler
 	subNodes map[string]*pathNode
+
+	batchFinders map[string]handler
+	// assocKeyFinders holds the names of the finders of an association that are called on a partial association key
+	assocKeyFinders map[string]bool
+	// keyCoercers holds the coercers of the collection's alternative keys, by name
+	keyCoercers map[string]altKeyCoercer
+
+	limits       *Limits
+	methodLimits map[Method]Limits
+	// maxBatchSizes are the maximum batch sizes declared by the @MaxBatchSize annotations of the resource's methods,
+	// which methodLimits take precedence over
+	maxBatchSizes map[Method]int
+	// concurrencyLimiter and methodConcurrencyLimiters enforce the ConcurrencyLimits of the resource and its methods.
+	// They are shared by all copies of this node, such that they are observable through the Server.
+	concurrencyLimiter        *concurrencyLimiter
+	methodConcurrencyLimiters map[Method]*concurrencyLimiter
+	serviceErrors             map[methodKey]map[string]bool
+	schema                    *ResourceSchema
 }
 
 func copyMap[K comparable, V any](m map[K]V) map[K]V {
@@ -58,23 +87,51 @@ func (p *pathNode) clone() *pathNode {
 		rootNode:            p.rootNode,
 		methods:             copyMap(p.methods),
 		finders:             copyMap(p.finders),
+		batchFinders:        copyMap(p.batchFinders),
+		assocKeyFinders:     copyMap(p.assocKeyFinders),
+		keyCoercers:         copyMap(p.keyCoercers),
 		actions:             copyMap(p.actions),
 		subNodes:            copyCloneableMap(p.subNodes),
+		limits:              p.limits,
+		methodLimits:        copyMap(p.methodLimits),
+		maxBatchSizes:       copyMap(p.maxBatchSizes),
+
+		concurrencyLimiter:        p.concurrencyLimiter,
+		methodConcurrencyLimiters: copyMap(p.methodConcurrencyLimiters),
+
+		serviceErrors: copyMap(p.serviceErrors),
+		schema:        p.schema,
 	}
 }
 
 func (r *rootNode) Handler() http.Handler {
 	deepCopy := &rootNode{
-		prefix:  r.prefix,
-		filters: append([]Filter(nil), r.filters...),
+		prefix:   r.prefix,
+		filters:  append([]Filter(nil), r.filters...),
+		observer: r.observer,
+		tracer:   r.tracer,
+
+		priorityClassifier: r.priorityClassifier,
+		validationStatus:   r.validationStatus,
+		errorPolicy:        r.errorPolicy,
 	}
 	p := new(pathNode)
 	*p = *r.pathNode
 	p.rootNode = deepCopy
 	deepCopy.pathNode = p.clone()
+	deepCopy.compileRoutes()
 	return deepCopy
 }
 
+// compileRoutes builds the routes of the root resources, if they were not already built. Note that once built, the
+// routes do not reflect resources or limits registered afterwards.
+func (r *rootNode) compileRoutes() map[string]*route {
+	r.routesOnce.Do(func() {
+		r.routes = newRoutes(r.pathNode, nil, Limits{}.merge(r.limits), 0)
+	})
+	return r.routes
+}
+
 func (r *rootNode) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	path := req.URL.RawPath
 	if path == "" {
@@ -88,28 +145,76 @@ func (r *rootNode) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 
 	path = strings.TrimPrefix(path, r.prefix)
 
-	segments := strings.Split(path, "/")
-	if len(segments) == 0 || r.subNodes[segments[0]] == nil {
+	rootResource, _, _ := strings.Cut(path, "/")
+	root := r.compileRoutes()[rootResource]
+	if root == nil {
 		http.NotFound(res, req)
 		return
 	}
 
-	err := DecodeTunnelledQuery(req)
-	if err != nil {
-		http.Error(res, err.Error(), http.StatusBadRequest)
-		return
+	if tc, ok := ExtractTraceContext(req.Header); ok {
+		req = req.WithContext(WithTraceContext(req.Context(), tc))
+	}
+
+	var err error
+	var call *serverCall
+	if r.observer != nil {
+		call = newServerCall(r.observer, res, req, rootResource)
+		res = call.res
+		defer func() { call.end(err) }()
 	}
 
-	sub := r.subNodes[segments[0]]
 	ctx := &RequestContext{
 		Request:         req,
 		ResponseHeaders: res.Header(),
 		ResponseStatus:  http.StatusOK,
+		limits:          root.limits,
+		call:            call,
+		tracer:          r.tracer,
+		filters:         r.filters,
+
+		validationStatus: r.validationStatus,
+		errorPolicy:      r.errorPolicy,
 	}
+	defer func() {
+		if ctx.span != nil {
+			ctx.span.End(err)
+		}
+	}()
 
-	res.Header().Set(ProtocolVersionHeader, ProtocolVersion)
+	if req.Header.Get(canonicalProtocolVersionHeader) == ProtocolVersion1 {
+		ctx.protocolVersion = ProtocolVersion1
+	} else {
+		ctx.protocolVersion = ProtocolVersion
+	}
+	res.Header().Set(canonicalProtocolVersionHeader, ctx.protocolVersion)
 
-	responseBody, err := sub.receive(ctx, nil, nil, segments)
+	var responseBody restlicodec.Marshaler
+	err = decodeTunnelledQuery(req, ctx.limits)
+	if err != nil {
+		_, err = newErrorResponsef(err, http.StatusBadRequest, "Invalid tunnelled query: %s")
+	} else if ctx.protocolVersion == ProtocolVersion1 {
+		err = decodeProtocol1Request(req)
+		if err != nil {
+			_, err = newErrorResponsef(err, http.StatusBadRequest, "Invalid rest.li protocol 1.0.0 request: %s")
+		} else {
+			path = strings.TrimPrefix(ctx.RequestPath(), r.prefix)
+		}
+	}
+	if err == nil {
+		var rt *route
+		var entitySegments []validatedRor2String
+		var hasEntity bool
+		rt, entitySegments, hasEntity, err = r.match(path)
+		if err == nil {
+			responseBody, err = rt.receive(ctx, entitySegments, hasEntity)
+		}
+	}
+	if err == nil && len(ctx.responseAttachments) > 0 && !ctx.ResponseAttachmentsAccepted() {
+		_, err = newErrorResponsef(nil, http.StatusNotAcceptable,
+			"Response contains attachments but the request's %q header does not accept %s",
+			AcceptHeader, MultipartRelatedContentType)
+	}
 	if err == nil {
 		for i := len(r.filters) - 1; i >= 0; i-- {
 			err = r.filters[i].PostRequest(ctx.Request.Context(), res.Header())
@@ -119,108 +224,156 @@ func (r *rootNode) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	if errRes, ok := err.(*common.ErrorResponse); ok {
-		res.Header().Set(ErrorResponseHeader, "true")
-		responseBody = errRes
-		if errRes.Status != nil {
-			ctx.ResponseStatus = int(*errRes.Status)
-		} else {
-			ctx.ResponseStatus = http.StatusInternalServerError
-		}
-		if errRes.Message == nil {
-			errRes.Message = StringPointer(http.StatusText(int(*errRes.Status)))
-		}
+	if err != nil && ctx.errorPolicy != nil {
+		_, err = ctx.errorPolicy.wrap(err, http.StatusInternalServerError, "%s")
+	}
+
+	if errRes, ok := asErrorResponse(err); ok {
+		responseBody = prepareErrorResponse(res, ctx, errRes)
 	} else if err != nil {
 		http.Error(res, err.Error(), http.StatusInternalServerError)
 		return
+	} else {
+		responseBody = encodeProtocol1BatchResponse(ctx, responseBody)
 	}
 
-	if responseBody != nil {
-		w := restlicodec.NewCompactJsonWriter()
-		err = responseBody.MarshalRestLi(w)
-		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
-			return
-		} else {
-			data := []byte(w.Finalize())
-			res.Header().Set("Content-Type", "application/json")
-			res.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	writeResponse(res, ctx, responseBody)
+}
 
-			res.WriteHeader(ctx.ResponseStatus)
-			// not much we can do about this error
-			_, _ = res.Write(data)
-		}
+func prepareErrorResponse(res http.ResponseWriter, ctx *RequestContext, errRes errorResponse) errorResponse {
+	res.Header().Set(ErrorResponseHeader, "true")
+	e := errRes.errorResponse()
+	ctx.errorPolicy.apply(ctx, e)
+	if e.Status != nil {
+		ctx.ResponseStatus = int(*e.Status)
 	} else {
-		res.WriteHeader(ctx.ResponseStatus)
+		ctx.ResponseStatus = http.StatusInternalServerError
 	}
+	if e.Message == nil {
+		e.Message = StringPointer(http.StatusText(ctx.ResponseStatus))
+	}
+	return errRes
 }
 
-func (p *pathNode) receive(
-	ctx *RequestContext,
-	pathSegments []ResourcePathSegment,
-	entitySegments []validatedRor2String,
-	remainingSegments []string,
-) (responseBody restlicodec.Marshaler, err error) {
-	pathSegments = append(pathSegments, p.ResourcePathSegment)
-	hasEntity := false
-	if len(remainingSegments) >= 1 {
-		if p.isCollection && len(remainingSegments) > 1 {
-			err = restlicodec.ValidateRor2Input(remainingSegments[1])
-			if err != nil {
-				return newErrorResponsef(err, http.StatusNotFound, "Invalid path segment %q: %s", remainingSegments[1])
-			}
-			hasEntity = true
-			entitySegments = append(entitySegments, validatedRor2String(remainingSegments[1]))
+// writeResponse streams the serialized response body directly into the http.ResponseWriter. If the entire body fits
+// under the flush threshold, the Content-Length header is set and the body is written in one go. Otherwise, the body is
+// flushed as it is serialized and the response is sent using chunked encoding. Should serialization fail before
+// anything was flushed, a regular rest.li error is returned instead. If it fails after the response was partially
+// written, the connection is aborted, since there is no way to signal the error to the client otherwise.
+func writeResponse(res http.ResponseWriter, ctx *RequestContext, responseBody restlicodec.Marshaler) {
+	if _, ok := responseBody.(errorResponse); !ok && len(ctx.responseAttachments) > 0 {
+		writeResponseWithAttachments(res, ctx, responseBody)
+		return
+	}
 
-			remainingSegments = remainingSegments[2:]
-		} else {
-			remainingSegments = remainingSegments[1:]
+	if responseBody == nil {
+		res.WriteHeader(ctx.ResponseStatus)
+		return
+	}
+
+	res.Header().Set(ContentTypeHeader, ApplicationJsonContentType)
+	stream := &responseStream{ResponseWriter: res, status: ctx.ResponseStatus}
+	w := restlicodec.NewCompactJsonStreamWriter(stream, responseFlushThreshold)
+	err := responseBody.MarshalRestLi(w)
+	if err == nil {
+		if !w.Flushed() {
+			res.Header().Set("Content-Length", strconv.Itoa(w.Buffered()))
 		}
-		if len(remainingSegments) != 0 {
-			subResource := remainingSegments[0]
-			if sub, ok := p.subNodes[subResource]; ok {
-				return sub.receive(ctx, pathSegments, entitySegments, remainingSegments)
-			} else {
-				return newErrorResponsef(nil, http.StatusNotFound, "Unknown sub resource: %q", subResource)
-			}
+		// not much we can do about this error
+		_ = w.Flush()
+		return
+	}
+
+	if w.Flushed() {
+		if stream.err == nil {
+			log.Printf("go-restli: Failed to serialize response for %q after it was partially written: %s",
+				ctx.Request.URL, err)
 		}
+		panic(http.ErrAbortHandler)
 	}
 
-	restLiMethod := MethodNameMapping[ctx.Request.Header.Get(MethodHeader)]
-	httpMethod := ctx.Request.Method
-	params, err := restlicodec.ParseQueryParams(ctx.Request.URL.RawQuery)
-	if err != nil {
-		return nil, err
+	if _, ok := responseBody.(errorResponse); ok {
+		res.Header().Del(ContentTypeHeader)
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	var finder string
-	if q, ok := params["q"]; ok {
-		finder = q.String()
+	_, err = newErrorResponsef(err, http.StatusInternalServerError, "Failed to serialize response: %s")
+	errRes, _ := asErrorResponse(err)
+	writeResponse(res, ctx, prepareErrorResponse(res, ctx, errRes))
+}
+
+// responseFlushThreshold is the number of bytes after which a response will be flushed to the client
+const responseFlushThreshold = restlicodec.DefaultFlushThreshold
+
+// responseStream writes the response header on the first call to Write. Once more than responseFlushThreshold bytes
+// were written, it flushes the underlying http.ResponseWriter after each call to Write so that the client receives the
+// response as it is being serialized. Responses that fit under the threshold are therefore sent in one piece.
+type responseStream struct {
+	http.ResponseWriter
+	status        int
+	headerWritten bool
+	written       int
+	err           error
+}
+
+func (s *responseStream) Write(data []byte) (n int, err error) {
+	if !s.headerWritten {
+		s.ResponseWriter.WriteHeader(s.status)
+		s.headerWritten = true
 	}
+	n, err = s.ResponseWriter.Write(data)
+	s.written += n
+	if err != nil {
+		s.err = err
+		return n, err
+	}
+	if f, ok := s.ResponseWriter.(http.Flusher); ok && s.written > responseFlushThreshold {
+		f.Flush()
+	}
+	return n, nil
+}
+
+func (rt *route) receive(
+	ctx *RequestContext,
+	entitySegments []validatedRor2String,
+	hasEntity bool,
+) (responseBody restlicodec.Marshaler, err error) {
+	p := rt.node
+	ctx.limits = rt.limits
 
-	var action string
-	if a, ok := params["action"]; ok {
-		action = a.String()
+	if ctx.Request.Method == http.MethodOptions {
+		return p.options(rt.pathSegments)
 	}
 
+	restLiMethod := MethodNameMapping[ctx.Request.Header.Get(canonicalMethodHeader)]
+	httpMethod := ctx.Request.Method
+	params := parseRoutingParams(ctx.Request.URL.RawQuery)
+	finder, batchFinder, action := params.finder, params.batchFinder, params.action
+
 	if p.isCollection {
 		// For whatever reason rest.li makes the method header optional, because it supposedly can be inferred from the
 		// method itself and query params. The documentation (https://linkedin.github.io/rest.li/spec/protocol#message-headers)
-		// implies this procedure is unambiguous so the following assumes "q", "action" and "ids" are reserved query
+		// implies this procedure is unambiguous so the following assumes "q", "bq", "action" and "ids" are reserved query
 		// parameter names for their corresponding HTTP method to make the routing simpler. In practice, this isn't
 		// actually true since the Java implementation lets you define query parameters named "q" or "action" for
 		// methods like GET, but until this bites, this is how this logic will be implemented.
 		if restLiMethod == Method_Unknown {
-			hasIds := params[batchkeyset.EntityIDsField] != nil
+			hasIds := params.hasIds
 
 			switch httpMethod {
 			case http.MethodGet:
 				switch {
+				// assocKey finders are called on a partial association key, which is provided as the entity
+				case hasEntity && p.assocKeyFinders[finder]:
+					restLiMethod = Method_finder
 				// Only the GET method can specify an entity, so skip checking the query params
 				case hasEntity:
 					restLiMethod = Method_get
 				case finder != "":
 					restLiMethod = Method_finder
+				case batchFinder != "":
+					restLiMethod = Method_batch_finder
 				case hasIds:
 					restLiMethod = Method_batch_get
 				default:
@@ -249,7 +402,14 @@ func (p *pathNode) receive(
 			if !hasEntity {
 				return newErrorResponsef(nil, http.StatusBadRequest, "No entity provided for %q method", restLiMethod)
 			}
-		case Method_finder, Method_create, Method_batch_get, Method_batch_create, Method_batch_delete,
+		case Method_finder:
+			if hasEntity && !p.assocKeyFinders[finder] {
+				return newErrorResponsef(nil, http.StatusBadRequest, "Cannot provide an entity for %q", restLiMethod)
+			}
+			if !hasEntity && p.assocKeyFinders[finder] {
+				return newErrorResponsef(nil, http.StatusBadRequest, "No association key provided for finder %q", finder)
+			}
+		case Method_batch_finder, Method_create, Method_batch_get, Method_batch_create, Method_batch_delete,
 			Method_batch_update, Method_batch_partial_update, Method_get_all:
 			if hasEntity {
 				return newErrorResponsef(nil, http.StatusBadRequest, "Cannot provide an entity for %q", restLiMethod)
@@ -275,23 +435,33 @@ func (p *pathNode) receive(
 		}
 	}
 
-	newCtx := context.WithValue(ctx.Request.Context(), methodCtxKey, restLiMethod)
-	newCtx = context.WithValue(newCtx, resourcePathSegmentsCtxKey, pathSegments)
-	newCtx = context.WithValue(newCtx, entitySegmentsCtxKey, entitySegments)
+	if params.hasAltKey && p.isCollection {
+		entitySegments, err = p.coerceAltKeys(ctx, restLiMethod, params.altKey, entitySegments)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	var h handler
+	var name string
 	if restLiMethod == Method_finder {
 		h = p.finders[finder]
 		if h == nil {
 			return newErrorResponsef(nil, http.StatusBadRequest, "Finder %q not defined on %q", finder, p.name)
 		}
-		newCtx = context.WithValue(newCtx, finderNameCtxKey, finder)
+		name = finder
+	} else if restLiMethod == Method_batch_finder {
+		h = p.batchFinders[batchFinder]
+		if h == nil {
+			return newErrorResponsef(nil, http.StatusBadRequest, "Batch finder %q not defined on %q", batchFinder, p.name)
+		}
+		name = batchFinder
 	} else if restLiMethod == Method_action {
 		h = p.actions[action]
 		if h == nil {
 			return newErrorResponsef(nil, http.StatusBadRequest, "Action %q not defined on %q", action, p.name)
 		}
-		newCtx = context.WithValue(newCtx, actionNameCtxKey, action)
+		name = action
 	} else {
 		h = p.methods[restLiMethod]
 		if h == nil {
@@ -299,24 +469,53 @@ func (p *pathNode) receive(
 		}
 	}
 
+	ctx.info = RequestInfo{
+		Method:               restLiMethod,
+		ResourcePathSegments: rt.pathSegments,
+		Name:                 name,
+		entitySegments:       entitySegments,
+	}
+	newCtx := context.WithValue(ctx.Request.Context(), requestInfoCtxKey, &ctx.info)
+
+	if l, ok := rt.methodLimits[restLiMethod]; ok {
+		ctx.limits = l
+	}
+
+	if ctx.tracer != nil {
+		call := Call{Server: true, RootResource: rt.pathSegments[0].name, Method: restLiMethod, Name: name}
+		newCtx, ctx.span = ctx.tracer.StartSpan(newCtx, SpanName(call), call)
+	}
+
+	ctx.call.routed(newCtx, restLiMethod, name)
+
+	release, err := p.admit(ctx, restLiMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { release(err) }()
+
+	// Deferred before recovering from panics, such that filters also see the panics
+	defer func() {
+		responseBody, err = filterResponse(ctx, responseBody, err)
+	}()
 	defer func() {
 		if r := recover(); r != nil {
 			stack := string(debug.Stack())
-			log.Printf("Failed to handle %q (%s)\n%s", ctx.Request.URL, r, stack)
-			err = &common.ErrorResponse{
-				Status:     Int32Pointer(http.StatusInternalServerError),
-				Message:    StringPointer(fmt.Sprint(r)),
-				StackTrace: &stack,
+			if id := ctx.correlationID(); id != "" {
+				log.Printf("Failed to handle %q (%s) [%s]\n%s", ctx.Request.URL, r, id, stack)
+			} else {
+				log.Printf("Failed to handle %q (%s)\n%s", ctx.Request.URL, r, stack)
 			}
+			err = ctx.errorPolicy.recovered(r, stack)
 		}
 	}()
-	body, err := io.ReadAll(ctx.Request.Body)
+	body, err := readRequestBody(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	ctx.Request = ctx.Request.WithContext(newCtx)
-	for _, f := range p.rootNode.filters {
+	for _, f := range ctx.filters {
 		newCtx, err = f.PreRequest(ctx.Request)
 		if err != nil {
 			return nil, err
@@ -324,9 +523,11 @@ func (p *pathNode) receive(
 		if newCtx != nil {
 			ctx.Request = ctx.Request.WithContext(newCtx)
 		}
+		ctx.filtered++
 	}
 
-	return h(ctx, segmentReaders(entitySegments), body)
+	responseBody, err = h(ctx, segmentReaders(entitySegments), body)
+	return responseBody, p.checkServiceError(ctx, newMethodKey(restLiMethod, name), err)
 }
 
 type validatedRor2String string
@@ -339,29 +540,9 @@ func segmentReaders(segments []validatedRor2String) (readers []restlicodec.Reade
 	return readers
 }
 
-func GetMethodFromContext(ctx context.Context) Method {
-	return ctx.Value(methodCtxKey).(Method)
-}
-
-func GetResourcePathSegmentsFromContext(ctx context.Context) []ResourcePathSegment {
-	return ctx.Value(resourcePathSegmentsCtxKey).([]ResourcePathSegment)
-}
-
-func GetEntitySegmentsFromContext(ctx context.Context) []restlicodec.Reader {
-	return segmentReaders(ctx.Value(entitySegmentsCtxKey).([]validatedRor2String))
-}
-
-func GetFinderNameFromContext(ctx context.Context) string {
-	return ctx.Value(finderNameCtxKey).(string)
-}
-
-func GetActionNameFromContext(ctx context.Context) string {
-	return ctx.Value(actionNameCtxKey).(string)
-}
-
 func newErrorResponsef(cause error, status int, format string, a ...any) (restlicodec.Marshaler, error) {
-	if e, ok := cause.(*common.ErrorResponse); ok {
-		return nil, e
+	if _, ok := asErrorResponse(cause); ok {
+		return nil, cause
 	}
 	if cause != nil {
 		a = append(a, cause)
@@ -390,6 +571,9 @@ func (p *pathNode) newSubNode(segment ResourcePathSegment) *pathNode {
 		rootNode:            p.rootNode,
 		methods:             map[Method]handler{},
 		finders:             map[string]handler{},
+		batchFinders:        map[string]handler{},
+		assocKeyFinders:     map[string]bool{},
+		keyCoercers:         map[string]altKeyCoercer{},
 		actions:             map[string]handler{},
 		subNodes:            map[string]*pathNode{},
 	}
@@ -400,10 +584,11 @@ func (p *pathNode) newSubNode(segment ResourcePathSegment) *pathNode {
 // they were passed to NewServer, and PostRequest methods in the inverse order.
 type Filter interface {
 	// PreRequest is called after the request is parsed and the corresponding method is found. It is not called on any
-	// invalid requests. The request's context will have corresponding values for the method, resource segments, entity
-	// segments, finder name (only set if the method is Method_finder) and action name (only set if the method is
-	// Method_action). Use the corresponding FromContext methods to get the values. If the returned context is non-nil,
-	// it will replace the context passed to the actual resource implementation.
+	// invalid requests. The request's context will have a RequestInfo describing the method, resource segments, entity
+	// segments, and finder or action name of the request, which can be read with GetRequestInfoFromContext (or the
+	// corresponding FromContext methods). The incoming TraceContext, if any, can be read with
+	// GetTraceContextFromContext. If the returned context is non-nil, it will replace the context passed to the actual
+	// resource implementation.
 	PreRequest(req *http.Request) (context.Context, error)
 	// PostRequest is called with the original request context and the response header map right before the response
 	// header is written.
@@ -422,6 +607,9 @@ func NewPrefixedServer(prefix string, filters ...Filter) Server {
 			ResourcePathSegment: ResourcePathSegment{},
 			methods:             map[Method]handler{},
 			finders:             map[string]handler{},
+			batchFinders:        map[string]handler{},
+			assocKeyFinders:     map[string]bool{},
+			keyCoercers:         map[string]altKeyCoercer{},
 			actions:             map[string]handler{},
 			subNodes:            map[string]*pathNode{},
 		},
@@ -506,8 +694,8 @@ func registerMethod[RP ResourcePathUnmarshaler[RP], QP restlicodec.QueryParamsDe
 		}
 
 		responseBody, err = h(ctx, rp, queryParams, body)
-		if _, ok := err.(*common.ErrorResponse); err != nil && !ok {
-			return newErrorResponsef(err, http.StatusInternalServerError, "%q failed: %s", method)
+		if _, ok := asErrorResponse(err); err != nil && !ok {
+			return ctx.errorPolicy.wrap(err, http.StatusInternalServerError, "%q failed: %s", method)
 		} else {
 			return responseBody, err
 		}
@@ -530,7 +718,9 @@ func registerMethodWithNoBody[RP ResourcePathUnmarshaler[RP], QP restlicodec.Que
 				return newErrorResponsef(nil, http.StatusBadRequest, "%q does not take a body", method)
 			}
 
-			return h(ctx, rp, qp)
+			return filterRequest(ctx, newDecodedRequest(rp, qp, nil), func() (restlicodec.Marshaler, error) {
+				return h(ctx, rp, qp)
+			})
 		})
 }
 
@@ -553,13 +743,25 @@ func registerMethodWithBody[RP ResourcePathUnmarshaler[RP], QP restlicodec.Query
 			var v V
 			r, err := restlicodec.NewJsonReaderWithExcludedFields(body, excludedFields, leadingScopeToIgnore)
 			if err == nil {
+				if ctx.protocolVersion == ProtocolVersion1 && protocol1BatchMethods[method] {
+					r = restlicodec.NewProtocol1KeysReader(r, common.EntitiesField)
+				}
 				v, err = unmarshaler(r)
 			}
 			if err != nil {
 				return newErrorResponsef(err, http.StatusBadRequest, "Invalid request body for %q: %s", method)
 			}
 
-			return h(ctx, rp, v, qp)
+			if ctx.validationStatus != 0 {
+				err = validation.Validate(v)
+				if err != nil {
+					return newErrorResponsef(err, ctx.validationStatus, "Invalid request body for %q: %s", method)
+				}
+			}
+
+			return filterRequest(ctx, newDecodedRequest(rp, qp, v), func() (restlicodec.Marshaler, error) {
+				return h(ctx, rp, v, qp)
+			})
 		})
 }
 
@@ -567,6 +769,33 @@ type RequestContext struct {
 	Request         *http.Request
 	ResponseHeaders http.Header
 	ResponseStatus  int
+
+	limits Limits
+	call   *serverCall
+	tracer Tracer
+	span   Span
+
+	filters []Filter
+	// filtered is the number of filters whose PreRequest method was called, which are the only filters whose
+	// FilterResponse method is called (if they implement ExtendedFilter)
+	filtered int
+	// info is the RequestInfo added to the request's context once the request is routed
+	info RequestInfo
+	// decodedRequest holds the decoded inputs of the request, once they are decoded
+	decodedRequest *DecodedRequest
+
+	validationStatus int
+	errorPolicy      *ErrorPolicy
+	// errorCorrelationID is the memoized result of ErrorPolicy.CorrelationID (see correlationID)
+	errorCorrelationID *string
+	// protocolVersion is the rest.li protocol version of the request, i.e. either ProtocolVersion or ProtocolVersion1
+	protocolVersion string
+	// altKeyCoercer is set for batch_get requests made with alternative keys, such that the keys of the response can
+	// be coerced back to alternative keys
+	altKeyCoercer altKeyCoercer
+
+	requestAttachments  *multipart.Reader
+	responseAttachments []*Attachment
 }
 
 func (c *RequestContext) RequestPath() string {
@@ -0,0 +1,163 @@
+package restli
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+type testElement struct {
+	value string
+	err   error
+}
+
+func (t *testElement) MarshalRestLi(writer restlicodec.Writer) error {
+	if t.err != nil {
+		return t.err
+	}
+	return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) error {
+		keyWriter("value").WriteString(t.value)
+		return nil
+	})
+}
+
+func newTestContext() *RequestContext {
+	return &RequestContext{
+		Request:        httptest.NewRequest(http.MethodGet, "/test", nil),
+		ResponseStatus: http.StatusOK,
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	res := httptest.NewRecorder()
+	writeResponse(res, newTestContext(), &common.Elements[*testElement]{
+		Elements: []*testElement{{value: "foo"}},
+	})
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, strconv.Itoa(res.Body.Len()), res.Header().Get("Content-Length"))
+	require.JSONEq(t, `{"elements":[{"value":"foo"}]}`, res.Body.String())
+	// Responses under the flush threshold are sent in one piece
+	require.False(t, res.Flushed)
+}
+
+func TestWriteResponseStreamsSource(t *testing.T) {
+	const count = 10_000
+	value := strings.Repeat("a", 100)
+
+	res := httptest.NewRecorder()
+	writeResponse(res, newTestContext(), &common.Elements[*testElement]{
+		Source: func(yield func(*testElement) error) error {
+			for i := 0; i < count; i++ {
+				if err := yield(&testElement{value: value}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Empty(t, res.Header().Get("Content-Length"))
+	require.True(t, res.Flushed)
+
+	var elements struct {
+		Elements []struct {
+			Value string `json:"value"`
+		} `json:"elements"`
+	}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &elements))
+	require.Len(t, elements.Elements, count)
+}
+
+func TestWriteResponseErrorBeforeFlush(t *testing.T) {
+	res := httptest.NewRecorder()
+	writeResponse(res, newTestContext(), &common.Elements[*testElement]{
+		Elements: []*testElement{{value: "foo"}, {err: errors.New("bad element")}},
+	})
+	require.Equal(t, http.StatusInternalServerError, res.Code)
+	require.Equal(t, "true", res.Header().Get(ErrorResponseHeader))
+
+	reader, err := restlicodec.NewJsonReader(res.Body.Bytes())
+	require.NoError(t, err)
+	errRes, err := restlicodec.UnmarshalRestLi[*common.ErrorResponse](reader)
+	require.NoError(t, err)
+	require.Equal(t, "Failed to serialize response: bad element", *errRes.Message)
+}
+
+func TestWriteResponseErrorAfterFlush(t *testing.T) {
+	value := strings.Repeat("a", responseFlushThreshold)
+
+	res := httptest.NewRecorder()
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		writeResponse(res, newTestContext(), &common.Elements[*testElement]{
+			Elements: []*testElement{{value: value}, {value: value}, {err: errors.New("bad element")}},
+		})
+	})
+	require.Equal(t, http.StatusOK, res.Code)
+	require.True(t, res.Flushed)
+}
+
+// discardResponseWriter is an http.ResponseWriter that discards everything written to it, such that benchmarks only
+// measure the Server
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
+
+func benchmarkServer(b *testing.B, method Method, httpMethod, target string) {
+	s := NewServer()
+	RegisterGet(s, testCollectionSegments,
+		func(*RequestContext, *testEntityResourcePath, common.EmptyRecord) (validatedName, error) {
+			return "entity", nil
+		})
+	RegisterFinder(s, testCollectionSegments, "all",
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Elements[validatedName], error) {
+			return &common.Elements[validatedName]{Elements: []validatedName{"entity"}}, nil
+		})
+	RegisterAction(s, testCollectionSegments, "do",
+		func(*RequestContext, testResourcePath, common.EmptyRecord) error {
+			return nil
+		})
+	h := s.Handler()
+
+	req := httptest.NewRequest(httpMethod, target, http.NoBody)
+	req.Header.Set(MethodHeader, method.String())
+	res := &discardResponseWriter{header: make(http.Header)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for k := range res.header {
+			delete(res.header, k)
+		}
+		h.ServeHTTP(res, req)
+	}
+}
+
+func BenchmarkServerGet(b *testing.B) {
+	benchmarkServer(b, Method_get, http.MethodGet, "/collection/1")
+}
+
+func BenchmarkServerFinder(b *testing.B) {
+	benchmarkServer(b, Method_finder, http.MethodGet, "/collection?q=all&start=0&count=10")
+}
+
+func BenchmarkServerAction(b *testing.B) {
+	benchmarkServer(b, Method_action, http.MethodPost, "/collection?action=do")
+}
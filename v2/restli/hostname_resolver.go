@@ -17,3 +17,12 @@ type HostnameResolver interface {
 	// different strategy.
 	ResolveHostnameAndContextForQuery(rootResource string, query *url.URL) (*url.URL, error)
 }
+
+// ProtocolVersionResolver can optionally be implemented by a HostnameResolver to announce the rest.li protocol version
+// supported by each service (e.g. d2.Client reads it from the service's properties). See Client.ProtocolVersions.
+type ProtocolVersionResolver interface {
+	// ResolveProtocolVersion returns the rest.li protocol version supported by the service that hosts the given root
+	// resource, i.e. either ProtocolVersion or ProtocolVersion1. An empty string means the version is unknown, in which
+	// case ProtocolVersion is used.
+	ResolveProtocolVersion(rootResource string) (string, error)
+}
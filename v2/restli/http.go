@@ -4,7 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
@@ -14,18 +15,37 @@ import (
 
 const (
 	ProtocolVersion = "2.0.0"
+	// ProtocolVersion1 is the legacy rest.li protocol version, which is only used to talk to services that do not
+	// support ProtocolVersion yet. See Client.ProtocolVersions.
+	ProtocolVersion1 = "1.0.0"
 
 	IDHeader              = "X-RestLi-Id"
 	MethodHeader          = "X-RestLi-Method"
 	ProtocolVersionHeader = "X-RestLi-Protocol-Version"
 	ErrorResponseHeader   = "X-RestLi-Error-Response"
 	MethodOverrideHeader  = "X-HTTP-Method-Override"
+	// LegacyIDHeader replaces IDHeader in responses to ProtocolVersion1 requests
+	LegacyIDHeader = "X-LinkedIn-Id"
+
+	ContentTypeHeader           = "Content-Type"
+	ContentIDHeader             = "Content-ID"
+	AcceptHeader                = "Accept"
+	RetryAfterHeader            = "Retry-After"
+	MultipartMixedContentType   = "multipart/mixed"
+	MultipartRelatedContentType = "multipart/related"
+	MultipartBoundary           = "boundary"
+	ApplicationJsonContentType  = "application/json"
+	FormUrlEncodedContentType   = "application/x-www-form-urlencoded"
+)
 
-	ContentTypeHeader          = "Content-Type"
-	MultipartMixedContentType  = "multipart/mixed"
-	MultipartBoundary          = "boundary"
-	ApplicationJsonContentType = "application/json"
-	FormUrlEncodedContentType  = "application/x-www-form-urlencoded"
+// The canonical forms of the headers the Server reads or writes for every request. Since the keys of an http.Header are
+// canonical, looking headers up by their canonical form does not allocate, unlike looking them up by the constants
+// above.
+var (
+	canonicalMethodHeader          = http.CanonicalHeaderKey(MethodHeader)
+	canonicalProtocolVersionHeader = http.CanonicalHeaderKey(ProtocolVersionHeader)
+	canonicalMethodOverrideHeader  = http.CanonicalHeaderKey(MethodOverrideHeader)
+	canonicalTraceParentHeader     = http.CanonicalHeaderKey(TraceParentHeader)
 )
 
 type Method int
@@ -50,11 +70,12 @@ const (
 
 	Method_action
 	Method_finder
+	Method_batch_finder
 )
 
 var MethodNameMapping = func() map[string]Method {
 	mapping := make(map[string]Method)
-	for m := Method_get; m <= Method_finder; m++ {
+	for m := Method_get; m <= Method_batch_finder; m++ {
 		mapping[m.String()] = m
 	}
 	return mapping
@@ -70,6 +91,20 @@ type Client struct {
 	// request will instead be sent via POST, with the query encoded as a form query and the MethodOverrideHeader set to
 	// the original HTTP method.
 	QueryTunnellingThreshold int
+	// The maximum number of concurrent requests used when a batch request is split into multiple requests because it
+	// exceeds the method's maximum batch size (see SplitBatchKeys). Defaults to DefaultMaxBatchConcurrency if not set.
+	MaxBatchConcurrency int
+	// If non-nil, the Observer will be notified of the start and end of every call made by this Client.
+	Observer Observer
+	// If non-nil, the Tracer will be used to create a span for every call made by this Client. Regardless of whether a
+	// Tracer is set, the TraceContext carried by the call's context (see WithTraceContext) is always injected in the
+	// request's headers.
+	Tracer Tracer
+	// ProtocolVersions maps root resources to the rest.li protocol version requests to them should use, i.e. either
+	// ProtocolVersion or ProtocolVersion1. For root resources missing from the map, the version is resolved by the
+	// HostnameResolver if it implements ProtocolVersionResolver, and defaults to ProtocolVersion otherwise. Note that
+	// the version is negotiated by the generated clients, and that the dynamic client only supports ProtocolVersion.
+	ProtocolVersions map[string]string
 }
 
 func (c *Client) formatQueryUrl(rp ResourcePath, query QueryParamsEncoder) (*url.URL, error) {
@@ -117,11 +152,11 @@ type contextKey int
 const (
 	extraRequestHeadersKey contextKey = iota
 	responseHeadersCaptorKey
-	methodCtxKey
-	resourcePathSegmentsCtxKey
-	entitySegmentsCtxKey
-	finderNameCtxKey
-	actionNameCtxKey
+	requestInfoCtxKey
+	clientCallCtxKey
+	clientSpanCtxKey
+	requestAttachmentsCtxKey
+	responseAttachmentsCtxKey
 )
 
 // ExtraRequestHeaders returns a context.Context to be passed into any generated client methods. Upon request creation,
@@ -149,6 +184,17 @@ func newRequest(
 	contents restlicodec.Marshaler,
 	excludedFields restlicodec.PathSpec,
 ) (req *http.Request, err error) {
+	version, err := c.protocolVersion(rp.RootResource())
+	if err != nil {
+		return nil, err
+	}
+	if version == ProtocolVersion1 {
+		rp = protocol1ResourcePath{rp}
+		if query != nil {
+			query = protocol1QueryParams{query}
+		}
+	}
+
 	u, err := c.formatQueryUrl(rp, query)
 	if err != nil {
 		return nil, err
@@ -169,28 +215,62 @@ func newRequest(
 		headers.Set(ContentTypeHeader, ApplicationJsonContentType)
 	}
 
-	if c.QueryTunnellingThreshold > 0 && len(u.RawQuery) > c.QueryTunnellingThreshold {
-		var tunnelHeaders http.Header
-		body, tunnelHeaders = EncodeTunnelledQuery(httpMethod, u.RawQuery, body)
-		for k := range tunnelHeaders {
-			headers.Set(k, tunnelHeaders.Get(k))
+	if c.Observer != nil || c.Tracer != nil {
+		call := newClientCall(rp, method, u.Query())
+		ctx = context.WithValue(ctx, clientCallCtxKey, call)
+		if c.Tracer != nil {
+			var span Span
+			ctx, span = c.Tracer.StartSpan(ctx, SpanName(call), call)
+			ctx = context.WithValue(ctx, clientSpanCtxKey, span)
+			defer func() {
+				if err != nil {
+					span.End(err)
+				}
+			}()
+		}
+	}
+
+	var bodyReader io.Reader
+	if attachments, ok := ctx.Value(requestAttachmentsCtxKey).([]*Attachment); ok && len(attachments) > 0 {
+		if body == nil {
+			return nil, fmt.Errorf("go-restli: Cannot send attachments with %q, it does not have a request body", method)
+		}
+		boundary := multipart.NewWriter(nil).Boundary()
+		headers.Set(ContentTypeHeader, multipartRelatedContentType(boundary))
+		bodyReader = multipartRelatedBody(boundary, body, attachments)
+	} else {
+		if c.QueryTunnellingThreshold > 0 && len(u.RawQuery) > c.QueryTunnellingThreshold {
+			var tunnelHeaders http.Header
+			body, tunnelHeaders = EncodeTunnelledQuery(httpMethod, u.RawQuery, body)
+			for k := range tunnelHeaders {
+				headers.Set(k, tunnelHeaders.Get(k))
+			}
+			httpMethod = http.MethodPost
+			u.RawQuery = ""
 		}
-		httpMethod = http.MethodPost
-		u.RawQuery = ""
+		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err = http.NewRequestWithContext(ctx, httpMethod, u.String(), bytes.NewReader(body))
+	req, err = http.NewRequestWithContext(ctx, httpMethod, u.String(), bodyReader)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set(ProtocolVersionHeader, ProtocolVersion)
+	req.Header.Set(ProtocolVersionHeader, version)
 	req.Header.Set(MethodHeader, method.String())
-	req.Header.Set("Accept", ApplicationJsonContentType)
+	if _, ok := ctx.Value(responseAttachmentsCtxKey).(func(*Attachment) error); ok {
+		req.Header.Set(AcceptHeader, MultipartRelatedContentType+";q=1.0, "+ApplicationJsonContentType+";q=0.9")
+	} else {
+		req.Header.Set(AcceptHeader, ApplicationJsonContentType)
+	}
 	for k, v := range headers {
 		req.Header[k] = v
 	}
 
+	if tc, ok := GetTraceContextFromContext(ctx); ok {
+		tc.Inject(req.Header)
+	}
+
 	if extraHeaders, ok := req.Context().Value(extraRequestHeadersKey).(func() (http.Header, error)); ok {
 		extras, err := extraHeaders()
 		if err != nil {
@@ -308,33 +388,55 @@ func DoAndIgnore(c *Client, req *http.Request) (*http.Response, error) {
 	return res, err
 }
 
-func (c *Client) do(req *http.Request) ([]byte, *http.Response, error) {
+func (c *Client) do(req *http.Request) (data []byte, res *http.Response, err error) {
+	if span, ok := req.Context().Value(clientSpanCtxKey).(Span); ok {
+		defer func() { span.End(err) }()
+	}
+	if c.Observer != nil {
+		return c.observeCall(req)
+	}
+	return c.doAndRead(req)
+}
 
+func (c *Client) doAndRead(req *http.Request) ([]byte, *http.Response, error) {
 	res, err := c.Do(req)
 	if err != nil {
-		return nil, res, err
+		return nil, res, newTransportError(err)
 	}
 
-	if v := res.Header.Get(ProtocolVersionHeader); v != ProtocolVersion {
-		return nil, nil, &UnsupportedRestLiProtocolVersion{v}
+	requested := req.Header.Get(ProtocolVersionHeader)
+	if requested == "" {
+		requested = ProtocolVersion
+	}
+	if v := res.Header.Get(ProtocolVersionHeader); v != requested {
+		return nil, nil, &UnsupportedRestLiProtocolVersion{ReturnedVersion: v, RequestedVersion: requested, Response: res}
 	}
 
-	data, err := ioutil.ReadAll(res.Body)
+	data, attachments, err := readResponseBody(res)
 	if err != nil {
-		return nil, nil, &url.Error{
+		return nil, nil, newTransportError(&url.Error{
 			Op:  "ReadResponse",
 			URL: req.URL.String(),
 			Err: err,
+		})
+	}
+
+	if attachments != nil {
+		f, _ := req.Context().Value(responseAttachmentsCtxKey).(func(*Attachment) error)
+		err = readResponseAttachments(attachments, f)
+		if err != nil {
+			_ = res.Body.Close()
+			return nil, nil, err
 		}
 	}
 
 	err = res.Body.Close()
 	if err != nil {
-		return nil, nil, &url.Error{
+		return nil, nil, newTransportError(&url.Error{
 			Op:  "CloseResponse",
 			URL: req.URL.String(),
 			Err: err,
-		}
+		})
 	}
 
 	if resHeaders, ok := req.Context().Value(responseHeadersCaptorKey).(http.Header); ok {
@@ -0,0 +1,899 @@
+package restli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RestSpecFileExtension is the extension of the restspec files generated by GenerateIDL
+	RestSpecFileExtension = ".restspec.json"
+	// PdlFileExtension is the extension of the schema files generated by GenerateIDL
+	PdlFileExtension = ".pdl"
+)
+
+// ResourceSchema describes a resource as declared by its original restspec. Generated code declares it when the
+// resource is registered (see DeclareResourceSchema), which allows a Server to regenerate the IDL of all its resources
+// with GenerateIDL, e.g. for Java clients to be generated from a Go-first service.
+type ResourceSchema struct {
+	Namespace string
+	Doc       string
+	// Schema is the fully qualified name of the resource's entity type. It is empty for action sets.
+	Schema string
+	// Key is the key of the resource, and is only set for collections and associations
+	Key *KeySchema
+	// AlternativeKeys are the alternative keys a collection or association's entities can also be addressed with
+	AlternativeKeys  []AlternativeKeySchema
+	ReadOnlyFields   []string
+	CreateOnlyFields []string
+	ServiceErrors    []ServiceErrorDefinition
+	Methods          []MethodSchema
+	// DataSchemas holds the .pdl definition of every named type referenced by the resource, indexed by the type's fully
+	// qualified name
+	DataSchemas map[string]string
+	// JSONDataSchemas holds the JSON (.pdsc) definition of the same types as DataSchemas, which are returned as the
+	// models of OPTIONS requests
+	JSONDataSchemas map[string]string
+}
+
+// KeySchema describes the key of a collection or association
+type KeySchema struct {
+	Name string
+	// Type is the type of the key, in the same format as ParameterSchema.Type. For complex keys, it is the type of the
+	// key record, and Params is the type of its parameters record. It is empty for associations.
+	Type   string
+	Params string
+	// AssocKeys are the key parts of an association's key, and are only set for associations
+	AssocKeys []AssocKeySchema
+}
+
+// AssocKeySchema describes a key part of an association's key
+type AssocKeySchema struct {
+	Name string
+	Type string
+}
+
+// AlternativeKeySchema describes an alternative key of a collection or association. KeyCoercer is the fully qualified
+// name of the Java class that coerced it to the primary key in the original resource.
+type AlternativeKeySchema struct {
+	Name       string
+	Doc        string
+	Type       string
+	KeyCoercer string
+}
+
+// MethodSchema describes a method of a resource
+type MethodSchema struct {
+	Method Method
+	// Name is the name of the finder, batch finder or action, and is empty for all other methods
+	Name string
+	Doc  string
+	// OnEntity is true for actions that are declared on the entities of a collection instead of on the collection
+	OnEntity        bool
+	Params          []ParameterSchema
+	PagingSupported bool
+	// Returns is the type returned by an action, if any
+	Returns string
+	// Metadata is the type of the metadata returned by a finder, batch finder or get_all, if any
+	Metadata string
+	// BatchParam is the name of the parameter that holds the criteria of a batch finder
+	BatchParam string
+	// AssocKeys are the association key parts an assocKey finder is called on
+	AssocKeys     []string
+	ReturnEntity  bool
+	MaxBatchSize  *MaxBatchSize
+	ServiceErrors []ServiceErrorDefinition
+}
+
+// ParameterSchema describes a query or action parameter
+type ParameterSchema struct {
+	Name string
+	// Type is the name of a primitive (e.g. "long") or the fully qualified name of a named type, or the JSON-encoded
+	// schema of arrays and maps (e.g. {"type":"array","items":"string"}), like in restspecs
+	Type     string
+	Doc      string
+	Optional bool
+	// Default is the JSON-encoded default value of the parameter, if any
+	Default *string
+}
+
+// MaxBatchSize is the maximum number of keys or entities a batch method accepts. Requests with more are only rejected
+// if Validate is true.
+type MaxBatchSize struct {
+	Value    int  `json:"value"`
+	Validate bool `json:"validate"`
+}
+
+// DeclareResourceSchema declares the schema of the resource at the given path. It is called by generated code when a
+// resource is registered, and only needs to be called manually for resources that are implemented directly against
+// the Register* functions.
+func DeclareResourceSchema(s Server, segments []ResourcePathSegment, schema *ResourceSchema) {
+	s.subNode(segments).schema = schema
+}
+
+// IDL holds the files generated by GenerateIDL, indexed by their path relative to the directory they should be written
+// to
+type IDL struct {
+	// RestSpecs holds a <namespace>.<resource>.restspec.json file per root resource. Subresources are declared in the
+	// restspec of their root resource.
+	RestSpecs map[string][]byte
+	// Schemas holds a .pdl file per named type referenced by the resources, under a directory that matches the type's
+	// namespace, e.g. com/example/Fruit.pdl
+	Schemas map[string][]byte
+}
+
+// GenerateIDL generates the IDL of every resource registered against the given Server that declared its schema (see
+// DeclareResourceSchema). Resources that did not declare their schema are skipped.
+func GenerateIDL(s Server) (*IDL, error) {
+	specs, err := s.subNode(nil).restSpecs("")
+	if err != nil {
+		return nil, err
+	}
+
+	idl := &IDL{
+		RestSpecs: make(map[string][]byte, len(specs)),
+		Schemas:   make(map[string][]byte),
+	}
+	for _, spec := range specs {
+		data, err := marshalRestSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		idl.RestSpecs[restSpecFilename(spec)] = data
+	}
+
+	s.subNode(nil).walk(func(p *pathNode) {
+		if p.schema == nil {
+			return
+		}
+		for name, pdl := range p.schema.DataSchemas {
+			idl.Schemas[filepath.Join(strings.Split(name, ".")...)+PdlFileExtension] = []byte(pdl)
+		}
+	})
+
+	return idl, nil
+}
+
+// WriteIDL generates the IDL of the given Server (see GenerateIDL) and writes the restspecs to restSpecDir and the .pdl
+// schemas to schemaDir. The schemas are not written if schemaDir is empty.
+func WriteIDL(s Server, restSpecDir, schemaDir string) error {
+	idl, err := GenerateIDL(s)
+	if err != nil {
+		return err
+	}
+
+	write := func(dir string, files map[string][]byte) error {
+		for name, data := range files {
+			name = filepath.Join(dir, name)
+			err := os.MkdirAll(filepath.Dir(name), os.ModePerm)
+			if err != nil {
+				return err
+			}
+			err = os.WriteFile(name, data, 0644)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = write(restSpecDir, idl.RestSpecs)
+	if err != nil {
+		return err
+	}
+	if schemaDir != "" {
+		return write(schemaDir, idl.Schemas)
+	}
+	return nil
+}
+
+// IDLDifference is a difference between the IDL generated from a Server and the IDL checked in, as returned by
+// CheckIDL
+type IDLDifference struct {
+	// Path locates the difference, e.g. "com.example.fruits.restspec.json: finders.search.parameters.color"
+	Path    string
+	Message string
+	// Compatible is true if the difference is backwards compatible, i.e. clients generated from the checked-in IDL can
+	// still call the Server. Removing methods or changing types are examples of incompatible changes, while adding
+	// methods or optional parameters are compatible.
+	Compatible bool
+}
+
+func (d IDLDifference) String() string {
+	compatibility := "incompatible"
+	if d.Compatible {
+		compatibility = "compatible"
+	}
+	return fmt.Sprintf("%s: %s (%s)", d.Path, d.Message, compatibility)
+}
+
+// CheckIDL compares the restspecs generated from the given Server with the restspecs checked in to the given
+// directory, and returns all the differences between the two. Any difference means the checked-in IDL should be
+// regenerated with WriteIDL, and incompatible differences mean the Server is not backwards compatible with clients
+// generated from the checked-in IDL. Restspecs in the directory that do not correspond to any of the Server's resources
+// are considered removed, therefore the directory should only contain the restspecs of the Server.
+func CheckIDL(s Server, restSpecDir string) ([]IDLDifference, error) {
+	idl, err := GenerateIDL(s)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(restSpecDir, "*"+RestSpecFileExtension))
+	if err != nil {
+		return nil, err
+	}
+	checkedIn := make(map[string][]byte, len(files))
+	for _, f := range files {
+		checkedIn[filepath.Base(f)], err = os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var names []string
+	for name := range idl.RestSpecs {
+		names = append(names, name)
+	}
+	for name := range checkedIn {
+		if _, ok := idl.RestSpecs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	c := new(idlComparator)
+	for _, name := range names {
+		expectedData, ok := checkedIn[name]
+		if !ok {
+			c.add(name, true, "resource was added")
+			continue
+		}
+		actualData, ok := idl.RestSpecs[name]
+		if !ok {
+			c.add(name, false, "resource was removed")
+			continue
+		}
+
+		expected, actual := new(restSpec), new(restSpec)
+		err = json.Unmarshal(expectedData, expected)
+		if err != nil {
+			return nil, fmt.Errorf("go-restli: Could not read %q: %w", filepath.Join(restSpecDir, name), err)
+		}
+		err = json.Unmarshal(actualData, actual)
+		if err != nil {
+			return nil, err
+		}
+		c.compareResource(name+":", expected, actual)
+	}
+
+	return c.diffs, nil
+}
+
+type restSpec struct {
+	Annotations   map[string]any         `json:"annotations,omitempty"`
+	Name          string                 `json:"name"`
+	Namespace     string                 `json:"namespace,omitempty"`
+	Path          string                 `json:"path"`
+	Schema        string                 `json:"schema,omitempty"`
+	Doc           string                 `json:"doc,omitempty"`
+	ServiceErrors []restSpecServiceError `json:"serviceErrors,omitempty"`
+	Collection    *restSpecCollection    `json:"collection,omitempty"`
+	Association   *restSpecAssociation   `json:"association,omitempty"`
+	Simple        *restSpecSimple        `json:"simple,omitempty"`
+	ActionsSet    *restSpecActionsSet    `json:"actionsSet,omitempty"`
+}
+
+type restSpecCollection struct {
+	Identifier      restSpecIdentifier       `json:"identifier"`
+	AlternativeKeys []restSpecAlternativeKey `json:"alternativeKeys,omitempty"`
+	Supports        []string                 `json:"supports"`
+	Methods         []restSpecMethod         `json:"methods,omitempty"`
+	Finders         []restSpecMethod         `json:"finders,omitempty"`
+	BatchFinders    []restSpecMethod         `json:"batchFinders,omitempty"`
+	Actions         []restSpecMethod         `json:"actions,omitempty"`
+	Entity          restSpecEntity           `json:"entity"`
+}
+
+type restSpecAssociation struct {
+	Identifier      string                   `json:"identifier"`
+	AssocKeys       []restSpecAssocKey       `json:"assocKeys"`
+	AlternativeKeys []restSpecAlternativeKey `json:"alternativeKeys,omitempty"`
+	Supports        []string                 `json:"supports"`
+	Methods         []restSpecMethod         `json:"methods,omitempty"`
+	Finders         []restSpecMethod         `json:"finders,omitempty"`
+	BatchFinders    []restSpecMethod         `json:"batchFinders,omitempty"`
+	Actions         []restSpecMethod         `json:"actions,omitempty"`
+	Entity          restSpecEntity           `json:"entity"`
+}
+
+type restSpecAlternativeKey struct {
+	Name       string `json:"name"`
+	Doc        string `json:"doc,omitempty"`
+	Type       string `json:"type"`
+	KeyCoercer string `json:"keyCoercer"`
+}
+
+type restSpecAssocKey struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type restSpecSimple struct {
+	Supports []string         `json:"supports"`
+	Methods  []restSpecMethod `json:"methods,omitempty"`
+	Actions  []restSpecMethod `json:"actions,omitempty"`
+	Entity   restSpecEntity   `json:"entity"`
+}
+
+type restSpecActionsSet struct {
+	Actions []restSpecMethod `json:"actions,omitempty"`
+}
+
+type restSpecIdentifier struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Params string `json:"params,omitempty"`
+}
+
+type restSpecEntity struct {
+	Path         string           `json:"path"`
+	Actions      []restSpecMethod `json:"actions,omitempty"`
+	Subresources []*restSpec      `json:"subresources,omitempty"`
+}
+
+// restSpecMethod is a rest method, finder, batch finder or action. Rest methods are identified by Method, while finders,
+// batch finders and actions are identified by Name.
+type restSpecMethod struct {
+	Annotations     map[string]any         `json:"annotations,omitempty"`
+	Method          string                 `json:"method,omitempty"`
+	Name            string                 `json:"name,omitempty"`
+	Doc             string                 `json:"doc,omitempty"`
+	ServiceErrors   []restSpecServiceError `json:"serviceErrors,omitempty"`
+	Parameters      []restSpecParameter    `json:"parameters,omitempty"`
+	Metadata        *restSpecMetadata      `json:"metadata,omitempty"`
+	PagingSupported bool                   `json:"pagingSupported,omitempty"`
+	BatchParam      string                 `json:"batchParam,omitempty"`
+	AssocKeys       []string               `json:"assocKeys,omitempty"`
+	MaxBatchSize    *MaxBatchSize          `json:"maxBatchSize,omitempty"`
+	Returns         string                 `json:"returns,omitempty"`
+
+	isFinder bool
+}
+
+// MarshalJSON always includes the parameters of finders and batch finders, even if they have none, like restspecs do
+func (m restSpecMethod) MarshalJSON() ([]byte, error) {
+	type method restSpecMethod
+	if !m.isFinder {
+		return json.Marshal(method(m))
+	}
+	params := m.Parameters
+	if params == nil {
+		params = []restSpecParameter{}
+	}
+	return json.Marshal(struct {
+		method
+		Parameters []restSpecParameter `json:"parameters"`
+	}{method(m), params})
+}
+
+type restSpecParameter struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Doc      string  `json:"doc,omitempty"`
+	Optional bool    `json:"optional,omitempty"`
+	Default  *string `json:"default,omitempty"`
+}
+
+type restSpecMetadata struct {
+	Type string `json:"type"`
+}
+
+type restSpecServiceError struct {
+	Status          int    `json:"status"`
+	Code            string `json:"code"`
+	Message         string `json:"message,omitempty"`
+	ErrorDetailType string `json:"errorDetailType,omitempty"`
+}
+
+func marshalRestSpec(spec *restSpec) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(spec)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func restSpecFilename(spec *restSpec) string {
+	return restSpecFullName(spec) + RestSpecFileExtension
+}
+
+func restSpecFullName(spec *restSpec) string {
+	if spec.Namespace == "" {
+		return spec.Name
+	}
+	return spec.Namespace + "." + spec.Name
+}
+
+func (p *pathNode) walk(f func(p *pathNode)) {
+	f(p)
+	for _, name := range sortedKeys(p.subNodes) {
+		p.subNodes[name].walk(f)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// restSpecs returns the restspecs of all the sub nodes of this node that declared a schema
+func (p *pathNode) restSpecs(path string) (specs []*restSpec, err error) {
+	for _, name := range sortedKeys(p.subNodes) {
+		sub := p.subNodes[name]
+		if sub.schema == nil {
+			var undeclared *pathNode
+			sub.walk(func(p *pathNode) {
+				if undeclared == nil && p.schema != nil {
+					undeclared = p
+				}
+			})
+			if undeclared != nil {
+				return nil, fmt.Errorf("go-restli: Cannot generate IDL of %q since its parent %q did not declare its schema",
+					undeclared.name, path+"/"+name)
+			}
+			continue
+		}
+
+		spec, err := sub.restSpec(path + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (p *pathNode) restSpec(path string) (spec *restSpec, err error) {
+	schema := p.schema
+	spec = &restSpec{
+		Name:          p.name,
+		Namespace:     schema.Namespace,
+		Path:          path,
+		Schema:        schema.Schema,
+		Doc:           schema.Doc,
+		ServiceErrors: restSpecServiceErrors(schema.ServiceErrors),
+	}
+
+	if len(schema.ReadOnlyFields) > 0 || len(schema.CreateOnlyFields) > 0 {
+		spec.Annotations = map[string]any{}
+		if len(schema.ReadOnlyFields) > 0 {
+			spec.Annotations["readOnly"] = map[string]any{"value": schema.ReadOnlyFields}
+		}
+		if len(schema.CreateOnlyFields) > 0 {
+			spec.Annotations["createOnly"] = map[string]any{"value": schema.CreateOnlyFields}
+		}
+	}
+
+	var supports []string
+	var methods, finders, batchFinders, actions, entityActions []restSpecMethod
+	for _, m := range schema.Methods {
+		method := restSpecMethod{
+			Doc:             m.Doc,
+			ServiceErrors:   restSpecServiceErrors(m.ServiceErrors),
+			PagingSupported: m.PagingSupported,
+			BatchParam:      m.BatchParam,
+			AssocKeys:       m.AssocKeys,
+			MaxBatchSize:    m.MaxBatchSize,
+			Returns:         m.Returns,
+		}
+		for _, param := range m.Params {
+			method.Parameters = append(method.Parameters, restSpecParameter{
+				Name:     param.Name,
+				Type:     param.Type,
+				Doc:      param.Doc,
+				Optional: param.Optional,
+				Default:  param.Default,
+			})
+		}
+		if m.Metadata != "" {
+			method.Metadata = &restSpecMetadata{Type: m.Metadata}
+		}
+		if m.ReturnEntity {
+			method.Annotations = map[string]any{"returnEntity": map[string]any{}}
+		}
+
+		switch m.Method {
+		case Method_finder:
+			method.Name = m.Name
+			method.isFinder = true
+			finders = append(finders, method)
+		case Method_batch_finder:
+			method.Name = m.Name
+			method.isFinder = true
+			batchFinders = append(batchFinders, method)
+		case Method_action:
+			method.Name = m.Name
+			if m.OnEntity {
+				entityActions = append(entityActions, method)
+			} else {
+				actions = append(actions, method)
+			}
+		default:
+			method.Method = m.Method.String()
+			supports = append(supports, method.Method)
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(supports)
+	if supports == nil {
+		supports = []string{}
+	}
+
+	entity := restSpecEntity{Path: path, Actions: entityActions}
+	switch {
+	case p.isCollection && schema.Key != nil && len(schema.Key.AssocKeys) > 0:
+		entity.Path = path + "/{" + schema.Key.Name + "}"
+		spec.Association = &restSpecAssociation{
+			Identifier:      schema.Key.Name,
+			AlternativeKeys: restSpecAlternativeKeys(schema.AlternativeKeys),
+			Supports:        supports,
+			Methods:         methods,
+			Finders:         finders,
+			BatchFinders:    batchFinders,
+			Actions:         actions,
+			Entity:          entity,
+		}
+		for _, k := range schema.Key.AssocKeys {
+			spec.Association.AssocKeys = append(spec.Association.AssocKeys, restSpecAssocKey{Name: k.Name, Type: k.Type})
+		}
+		entity.Subresources, err = p.restSpecs(entity.Path)
+		spec.Association.Entity.Subresources = entity.Subresources
+	case p.isCollection:
+		if schema.Key == nil {
+			return nil, fmt.Errorf("go-restli: Collection %q did not declare its key", path)
+		}
+		entity.Path = path + "/{" + schema.Key.Name + "}"
+		spec.Collection = &restSpecCollection{
+			Identifier: restSpecIdentifier{
+				Name:   schema.Key.Name,
+				Type:   schema.Key.Type,
+				Params: schema.Key.Params,
+			},
+			AlternativeKeys: restSpecAlternativeKeys(schema.AlternativeKeys),
+			Supports:        supports,
+			Methods:         methods,
+			Finders:         finders,
+			BatchFinders:    batchFinders,
+			Actions:         actions,
+			Entity:          entity,
+		}
+		entity.Subresources, err = p.restSpecs(entity.Path)
+		spec.Collection.Entity.Subresources = entity.Subresources
+	case schema.Schema != "":
+		entity.Actions = nil
+		spec.Simple = &restSpecSimple{
+			Supports: supports,
+			Methods:  methods,
+			Actions:  append(actions, entityActions...),
+			Entity:   entity,
+		}
+		spec.Simple.Entity.Subresources, err = p.restSpecs(entity.Path)
+	default:
+		spec.ActionsSet = &restSpecActionsSet{Actions: append(actions, entityActions...)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+func restSpecAlternativeKeys(schemas []AlternativeKeySchema) (altKeys []restSpecAlternativeKey) {
+	for _, k := range schemas {
+		altKeys = append(altKeys, restSpecAlternativeKey{
+			Name:       k.Name,
+			Doc:        k.Doc,
+			Type:       k.Type,
+			KeyCoercer: k.KeyCoercer,
+		})
+	}
+	return altKeys
+}
+
+func restSpecServiceErrors(definitions []ServiceErrorDefinition) (errors []restSpecServiceError) {
+	for _, d := range definitions {
+		errors = append(errors, restSpecServiceError{
+			Status:          d.Status,
+			Code:            d.Code,
+			Message:         d.Message,
+			ErrorDetailType: d.ErrorDetailType,
+		})
+	}
+	return errors
+}
+
+type idlComparator struct {
+	diffs []IDLDifference
+}
+
+func (c *idlComparator) add(path string, compatible bool, format string, a ...any) {
+	c.diffs = append(c.diffs, IDLDifference{
+		Path:       path,
+		Message:    fmt.Sprintf(format, a...),
+		Compatible: compatible,
+	})
+}
+
+func joinIDLPath(path string, elements ...string) string {
+	for _, e := range elements {
+		if strings.HasSuffix(path, ":") {
+			path += " " + e
+		} else {
+			path += "." + e
+		}
+	}
+	return path
+}
+
+// normalizeRestSpecType re-encodes the JSON schemas of arrays and maps such that they can be compared regardless of
+// their formatting
+func normalizeRestSpecType(t string) string {
+	if !strings.HasPrefix(strings.TrimSpace(t), "{") {
+		return t
+	}
+	var v any
+	if err := json.Unmarshal([]byte(t), &v); err != nil {
+		return t
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func (c *idlComparator) compareType(path, name, expected, actual string) {
+	if normalizeRestSpecType(expected) != normalizeRestSpecType(actual) {
+		c.add(path, false, "%s changed from %q to %q", name, expected, actual)
+	}
+}
+
+func (c *idlComparator) compareDoc(path, expected, actual string) {
+	if strings.TrimSpace(expected) != strings.TrimSpace(actual) {
+		c.add(path, true, "doc changed")
+	}
+}
+
+func (c *idlComparator) compareCompatible(path, name string, expected, actual any) {
+	if !reflect.DeepEqual(expected, actual) {
+		c.add(path, true, "%s changed", name)
+	}
+}
+
+func (c *idlComparator) compareResource(path string, expected, actual *restSpec) {
+	c.compareType(path, "schema", expected.Schema, actual.Schema)
+	c.compareDoc(path, expected.Doc, actual.Doc)
+	c.compareCompatible(path, "annotations", expected.Annotations, actual.Annotations)
+	c.compareCompatible(path, "service errors", expected.ServiceErrors, actual.ServiceErrors)
+
+	switch {
+	case expected.Collection != nil && actual.Collection != nil:
+		e, a := expected.Collection, actual.Collection
+		c.compareType(path, "key name", e.Identifier.Name, a.Identifier.Name)
+		c.compareType(path, "key type", e.Identifier.Type, a.Identifier.Type)
+		c.compareType(path, "key params", e.Identifier.Params, a.Identifier.Params)
+		c.compareAlternativeKeys(joinIDLPath(path, "alternativeKeys"), e.AlternativeKeys, a.AlternativeKeys)
+		c.compareMethods(joinIDLPath(path, "methods"), e.Methods, a.Methods)
+		c.compareMethods(joinIDLPath(path, "finders"), e.Finders, a.Finders)
+		c.compareMethods(joinIDLPath(path, "batchFinders"), e.BatchFinders, a.BatchFinders)
+		c.compareMethods(joinIDLPath(path, "actions"), e.Actions, a.Actions)
+		c.compareMethods(joinIDLPath(path, "entity", "actions"), e.Entity.Actions, a.Entity.Actions)
+		c.compareSubresources(joinIDLPath(path, "entity", "subresources"), e.Entity.Subresources, a.Entity.Subresources)
+	case expected.Association != nil && actual.Association != nil:
+		e, a := expected.Association, actual.Association
+		c.compareType(path, "key name", e.Identifier, a.Identifier)
+		c.compareAssocKeys(joinIDLPath(path, "assocKeys"), e.AssocKeys, a.AssocKeys)
+		c.compareAlternativeKeys(joinIDLPath(path, "alternativeKeys"), e.AlternativeKeys, a.AlternativeKeys)
+		c.compareMethods(joinIDLPath(path, "methods"), e.Methods, a.Methods)
+		c.compareMethods(joinIDLPath(path, "finders"), e.Finders, a.Finders)
+		c.compareMethods(joinIDLPath(path, "batchFinders"), e.BatchFinders, a.BatchFinders)
+		c.compareMethods(joinIDLPath(path, "actions"), e.Actions, a.Actions)
+		c.compareMethods(joinIDLPath(path, "entity", "actions"), e.Entity.Actions, a.Entity.Actions)
+		c.compareSubresources(joinIDLPath(path, "entity", "subresources"), e.Entity.Subresources, a.Entity.Subresources)
+	case expected.Simple != nil && actual.Simple != nil:
+		e, a := expected.Simple, actual.Simple
+		c.compareMethods(joinIDLPath(path, "methods"), e.Methods, a.Methods)
+		c.compareMethods(joinIDLPath(path, "actions"), e.Actions, a.Actions)
+		c.compareSubresources(joinIDLPath(path, "entity", "subresources"), e.Entity.Subresources, a.Entity.Subresources)
+	case expected.ActionsSet != nil && actual.ActionsSet != nil:
+		c.compareMethods(joinIDLPath(path, "actions"), expected.ActionsSet.Actions, actual.ActionsSet.Actions)
+	default:
+		c.add(path, false, "resource type changed from %s to %s", expected.resourceType(), actual.resourceType())
+	}
+}
+
+func (s *restSpec) resourceType() string {
+	switch {
+	case s.Collection != nil:
+		return "collection"
+	case s.Association != nil:
+		return "association"
+	case s.Simple != nil:
+		return "simple"
+	case s.ActionsSet != nil:
+		return "actionsSet"
+	default:
+		return "unknown"
+	}
+}
+
+// compareAssocKeys compares the key parts of an association's key. Since they make up the key's wire format, any
+// change to them is incompatible.
+func (c *idlComparator) compareAssocKeys(path string, expected, actual []restSpecAssocKey) {
+	if len(expected) != len(actual) {
+		c.add(path, false, "number of key parts changed from %d to %d", len(expected), len(actual))
+		return
+	}
+	for i := range expected {
+		c.compareType(path, "key part "+strconv.Itoa(i)+" name", expected[i].Name, actual[i].Name)
+		c.compareType(joinIDLPath(path, expected[i].Name), "type", expected[i].Type, actual[i].Type)
+	}
+}
+
+// compareAlternativeKeys compares the alternative keys of a resource. Removing an alternative key or changing its type
+// breaks the clients that use it, while adding one is compatible.
+func (c *idlComparator) compareAlternativeKeys(path string, expected, actual []restSpecAlternativeKey) {
+	actualByName := make(map[string]restSpecAlternativeKey, len(actual))
+	for _, k := range actual {
+		actualByName[k.Name] = k
+	}
+	for _, e := range expected {
+		a, ok := actualByName[e.Name]
+		if !ok {
+			c.add(path, false, "alternative key %q removed", e.Name)
+			continue
+		}
+		delete(actualByName, e.Name)
+		keyPath := joinIDLPath(path, e.Name)
+		c.compareType(keyPath, "type", e.Type, a.Type)
+		c.compareDoc(keyPath, e.Doc, a.Doc)
+		c.compareCompatible(keyPath, "key coercer", e.KeyCoercer, a.KeyCoercer)
+	}
+	for _, a := range actual {
+		if _, ok := actualByName[a.Name]; ok {
+			c.add(path, true, "alternative key %q added", a.Name)
+		}
+	}
+}
+
+func (c *idlComparator) compareSubresources(path string, expected, actual []*restSpec) {
+	actualByName := make(map[string]*restSpec, len(actual))
+	for _, a := range actual {
+		actualByName[a.Name] = a
+	}
+	expectedByName := make(map[string]*restSpec, len(expected))
+	for _, e := range expected {
+		expectedByName[e.Name] = e
+		a, ok := actualByName[e.Name]
+		if !ok {
+			c.add(joinIDLPath(path, e.Name), false, "resource was removed")
+			continue
+		}
+		c.compareResource(joinIDLPath(path, e.Name), e, a)
+	}
+	for _, a := range actual {
+		if _, ok := expectedByName[a.Name]; !ok {
+			c.add(joinIDLPath(path, a.Name), true, "resource was added")
+		}
+	}
+}
+
+func (m *restSpecMethod) key() string {
+	if m.Method != "" {
+		return m.Method
+	}
+	return m.Name
+}
+
+func (c *idlComparator) compareMethods(path string, expected, actual []restSpecMethod) {
+	actualByKey := make(map[string]*restSpecMethod, len(actual))
+	for i := range actual {
+		actualByKey[actual[i].key()] = &actual[i]
+	}
+	expectedByKey := make(map[string]*restSpecMethod, len(expected))
+	for i := range expected {
+		e := &expected[i]
+		expectedByKey[e.key()] = e
+		methodPath := joinIDLPath(path, e.key())
+		a, ok := actualByKey[e.key()]
+		if !ok {
+			c.add(methodPath, false, "method was removed")
+			continue
+		}
+
+		c.compareDoc(methodPath, e.Doc, a.Doc)
+		c.compareCompatible(methodPath, "annotations", e.Annotations, a.Annotations)
+		c.compareCompatible(methodPath, "service errors", e.ServiceErrors, a.ServiceErrors)
+		c.compareCompatible(methodPath, "max batch size", e.MaxBatchSize, a.MaxBatchSize)
+		c.compareParameters(joinIDLPath(methodPath, "parameters"), e.Parameters, a.Parameters)
+		c.compareType(methodPath, "return type", e.Returns, a.Returns)
+		c.compareType(methodPath, "batch param", e.BatchParam, a.BatchParam)
+		c.compareType(methodPath, "assoc keys", strings.Join(e.AssocKeys, ","), strings.Join(a.AssocKeys, ","))
+		if e.Metadata != nil || a.Metadata != nil {
+			var expectedMetadata, actualMetadata string
+			if e.Metadata != nil {
+				expectedMetadata = e.Metadata.Type
+			}
+			if a.Metadata != nil {
+				actualMetadata = a.Metadata.Type
+			}
+			c.compareType(methodPath, "metadata type", expectedMetadata, actualMetadata)
+		}
+		if e.PagingSupported != a.PagingSupported {
+			c.add(methodPath, a.PagingSupported, "pagingSupported changed from %t to %t", e.PagingSupported, a.PagingSupported)
+		}
+	}
+	for i := range actual {
+		if _, ok := expectedByKey[actual[i].key()]; !ok {
+			c.add(joinIDLPath(path, actual[i].key()), true, "method was added")
+		}
+	}
+}
+
+func (c *idlComparator) compareParameters(path string, expected, actual []restSpecParameter) {
+	actualByName := make(map[string]*restSpecParameter, len(actual))
+	for i := range actual {
+		actualByName[actual[i].Name] = &actual[i]
+	}
+	expectedByName := make(map[string]*restSpecParameter, len(expected))
+	for i := range expected {
+		e := &expected[i]
+		expectedByName[e.Name] = e
+		paramPath := joinIDLPath(path, e.Name)
+		a, ok := actualByName[e.Name]
+		if !ok {
+			c.add(paramPath, false, "parameter was removed")
+			continue
+		}
+
+		c.compareDoc(paramPath, e.Doc, a.Doc)
+		c.compareType(paramPath, "type", e.Type, a.Type)
+		expectedRequired, actualRequired := e.isRequired(), a.isRequired()
+		if expectedRequired != actualRequired {
+			c.add(paramPath, !actualRequired, "parameter changed from %s to %s",
+				requiredString(expectedRequired), requiredString(actualRequired))
+		}
+		if !reflect.DeepEqual(e.Default, a.Default) {
+			c.add(paramPath, true, "default value changed")
+		}
+	}
+	for i := range actual {
+		a := &actual[i]
+		if _, ok := expectedByName[a.Name]; !ok {
+			c.add(joinIDLPath(path, a.Name), !a.isRequired(), "%s parameter was added", requiredString(a.isRequired()))
+		}
+	}
+}
+
+func (p *restSpecParameter) isRequired() bool {
+	return !p.Optional && p.Default == nil
+}
+
+func requiredString(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
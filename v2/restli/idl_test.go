@@ -0,0 +1,283 @@
+package restli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	idlFruitsSegments = []ResourcePathSegment{NewResourcePathSegment("fruits", true)}
+	idlColorSegments  = []ResourcePathSegment{NewResourcePathSegment("fruits", true), NewResourcePathSegment("color", false)}
+)
+
+func newIDLTestSchema() *ResourceSchema {
+	return &ResourceSchema{
+		Namespace:      "com.example",
+		Doc:            "All the fruits",
+		Schema:         "com.example.Fruit",
+		Key:            &KeySchema{Name: "fruitsId", Type: "long"},
+		ReadOnlyFields: []string{"id"},
+		Methods: []MethodSchema{
+			{Method: Method_get, ServiceErrors: []ServiceErrorDefinition{testServiceErrorWithoutDetails}},
+			{Method: Method_create, ReturnEntity: true},
+			{Method: Method_batch_get, MaxBatchSize: &MaxBatchSize{Value: 10, Validate: true}},
+			{
+				Method:          Method_finder,
+				Name:            "search",
+				PagingSupported: true,
+				Params: []ParameterSchema{
+					{Name: "name", Type: "string"},
+					{Name: "tags", Type: `{"type":"array","items":"string"}`, Optional: true},
+				},
+			},
+			{Method: Method_finder, Name: "all"},
+			{Method: Method_action, Name: "ripen", OnEntity: true, Returns: "boolean"},
+		},
+		DataSchemas: map[string]string{
+			"com.example.Fruit": "namespace com.example\n\nrecord Fruit {}\n",
+		},
+	}
+}
+
+func newIDLTestServer(schema *ResourceSchema) Server {
+	s := NewServer()
+	DeclareResourceSchema(s, idlFruitsSegments, schema)
+	DeclareResourceSchema(s, idlColorSegments, &ResourceSchema{
+		Namespace: "com.example.fruits",
+		Schema:    "com.example.Color",
+		Methods:   []MethodSchema{{Method: Method_get}},
+	})
+	// Resources that do not declare their schema are skipped
+	s.subNode([]ResourcePathSegment{NewResourcePathSegment("undeclared", false)})
+	return s
+}
+
+func TestGenerateIDL(t *testing.T) {
+	idl, err := GenerateIDL(newIDLTestServer(newIDLTestSchema()))
+	require.NoError(t, err)
+
+	require.Len(t, idl.RestSpecs, 1)
+	require.JSONEq(t, `{
+  "annotations": {"readOnly": {"value": ["id"]}},
+  "name": "fruits",
+  "namespace": "com.example",
+  "path": "/fruits",
+  "schema": "com.example.Fruit",
+  "doc": "All the fruits",
+  "collection": {
+    "identifier": {"name": "fruitsId", "type": "long"},
+    "supports": ["batch_get", "create", "get"],
+    "methods": [
+      {"method": "get", "serviceErrors": [{"status": 404, "code": "WITHOUT_DETAILS"}]},
+      {"method": "create", "annotations": {"returnEntity": {}}},
+      {"method": "batch_get", "maxBatchSize": {"value": 10, "validate": true}}
+    ],
+    "finders": [
+      {
+        "name": "search",
+        "parameters": [
+          {"name": "name", "type": "string"},
+          {"name": "tags", "type": "{\"type\":\"array\",\"items\":\"string\"}", "optional": true}
+        ],
+        "pagingSupported": true
+      },
+      {"name": "all", "parameters": []}
+    ],
+    "entity": {
+      "path": "/fruits/{fruitsId}",
+      "actions": [{"name": "ripen", "returns": "boolean"}],
+      "subresources": [{
+        "name": "color",
+        "namespace": "com.example.fruits",
+        "path": "/fruits/{fruitsId}/color",
+        "schema": "com.example.Color",
+        "simple": {
+          "supports": ["get"],
+          "methods": [{"method": "get"}],
+          "entity": {"path": "/fruits/{fruitsId}/color"}
+        }
+      }]
+    }
+  }
+}`, string(idl.RestSpecs["com.example.fruits.restspec.json"]))
+
+	require.Equal(t, map[string][]byte{
+		filepath.Join("com", "example", "Fruit.pdl"): []byte("namespace com.example\n\nrecord Fruit {}\n"),
+	}, idl.Schemas)
+}
+
+func TestGenerateIDLAssociation(t *testing.T) {
+	s := NewServer()
+	DeclareResourceSchema(s, []ResourcePathSegment{NewResourcePathSegment("links", true)}, &ResourceSchema{
+		Namespace: "com.example",
+		Schema:    "com.example.Link",
+		Key: &KeySchema{
+			Name:      "linksId",
+			AssocKeys: []AssocKeySchema{{Name: "src", Type: "long"}, {Name: "dest", Type: "string"}},
+		},
+		Methods: []MethodSchema{
+			{Method: Method_get},
+			{Method: Method_finder, Name: "bySrc", AssocKeys: []string{"src"}},
+		},
+	})
+	idl, err := GenerateIDL(s)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+  "name": "links",
+  "namespace": "com.example",
+  "path": "/links",
+  "schema": "com.example.Link",
+  "association": {
+    "identifier": "linksId",
+    "assocKeys": [{"name": "src", "type": "long"}, {"name": "dest", "type": "string"}],
+    "supports": ["get"],
+    "methods": [{"method": "get"}],
+    "finders": [{"name": "bySrc", "assocKeys": ["src"], "parameters": []}],
+    "entity": {"path": "/links/{linksId}"}
+  }
+}`, string(idl.RestSpecs["com.example.links.restspec.json"]))
+
+	restSpecDir := t.TempDir()
+	require.NoError(t, WriteIDL(s, restSpecDir, ""))
+
+	s = NewServer()
+	DeclareResourceSchema(s, []ResourcePathSegment{NewResourcePathSegment("links", true)}, &ResourceSchema{
+		Namespace: "com.example",
+		Schema:    "com.example.Link",
+		Key: &KeySchema{
+			Name:      "linksId",
+			AssocKeys: []AssocKeySchema{{Name: "src", Type: "string"}, {Name: "dest", Type: "string"}},
+		},
+		Methods: []MethodSchema{
+			{Method: Method_get},
+			{Method: Method_finder, Name: "bySrc", AssocKeys: []string{"dest"}},
+		},
+	})
+	diffs, err := CheckIDL(s, restSpecDir)
+	require.NoError(t, err)
+	require.Equal(t, []IDLDifference{
+		{Path: "com.example.links.restspec.json: assocKeys.src", Message: `type changed from "long" to "string"`},
+		{Path: "com.example.links.restspec.json: finders.bySrc", Message: `assoc keys changed from "src" to "dest"`},
+	}, diffs)
+}
+
+func TestGenerateIDLAlternativeKeys(t *testing.T) {
+	schema := func(altKeys ...AlternativeKeySchema) *ResourceSchema {
+		return &ResourceSchema{
+			Namespace:       "com.example",
+			Schema:          "com.example.Item",
+			Key:             &KeySchema{Name: "itemsId", Type: "long"},
+			AlternativeKeys: altKeys,
+			Methods:         []MethodSchema{{Method: Method_get}},
+		}
+	}
+	segments := []ResourcePathSegment{NewResourcePathSegment("items", true)}
+
+	s := NewServer()
+	DeclareResourceSchema(s, segments, schema(
+		AlternativeKeySchema{Name: "urn", Doc: "The urn of the item", Type: "string", KeyCoercer: "com.example.UrnCoercer"},
+	))
+	idl, err := GenerateIDL(s)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+  "name": "items",
+  "namespace": "com.example",
+  "path": "/items",
+  "schema": "com.example.Item",
+  "collection": {
+    "identifier": {"name": "itemsId", "type": "long"},
+    "alternativeKeys": [
+      {"name": "urn", "doc": "The urn of the item", "type": "string", "keyCoercer": "com.example.UrnCoercer"}
+    ],
+    "supports": ["get"],
+    "methods": [{"method": "get"}],
+    "entity": {"path": "/items/{itemsId}"}
+  }
+}`, string(idl.RestSpecs["com.example.items.restspec.json"]))
+
+	restSpecDir := t.TempDir()
+	require.NoError(t, WriteIDL(s, restSpecDir, ""))
+
+	s = NewServer()
+	DeclareResourceSchema(s, segments, schema(
+		AlternativeKeySchema{Name: "email", Type: "string", KeyCoercer: "com.example.EmailCoercer"},
+	))
+	diffs, err := CheckIDL(s, restSpecDir)
+	require.NoError(t, err)
+	require.Equal(t, []IDLDifference{
+		{Path: "com.example.items.restspec.json: alternativeKeys", Message: `alternative key "urn" removed`},
+		{Path: "com.example.items.restspec.json: alternativeKeys", Message: `alternative key "email" added`, Compatible: true},
+	}, diffs)
+}
+
+func TestGenerateIDLUndeclaredParent(t *testing.T) {
+	s := NewServer()
+	DeclareResourceSchema(s, idlColorSegments, &ResourceSchema{Schema: "com.example.Color"})
+	_, err := GenerateIDL(s)
+	require.Error(t, err)
+}
+
+func TestCheckIDL(t *testing.T) {
+	restSpecDir, schemaDir := t.TempDir(), t.TempDir()
+	require.NoError(t, WriteIDL(newIDLTestServer(newIDLTestSchema()), restSpecDir, schemaDir))
+	require.FileExists(t, filepath.Join(schemaDir, "com", "example", "Fruit.pdl"))
+
+	diffs, err := CheckIDL(newIDLTestServer(newIDLTestSchema()), restSpecDir)
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+
+	t.Run("compatible", func(t *testing.T) {
+		schema := newIDLTestSchema()
+		schema.Doc = "Some fruits"
+		schema.Methods[3].Params = append(schema.Methods[3].Params, ParameterSchema{Name: "color", Type: "string", Optional: true})
+		schema.Methods = append(schema.Methods, MethodSchema{Method: Method_delete})
+
+		diffs, err := CheckIDL(newIDLTestServer(schema), restSpecDir)
+		require.NoError(t, err)
+		require.Equal(t, []IDLDifference{
+			{Path: "com.example.fruits.restspec.json:", Message: "doc changed", Compatible: true},
+			{Path: "com.example.fruits.restspec.json: methods.delete", Message: "method was added", Compatible: true},
+			{
+				Path:       "com.example.fruits.restspec.json: finders.search.parameters.color",
+				Message:    "optional parameter was added",
+				Compatible: true,
+			},
+		}, diffs)
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		schema := newIDLTestSchema()
+		schema.Key.Type = "string"
+		schema.Methods[3].Params[1].Optional = false
+		schema.Methods[3].PagingSupported = false
+		schema.Methods = schema.Methods[:4]
+
+		diffs, err := CheckIDL(newIDLTestServer(schema), restSpecDir)
+		require.NoError(t, err)
+		require.Equal(t, []IDLDifference{
+			{Path: "com.example.fruits.restspec.json:", Message: `key type changed from "long" to "string"`},
+			{
+				Path:    "com.example.fruits.restspec.json: finders.search.parameters.tags",
+				Message: "parameter changed from optional to required",
+			},
+			{
+				Path:    "com.example.fruits.restspec.json: finders.search",
+				Message: "pagingSupported changed from true to false",
+			},
+			{Path: "com.example.fruits.restspec.json: finders.all", Message: "method was removed"},
+			{Path: "com.example.fruits.restspec.json: entity.actions.ripen", Message: "method was removed"},
+		}, diffs)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		diffs, err := CheckIDL(NewServer(), restSpecDir)
+		require.NoError(t, err)
+		require.Equal(t, []IDLDifference{
+			{Path: "com.example.fruits.restspec.json", Message: "resource was removed"},
+		}, diffs)
+	})
+}
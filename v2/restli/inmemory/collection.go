@@ -0,0 +1,612 @@
+// Package inmemory provides a generic, concurrency-safe implementation of the rest methods of a collection resource,
+// intended to be used as a fake in tests or to quickly prototype a resource. The code generator emits a
+// NewInMemoryResource function alongside each collection's MockResource, which wires an InMemoryCollection into the
+// resource's methods:
+//
+//	collection := inmemory.NewInMemoryCollection[int64, *Message](ReadOnlyFields, CreateAndReadOnlyFields)
+//	RegisterResource(server, collectiontest.NewInMemoryResource(collection))
+package inmemory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PapaCharlie/go-restli/v2/fnv1a"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restli/batchkeyset"
+	"github.com/PapaCharlie/go-restli/v2/restli/patch"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+// InMemoryCollection stores the entities of a collection resource in memory. Its methods have the same signatures as
+// the corresponding methods of a generated Resource interface (minus any query parameters), and can be passed directly
+// to the corresponding restli.Register functions. Entities are stored in their serialized form, meaning the entities
+// passed to and returned by the collection can be freely modified by the caller.
+//
+// Keys are indexed by their fnv1a hash and compared with their Equals (or ComplexKeyEquals) method when they have one,
+// which means complex keys can be used. Read-only fields are stripped from created entities, and both read-only and
+// create-only fields are preserved across updates and rejected in partial updates.
+type InMemoryCollection[K comparable, V restlicodec.Marshaler] struct {
+	// KeyGenerator returns the key of every newly created entity. It is called while the collection is locked, and the
+	// generated key must not already be used. It defaults to a sequence of unused keys if K is an int32, int64 or
+	// string, and must be set for all other key types in order to create entities.
+	KeyGenerator func() (K, error)
+
+	readOnlyFields          restlicodec.PathSpec
+	createAndReadOnlyFields restlicodec.PathSpec
+	hash                    func(K) fnv1a.Hash
+	equals                  func(left, right K) bool
+
+	lock     sync.RWMutex
+	entries  map[fnv1a.HashMapKey][]*entry[K]
+	size     int
+	sequence uint64
+	lastKey  uint64
+}
+
+type entry[K any] struct {
+	key K
+	// entity is the entity's JSON representation, as decoded by encoding/json
+	entity   map[string]any
+	sequence uint64
+}
+
+// NewInMemoryCollection returns a new empty InMemoryCollection. The given PathSpecs should be the resource's
+// ReadOnlyFields and CreateAndReadOnlyFields (or restlicodec.NoExcludedFields if the resource declares none).
+func NewInMemoryCollection[K comparable, V restlicodec.Marshaler](
+	readOnlyFields, createAndReadOnlyFields restlicodec.PathSpec,
+) *InMemoryCollection[K, V] {
+	c := &InMemoryCollection[K, V]{
+		readOnlyFields:          readOnlyFields,
+		createAndReadOnlyFields: createAndReadOnlyFields,
+		entries:                 make(map[fnv1a.HashMapKey][]*entry[K]),
+	}
+	c.hash, c.equals = keyFuncs[K]()
+	c.KeyGenerator = c.defaultKeyGenerator()
+	return c
+}
+
+func keyFuncs[K comparable]() (hash func(K) fnv1a.Hash, equals func(left, right K) bool) {
+	var k K
+	switch any(k).(type) {
+	case batchkeyset.ComplexKey[K]:
+		return func(k K) fnv1a.Hash { return any(k).(batchkeyset.ComplexKey[K]).ComputeComplexKeyHash() },
+			func(left, right K) bool { return any(left).(batchkeyset.ComplexKey[K]).ComplexKeyEquals(right) }
+	case batchkeyset.SimpleKey[K]:
+		return func(k K) fnv1a.Hash { return any(k).(batchkeyset.SimpleKey[K]).ComputeHash() },
+			func(left, right K) bool { return any(left).(batchkeyset.SimpleKey[K]).Equals(right) }
+	default:
+		return func(k K) fnv1a.Hash { return fnv1a.HashString(formatKey(k)) },
+			func(left, right K) bool { return left == right }
+	}
+}
+
+func (c *InMemoryCollection[K, V]) defaultKeyGenerator() func() (K, error) {
+	var k K
+	var next func() K
+	switch any(k).(type) {
+	case int32:
+		next = func() K { return any(int32(c.lastKey)).(K) }
+	case int64:
+		next = func() K { return any(int64(c.lastKey)).(K) }
+	case string:
+		next = func() K { return any(strconv.FormatUint(c.lastKey, 10)).(K) }
+	default:
+		return nil
+	}
+	return func() (K, error) {
+		for {
+			c.lastKey++
+			if k := next(); c.find(k) == nil {
+				return k, nil
+			}
+		}
+	}
+}
+
+func formatKey[K any](k K) string {
+	w := restlicodec.NewRor2HeaderWriter()
+	if err := restlicodec.MarshalRestLi(k, w); err != nil {
+		return fmt.Sprintf("%+v", k)
+	}
+	return w.Finalize()
+}
+
+func notFound[K any](k K) *common.ErrorResponse {
+	return &common.ErrorResponse{
+		Status:  restli.Int32Pointer(http.StatusNotFound),
+		Message: restli.StringPointerf("No entity with key %q", formatKey(k)),
+	}
+}
+
+func (c *InMemoryCollection[K, V]) find(k K) *entry[K] {
+	for _, e := range c.entries[c.hash(k).MapKey()] {
+		if c.equals(e.key, k) {
+			return e
+		}
+	}
+	return nil
+}
+
+func (c *InMemoryCollection[K, V]) put(k K, entity map[string]any) {
+	if e := c.find(k); e != nil {
+		e.entity = entity
+		return
+	}
+	c.sequence++
+	h := c.hash(k).MapKey()
+	c.entries[h] = append(c.entries[h], &entry[K]{key: k, entity: entity, sequence: c.sequence})
+	c.size++
+}
+
+func (c *InMemoryCollection[K, V]) remove(k K) bool {
+	h := c.hash(k).MapKey()
+	entries := c.entries[h]
+	for i, e := range entries {
+		if c.equals(e.key, k) {
+			entries = append(entries[:i:i], entries[i+1:]...)
+			if len(entries) == 0 {
+				delete(c.entries, h)
+			} else {
+				c.entries[h] = entries
+			}
+			c.size--
+			return true
+		}
+	}
+	return false
+}
+
+func (c *InMemoryCollection[K, V]) encode(v V) (map[string]any, error) {
+	w := restlicodec.NewCompactJsonWriter()
+	err := v.MarshalRestLi(w)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJson(w.Finalize())
+}
+
+func decodeJson(data string) (m map[string]any, err error) {
+	d := json.NewDecoder(strings.NewReader(data))
+	// Preserve the exact value of int64s
+	d.UseNumber()
+	err = d.Decode(&m)
+	return m, err
+}
+
+func (c *InMemoryCollection[K, V]) decode(entity map[string]any) (v V, err error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return v, err
+	}
+	r, err := restlicodec.NewJsonReader(data)
+	if err != nil {
+		return v, err
+	}
+	v, err = restlicodec.UnmarshalRestLi[V](r)
+	// Required read-only fields are stripped on creation, so missing fields are expected
+	if _, mfe := err.(*restlicodec.MissingRequiredFieldsError); mfe {
+		err = nil
+	}
+	return v, err
+}
+
+// Put inserts or replaces the entity with the given key, bypassing any field checks. It is intended to be used to seed
+// the collection.
+func (c *InMemoryCollection[K, V]) Put(key K, entity V) error {
+	encoded, err := c.encode(entity)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.put(key, encoded)
+	return nil
+}
+
+// Len returns the number of entities in the collection.
+func (c *InMemoryCollection[K, V]) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.size
+}
+
+// Get returns the entity with the given key, or a 404 if it does not exist.
+func (c *InMemoryCollection[K, V]) Get(_ *restli.RequestContext, key K) (entity V, err error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	e := c.find(key)
+	if e == nil {
+		return entity, notFound(key)
+	}
+	return c.decode(e.entity)
+}
+
+// GetAll returns all the entities in the collection, in the order in which they were first inserted.
+func (c *InMemoryCollection[K, V]) GetAll(*restli.RequestContext) (*common.Elements[V], error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	entries := make([]*entry[K], 0, c.size)
+	for _, es := range c.entries {
+		entries = append(entries, es...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].sequence < entries[j].sequence })
+	elements := &common.Elements[V]{Elements: make([]V, len(entries))}
+	for i, e := range entries {
+		var err error
+		elements.Elements[i], err = c.decode(e.entity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return elements, nil
+}
+
+// BatchGet returns the entities with the given keys. Keys that do not exist have a 404 error in the response.
+func (c *InMemoryCollection[K, V]) BatchGet(ctx *restli.RequestContext, keys []K) (*common.BatchResponse[K, V], error) {
+	results := new(common.BatchResponse[K, V])
+	for _, k := range keys {
+		v, err := c.Get(ctx, k)
+		if err != nil {
+			if errRes, ok := err.(*common.ErrorResponse); ok {
+				results.AddError(k, errRes)
+				continue
+			}
+			return nil, err
+		}
+		results.AddResult(k, v)
+	}
+	return results, nil
+}
+
+func (c *InMemoryCollection[K, V]) create(ctx *restli.RequestContext, entity V) (*common.CreatedEntity[K], error) {
+	encoded, err := c.encode(entity)
+	if err != nil {
+		return nil, err
+	}
+	copyFields(encoded, nil, c.readOnlyFields)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.KeyGenerator == nil {
+		return nil, &common.ErrorResponse{
+			Status:  restli.Int32Pointer(http.StatusInternalServerError),
+			Message: restli.StringPointer("InMemoryCollection.KeyGenerator is not set"),
+		}
+	}
+	k, err := c.KeyGenerator()
+	if err != nil {
+		return nil, err
+	}
+	if c.find(k) != nil {
+		return nil, &common.ErrorResponse{
+			Status:  restli.Int32Pointer(http.StatusConflict),
+			Message: restli.StringPointerf("Entity with key %q already exists", formatKey(k)),
+		}
+	}
+	c.put(k, encoded)
+
+	created := &common.CreatedEntity[K]{Id: k, Status: http.StatusCreated}
+	if ctx != nil {
+		_ = restli.SetLocation(ctx, created)
+	}
+	return created, nil
+}
+
+// Create inserts the given entity under a new key (see KeyGenerator), after stripping any read-only fields.
+func (c *InMemoryCollection[K, V]) Create(ctx *restli.RequestContext, entity V) (*common.CreatedEntity[K], error) {
+	return c.create(ctx, entity)
+}
+
+// CreateWithReturnEntity calls Create and also returns the entity as it was stored.
+func (c *InMemoryCollection[K, V]) CreateWithReturnEntity(
+	ctx *restli.RequestContext,
+	entity V,
+) (*common.CreatedAndReturnedEntity[K, V], error) {
+	created, err := c.create(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.Get(ctx, created.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &common.CreatedAndReturnedEntity[K, V]{CreatedEntity: *created, Entity: v}, nil
+}
+
+// BatchCreate calls Create for each entity, stopping at the first entity that could not be created.
+func (c *InMemoryCollection[K, V]) BatchCreate(
+	ctx *restli.RequestContext,
+	entities []V,
+) ([]*common.CreatedEntity[K], error) {
+	created := make([]*common.CreatedEntity[K], len(entities))
+	for i, v := range entities {
+		var err error
+		created[i], err = c.create(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+// BatchCreateWithReturnEntity calls CreateWithReturnEntity for each entity, stopping at the first entity that could not
+// be created.
+func (c *InMemoryCollection[K, V]) BatchCreateWithReturnEntity(
+	ctx *restli.RequestContext,
+	entities []V,
+) ([]*common.CreatedAndReturnedEntity[K, V], error) {
+	created := make([]*common.CreatedAndReturnedEntity[K, V], len(entities))
+	for i, v := range entities {
+		var err error
+		created[i], err = c.CreateWithReturnEntity(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+// Update replaces the entity with the given key, preserving any read-only or create-only fields. It returns a 404 if
+// the entity does not exist.
+func (c *InMemoryCollection[K, V]) Update(_ *restli.RequestContext, key K, entity V) error {
+	encoded, err := c.encode(entity)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e := c.find(key)
+	if e == nil {
+		return notFound(key)
+	}
+	copyFields(encoded, e.entity, c.createAndReadOnlyFields)
+	e.entity = encoded
+	return nil
+}
+
+// BatchUpdate calls Update for each entity, and reports the status of each update in the response.
+func (c *InMemoryCollection[K, V]) BatchUpdate(
+	ctx *restli.RequestContext,
+	entities map[K]V,
+) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
+	return batchUpdate(entities, func(k K, v V) error {
+		return c.Update(ctx, k, v)
+	})
+}
+
+func batchUpdate[K comparable, V any](
+	entities map[K]V,
+	update func(K, V) error,
+) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
+	results := new(common.BatchResponse[K, *common.BatchEntityUpdateResponse])
+	for k, v := range entities {
+		err := update(k, v)
+		if err != nil {
+			if errRes, ok := err.(*common.ErrorResponse); ok {
+				results.AddError(k, errRes)
+				continue
+			}
+			return nil, err
+		}
+		results.AddResult(k, &common.BatchEntityUpdateResponse{Status: http.StatusNoContent})
+	}
+	return results, nil
+}
+
+// PartialUpdate applies the given patch (a generated _PartialUpdate struct) to the entity with the given key. It
+// returns a 404 if the entity does not exist, and a 400 if the patch modifies read-only or create-only fields or if
+// the patched entity is invalid.
+func (c *InMemoryCollection[K, V]) PartialUpdate(_ *restli.RequestContext, key K, patch restlicodec.Marshaler) error {
+	_, err := c.partialUpdate(key, patch, false)
+	return err
+}
+
+// PartialUpdateWithReturnEntity calls PartialUpdate and returns the patched entity.
+func (c *InMemoryCollection[K, V]) PartialUpdateWithReturnEntity(
+	_ *restli.RequestContext,
+	key K,
+	patch restlicodec.Marshaler,
+) (V, error) {
+	return c.partialUpdate(key, patch, true)
+}
+
+func (c *InMemoryCollection[K, V]) partialUpdate(key K, p restlicodec.Marshaler, returnEntity bool) (v V, err error) {
+	w := restlicodec.NewCompactJsonWriter()
+	err = p.MarshalRestLi(w)
+	if err != nil {
+		return v, err
+	}
+	wrapper, err := decodeJson(w.Finalize())
+	if err != nil {
+		return v, err
+	}
+	patchData, _ := wrapper[patch.PatchField].(map[string]any)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e := c.find(key)
+	if e == nil {
+		return v, notFound(key)
+	}
+
+	// Apply the patch on a copy of the entity, so that the original is left untouched should the patch be invalid
+	patched, err := decodeJson(mustMarshal(e.entity))
+	if err != nil {
+		return v, err
+	}
+	err = c.applyPatch(patched, patchData, nil)
+	if err != nil {
+		return v, &common.ErrorResponse{
+			Status:  restli.Int32Pointer(http.StatusBadRequest),
+			Message: restli.StringPointerf("Invalid partial update: %s", err),
+		}
+	}
+	v, err = c.decode(patched)
+	if err != nil {
+		return v, &common.ErrorResponse{
+			Status:  restli.Int32Pointer(http.StatusBadRequest),
+			Message: restli.StringPointerf("Partial update produced an invalid entity: %s", err),
+		}
+	}
+	e.entity = patched
+	if !returnEntity {
+		var zero V
+		v = zero
+	}
+	return v, nil
+}
+
+func mustMarshal(m map[string]any) string {
+	data, err := json.Marshal(m)
+	if err != nil {
+		// The map was decoded by encoding/json, so it can always be marshaled back
+		panic(err)
+	}
+	return string(data)
+}
+
+// applyPatch applies a rest.li patch (see https://linkedin.github.io/rest.li/spec/protocol#partial-update) to the given
+// entity
+func (c *InMemoryCollection[K, V]) applyPatch(entity, patchData map[string]any, path []string) error {
+	checkField := func(field string) error {
+		fieldPath := append(append([]string(nil), path...), field)
+		if c.createAndReadOnlyFields.Matches(fieldPath) {
+			return fmt.Errorf("cannot modify read-only or create-only field %q", strings.Join(fieldPath, "/"))
+		}
+		return nil
+	}
+
+	for key, value := range patchData {
+		switch key {
+		case "$delete":
+			fields, _ := value.([]any)
+			for _, f := range fields {
+				field, ok := f.(string)
+				if !ok {
+					return fmt.Errorf("illegal $delete field: %v", f)
+				}
+				if err := checkField(field); err != nil {
+					return err
+				}
+				delete(entity, field)
+			}
+		case "$set":
+			fields, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("illegal $set: %v", value)
+			}
+			for field, v := range fields {
+				if err := checkField(field); err != nil {
+					return err
+				}
+				entity[field] = v
+			}
+		default:
+			nestedPatch, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("illegal patch for field %q: %v", key, value)
+			}
+			nested, _ := entity[key].(map[string]any)
+			if nested == nil {
+				nested = make(map[string]any)
+			}
+			err := c.applyPatch(nested, nestedPatch, append(path, key))
+			if err != nil {
+				return err
+			}
+			entity[key] = nested
+		}
+	}
+	return nil
+}
+
+// BatchPartialUpdate calls PartialUpdate for each patch, and reports the status of each update in the response. It is
+// a function rather than a method since the type of the patches cannot be expressed as a type parameter of the
+// collection.
+func BatchPartialUpdate[K comparable, V, P restlicodec.Marshaler](
+	c *InMemoryCollection[K, V],
+	ctx *restli.RequestContext,
+	patches map[K]P,
+) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
+	return batchUpdate(patches, func(k K, p P) error {
+		return c.PartialUpdate(ctx, k, p)
+	})
+}
+
+// Delete removes the entity with the given key, or returns a 404 if it does not exist.
+func (c *InMemoryCollection[K, V]) Delete(_ *restli.RequestContext, key K) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.remove(key) {
+		return notFound(key)
+	}
+	return nil
+}
+
+// BatchDelete calls Delete for each key, and reports the status of each deletion in the response.
+func (c *InMemoryCollection[K, V]) BatchDelete(
+	ctx *restli.RequestContext,
+	keys []K,
+) (*common.BatchResponse[K, *common.BatchEntityUpdateResponse], error) {
+	entities := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		entities[k] = struct{}{}
+	}
+	return batchUpdate(entities, func(k K, _ struct{}) error {
+		return c.Delete(ctx, k)
+	})
+}
+
+// copyFields copies all the fields matched by the given PathSpec from src to dst. Fields that are absent from src are
+// deleted from dst.
+func copyFields(dst, src map[string]any, spec restlicodec.PathSpec) {
+	for field, nestedSpec := range spec {
+		var fields []string
+		if field == restlicodec.WildCard {
+			for f := range dst {
+				fields = append(fields, f)
+			}
+			for f := range src {
+				if _, ok := dst[f]; !ok {
+					fields = append(fields, f)
+				}
+			}
+		} else {
+			fields = []string{field}
+		}
+
+		for _, f := range fields {
+			srcValue, inSrc := src[f]
+			if len(nestedSpec) == 0 {
+				if inSrc {
+					dst[f] = srcValue
+				} else {
+					delete(dst, f)
+				}
+				continue
+			}
+
+			srcNested, _ := srcValue.(map[string]any)
+			dstNested, _ := dst[f].(map[string]any)
+			if dstNested == nil {
+				if srcNested == nil {
+					continue
+				}
+				dstNested = make(map[string]any)
+				dst[f] = dstNested
+			}
+			copyFields(dstNested, srcNested, nestedSpec)
+		}
+	}
+}
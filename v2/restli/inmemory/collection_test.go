@@ -0,0 +1,128 @@
+package inmemory
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCollection() *InMemoryCollection[int64, *common.Link] {
+	// "type" is read-only and "rel" is create-only
+	return NewInMemoryCollection[int64, *common.Link](
+		restlicodec.NewPathSpec("type"),
+		restlicodec.NewPathSpec("rel", "type"),
+	)
+}
+
+func requireStatus(t *testing.T, status int, err error) {
+	t.Helper()
+	require.IsType(t, new(common.ErrorResponse), err)
+	require.Equal(t, int32(status), *err.(*common.ErrorResponse).Status)
+}
+
+func TestInMemoryCollection(t *testing.T) {
+	c := newTestCollection()
+
+	created, err := c.BatchCreate(nil, []*common.Link{
+		{Rel: "self", Href: "/1", Type: "ignored"},
+		{Rel: "next", Href: "/2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created[0].Id)
+	require.Equal(t, int64(2), created[1].Id)
+	require.Equal(t, http.StatusCreated, created[0].Status)
+	require.Equal(t, 2, c.Len())
+
+	link, err := c.Get(nil, 1)
+	require.NoError(t, err)
+	require.Equal(t, &common.Link{Rel: "self", Href: "/1"}, link)
+
+	// Returned entities are copies
+	link.Href = "modified"
+	link, _ = c.Get(nil, 1)
+	require.Equal(t, "/1", link.Href)
+
+	_, err = c.Get(nil, 3)
+	requireStatus(t, http.StatusNotFound, err)
+
+	err = c.Update(nil, 1, &common.Link{Rel: "other", Href: "/updated", Type: "other"})
+	require.NoError(t, err)
+	link, _ = c.Get(nil, 1)
+	require.Equal(t, &common.Link{Rel: "self", Href: "/updated"}, link)
+
+	requireStatus(t, http.StatusNotFound, c.Update(nil, 3, new(common.Link)))
+
+	p := new(common.Link_PartialUpdate)
+	p.Set_Fields.Href = new(string)
+	*p.Set_Fields.Href = "/patched"
+	link, err = c.PartialUpdateWithReturnEntity(nil, 2, p)
+	require.NoError(t, err)
+	require.Equal(t, &common.Link{Rel: "next", Href: "/patched"}, link)
+
+	p = new(common.Link_PartialUpdate)
+	p.Set_Fields.Rel = new(string)
+	requireStatus(t, http.StatusBadRequest, c.PartialUpdate(nil, 2, p))
+
+	batchGet, err := c.BatchGet(nil, []int64{1, 3})
+	require.NoError(t, err)
+	require.Equal(t, &common.Link{Rel: "self", Href: "/updated"}, batchGet.Results[1])
+	require.Equal(t, int32(http.StatusNotFound), *batchGet.Errors[3].Status)
+
+	p = new(common.Link_PartialUpdate)
+	p.Set_Fields.Href = new(string)
+	batchUpdate, err := BatchPartialUpdate(c, nil, map[int64]*common.Link_PartialUpdate{1: p, 3: p})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, batchUpdate.Results[1].Status)
+	require.Equal(t, int32(http.StatusNotFound), *batchUpdate.Errors[3].Status)
+
+	require.NoError(t, c.Put(5, &common.Link{Rel: "seeded", Type: "seeded"}))
+	created, err = c.BatchCreate(nil, []*common.Link{{}, {}, {}})
+	require.NoError(t, err)
+	// The generated keys skip over the seeded key
+	require.Equal(t, []int64{3, 4, 6}, []int64{created[0].Id, created[1].Id, created[2].Id})
+
+	all, err := c.GetAll(nil)
+	require.NoError(t, err)
+	require.Equal(t, []*common.Link{
+		{Rel: "self"},
+		{Rel: "next", Href: "/patched"},
+		{Rel: "seeded", Type: "seeded"},
+		{},
+		{},
+		{},
+	}, all.Elements)
+
+	require.NoError(t, c.Delete(nil, 1))
+	requireStatus(t, http.StatusNotFound, c.Delete(nil, 1))
+
+	batchDelete, err := c.BatchDelete(nil, []int64{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, batchDelete.Results[2].Status)
+	require.Equal(t, int32(http.StatusNotFound), *batchDelete.Errors[1].Status)
+	require.Equal(t, 4, c.Len())
+}
+
+func TestInMemoryCollectionComplexKeys(t *testing.T) {
+	c := NewInMemoryCollection[*common.Link, *common.Link](nil, nil)
+
+	_, err := c.Create(nil, new(common.Link))
+	requireStatus(t, http.StatusInternalServerError, err)
+
+	c.KeyGenerator = func() (*common.Link, error) {
+		return &common.Link{Href: "/generated"}, nil
+	}
+	created, err := c.Create(nil, &common.Link{Rel: "generated"})
+	require.NoError(t, err)
+	require.Equal(t, &common.Link{Href: "/generated"}, created.Id)
+
+	// Keys are compared by value, not by pointer
+	link, err := c.Get(nil, &common.Link{Href: "/generated"})
+	require.NoError(t, err)
+	require.Equal(t, &common.Link{Rel: "generated"}, link)
+
+	_, err = c.Create(nil, &common.Link{Rel: "conflict"})
+	requireStatus(t, http.StatusConflict, err)
+}
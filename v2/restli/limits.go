@@ -0,0 +1,119 @@
+package restli
+
+import (
+	"io"
+	"net/http"
+)
+
+// Limits bound the size of the requests a Server will accept, protecting it from callers that would otherwise be able
+// to exhaust its memory. A zero value for any field means the corresponding limit is inherited from the enclosing
+// scope (the Server for resources, the resource for sub-resources and methods), and a negative value explicitly
+// removes an inherited limit. Limits that are never set are not enforced.
+type Limits struct {
+	// MaxRequestBodySize is the maximum number of bytes that will be read from a request's body. Requests with larger
+	// bodies are rejected with a 413 (Request Entity Too Large).
+	MaxRequestBodySize int64
+	// MaxTunnelledQuerySize is the maximum size, in bytes, of a query tunnelled through the body of a POST request (see
+	// DecodeTunnelledQuery). Requests with larger tunnelled queries are rejected with a 413 (Request Entity Too Large).
+	// Because the query is untunnelled before the request is routed, only the Server's and the root resource's limits
+	// are considered.
+	MaxTunnelledQuerySize int64
+	// MaxBatchSize is the maximum number of keys or entities a single batch request can specify. Requests with more
+	// keys or entities are rejected with a 400 (Bad Request).
+	MaxBatchSize int
+}
+
+// merge returns a copy of l where all the non-zero fields in override take precedence
+func (l Limits) merge(override *Limits) Limits {
+	if override == nil {
+		return l
+	}
+	if override.MaxRequestBodySize != 0 {
+		l.MaxRequestBodySize = override.MaxRequestBodySize
+	}
+	if override.MaxTunnelledQuerySize != 0 {
+		l.MaxTunnelledQuerySize = override.MaxTunnelledQuerySize
+	}
+	if override.MaxBatchSize != 0 {
+		l.MaxBatchSize = override.MaxBatchSize
+	}
+	return l
+}
+
+func exceedsLimit[N int | int64](limit, n N) bool {
+	return limit > 0 && n > limit
+}
+
+// SetLimits sets the default Limits for all resources registered against the given Server.
+func SetLimits(s Server, limits Limits) {
+	SetResourceLimits(s, nil, limits)
+}
+
+// SetResourceLimits overrides the Server's Limits for the resource identified by the given segments, and any of its
+// sub-resources.
+func SetResourceLimits(s Server, segments []ResourcePathSegment, limits Limits) {
+	s.subNode(segments).limits = &limits
+}
+
+// SetMethodLimits overrides the resource's Limits for a single method. Note that MaxTunnelledQuerySize cannot be
+// overridden for individual methods. The maximum batch size declared by the method's @MaxBatchSize annotation (see
+// DeclareMaxBatchSize) still applies, unless limits.MaxBatchSize is non-zero.
+func SetMethodLimits(s Server, segments []ResourcePathSegment, method Method, limits Limits) {
+	p := s.subNode(segments)
+	if p.methodLimits == nil {
+		p.methodLimits = make(map[Method]Limits)
+	}
+	p.methodLimits[method] = limits
+}
+
+// DeclareMaxBatchSize declares the maximum batch size of the given method, as specified by its @MaxBatchSize annotation.
+// It is called by generated code when the annotation requests validation, and can be overridden with SetMethodLimits.
+func DeclareMaxBatchSize(s Server, segments []ResourcePathSegment, method Method, size int) {
+	p := s.subNode(segments)
+	if p.maxBatchSizes == nil {
+		p.maxBatchSizes = make(map[Method]int)
+	}
+	p.maxBatchSizes[method] = size
+}
+
+func newRequestEntityTooLargeError(format string, a ...any) error {
+	_, err := newErrorResponsef(nil, http.StatusRequestEntityTooLarge, format, a...)
+	return err
+}
+
+// readBody reads the entire body of the given request, returning an error if it is larger than maxSize
+func readBody(req *http.Request, maxSize int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if exceedsLimit(maxSize, req.ContentLength) {
+		return nil, newRequestEntityTooLargeError("Request body cannot exceed %d bytes (got %d)",
+			maxSize, req.ContentLength)
+	}
+	return readAtMost(req.Body, maxSize, "Request body")
+}
+
+// readAtMost reads the entire given reader, returning an error if it contains more than maxSize bytes. If maxSize is
+// not positive, the reader is read without limit.
+func readAtMost(r io.Reader, maxSize int64, name string) ([]byte, error) {
+	if maxSize <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if exceedsLimit(maxSize, int64(len(data))) {
+		return nil, newRequestEntityTooLargeError("%s cannot exceed %d bytes", name, maxSize)
+	}
+	return data, nil
+}
+
+func checkBatchSize(ctx *RequestContext, method Method, size int) error {
+	if exceedsLimit(ctx.limits.MaxBatchSize, size) {
+		_, err := newErrorResponsef(nil, http.StatusBadRequest, "%q cannot specify more than %d keys or entities (got %d)",
+			method, ctx.limits.MaxBatchSize, size)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package restli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimitsTestServer() Server {
+	s := NewServer()
+	RegisterBatchCreate[int64](s, testCollectionSegments, nil,
+		func(_ *RequestContext, _ testResourcePath, entities []common.EmptyRecord, _ common.EmptyRecord) ([]*common.CreatedEntity[int64], error) {
+			created := make([]*common.CreatedEntity[int64], len(entities))
+			for i := range created {
+				created[i] = &common.CreatedEntity[int64]{Id: int64(i), Status: http.StatusCreated}
+			}
+			return created, nil
+		})
+	RegisterUpdate(s, testSimpleSegments, nil,
+		func(*RequestContext, testResourcePath, common.EmptyRecord, common.EmptyRecord) error {
+			return nil
+		})
+	return s
+}
+
+func newBatchCreateRequest(count int) *http.Request {
+	body := "{\"elements\":[" + strings.TrimSuffix(strings.Repeat("{},", count), ",") + "]}"
+	return newTestRequest(http.MethodPost, "/collection", Method_batch_create, body)
+}
+
+func newTunnelledUpdateRequest(query string) *http.Request {
+	body, headers := EncodeTunnelledQuery(http.MethodPut, query, []byte("{}"))
+	req := httptest.NewRequest(http.MethodPost, "/simple", strings.NewReader(string(body)))
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	return req
+}
+
+func TestMaxBatchSize(t *testing.T) {
+	s := newLimitsTestServer()
+
+	code, _ := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(10))
+	require.Equal(t, http.StatusOK, code)
+
+	SetLimits(s, Limits{MaxBatchSize: 5})
+	code, errRes := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(10))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, `"batch_create" cannot specify more than 5 keys or entities (got 10)`, *errRes.Message)
+
+	SetResourceLimits(s, testCollectionSegments, Limits{MaxBatchSize: 20})
+	code, _ = serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(10))
+	require.Equal(t, http.StatusOK, code)
+
+	SetMethodLimits(s, testCollectionSegments, Method_batch_create, Limits{MaxBatchSize: 2})
+	code, _ = serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(3))
+	require.Equal(t, http.StatusBadRequest, code)
+
+	SetMethodLimits(s, testCollectionSegments, Method_batch_create, Limits{MaxBatchSize: -1})
+	code, _ = serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(100))
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestDeclaredMaxBatchSize(t *testing.T) {
+	oversizedBody := func() *http.Request {
+		req := newBatchCreateRequest(1)
+		req.Body = io.NopCloser(strings.NewReader(`{"elements":[{}]}` + strings.Repeat(" ", 32)))
+		req.ContentLength = -1
+		return req
+	}
+
+	for _, declareFirst := range []bool{true, false} {
+		t.Run(fmt.Sprintf("declareFirst=%v", declareFirst), func(t *testing.T) {
+			s := newLimitsTestServer()
+			declare := func() { DeclareMaxBatchSize(s, testCollectionSegments, Method_batch_create, 2) }
+			if declareFirst {
+				declare()
+			}
+			SetMethodLimits(s, testCollectionSegments, Method_batch_create, Limits{MaxRequestBodySize: 32})
+			if !declareFirst {
+				declare()
+			}
+
+			code, _ := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(2))
+			require.Equal(t, http.StatusOK, code)
+
+			code, errRes := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(3))
+			require.Equal(t, http.StatusBadRequest, code)
+			require.Equal(t, `"batch_create" cannot specify more than 2 keys or entities (got 3)`, *errRes.Message)
+
+			code, _ = serveTestErrorRequest(t, s.Handler(), oversizedBody())
+			require.Equal(t, http.StatusRequestEntityTooLarge, code)
+		})
+	}
+
+	// The method's limits take precedence over the declared size
+	s := newLimitsTestServer()
+	DeclareMaxBatchSize(s, testCollectionSegments, Method_batch_create, 2)
+	SetMethodLimits(s, testCollectionSegments, Method_batch_create, Limits{MaxBatchSize: 5})
+	code, _ := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(5))
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestMaxRequestBodySize(t *testing.T) {
+	s := newLimitsTestServer()
+	SetLimits(s, Limits{MaxRequestBodySize: 32})
+
+	code, _ := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(2))
+	require.Equal(t, http.StatusOK, code)
+
+	code, errRes := serveTestErrorRequest(t, s.Handler(), newBatchCreateRequest(10))
+	require.Equal(t, http.StatusRequestEntityTooLarge, code)
+	require.Contains(t, *errRes.Message, "cannot exceed 32 bytes")
+
+	// Also check that bodies without a Content-Length are correctly limited
+	req := newBatchCreateRequest(10)
+	req.ContentLength = -1
+	code, _ = serveTestErrorRequest(t, s.Handler(), req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, code)
+}
+
+func TestMaxTunnelledQuerySize(t *testing.T) {
+	s := newLimitsTestServer()
+	SetResourceLimits(s, testSimpleSegments, Limits{MaxTunnelledQuerySize: 16, MaxRequestBodySize: 16})
+
+	code, _ := serveTestErrorRequest(t, s.Handler(), newTunnelledUpdateRequest("foo=bar"))
+	require.Equal(t, http.StatusNoContent, code)
+
+	code, errRes := serveTestErrorRequest(t, s.Handler(), newTunnelledUpdateRequest("foo="+strings.Repeat("a", 16)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, code)
+	require.Equal(t, "Tunnelled query cannot exceed 16 bytes", *errRes.Message)
+}
+
+func TestMaxTunnelledBodySize(t *testing.T) {
+	s := newLimitsTestServer()
+	SetLimits(s, Limits{MaxRequestBodySize: 1})
+
+	code, errRes := serveTestErrorRequest(t, s.Handler(), newTunnelledUpdateRequest("foo=bar"))
+	require.Equal(t, http.StatusRequestEntityTooLarge, code)
+	require.Equal(t, "Request body cannot exceed 1 bytes", *errRes.Message)
+}
@@ -22,11 +22,12 @@ func _() {
 	_ = x[Method_get_all-11]
 	_ = x[Method_action-12]
 	_ = x[Method_finder-13]
+	_ = x[Method_batch_finder-14]
 }
 
-const _Method_name = "Unknowngetcreatedeleteupdatepartial_updatebatch_getbatch_createbatch_deletebatch_updatebatch_partial_updateget_allactionfinder"
+const _Method_name = "Unknowngetcreatedeleteupdatepartial_updatebatch_getbatch_createbatch_deletebatch_updatebatch_partial_updateget_allactionfinderbatch_finder"
 
-var _Method_index = [...]uint8{0, 7, 10, 16, 22, 28, 42, 51, 63, 75, 87, 107, 114, 120, 126}
+var _Method_index = [...]uint8{0, 7, 10, 16, 22, 28, 42, 51, 63, 75, 87, 107, 114, 120, 126, 138}
 
 func (i Method) String() string {
 	if i < 0 || i >= Method(len(_Method_index)-1) {
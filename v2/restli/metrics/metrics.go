@@ -0,0 +1,324 @@
+// Package metrics provides a restli.Observer that records Prometheus-style metrics, without depending on the Prometheus
+// client library. The metrics are exposed in the Prometheus text format by serving the Observer as a http.Handler:
+//
+//	observer := metrics.NewObserver()
+//	restLiClient.Observer = observer
+//	restli.SetObserver(server, observer)
+//	d2Client.Observer = observer
+//	http.Handle("/metrics", observer)
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PapaCharlie/go-restli/v2/d2"
+	"github.com/PapaCharlie/go-restli/v2/restli"
+)
+
+var (
+	// DefaultDurationBuckets are the default buckets of the call duration histograms, in seconds
+	DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// DefaultSizeBuckets are the default buckets of the request and response size histograms, in bytes
+	DefaultSizeBuckets = []float64{64, 256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20}
+)
+
+// Observer implements restli.Observer (and d2.Observer) by recording the following metrics:
+//   - restli_calls_in_flight: the number of calls currently in flight (gauge)
+//   - restli_calls_total: the number of completed calls, by status and error category (counter)
+//   - restli_call_duration_seconds: the latency of calls (histogram)
+//   - restli_request_size_bytes and restli_response_size_bytes: the size of the request and response bodies
+//     (histograms)
+//   - d2_host_chosen_total: the number of times a host was chosen for a service (counter)
+//   - d2_cluster_hosts: the number of hosts in a cluster (gauge)
+//   - d2_zk_state: set to 1 for the current state of the ZK session (gauge)
+//
+// All restli_ metrics are labeled with the side of the call (client or server), the root resource, the method and the
+// finder or action name.
+type Observer struct {
+	durationBuckets []float64
+	sizeBuckets     []float64
+
+	lock          sync.Mutex
+	inFlight      map[callLabels]int64
+	calls         map[resultLabels]uint64
+	durations     map[callLabels]*histogram
+	requestSizes  map[callLabels]*histogram
+	responseSizes map[callLabels]*histogram
+	hostsChosen   map[[2]string]uint64
+	clusterHosts  map[string]int
+	zkState       string
+}
+
+var (
+	_ restli.Observer = (*Observer)(nil)
+	_ d2.Observer     = (*Observer)(nil)
+)
+
+// NewObserver returns a new Observer that uses DefaultDurationBuckets and DefaultSizeBuckets.
+func NewObserver() *Observer {
+	return NewObserverWithBuckets(DefaultDurationBuckets, DefaultSizeBuckets)
+}
+
+// NewObserverWithBuckets returns a new Observer that uses the given histogram buckets. The duration buckets are in
+// seconds, and the size buckets are in bytes.
+func NewObserverWithBuckets(durationBuckets, sizeBuckets []float64) *Observer {
+	sortedCopy := func(buckets []float64) []float64 {
+		buckets = append([]float64(nil), buckets...)
+		sort.Float64s(buckets)
+		return buckets
+	}
+	return &Observer{
+		durationBuckets: sortedCopy(durationBuckets),
+		sizeBuckets:     sortedCopy(sizeBuckets),
+		inFlight:        make(map[callLabels]int64),
+		calls:           make(map[resultLabels]uint64),
+		durations:       make(map[callLabels]*histogram),
+		requestSizes:    make(map[callLabels]*histogram),
+		responseSizes:   make(map[callLabels]*histogram),
+		hostsChosen:     make(map[[2]string]uint64),
+		clusterHosts:    make(map[string]int),
+	}
+}
+
+type callLabels struct {
+	side     string
+	resource string
+	method   string
+	name     string
+}
+
+func newCallLabels(call restli.Call) callLabels {
+	l := callLabels{
+		side:     "client",
+		resource: call.RootResource,
+		method:   call.Method.String(),
+		name:     call.Name,
+	}
+	if call.Server {
+		l.side = "server"
+	}
+	return l
+}
+
+func (l callLabels) String() string {
+	return formatLabels("side", l.side, "resource", l.resource, "method", l.method, "name", l.name)
+}
+
+type resultLabels struct {
+	callLabels
+	status   string
+	category string
+}
+
+func (l resultLabels) String() string {
+	return formatLabels("side", l.side, "resource", l.resource, "method", l.method, "name", l.name,
+		"status", l.status, "error_category", l.category)
+}
+
+func (o *Observer) CallStarted(_ context.Context, call restli.Call) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.inFlight[newCallLabels(call)]++
+}
+
+func (o *Observer) CallEnded(_ context.Context, call restli.Call, result restli.CallResult) {
+	labels := newCallLabels(call)
+	results := resultLabels{
+		callLabels: labels,
+		status:     strconv.Itoa(result.Status),
+	}
+	if result.ErrorCategory != nil {
+		results.category = result.ErrorCategory.String()
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.inFlight[labels]--
+	o.calls[results]++
+	o.observe(o.durations, o.durationBuckets, labels, result.Latency.Seconds())
+	o.observe(o.requestSizes, o.sizeBuckets, labels, float64(result.RequestSize))
+	o.observe(o.responseSizes, o.sizeBuckets, labels, float64(result.ResponseSize))
+}
+
+func (o *Observer) observe(histograms map[callLabels]*histogram, buckets []float64, labels callLabels, v float64) {
+	h := histograms[labels]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(buckets))}
+		histograms[labels] = h
+	}
+	h.observe(buckets, v)
+}
+
+func (o *Observer) D2HostChosen(service string, host *url.URL) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.hostsChosen[[2]string{service, host.Host}]++
+}
+
+func (o *Observer) D2HostSetChanged(cluster string, hosts int) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.clusterHosts[cluster] = hosts
+}
+
+func (o *Observer) D2ZkStateChanged(state string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.zkState = state
+}
+
+// ServeHTTP writes all the metrics in the Prometheus text format.
+func (o *Observer) ServeHTTP(res http.ResponseWriter, _ *http.Request) {
+	res.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = o.WriteTo(res)
+}
+
+// WriteTo writes all the metrics in the Prometheus text format to the given writer.
+func (o *Observer) WriteTo(w io.Writer) (int64, error) {
+	b := new(strings.Builder)
+
+	o.lock.Lock()
+	writeMetric(b, "restli_calls_in_flight", "gauge", "The number of rest.li calls currently in flight.",
+		o.inFlight, func(v int64) string { return strconv.FormatInt(v, 10) })
+	writeMetric(b, "restli_calls_total", "counter", "The number of completed rest.li calls.",
+		o.calls, formatUint)
+	writeHistograms(b, "restli_call_duration_seconds", "The latency of rest.li calls, in seconds.",
+		o.durations, o.durationBuckets)
+	writeHistograms(b, "restli_request_size_bytes", "The size of rest.li request bodies, in bytes.",
+		o.requestSizes, o.sizeBuckets)
+	writeHistograms(b, "restli_response_size_bytes", "The size of rest.li response bodies, in bytes.",
+		o.responseSizes, o.sizeBuckets)
+
+	hostsChosen := make(map[rawLabels]uint64, len(o.hostsChosen))
+	for k, v := range o.hostsChosen {
+		hostsChosen[rawLabels(formatLabels("service", k[0], "host", k[1]))] = v
+	}
+	writeMetric(b, "d2_host_chosen_total", "counter", "The number of times a host was chosen for a d2 service.",
+		hostsChosen, formatUint)
+
+	clusterHosts := make(map[rawLabels]int, len(o.clusterHosts))
+	for k, v := range o.clusterHosts {
+		clusterHosts[rawLabels(formatLabels("cluster", k))] = v
+	}
+	writeMetric(b, "d2_cluster_hosts", "gauge", "The number of hosts announced in a d2 cluster.",
+		clusterHosts, strconv.Itoa)
+
+	zkState := make(map[rawLabels]int)
+	if o.zkState != "" {
+		zkState[rawLabels(formatLabels("state", o.zkState))] = 1
+	}
+	writeMetric(b, "d2_zk_state", "gauge", "Set to 1 for the current state of the d2 ZK session.",
+		zkState, strconv.Itoa)
+	o.lock.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+type stringerKey interface {
+	comparable
+	fmt.Stringer
+}
+
+type rawLabels string
+
+func (l rawLabels) String() string {
+	return string(l)
+}
+
+func formatLabels(keysAndValues ...string) string {
+	b := new(strings.Builder)
+	b.WriteByte('{')
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(keysAndValues[i])
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(keysAndValues[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func writeHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func sortedKeys[K stringerKey, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+func writeMetric[K stringerKey, V any](
+	b *strings.Builder,
+	name, metricType, help string,
+	values map[K]V,
+	format func(V) string,
+) {
+	if len(values) == 0 {
+		return
+	}
+	writeHeader(b, name, metricType, help)
+	for _, k := range sortedKeys(values) {
+		fmt.Fprintf(b, "%s%s %s\n", name, k, format(values[k]))
+	}
+}
+
+func writeHistograms(b *strings.Builder, name, help string, histograms map[callLabels]*histogram, buckets []float64) {
+	if len(histograms) == 0 {
+		return
+	}
+	writeHeader(b, name, "histogram", help)
+	for _, k := range sortedKeys(histograms) {
+		h := histograms[k]
+		l := strings.TrimSuffix(k.String(), "}")
+		var cumulative uint64
+		for i, upperBound := range buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "%s_bucket%s,le=\"%s\"} %d\n", name, l, formatFloat(upperBound), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s,le=\"+Inf\"} %d\n", name, l, h.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", name, k, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", name, k, h.count)
+	}
+}
+
+type histogram struct {
+	// counts holds the non-cumulative count of each bucket, which is converted to a cumulative count when written
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	i := sort.SearchFloat64s(buckets, v)
+	if i < len(buckets) {
+		h.counts[i]++
+	}
+	h.sum += v
+	h.count++
+}
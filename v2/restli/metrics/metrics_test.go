@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	o := NewObserverWithBuckets([]float64{1, .1}, []float64{100})
+
+	call := restli.Call{RootResource: "collection", Method: restli.Method_finder, Name: "search"}
+	o.CallStarted(context.Background(), call)
+	o.CallEnded(context.Background(), call, restli.CallResult{
+		Status:        http.StatusTooManyRequests,
+		ErrorCategory: restli.ErrThrottled,
+		RequestSize:   10,
+		ResponseSize:  1000,
+		Latency:       50 * time.Millisecond,
+	})
+
+	o.D2HostChosen("collection", &url.URL{Scheme: "http", Host: "localhost:8080"})
+	o.D2HostSetChanged("cluster", 3)
+	o.D2ZkStateChanged("StateHasSession")
+
+	res := httptest.NewRecorder()
+	o.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	const labels = `side="client",resource="collection",method="finder",name="search"`
+	require.Equal(t, `# HELP restli_calls_in_flight The number of rest.li calls currently in flight.
+# TYPE restli_calls_in_flight gauge
+restli_calls_in_flight{`+labels+`} 0
+# HELP restli_calls_total The number of completed rest.li calls.
+# TYPE restli_calls_total counter
+restli_calls_total{`+labels+`,status="429",error_category="throttled"} 1
+# HELP restli_call_duration_seconds The latency of rest.li calls, in seconds.
+# TYPE restli_call_duration_seconds histogram
+restli_call_duration_seconds_bucket{`+labels+`,le="0.1"} 1
+restli_call_duration_seconds_bucket{`+labels+`,le="1"} 1
+restli_call_duration_seconds_bucket{`+labels+`,le="+Inf"} 1
+restli_call_duration_seconds_sum{`+labels+`} 0.05
+restli_call_duration_seconds_count{`+labels+`} 1
+# HELP restli_request_size_bytes The size of rest.li request bodies, in bytes.
+# TYPE restli_request_size_bytes histogram
+restli_request_size_bytes_bucket{`+labels+`,le="100"} 1
+restli_request_size_bytes_bucket{`+labels+`,le="+Inf"} 1
+restli_request_size_bytes_sum{`+labels+`} 10
+restli_request_size_bytes_count{`+labels+`} 1
+# HELP restli_response_size_bytes The size of rest.li response bodies, in bytes.
+# TYPE restli_response_size_bytes histogram
+restli_response_size_bytes_bucket{`+labels+`,le="100"} 0
+restli_response_size_bytes_bucket{`+labels+`,le="+Inf"} 1
+restli_response_size_bytes_sum{`+labels+`} 1000
+restli_response_size_bytes_count{`+labels+`} 1
+# HELP d2_host_chosen_total The number of times a host was chosen for a d2 service.
+# TYPE d2_host_chosen_total counter
+d2_host_chosen_total{service="collection",host="localhost:8080"} 1
+# HELP d2_cluster_hosts The number of hosts announced in a d2 cluster.
+# TYPE d2_cluster_hosts gauge
+d2_cluster_hosts{cluster="cluster"} 3
+# HELP d2_zk_state Set to 1 for the current state of the d2 ZK session.
+# TYPE d2_zk_state gauge
+d2_zk_state{state="StateHasSession"} 1
+`, res.Body.String())
+}
+
+func TestFormatLabels(t *testing.T) {
+	require.Equal(t, `{a="\"b\"\\\n"}`, formatLabels("a", "\"b\"\\\n"))
+}
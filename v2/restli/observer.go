@@ -0,0 +1,237 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Call describes a single rest.li call, either sent by a Client or received by a Server.
+type Call struct {
+	// Server is true if the call was received by a Server, and false if it was sent by a Client.
+	Server bool
+	// RootResource is the name of the root resource the call was made against.
+	RootResource string
+	// Method is the rest.li method of the call. Note that on the server side, it is Method_Unknown for calls that could
+	// not be routed to any method.
+	Method Method
+	// Name is the name of the finder, batch finder or action, if Method is Method_finder, Method_batch_finder or
+	// Method_action respectively.
+	Name string
+}
+
+// CallResult describes the outcome of a Call.
+type CallResult struct {
+	// Status is the HTTP status code of the response, or 0 if no response was received.
+	Status int
+	// Err is the error returned by the call, if any.
+	Err error
+	// ErrorCategory is the category of Err (see CategoryOf).
+	ErrorCategory *ErrorCategory
+	// RequestSize is the size of the request's body, in bytes.
+	RequestSize int64
+	// ResponseSize is the size of the response's body, in bytes.
+	ResponseSize int64
+	// Latency is the time elapsed between the start and end of the call.
+	Latency time.Duration
+}
+
+// Observer receives events from Clients, Servers and d2.Clients, for example to export metrics (see the metrics package
+// for a Prometheus-style implementation). Observers are called synchronously and concurrently, so they should be
+// thread-safe and return quickly. An Observer can be set on a Client with the Client.Observer field, and on a Server
+// with SetObserver. It is also a superset of d2.Observer, so the same Observer can be used on a d2.Client.
+type Observer interface {
+	// CallStarted is called before a Client sends a request, or once a Server has routed a request to a method.
+	CallStarted(ctx context.Context, call Call)
+	// CallEnded is called after a Client has fully read the response, or once a Server has fully written the response.
+	CallEnded(ctx context.Context, call Call, result CallResult)
+
+	// D2HostChosen is called every time a d2.Client chooses a host for the given service.
+	D2HostChosen(service string, host *url.URL)
+	// D2HostSetChanged is called whenever a host is announced or removed from the given cluster, with the new number of
+	// hosts in the cluster.
+	D2HostSetChanged(cluster string, hosts int)
+	// D2ZkStateChanged is called whenever the state of the d2.Client's ZK session changes.
+	D2ZkStateChanged(state string)
+}
+
+// NoopObserver implements Observer by ignoring all the events. It can be embedded in Observer implementations that only
+// care about some of the events.
+type NoopObserver struct{}
+
+func (NoopObserver) CallStarted(context.Context, Call)           {}
+func (NoopObserver) CallEnded(context.Context, Call, CallResult) {}
+func (NoopObserver) D2HostChosen(string, *url.URL)               {}
+func (NoopObserver) D2HostSetChanged(string, int)                {}
+func (NoopObserver) D2ZkStateChanged(string)                     {}
+
+// SetObserver sets the Observer notified of every call received by the given Server.
+func SetObserver(s Server, observer Observer) {
+	s.subNode(nil).rootNode.observer = observer
+}
+
+func newClientCall(rp ResourcePath, method Method, query url.Values) Call {
+	call := Call{
+		RootResource: rp.RootResource(),
+		Method:       method,
+	}
+	switch method {
+	case Method_finder:
+		call.Name = query.Get("q")
+	case Method_batch_finder:
+		call.Name = query.Get(BatchFinderNameParam)
+	case Method_action:
+		call.Name = query.Get("action")
+	}
+	return call
+}
+
+func (c *Client) observeCall(req *http.Request) ([]byte, *http.Response, error) {
+	call, ok := req.Context().Value(clientCallCtxKey).(Call)
+	if !ok {
+		return c.doAndRead(req)
+	}
+
+	start := time.Now()
+	c.Observer.CallStarted(req.Context(), call)
+
+	data, res, err := c.doAndRead(req)
+
+	result := CallResult{
+		Err:           err,
+		ErrorCategory: CategoryOf(err),
+		RequestSize:   req.ContentLength,
+		ResponseSize:  int64(len(data)),
+	}
+	if res != nil {
+		result.Status = res.StatusCode
+	} else if status, ok := ErrorStatus(err); ok {
+		result.Status = status
+	}
+	if result.ResponseSize == 0 {
+		result.ResponseSize = int64(len(errorResponseBody(err)))
+	}
+	result.Latency = time.Since(start)
+	c.Observer.CallEnded(req.Context(), call, result)
+
+	return data, res, err
+}
+
+func errorResponseBody(err error) []byte {
+	var restLiErr *Error
+	if errors.As(err, &restLiErr) {
+		return restLiErr.ResponseBody
+	}
+	var statusErr *UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.ResponseBody
+	}
+	return nil
+}
+
+// serverCall tracks a single call received by a Server
+type serverCall struct {
+	Call
+	observer Observer
+	ctx      context.Context
+	started  bool
+	start    time.Time
+	req      *countingReader
+	res      *countingResponseWriter
+}
+
+func newServerCall(observer Observer, res http.ResponseWriter, req *http.Request, rootResource string) *serverCall {
+	call := &serverCall{
+		Call: Call{
+			Server:       true,
+			RootResource: rootResource,
+		},
+		observer: observer,
+		ctx:      req.Context(),
+		start:    time.Now(),
+		res:      &countingResponseWriter{ResponseWriter: res},
+	}
+	if req.Body != nil {
+		call.req = &countingReader{ReadCloser: req.Body}
+		req.Body = call.req
+	}
+	return call
+}
+
+func (c *serverCall) routed(ctx context.Context, method Method, name string) {
+	if c == nil || c.started {
+		return
+	}
+	c.Method = method
+	c.Name = name
+	c.ctx = ctx
+	c.started = true
+	c.observer.CallStarted(c.ctx, c.Call)
+}
+
+func (c *serverCall) end(err error) {
+	if !c.started {
+		c.started = true
+		c.observer.CallStarted(c.ctx, c.Call)
+	}
+
+	result := CallResult{
+		Status:        c.res.status,
+		Err:           err,
+		ErrorCategory: CategoryOf(err),
+		ResponseSize:  c.res.written,
+		Latency:       time.Since(c.start),
+	}
+	if result.Status == 0 && c.res.written > 0 {
+		result.Status = http.StatusOK
+	}
+	if result.ErrorCategory == nil && result.Status >= 400 {
+		result.ErrorCategory = statusCategory(result.Status)
+	}
+	if c.req != nil {
+		result.RequestSize = c.req.read
+	}
+	c.observer.CallEnded(c.ctx, c.Call, result)
+}
+
+type countingReader struct {
+	io.ReadCloser
+	read int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.ReadCloser.Read(p)
+	c.read += int64(n)
+	return n, err
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (c *countingResponseWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *countingResponseWriter) Write(p []byte) (n int, err error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	n, err = c.ResponseWriter.Write(p)
+	c.written += int64(n)
+	return n, err
+}
+
+func (c *countingResponseWriter) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,121 @@
+package restli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedCall struct {
+	Call
+	CallResult
+}
+
+type recordingObserver struct {
+	NoopObserver
+	lock    sync.Mutex
+	started []Call
+	ended   []recordedCall
+}
+
+func (r *recordingObserver) CallStarted(_ context.Context, call Call) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.started = append(r.started, call)
+}
+
+func (r *recordingObserver) CallEnded(_ context.Context, call Call, result CallResult) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ended = append(r.ended, recordedCall{Call: call, CallResult: result})
+}
+
+func (r *recordingObserver) calls(server bool) (started []Call, ended []recordedCall) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, c := range r.started {
+		if c.Server == server {
+			started = append(started, c)
+		}
+	}
+	for _, c := range r.ended {
+		if c.Server == server {
+			ended = append(ended, c)
+		}
+	}
+	return started, ended
+}
+
+type simpleResourcePath struct{}
+
+func (simpleResourcePath) RootResource() string {
+	return "simple"
+}
+
+func (simpleResourcePath) ResourcePath() (string, error) {
+	return "/simple", nil
+}
+
+func newObservedClient(t *testing.T) (*Client, *recordingObserver) {
+	observer := new(recordingObserver)
+	s := newLimitsTestServer()
+	SetObserver(s, observer)
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+		Observer:         observer,
+	}, observer
+}
+
+func TestObserver(t *testing.T) {
+	c, observer := newObservedClient(t)
+
+	err := Update(c, context.Background(), simpleResourcePath{}, new(common.EmptyRecord), nil, nil)
+	require.NoError(t, err)
+
+	for _, server := range []bool{false, true} {
+		started, ended := observer.calls(server)
+		expected := Call{Server: server, RootResource: "simple", Method: Method_update}
+		require.Equal(t, []Call{expected}, started)
+		require.Len(t, ended, 1)
+		require.Equal(t, expected, ended[0].Call)
+		require.Equal(t, http.StatusNoContent, ended[0].Status)
+		require.NoError(t, ended[0].Err)
+		require.Nil(t, ended[0].ErrorCategory)
+		require.Equal(t, int64(len("{}")), ended[0].RequestSize)
+		require.Positive(t, ended[0].Latency)
+	}
+}
+
+func TestObserverError(t *testing.T) {
+	c, observer := newObservedClient(t)
+
+	err := Delete(c, context.Background(), simpleResourcePath{}, nil)
+	require.ErrorIs(t, err, ErrBadRequest)
+
+	_, clientCalls := observer.calls(false)
+	require.Len(t, clientCalls, 1)
+	require.Equal(t, Method_delete, clientCalls[0].Method)
+	require.Equal(t, http.StatusBadRequest, clientCalls[0].Status)
+	require.Equal(t, ErrBadRequest, clientCalls[0].ErrorCategory)
+	require.Positive(t, clientCalls[0].ResponseSize)
+
+	// The server could not route the request to any method
+	_, serverCalls := observer.calls(true)
+	require.Len(t, serverCalls, 1)
+	require.Equal(t, Method_Unknown, serverCalls[0].Method)
+	require.Equal(t, http.StatusBadRequest, serverCalls[0].Status)
+	require.Equal(t, ErrBadRequest, serverCalls[0].ErrorCategory)
+	require.Equal(t, clientCalls[0].ResponseSize, serverCalls[0].ResponseSize)
+}
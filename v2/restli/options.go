@@ -0,0 +1,76 @@
+package restli
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+// optionsResponse is the body of the response to OPTIONS requests, which matches the OptionsResponse returned by Java
+// rest.li servers. Resources holds the restspec of the requested resource (including its subresources), indexed by its
+// fully qualified name, and Models holds the JSON schemas of all the types it references, indexed by their fully
+// qualified name.
+type optionsResponse struct {
+	Resources map[string]*restSpec       `json:"resources"`
+	Models    map[string]json.RawMessage `json:"models"`
+}
+
+// rawJSON is a restlicodec.Marshaler that writes the given bytes as-is
+type rawJSON []byte
+
+func (r rawJSON) MarshalRestLi(writer restlicodec.Writer) error {
+	writer.WriteRawBytes(r)
+	return nil
+}
+
+// options answers OPTIONS requests with the IDL of this resource, built from the schema declared with
+// DeclareResourceSchema. The given segments are the path of this resource, starting from the root resource.
+func (p *pathNode) options(pathSegments []ResourcePathSegment) (restlicodec.Marshaler, error) {
+	if p.schema == nil {
+		return newErrorResponsef(nil, http.StatusBadRequest, "%q did not declare its schema", p.name)
+	}
+
+	// Rebuild the path template of this resource (e.g. /collection/{collectionId}/subresource) from the keys of its
+	// parents
+	var path string
+	parent := p.rootNode.pathNode
+	for i, s := range pathSegments {
+		parent = parent.subNodes[s.name]
+		path += "/" + s.name
+		if i == len(pathSegments)-1 {
+			break
+		}
+		if parent.schema == nil {
+			return newErrorResponsef(nil, http.StatusBadRequest, "Parent %q of %q did not declare its schema",
+				parent.name, p.name)
+		}
+		if parent.isCollection && parent.schema.Key != nil {
+			path += "/{" + parent.schema.Key.Name + "}"
+		}
+	}
+
+	spec, err := p.restSpec(path)
+	if err != nil {
+		return newErrorResponsef(err, http.StatusInternalServerError, "Could not generate IDL of %q: %s", p.name)
+	}
+
+	res := optionsResponse{
+		Resources: map[string]*restSpec{restSpecFullName(spec): spec},
+		Models:    map[string]json.RawMessage{},
+	}
+	p.walk(func(p *pathNode) {
+		if p.schema == nil {
+			return
+		}
+		for name, schema := range p.schema.JSONDataSchemas {
+			res.Models[name] = json.RawMessage(schema)
+		}
+	})
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return rawJSON(data), nil
+}
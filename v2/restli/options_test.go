@@ -0,0 +1,70 @@
+package restli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func serveOptionsRequest(t *testing.T, s Server, path string) (int, map[string]any) {
+	res := serveTestRequest(s.Handler(), httptest.NewRequest(http.MethodOptions, path, nil))
+	body, err := io.ReadAll(res.Result().Body)
+	require.NoError(t, err)
+
+	var v map[string]any
+	require.NoError(t, json.Unmarshal(body, &v))
+	return res.Code, v
+}
+
+func TestOptions(t *testing.T) {
+	schema := newIDLTestSchema()
+	schema.JSONDataSchemas = map[string]string{
+		"com.example.Fruit": `{"name":"Fruit","namespace":"com.example","type":"record","fields":[]}`,
+	}
+	s := newIDLTestServer(schema)
+
+	status, res := serveOptionsRequest(t, s, "/fruits")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, map[string]any{
+		"com.example.Fruit": map[string]any{
+			"name":      "Fruit",
+			"namespace": "com.example",
+			"type":      "record",
+			"fields":    []any{},
+		},
+	}, res["models"])
+
+	idl, err := GenerateIDL(s)
+	require.NoError(t, err)
+	var expected map[string]any
+	require.NoError(t, json.Unmarshal(idl.RestSpecs["com.example.fruits.restspec.json"], &expected))
+	require.Equal(t, map[string]any{"com.example.fruits": expected}, res["resources"])
+
+	t.Run("subresource", func(t *testing.T) {
+		status, res := serveOptionsRequest(t, s, "/fruits/1/color")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, map[string]any{
+			"com.example.fruits.color": map[string]any{
+				"name":      "color",
+				"namespace": "com.example.fruits",
+				"path":      "/fruits/{fruitsId}/color",
+				"schema":    "com.example.Color",
+				"simple": map[string]any{
+					"supports": []any{"get"},
+					"methods":  []any{map[string]any{"method": "get"}},
+					"entity":   map[string]any{"path": "/fruits/{fruitsId}/color"},
+				},
+			},
+		}, res["resources"])
+		require.Equal(t, map[string]any{}, res["models"])
+	})
+
+	t.Run("undeclared", func(t *testing.T) {
+		status, _ := serveOptionsRequest(t, s, "/undeclared")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
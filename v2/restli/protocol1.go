@@ -0,0 +1,158 @@
+package restli
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/restli/batchkeyset"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+// protocolVersion returns the rest.li protocol version requests to the given root resource should use (see
+// Client.ProtocolVersions)
+func (c *Client) protocolVersion(rootResource string) (version string, err error) {
+	if v, ok := c.ProtocolVersions[rootResource]; ok {
+		version = v
+	} else if r, ok := c.HostnameResolver.(ProtocolVersionResolver); ok {
+		version, err = r.ResolveProtocolVersion(rootResource)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch version {
+	case "":
+		return ProtocolVersion, nil
+	case ProtocolVersion, ProtocolVersion1:
+		return version, nil
+	default:
+		return "", fmt.Errorf("go-restli: Unsupported rest.li protocol version for %q: %s", rootResource, version)
+	}
+}
+
+// newBatchKeySet returns a new BatchKeySet for the given resource, whose keys are encoded using the resource's protocol
+// version
+func newBatchKeySet[K any](c *Client, rp ResourcePath) (keys batchkeyset.BatchKeySet[K], protocol1 bool, err error) {
+	version, err := c.protocolVersion(rp.RootResource())
+	if err != nil {
+		return nil, false, err
+	}
+
+	keys = batchkeyset.NewBatchKeySet[K]()
+	if version == ProtocolVersion1 {
+		return batchkeyset.NewProtocol1BatchKeySet(keys), true, nil
+	}
+	return keys, false, nil
+}
+
+// protocol1ResourcePath translates the path of the wrapped ResourcePath to the rest.li protocol 1.0.0
+type protocol1ResourcePath struct {
+	rp ResourcePath
+}
+
+func (rp protocol1ResourcePath) RootResource() string {
+	return rp.rp.RootResource()
+}
+
+func (rp protocol1ResourcePath) ResourcePath() (string, error) {
+	path, err := rp.rp.ResourcePath()
+	if err != nil {
+		return "", err
+	}
+
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s == "" {
+			continue
+		}
+		segments[i], err = restlicodec.EncodeProtocol1PathSegment(s)
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// protocol1QueryParams translates the query of the wrapped QueryParamsEncoder to the rest.li protocol 1.0.0
+type protocol1QueryParams struct {
+	QueryParamsEncoder
+}
+
+func (q protocol1QueryParams) EncodeQueryParams() (string, error) {
+	query, err := q.QueryParamsEncoder.EncodeQueryParams()
+	if err != nil {
+		return "", err
+	}
+	return restlicodec.EncodeProtocol1Query(query)
+}
+
+// decodeProtocol1Request translates the path and query of a rest.li protocol 1.0.0 request to their ROR2 equivalent,
+// such that it can be handled like any other request. Note that the "ids" query parameter always holds keys.
+func decodeProtocol1Request(req *http.Request) error {
+	path := req.URL.RawPath
+	if path == "" {
+		path = req.URL.Path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s == "" {
+			continue
+		}
+		var err error
+		segments[i], err = restlicodec.DecodeProtocol1PathSegment(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	query, err := restlicodec.DecodeProtocol1Query(req.URL.RawQuery, batchkeyset.EntityIDsField)
+	if err != nil {
+		return err
+	}
+
+	req.URL.RawPath = strings.Join(segments, "/")
+	req.URL.Path, err = url.PathUnescape(req.URL.RawPath)
+	if err != nil {
+		return err
+	}
+	req.URL.RawQuery = query
+	return nil
+}
+
+// protocol1BatchMethods are the batch methods whose request or response bodies are keyed by the batch's keys
+var protocol1BatchMethods = map[Method]bool{
+	Method_batch_get:            true,
+	Method_batch_delete:         true,
+	Method_batch_update:         true,
+	Method_batch_partial_update: true,
+}
+
+// encodeProtocol1BatchResponse translates the keys of the given batch response to the rest.li protocol 1.0.0, if the
+// request is a rest.li protocol 1.0.0 batch request. OPTIONS requests are never routed to a method, and are skipped.
+func encodeProtocol1BatchResponse(ctx *RequestContext, responseBody restlicodec.Marshaler) restlicodec.Marshaler {
+	if responseBody == nil || ctx.protocolVersion != ProtocolVersion1 || ctx.Request.Method == http.MethodOptions {
+		return responseBody
+	}
+	if info := GetRequestInfoFromContext(ctx.Request.Context()); info == nil || !protocol1BatchMethods[info.Method] {
+		return responseBody
+	}
+	return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+		return responseBody.MarshalRestLi(restlicodec.NewProtocol1KeysWriter(writer,
+			common.ResultsField, common.StatusesField, common.ErrorsField))
+	})
+}
+
+// encodeBatchEntities translates the keys of the given batch entities to the rest.li protocol 1.0.0 if protocol1 is
+// true
+func encodeBatchEntities(entities restlicodec.Marshaler, protocol1 bool) restlicodec.Marshaler {
+	if !protocol1 {
+		return entities
+	}
+	return restlicodec.MarshalerFunc(func(writer restlicodec.Writer) error {
+		return entities.MarshalRestLi(restlicodec.NewProtocol1KeysWriter(writer, common.EntitiesField))
+	})
+}
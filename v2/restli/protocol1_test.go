@@ -0,0 +1,159 @@
+package restli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+// newProtocol1TestClient returns a Client whose requests to "collection" use the rest.li protocol 1.0.0, and a pointer
+// to the last request received by the server
+func newProtocol1TestClient(t *testing.T) (*Client, **http.Request) {
+	s := NewServer()
+	RegisterGet(s, testCollectionSegments,
+		func(_ *RequestContext, rp *testEntityResourcePath, _ common.EmptyRecord) (validatedName, error) {
+			require.Equal(t, int64(1), rp.Id)
+			return "entity", nil
+		})
+	RegisterCreate(s, testCollectionSegments, nil,
+		func(*RequestContext, testResourcePath, validatedName, common.EmptyRecord) (*common.CreatedEntity[int64], error) {
+			return &common.CreatedEntity[int64]{Id: 42}, nil
+		})
+	RegisterBatchGet(s, testCollectionSegments,
+		func(_ *RequestContext, _ testResourcePath, keys []string, _ *SliceBatchQueryParams[string]) (*common.BatchResponse[string, validatedName], error) {
+			res := new(common.BatchResponse[string, validatedName])
+			for _, k := range keys {
+				res.AddResult(k, validatedName(k))
+			}
+			return res, nil
+		})
+	RegisterBatchUpdate(s, testCollectionSegments, nil,
+		func(_ *RequestContext, _ testResourcePath, entities map[string]validatedName, _ *SliceBatchQueryParams[string]) (*common.BatchResponse[string, *common.BatchEntityUpdateResponse], error) {
+			res := &common.BatchResponse[string, *common.BatchEntityUpdateResponse]{
+				Results: make(map[string]*common.BatchEntityUpdateResponse),
+			}
+			for k, v := range entities {
+				require.Equal(t, validatedName(k), v)
+				res.Results[k] = &common.BatchEntityUpdateResponse{Status: http.StatusNoContent}
+			}
+			return res, nil
+		})
+
+	var lastRequest *http.Request
+	h := s.Handler()
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		lastRequest = req.Clone(req.Context())
+		h.ServeHTTP(res, req)
+	}))
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+		ProtocolVersions: map[string]string{"collection": ProtocolVersion1},
+	}, &lastRequest
+}
+
+func TestProtocol1(t *testing.T) {
+	c, lastRequest := newProtocol1TestClient(t)
+	ctx := context.Background()
+	requireProtocol1Request := func(rawQuery string) {
+		require.Equal(t, ProtocolVersion1, (*lastRequest).Header.Get(ProtocolVersionHeader))
+		require.Equal(t, rawQuery, (*lastRequest).URL.RawQuery)
+	}
+
+	name, err := Get[validatedName](c, ctx, ResourcePathString("/collection/1"), nil)
+	require.NoError(t, err)
+	require.Equal(t, validatedName("entity"), name)
+	requireProtocol1Request("")
+
+	created, err := Create[int64](c, ctx, ResourcePathString("/collection"), validatedName("entity"), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), created.Id)
+
+	keys := []string{"a b", "c:d"}
+	res, err := BatchGet[string, validatedName](c, ctx, ResourcePathString("/collection"), keys, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]validatedName{"a b": "a b", "c:d": "c:d"}, res.Results)
+	requireProtocol1Request("ids=a%20b&ids=c%3Ad")
+
+	updated, err := BatchUpdate[string, validatedName](c, ctx, ResourcePathString("/collection"),
+		map[string]validatedName{"a b": "a b", "c:d": "c:d"}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]*common.BatchEntityUpdateResponse{
+		"a b": {Status: http.StatusNoContent},
+		"c:d": {Status: http.StatusNoContent},
+	}, updated.Results)
+
+	// Other resources still use the default version
+	c.ProtocolVersions = nil
+	res, err = BatchGet[string, validatedName](c, ctx, ResourcePathString("/collection"), keys, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 2)
+	require.Equal(t, ProtocolVersion, (*lastRequest).Header.Get(ProtocolVersionHeader))
+	require.Equal(t, "ids=List(a%20b,c%3Ad)", (*lastRequest).URL.RawQuery)
+
+	c.ProtocolVersions = map[string]string{"collection": "3.0.0"}
+	_, err = Get[validatedName](c, ctx, ResourcePathString("/collection/1"), nil)
+	require.Error(t, err)
+}
+
+// protocolVersionResolver resolves the protocol version of every resource to the given version
+type protocolVersionResolver struct {
+	HostnameResolver
+	version string
+}
+
+func (r *protocolVersionResolver) ResolveProtocolVersion(string) (string, error) {
+	return r.version, nil
+}
+
+func TestProtocolVersionResolver(t *testing.T) {
+	c, lastRequest := newProtocol1TestClient(t)
+	c.ProtocolVersions = nil
+	c.HostnameResolver = &protocolVersionResolver{HostnameResolver: c.HostnameResolver, version: ProtocolVersion1}
+
+	_, err := Get[validatedName](c, context.Background(), ResourcePathString("/collection/1"), nil)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolVersion1, (*lastRequest).Header.Get(ProtocolVersionHeader))
+
+	// Configured versions take precedence over the resolver
+	c.ProtocolVersions = map[string]string{"collection": ProtocolVersion}
+	_, err = Get[validatedName](c, context.Background(), ResourcePathString("/collection/1"), nil)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolVersion, (*lastRequest).Header.Get(ProtocolVersionHeader))
+}
+
+func TestUnsupportedProtocolVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set(ProtocolVersionHeader, ProtocolVersion)
+	}))
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+	c := &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+		ProtocolVersions: map[string]string{"collection": ProtocolVersion1},
+	}
+
+	_, err := Get[validatedName](c, context.Background(), ResourcePathString("/collection/1"), nil)
+	var unsupported *UnsupportedRestLiProtocolVersion
+	require.True(t, errors.As(err, &unsupported), "%+v", err)
+	require.Equal(t, ProtocolVersion, unsupported.ReturnedVersion)
+	require.Equal(t, ProtocolVersion1, unsupported.RequestedVersion)
+}
+
+func TestProtocol1Options(t *testing.T) {
+	s := newIDLTestServer(newIDLTestSchema())
+	req := httptest.NewRequest(http.MethodOptions, "/fruits", nil)
+	req.Header.Set(ProtocolVersionHeader, ProtocolVersion1)
+	res := serveTestRequest(s.Handler(), req)
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Contains(t, res.Body.String(), `"com.example.fruits"`)
+}
@@ -0,0 +1,476 @@
+// Package restlitest provides utilities for testing rest.li clients and servers against wire fixtures, in the same
+// format as the rest.li test suite: each fixture is a pair of raw HTTP files, <dir>/requests-v2/<name>.req and
+// <dir>/responses-v2/<name>.res. Fixtures are normalized when they are recorded and compared, meaning that:
+//   - Tunnelled queries are decoded, so a tunnelled request matches the same fixture as the equivalent direct request
+//   - Query parameters and the fields of ROR2 objects are sorted
+//   - JSON bodies are compared semantically instead of byte-for-byte
+//   - VolatileHeaders are ignored
+//
+// A Recorder captures the fixtures of a client talking to a real server, a Replayer serves those fixtures back to the
+// client without a server and ServeFixtures checks that a Server still returns the recorded responses:
+//
+//	c := &restli.Client{Client: &http.Client{Transport: &restlitest.Recorder{Dir: "testdata"}}, ...}
+//	c := &restli.Client{Client: &http.Client{Transport: restlitest.MustNewReplayer("testdata")}, ...}
+//	restlitest.ServeFixtures(t, server, "testdata")
+package restlitest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+const (
+	RequestsDir           = "requests-v2"
+	ResponsesDir          = "responses-v2"
+	RequestFileExtension  = ".req"
+	ResponseFileExtension = ".res"
+
+	contentLengthHeader = "Content-Length"
+)
+
+// VolatileHeaders are the headers that are not recorded in fixtures and ignored when comparing requests and
+// responses, since they either change from one call to the next or are set by the HTTP transport itself.
+var VolatileHeaders = append([]string{
+	contentLengthHeader,
+	"Accept-Encoding",
+	"Date",
+	"User-Agent",
+	restli.TraceParentHeader,
+	restli.TraceStateHeader,
+}, restli.TrackingHeaders...)
+
+type fixtureRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+type fixtureResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type fixture struct {
+	name string
+	req  *fixtureRequest
+	res  *fixtureResponse
+	used bool
+}
+
+// readBody reads the given body and returns a copy of it that can be read again
+func readBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	if body == nil || body == http.NoBody {
+		return nil, body, nil
+	}
+	defer body.Close()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, io.NopCloser(bytes.NewReader(b)), nil
+}
+
+// normalizeRequest returns the normalized form of the given request. The request's body is read in full, and replaced
+// with a copy such that the request can still be sent.
+func normalizeRequest(req *http.Request) (r *fixtureRequest, err error) {
+	body, newBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = newBody
+
+	decoded := req.Clone(req.Context())
+	decoded.Body = io.NopCloser(bytes.NewReader(body))
+	err = restli.DecodeTunnelledQuery(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err = readBody(decoded.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fixtureRequest{
+		Method: decoded.Method,
+		Path:   decoded.URL.EscapedPath(),
+		Query:  normalizeQuery(decoded.URL.RawQuery),
+		Header: normalizeHeader(decoded.Header),
+		Body:   normalizeBody(body),
+	}, nil
+}
+
+func normalizeResponse(statusCode int, header http.Header, body []byte) *fixtureResponse {
+	return &fixtureResponse{
+		StatusCode: statusCode,
+		Header:     normalizeHeader(header),
+		Body:       normalizeBody(body),
+	}
+}
+
+func normalizeHeader(h http.Header) http.Header {
+	h = h.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	for _, k := range VolatileHeaders {
+		h.Del(k)
+	}
+	return h
+}
+
+// normalizeQuery sorts the given query's parameters, as well as the fields of any ROR2 objects within them, and
+// re-encodes every value with rest.li's query escaper. Queries that cannot be parsed as ROR2 only have their
+// parameters sorted.
+func normalizeQuery(query string) string {
+	params, err := parseQuery(query)
+	if err == nil {
+		var normalized string
+		normalized, err = restlicodec.BuildQueryParams(func(paramWriter func(string) restlicodec.Writer) error {
+			for k, v := range params {
+				if err := writeInterface(v, paramWriter(k)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			return normalized
+		}
+	}
+
+	var rawParams []string
+	for _, p := range strings.Split(query, "&") {
+		if p != "" {
+			rawParams = append(rawParams, p)
+		}
+	}
+	sort.Strings(rawParams)
+	return strings.Join(rawParams, "&")
+}
+
+// parseQuery parses the given query's parameters into the same structure returned by restlicodec.Reader's
+// ReadInterface
+func parseQuery(query string) (map[string]any, error) {
+	readers, err := restlicodec.ParseQueryParams(query)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]any, len(readers))
+	for k, r := range readers {
+		params[k], err = r.ReadInterface()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+func writeInterface(v any, writer restlicodec.Writer) error {
+	switch v := v.(type) {
+	case string:
+		writer.WriteString(v)
+		return nil
+	case []any:
+		return restlicodec.WriteArray(writer, v, writeInterface)
+	case map[string]any:
+		return restlicodec.WriteMap(writer, v, writeInterface)
+	default:
+		return fmt.Errorf("go-restli: Unexpected ROR2 value %v (%T)", v, v)
+	}
+}
+
+// normalizeBody re-encodes JSON bodies such that the fields of every object are sorted. Non-JSON bodies are returned
+// as-is.
+func normalizeBody(body []byte) []byte {
+	v, ok := decodeJson(body)
+	if !ok {
+		return body
+	}
+
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if encoder.Encode(v) != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+func decodeJson(body []byte) (v any, ok bool) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if decoder.Decode(&v) != nil || decoder.More() {
+		return nil, false
+	}
+	return v, true
+}
+
+func (r *fixtureRequest) requestURI() string {
+	if r.Query == "" {
+		return r.Path
+	}
+	return r.Path + "?" + r.Query
+}
+
+func (r *fixtureRequest) String() string {
+	buf := new(bytes.Buffer)
+	_ = r.write(buf)
+	return buf.String()
+}
+
+func (r *fixtureRequest) write(w io.Writer) error {
+	h := r.Header.Clone()
+	if len(r.Body) > 0 {
+		h.Set(contentLengthHeader, strconv.Itoa(len(r.Body)))
+	}
+	return writeMessage(w, r.Method+" "+r.requestURI()+" HTTP/1.1", h, r.Body)
+}
+
+func (r *fixtureResponse) write(w io.Writer) error {
+	h := r.Header.Clone()
+	h.Set(contentLengthHeader, strconv.Itoa(len(r.Body)))
+	return writeMessage(w, fmt.Sprintf("HTTP/1.1 %d %s", r.StatusCode, http.StatusText(r.StatusCode)), h, r.Body)
+}
+
+func writeMessage(w io.Writer, startLine string, h http.Header, body []byte) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString(startLine)
+	buf.WriteString("\r\n")
+	_ = h.Write(buf)
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func requestFilename(dir, name string) string {
+	return filepath.Join(dir, RequestsDir, name+RequestFileExtension)
+}
+
+func responseFilename(dir, name string) string {
+	return filepath.Join(dir, ResponsesDir, name+ResponseFileExtension)
+}
+
+func writeFixture(dir string, f *fixture) error {
+	write := func(filename string, writer func(io.Writer) error) error {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		if err := writer(buf); err != nil {
+			return err
+		}
+		return os.WriteFile(filename, buf.Bytes(), 0644)
+	}
+
+	err := write(requestFilename(dir, f.name), f.req.write)
+	if err != nil {
+		return err
+	}
+	return write(responseFilename(dir, f.name), f.res.write)
+}
+
+func readRequestFixture(filename string) (*fixtureRequest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("go-restli: Could not read request from %q: %w", filename, err)
+	}
+	r, err := normalizeRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("go-restli: Could not read request from %q: %w", filename, err)
+	}
+	return r, nil
+}
+
+func readResponseFixture(filename string) (*fixtureResponse, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("go-restli: Could not read response from %q: %w", filename, err)
+	}
+	body, _, err := readBody(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("go-restli: Could not read response from %q: %w", filename, err)
+	}
+	return normalizeResponse(res.StatusCode, res.Header, body), nil
+}
+
+// readFixtures reads all the fixtures in the given directory, sorted by name. Every request fixture must have a
+// corresponding response fixture.
+func readFixtures(dir string) (fixtures []*fixture, err error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, RequestsDir, "*"+RequestFileExtension))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		f := &fixture{name: strings.TrimSuffix(filepath.Base(filename), RequestFileExtension)}
+		f.req, err = readRequestFixture(filename)
+		if err != nil {
+			return nil, err
+		}
+		f.res, err = readResponseFixture(responseFilename(dir, f.name))
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	return fixtures, nil
+}
+
+func (r *fixtureRequest) diff(actual *fixtureRequest) (diffs []string) {
+	if r.Method != actual.Method {
+		diffs = append(diffs, fmt.Sprintf("method: expected %q, got %q", r.Method, actual.Method))
+	}
+	if r.Path != actual.Path {
+		diffs = append(diffs, fmt.Sprintf("path: expected %q, got %q", r.Path, actual.Path))
+	}
+	if r.Query != actual.Query {
+		expected, expectedErr := parseQuery(r.Query)
+		got, gotErr := parseQuery(actual.Query)
+		if expectedErr != nil || gotErr != nil {
+			diffs = append(diffs, fmt.Sprintf("query: expected %q, got %q", r.Query, actual.Query))
+		} else {
+			diffValues(&diffs, "query", expected, got)
+		}
+	}
+	diffs = append(diffs, diffHeaders(r.Header, actual.Header)...)
+	diffs = append(diffs, diffBodies(r.Body, actual.Body)...)
+	return diffs
+}
+
+func (r *fixtureResponse) diff(actual *fixtureResponse) (diffs []string) {
+	if r.StatusCode != actual.StatusCode {
+		diffs = append(diffs, fmt.Sprintf("status: expected %d, got %d", r.StatusCode, actual.StatusCode))
+	}
+	diffs = append(diffs, diffHeaders(r.Header, actual.Header)...)
+	diffs = append(diffs, diffBodies(r.Body, actual.Body)...)
+	return diffs
+}
+
+func diffHeaders(expected, actual http.Header) (diffs []string) {
+	toMap := func(h http.Header) map[string]any {
+		m := make(map[string]any, len(h))
+		for k := range h {
+			m[http.CanonicalHeaderKey(k)] = strings.Join(h.Values(k), ", ")
+		}
+		return m
+	}
+	diffValues(&diffs, "header", toMap(expected), toMap(actual))
+	return diffs
+}
+
+func diffBodies(expected, actual []byte) (diffs []string) {
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	expectedJson, expectedOk := decodeJson(expected)
+	actualJson, actualOk := decodeJson(actual)
+	if expectedOk && actualOk {
+		diffValues(&diffs, "body", expectedJson, actualJson)
+	}
+	if len(diffs) == 0 {
+		diffs = append(diffs, fmt.Sprintf("body: expected %q, got %q", expected, actual))
+	}
+	return diffs
+}
+
+// diffValues appends a description of every difference between the given values, as decoded by encoding/json or
+// restlicodec.Reader's ReadInterface, to diffs
+func diffValues(diffs *[]string, path string, expected, actual any) {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e)+len(a))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := e[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			ev, eOk := e[k]
+			av, aOk := a[k]
+			p := path + "." + k
+			switch {
+			case !aOk:
+				*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got nothing", p, formatValue(ev)))
+			case !eOk:
+				*diffs = append(*diffs, fmt.Sprintf("%s: expected nothing, got %s", p, formatValue(av)))
+			default:
+				diffValues(diffs, p, ev, av)
+			}
+		}
+		return
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(e) != len(a) {
+			break
+		}
+		for i := range e {
+			diffValues(diffs, path+"["+strconv.Itoa(i)+"]", e[i], a[i])
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got %s", path, formatValue(expected), formatValue(actual)))
+	}
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case json.Number:
+		return v.String()
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,83 @@
+package restlitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
+)
+
+// Recorder is an http.RoundTripper that forwards every request to Transport, and records the normalized request and
+// its response as a fixture in Dir. Fixture names are prefixed with a sequence number such that they are replayed in
+// the order they were recorded. Requests that fail (i.e. Transport returns an error) are not recorded.
+type Recorder struct {
+	// Dir is the directory in which the fixtures are written. It is created if it does not exist, and existing
+	// fixtures with the same name are overwritten.
+	Dir string
+	// Transport is used to execute the requests. Defaults to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+	// Name returns the name of the fixture for the given request, before it is prefixed with the fixture's sequence
+	// number. Defaults to DefaultFixtureName if nil.
+	Name func(req *http.Request) string
+
+	lock  sync.Mutex
+	count int
+}
+
+// DefaultFixtureName returns the request's rest.li method (or its HTTP method if the header is not set) followed by
+// the request's root resource, e.g. "batch_get-collection".
+func DefaultFixtureName(req *http.Request) string {
+	method := req.Header.Get(restli.MethodHeader)
+	if method == "" {
+		method = strings.ToLower(req.Method)
+	}
+	root, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if root == "" {
+		return method
+	}
+	return method + "-" + root
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := new(fixture)
+	var err error
+	f.req, err = normalizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	body, res.Body, err = readBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.res = normalizeResponse(res.StatusCode, res.Header, body)
+
+	name := DefaultFixtureName
+	if r.Name != nil {
+		name = r.Name
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.count++
+	f.name = fmt.Sprintf("%03d-%s", r.count, strings.ReplaceAll(name(req), "/", "_"))
+
+	err = writeFixture(r.Dir, f)
+	if err != nil {
+		_ = res.Body.Close()
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package restlitest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Replayer is an http.RoundTripper that serves the responses of previously recorded fixtures instead of sending
+// requests to a server. A request is served by the first fixture (in name order) whose normalized request matches it
+// and that has not yet been replayed, falling back to the last matching fixture if they have all been replayed. This
+// means that identical requests that were recorded with different responses (e.g. a get before and after an update)
+// are served in the order they were recorded. Requests that do not match any fixture fail with an
+// *UnmatchedRequestError.
+type Replayer struct {
+	lock     sync.Mutex
+	fixtures []*fixture
+}
+
+// NewReplayer returns a new Replayer that serves the fixtures in the given directory
+func NewReplayer(dir string) (*Replayer, error) {
+	fixtures, err := readFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{fixtures: fixtures}, nil
+}
+
+// MustNewReplayer is like NewReplayer, except it panics if the fixtures cannot be read
+func MustNewReplayer(dir string) *Replayer {
+	r, err := NewReplayer(dir)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// UnmatchedRequestError is returned by Replayer when a request does not match any fixture. Diff describes the
+// differences between the request and the closest fixture, if any.
+type UnmatchedRequestError struct {
+	Request string
+	Fixture string
+	Diff    []string
+}
+
+func (u *UnmatchedRequestError) Error() string {
+	msg := "go-restli: No fixture matched request:\n\n" + u.Request
+	if u.Fixture == "" {
+		return msg + "\n\nNo fixtures were loaded"
+	}
+	return msg + "\n\nClosest fixture (" + strconv.Quote(u.Fixture) + ") differs by:\n  " + strings.Join(u.Diff, "\n  ")
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	actual, err := normalizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var match *fixture
+	unmatched := &UnmatchedRequestError{Request: actual.String()}
+	for _, f := range r.fixtures {
+		diff := f.req.diff(actual)
+		if len(diff) == 0 {
+			match = f
+			if !f.used {
+				break
+			}
+		} else if unmatched.Fixture == "" || len(diff) < len(unmatched.Diff) {
+			unmatched.Fixture = f.name
+			unmatched.Diff = diff
+		}
+	}
+	if match == nil {
+		return nil, unmatched
+	}
+	match.used = true
+
+	header := match.res.Header.Clone()
+	header.Set(contentLengthHeader, strconv.Itoa(len(match.res.Body)))
+	return &http.Response{
+		Status:        strconv.Itoa(match.res.StatusCode) + " " + http.StatusText(match.res.StatusCode),
+		StatusCode:    match.res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(match.res.Body)),
+		ContentLength: int64(len(match.res.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Unused returns the names of the fixtures that have not been replayed yet
+func (r *Replayer) Unused() (names []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, f := range r.fixtures {
+		if !f.used {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
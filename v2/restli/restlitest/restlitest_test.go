@@ -0,0 +1,172 @@
+package restlitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/PapaCharlie/go-restli/v2/restli/inmemory"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+var linksSegments = []restli.ResourcePathSegment{restli.NewResourcePathSegment("links", true)}
+
+type linkPath struct {
+	id *int64
+}
+
+func (l *linkPath) NewInstance() *linkPath {
+	return new(linkPath)
+}
+
+func (l *linkPath) UnmarshalResourcePath(segments []restlicodec.Reader) error {
+	if len(segments) == 0 {
+		return nil
+	}
+	id, err := segments[0].ReadInt64()
+	l.id = &id
+	return err
+}
+
+func (l *linkPath) RootResource() string {
+	return "links"
+}
+
+func (l *linkPath) ResourcePath() (string, error) {
+	if l.id == nil {
+		return "/links", nil
+	}
+	return "/links/" + strconv.FormatInt(*l.id, 10), nil
+}
+
+func newLinksServer() restli.Server {
+	c := inmemory.NewInMemoryCollection[int64, *common.Link](nil, nil)
+	s := restli.NewServer()
+	restli.RegisterCreate[int64](s, linksSegments, nil,
+		func(ctx *restli.RequestContext, _ *linkPath, link *common.Link, _ common.EmptyRecord) (*common.CreatedEntity[int64], error) {
+			return c.Create(ctx, link)
+		})
+	restli.RegisterGet(s, linksSegments,
+		func(ctx *restli.RequestContext, rp *linkPath, _ common.EmptyRecord) (*common.Link, error) {
+			return c.Get(ctx, *rp.id)
+		})
+	return s
+}
+
+func newClient(transport http.RoundTripper) *restli.Client {
+	return &restli.Client{
+		Client:           &http.Client{Transport: transport},
+		HostnameResolver: &restli.SimpleHostnameResolver{Hostname: &url.URL{Scheme: "http", Host: "localhost"}},
+	}
+}
+
+func runLinksCalls(t *testing.T, c *restli.Client) {
+	ctx := restli.WithTraceContext(context.Background(), restli.NewChildTraceContext(restli.TraceContext{}))
+
+	created, err := restli.Create[int64](c, ctx, new(linkPath), &common.Link{Rel: "self", Href: "/1"}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created.Id)
+
+	id := created.Id
+	link, err := restli.Get[*common.Link](c, ctx, &linkPath{id: &id}, nil)
+	require.NoError(t, err)
+	require.Equal(t, &common.Link{Rel: "self", Href: "/1"}, link)
+
+	id = 2
+	_, err = restli.Get[*common.Link](c, ctx, &linkPath{id: &id}, nil)
+	require.IsType(t, new(restli.Error), err)
+	require.Equal(t, int32(http.StatusNotFound), *err.(*restli.Error).Status)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	server := httptest.NewServer(newLinksServer().Handler())
+	defer server.Close()
+	serverUrl, _ := url.Parse(server.URL)
+	recorder := &Recorder{Dir: dir, Transport: server.Client().Transport}
+	c := newClient(recorder)
+	c.HostnameResolver = &restli.SimpleHostnameResolver{Hostname: serverUrl}
+	runLinksCalls(t, c)
+
+	for _, name := range []string{"001-create-links", "002-get-links", "003-get-links"} {
+		req, err := os.ReadFile(requestFilename(dir, name))
+		require.NoError(t, err)
+		require.NotContains(t, string(req), restli.TraceParentHeader)
+		require.FileExists(t, responseFilename(dir, name))
+	}
+	req, err := os.ReadFile(requestFilename(dir, "001-create-links"))
+	require.NoError(t, err)
+	require.Contains(t, string(req), "\r\n\r\n{\"href\":\"/1\",\"rel\":\"self\",\"type\":\"\"}")
+
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+	runLinksCalls(t, newClient(replayer))
+	require.Empty(t, replayer.Unused())
+
+	id := int64(3)
+	_, err = restli.Get[*common.Link](newClient(replayer), context.Background(), &linkPath{id: &id}, nil)
+	unmatched := new(UnmatchedRequestError)
+	require.True(t, errors.As(err, &unmatched), err)
+	require.Equal(t, "002-get-links", unmatched.Fixture)
+	require.Equal(t, []string{`path: expected "/links/1", got "/links/3"`}, unmatched.Diff)
+
+	ServeFixtures(t, newLinksServer(), dir)
+}
+
+func TestServeFixturesMismatch(t *testing.T) {
+	dir := t.TempDir()
+	f := &fixture{
+		name: "get",
+		req: &fixtureRequest{
+			Method: http.MethodGet,
+			Path:   "/links/1",
+			Header: http.Header{restli.MethodHeader: {"get"}},
+		},
+		res: &fixtureResponse{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       []byte(`{"href":"/1","rel":"self"}`),
+		},
+	}
+	require.NoError(t, writeFixture(dir, f))
+
+	fixtures, err := readFixtures(dir)
+	require.NoError(t, err)
+	require.Len(t, fixtures, 1)
+
+	rec := httptest.NewRecorder()
+	newLinksServer().Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/links/1", nil))
+	actual := normalizeResponse(rec.Code, rec.Header(), rec.Body.Bytes())
+	diff := fixtures[0].res.diff(actual)
+	require.Contains(t, diff, "status: expected 200, got 404")
+	require.Contains(t, diff, `body.href: expected "/1", got nothing`)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	require.Equal(t, "a=(x:List(2,1),y:1)&b=%20&q=search",
+		normalizeQuery("q=search&b=+&a=(y:1,x:List(2,1))"))
+	// Queries that are not valid ROR2 only have their parameters sorted
+	require.Equal(t, "a=&b=1", normalizeQuery("b=1&a="))
+
+	var diffs []string
+	expected, _ := parseQuery("a=(x:List(1,2),y:1)")
+	actual, _ := parseQuery("a=(x:List(1,3),z:1)")
+	diffValues(&diffs, "query", expected, actual)
+	require.Equal(t, []string{
+		`query.a.x[1]: expected "2", got "3"`,
+		`query.a.y: expected "1", got nothing`,
+		`query.a.z: expected nothing, got "1"`,
+	}, diffs)
+
+	require.Equal(t, filepath.Join("dir", RequestsDir, "name.req"), requestFilename("dir", "name"))
+}
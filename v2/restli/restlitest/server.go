@@ -0,0 +1,42 @@
+package restlitest
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restli"
+	"github.com/stretchr/testify/require"
+)
+
+// ServeFixtures sends the request of every fixture in the given directory to the given Server, in name order, and
+// asserts that the Server's response matches the fixture's response. Each fixture is run as a subtest of t. The
+// requests are all served by the same handler, meaning that fixtures recorded against a stateful server can be
+// replayed as long as the Server starts in the same state.
+func ServeFixtures(t *testing.T, s restli.Server, dir string) {
+	fixtures, err := readFixtures(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures, "No fixtures found in %q", dir)
+
+	handler := s.Handler()
+	for _, f := range fixtures {
+		f := f
+		t.Run(f.name, func(t *testing.T) {
+			req := httptest.NewRequest(f.req.Method, f.req.requestURI(), bytes.NewReader(f.req.Body))
+			for k, v := range f.req.Header {
+				req.Header[k] = v
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			res := recorder.Result()
+
+			actual := normalizeResponse(res.StatusCode, res.Header, recorder.Body.Bytes())
+			if diff := f.res.diff(actual); len(diff) > 0 {
+				require.FailNowf(t, "Response did not match fixture",
+					"Fixture %q differs by:\n  %s", f.name, strings.Join(diff, "\n  "))
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package restli
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/PapaCharlie/go-restli/v2/restli/batchkeyset"
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+)
+
+// route is the precompiled routing information of a resource. Routes are built once by Handler and are never modified
+// afterwards, such that requests can be routed without recomputing anything that only depends on the resource.
+type route struct {
+	node *pathNode
+	// pathSegments are the segments of all the resources from the root resource to this one, shared by all the requests
+	// routed to this resource
+	pathSegments []ResourcePathSegment
+	// limits are the Limits of the Server merged with the Limits of all the resources from the root resource to this
+	// one, and methodLimits are further merged with the Limits of each method
+	limits       Limits
+	methodLimits map[Method]Limits
+	// entityDepth is the number of collections from the root resource to this one, i.e. the maximum number of entity
+	// segments a request to this resource can have
+	entityDepth int
+	subRoutes   map[string]*route
+}
+
+// newRoutes compiles the routes of all the sub resources of the given node
+func newRoutes(parent *pathNode, pathSegments []ResourcePathSegment, limits Limits, entityDepth int) map[string]*route {
+	routes := make(map[string]*route, len(parent.subNodes))
+	for name, p := range parent.subNodes {
+		rt := &route{
+			node: p,
+			// The segments are copied such that no two routes share the same backing array
+			pathSegments: make([]ResourcePathSegment, len(pathSegments)+1),
+			limits:       limits.merge(p.limits),
+			methodLimits: make(map[Method]Limits, len(p.methodLimits)),
+			entityDepth:  entityDepth,
+		}
+		copy(rt.pathSegments, pathSegments)
+		rt.pathSegments[len(pathSegments)] = p.ResourcePathSegment
+		for m, size := range p.maxBatchSizes {
+			rt.methodLimits[m] = rt.limits.merge(&Limits{MaxBatchSize: size})
+		}
+		for m, l := range p.methodLimits {
+			l := l
+			base, ok := rt.methodLimits[m]
+			if !ok {
+				base = rt.limits
+			}
+			rt.methodLimits[m] = base.merge(&l)
+		}
+		if p.isCollection {
+			rt.entityDepth++
+		}
+		rt.subRoutes = newRoutes(p, rt.pathSegments, rt.limits, rt.entityDepth)
+		routes[name] = rt
+	}
+	return routes
+}
+
+// match returns the route of the given path, which must already be stripped of the Server's prefix, along with the
+// entity segments of the path. hasEntity is true if the last segment of the path is the key of an entity of the matched
+// resource.
+func (r *rootNode) match(path string) (rt *route, entitySegments []validatedRor2String, hasEntity bool, err error) {
+	name, path, more := strings.Cut(path, "/")
+	rt, ok := r.routes[name]
+	if !ok {
+		_, err = newErrorResponsef(nil, http.StatusNotFound, "Unknown resource: %q", name)
+		return nil, nil, false, err
+	}
+	for more {
+		if rt.node.isCollection {
+			var segment string
+			segment, path, more = strings.Cut(path, "/")
+			err = restlicodec.ValidateRor2Input(segment)
+			if err != nil {
+				_, err = newErrorResponsef(err, http.StatusNotFound, "Invalid path segment %q: %s", segment)
+				return nil, nil, false, err
+			}
+			if entitySegments == nil {
+				entitySegments = make([]validatedRor2String, 0, rt.entityDepth)
+			}
+			entitySegments = append(entitySegments, validatedRor2String(segment))
+			hasEntity = true
+			if !more {
+				break
+			}
+		}
+
+		name, path, more = strings.Cut(path, "/")
+		sub, ok := rt.subRoutes[name]
+		if !ok {
+			_, err = newErrorResponsef(nil, http.StatusNotFound, "Unknown sub resource: %q", name)
+			return nil, nil, false, err
+		}
+		rt, hasEntity = sub, false
+	}
+	return rt, entitySegments, hasEntity, nil
+}
+
+// routingParams are the query parameters that determine how a request is routed
+type routingParams struct {
+	finder      string
+	batchFinder string
+	action      string
+	altKey      string
+	hasIds      bool
+	hasAltKey   bool
+}
+
+// parseRoutingParams reads the routingParams from the given raw query without parsing the rest of the query, which is
+// only parsed if and when the query parameters of the method are decoded. Like restlicodec.ParseQueryParams, the last
+// value of a repeated parameter wins, and the values are left escaped.
+func parseRoutingParams(rawQuery string) (p routingParams) {
+	for rawQuery != "" {
+		var param string
+		param, rawQuery, _ = strings.Cut(rawQuery, "&")
+		key, value, _ := strings.Cut(param, "=")
+		switch key {
+		case "q":
+			p.finder = value
+		case BatchFinderNameParam:
+			p.batchFinder = value
+		case "action":
+			p.action = value
+		case batchkeyset.EntityIDsField:
+			p.hasIds = true
+		case AltKeyParam:
+			p.altKey, p.hasAltKey = value, true
+		}
+	}
+	return p
+}
+
+// RequestInfo describes how an incoming request was routed. It is added to the request's context as a single value
+// before any Filter is called, and can be read with GetRequestInfoFromContext.
+type RequestInfo struct {
+	// Method is the rest.li method of the request.
+	Method Method
+	// ResourcePathSegments are the segments of all the resources from the root resource to the requested resource.
+	// They are shared by all requests to the resource and must not be modified.
+	ResourcePathSegments []ResourcePathSegment
+	// Name is the name of the finder, batch finder or action if Method is Method_finder, Method_batch_finder or
+	// Method_action respectively, and empty otherwise.
+	Name string
+
+	entitySegments []validatedRor2String
+}
+
+// EntitySegments returns a Reader for each of the entity segments (i.e. the keys) of the request's path.
+func (i *RequestInfo) EntitySegments() []restlicodec.Reader {
+	return segmentReaders(i.entitySegments)
+}
+
+// GetRequestInfoFromContext returns the RequestInfo of the request, or nil if the given context is not the context of
+// a request received by a Server.
+func GetRequestInfoFromContext(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoCtxKey).(*RequestInfo)
+	return info
+}
+
+// GetMethodFromContext returns the Method of the request, or Method_Unknown if the given context is not the context of
+// a request received by a Server.
+func GetMethodFromContext(ctx context.Context) Method {
+	if info := GetRequestInfoFromContext(ctx); info != nil {
+		return info.Method
+	}
+	return Method_Unknown
+}
+
+// GetResourcePathSegmentsFromContext returns the ResourcePathSegments of the request, or nil if the given context is
+// not the context of a request received by a Server.
+func GetResourcePathSegmentsFromContext(ctx context.Context) []ResourcePathSegment {
+	if info := GetRequestInfoFromContext(ctx); info != nil {
+		return info.ResourcePathSegments
+	}
+	return nil
+}
+
+// GetEntitySegmentsFromContext returns the EntitySegments of the request, or nil if the given context is not the
+// context of a request received by a Server.
+func GetEntitySegmentsFromContext(ctx context.Context) []restlicodec.Reader {
+	if info := GetRequestInfoFromContext(ctx); info != nil {
+		return info.EntitySegments()
+	}
+	return nil
+}
+
+// GetFinderNameFromContext returns the name of the finder or batch finder of the request, or "" if the request is not
+// a finder or batch finder request, or if the given context is not the context of a request received by a Server.
+func GetFinderNameFromContext(ctx context.Context) string {
+	info := GetRequestInfoFromContext(ctx)
+	if info != nil && (info.Method == Method_finder || info.Method == Method_batch_finder) {
+		return info.Name
+	}
+	return ""
+}
+
+// GetActionNameFromContext returns the name of the action of the request, or "" if the request is not an action
+// request, or if the given context is not the context of a request received by a Server.
+func GetActionNameFromContext(ctx context.Context) string {
+	if info := GetRequestInfoFromContext(ctx); info != nil && info.Method == Method_action {
+		return info.Name
+	}
+	return ""
+}
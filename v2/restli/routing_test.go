@@ -0,0 +1,137 @@
+package restli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	collection := NewResourcePathSegment("collection", true)
+	sub := NewResourcePathSegment("sub", true)
+	simple := NewResourcePathSegment("simple", false)
+
+	s := NewServer()
+	s.subNode([]ResourcePathSegment{collection, sub})
+	s.subNode([]ResourcePathSegment{collection, simple})
+	SetLimits(s, Limits{MaxBatchSize: 1, MaxRequestBodySize: 1})
+	SetResourceLimits(s, []ResourcePathSegment{collection}, Limits{MaxBatchSize: 2})
+	SetMethodLimits(s, []ResourcePathSegment{collection, sub}, Method_batch_get, Limits{MaxRequestBodySize: 3})
+	r := s.Handler().(*rootNode)
+
+	tests := []struct {
+		Path           string
+		PathSegments   []ResourcePathSegment
+		EntitySegments []validatedRor2String
+		HasEntity      bool
+	}{
+		{
+			Path:         "collection",
+			PathSegments: []ResourcePathSegment{collection},
+		},
+		{
+			Path:           "collection/1",
+			PathSegments:   []ResourcePathSegment{collection},
+			EntitySegments: []validatedRor2String{"1"},
+			HasEntity:      true,
+		},
+		{
+			Path:           "collection/1/sub",
+			PathSegments:   []ResourcePathSegment{collection, sub},
+			EntitySegments: []validatedRor2String{"1"},
+		},
+		{
+			Path:           "collection/1/sub/(a:b)",
+			PathSegments:   []ResourcePathSegment{collection, sub},
+			EntitySegments: []validatedRor2String{"1", "(a:b)"},
+			HasEntity:      true,
+		},
+		{
+			Path:           "collection/1/simple",
+			PathSegments:   []ResourcePathSegment{collection, simple},
+			EntitySegments: []validatedRor2String{"1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			rt, entitySegments, hasEntity, err := r.match(test.Path)
+			require.NoError(t, err)
+			require.Equal(t, test.PathSegments, rt.pathSegments)
+			require.Equal(t, test.EntitySegments, entitySegments)
+			require.Equal(t, test.HasEntity, hasEntity)
+		})
+	}
+
+	for _, path := range []string{"collection/1/unknown", "collection/1/simple/2", "collection/)/sub"} {
+		_, _, _, err := r.match(path)
+		status, ok := ErrorStatus(err)
+		require.True(t, ok, path)
+		require.Equal(t, http.StatusNotFound, status, path)
+	}
+
+	// Limits are merged once, when the routes are compiled
+	rt, _, _, err := r.match("collection/1/sub")
+	require.NoError(t, err)
+	require.Equal(t, Limits{MaxBatchSize: 2, MaxRequestBodySize: 1}, rt.limits)
+	require.Equal(t, map[Method]Limits{
+		Method_batch_get: {MaxBatchSize: 2, MaxRequestBodySize: 3},
+	}, rt.methodLimits)
+}
+
+func TestParseRoutingParams(t *testing.T) {
+	require.Equal(t, routingParams{
+		finder:      "last",
+		batchFinder: "search",
+		action:      "do",
+		altKey:      "urn",
+		hasIds:      true,
+		hasAltKey:   true,
+	}, parseRoutingParams("q=first&bq=search&&action=do&ids=List(1,2)&altkey=urn&q=last&start=0"))
+	require.Equal(t, routingParams{}, parseRoutingParams("start=0&count=10"))
+}
+
+func TestRequestInfo(t *testing.T) {
+	// All the getters return zero values outside of requests
+	require.Nil(t, GetRequestInfoFromContext(context.Background()))
+	require.Equal(t, Method_Unknown, GetMethodFromContext(context.Background()))
+	require.Nil(t, GetResourcePathSegmentsFromContext(context.Background()))
+	require.Nil(t, GetEntitySegmentsFromContext(context.Background()))
+	require.Empty(t, GetFinderNameFromContext(context.Background()))
+	require.Empty(t, GetActionNameFromContext(context.Background()))
+
+	s := NewServer()
+	var info *RequestInfo
+	RegisterAction(s, testCollectionSegments, "do",
+		func(ctx *RequestContext, _ testResourcePath, _ common.EmptyRecord) error {
+			info = GetRequestInfoFromContext(ctx.Request.Context())
+			require.Equal(t, "do", GetActionNameFromContext(ctx.Request.Context()))
+			require.Empty(t, GetFinderNameFromContext(ctx.Request.Context()))
+			return nil
+		})
+
+	res := serveTestRequest(s.Handler(), newTestRequest(http.MethodPost, "/collection?action=do", Method_action, ""))
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, Method_action, info.Method)
+	require.Equal(t, testCollectionSegments, info.ResourcePathSegments)
+	require.Equal(t, "do", info.Name)
+	require.Empty(t, info.EntitySegments())
+}
+
+func TestServeWithoutHandler(t *testing.T) {
+	s := NewServer()
+	RegisterAction(s, testCollectionSegments, "do",
+		func(*RequestContext, testResourcePath, common.EmptyRecord) error {
+			return nil
+		})
+
+	// The Server can be used directly as an http.Handler, in which case its routes are built on the first request
+	res := serveTestRequest(s.(http.Handler), newTestRequest(http.MethodPost, "/collection?action=do", Method_action, ""))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	res = serveTestRequest(s.(http.Handler), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	require.Equal(t, http.StatusNotFound, res.Code)
+}
@@ -43,7 +43,20 @@ func writeIdHeaders[K any](ctx *RequestContext, id K) (err error) {
 		return err
 	}
 	s := w.Finalize()
-	ctx.ResponseHeaders.Add(IDHeader, s)
+	if ctx.protocolVersion == ProtocolVersion1 {
+		var key string
+		key, err = restlicodec.EncodeProtocol1Key(s)
+		if err != nil {
+			return err
+		}
+		ctx.ResponseHeaders.Add(LegacyIDHeader, key)
+		s, err = restlicodec.EncodeProtocol1PathSegment(s)
+		if err != nil {
+			return err
+		}
+	} else {
+		ctx.ResponseHeaders.Add(IDHeader, s)
+	}
 	ctx.ResponseHeaders.Add("Location", path.Join(ctx.RequestPath(), s))
 	return nil
 }
@@ -85,6 +98,10 @@ func RegisterBatchCreate[K any, RP ResourcePathUnmarshaler[RP], QP restlicodec.Q
 	registerMethodWithBody(s, segments, Method_batch_create, readOnlyFields, 2,
 		restlicodec.UnmarshalRestLi[*common.Elements[V]],
 		func(ctx *RequestContext, rp RP, v *common.Elements[V], qp QP) (responseBody restlicodec.Marshaler, err error) {
+			err = checkBatchSize(ctx, Method_batch_create, len(v.Elements))
+			if err != nil {
+				return nil, err
+			}
 			entities, err := batchCreate(ctx, rp, v.Elements, qp)
 			if err != nil {
 				return nil, err
@@ -104,6 +121,10 @@ func RegisterBatchCreateWithReturnEntity[K any, RP ResourcePathUnmarshaler[RP],
 	registerMethodWithBody(s, segments, Method_batch_create, readOnlyFields, 2,
 		restlicodec.UnmarshalRestLi[*common.Elements[V]],
 		func(ctx *RequestContext, rp RP, v *common.Elements[V], qp QP) (responseBody restlicodec.Marshaler, err error) {
+			err = checkBatchSize(ctx, Method_batch_create, len(v.Elements))
+			if err != nil {
+				return nil, err
+			}
 			entities, err := batchCreate(ctx, rp, v.Elements, qp)
 			if err != nil {
 				return nil, err
@@ -132,7 +153,21 @@ func RegisterBatchGet[K comparable, RP ResourcePathUnmarshaler[RP], QP batchQuer
 ) {
 	registerMethodWithNoBody(s, segments, Method_batch_get,
 		func(ctx *RequestContext, rp RP, qp *wrappedBatchQueryParamsDecoder[K, QP]) (responseBody restlicodec.Marshaler, err error) {
-			return batchGet(ctx, rp, qp.keys, qp.qp)
+			err = checkBatchSize(ctx, Method_batch_get, len(qp.keys))
+			if err != nil {
+				return nil, err
+			}
+			res, err := batchGet(ctx, rp, qp.keys, qp.qp)
+			if err != nil || ctx.altKeyCoercer == nil {
+				return res, err
+			}
+
+			coerced, err := coerceBatchResponseKeys(ctx.altKeyCoercer, res)
+			if err != nil {
+				return newErrorResponsef(err, http.StatusInternalServerError, "Could not coerce keys of %q response: %s",
+					Method_batch_get)
+			}
+			return coerced, nil
 		})
 }
 
@@ -166,6 +201,10 @@ func RegisterBatchDelete[K comparable, RP ResourcePathUnmarshaler[RP], QP batchQ
 ) {
 	registerMethodWithNoBody(s, segments, Method_batch_delete,
 		func(ctx *RequestContext, rp RP, qp *wrappedBatchQueryParamsDecoder[K, QP]) (responseBody restlicodec.Marshaler, err error) {
+			err = checkBatchSize(ctx, Method_batch_delete, len(qp.keys))
+			if err != nil {
+				return nil, err
+			}
 			return batchDelete(ctx, rp, qp.keys, qp.qp)
 		})
 }
@@ -196,6 +235,10 @@ func RegisterBatchUpdate[K comparable, RP ResourcePathUnmarshaler[RP], QP batchQ
 			return entities, batchEntities[K, V](entities).UnmarshalRestLi(reader)
 		},
 		func(ctx *RequestContext, rp RP, v map[K]V, qp *wrappedBatchQueryParamsDecoder[K, QP]) (responseBody restlicodec.Marshaler, err error) {
+			err = checkBatchSize(ctx, Method_batch_update, len(v))
+			if err != nil {
+				return nil, err
+			}
 			return batchUpdate(ctx, rp, v, qp.qp)
 		})
 }
@@ -240,6 +283,10 @@ func RegisterBatchPartialUpdate[K comparable, RP ResourcePathUnmarshaler[RP], QP
 			return entities, batchEntities[K, V](entities).UnmarshalRestLi(reader)
 		},
 		func(ctx *RequestContext, rp RP, v map[K]V, qp *wrappedBatchQueryParamsDecoder[K, QP]) (responseBody restlicodec.Marshaler, err error) {
+			err = checkBatchSize(ctx, Method_batch_partial_update, len(v))
+			if err != nil {
+				return nil, err
+			}
 			return batchPartialUpdate(ctx, rp, v, qp.qp)
 		})
 }
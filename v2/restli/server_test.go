@@ -0,0 +1,79 @@
+package restli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+// The test resources of this package are registered under either a collection or a simple resource
+var (
+	testCollectionSegments = []ResourcePathSegment{NewResourcePathSegment("collection", true)}
+	testSimpleSegments     = []ResourcePathSegment{NewResourcePathSegment("simple", false)}
+)
+
+// testResourcePath is the resource path of a root resource, or of a collection's non-entity methods
+type testResourcePath struct{}
+
+func (t testResourcePath) NewInstance() testResourcePath {
+	return t
+}
+
+func (t testResourcePath) UnmarshalResourcePath([]restlicodec.Reader) error {
+	return nil
+}
+
+// testEntityResourcePath is the resource path of an entity of a collection keyed by an int64
+type testEntityResourcePath struct {
+	Id int64
+}
+
+func (t *testEntityResourcePath) NewInstance() *testEntityResourcePath {
+	return new(testEntityResourcePath)
+}
+
+func (t *testEntityResourcePath) UnmarshalResourcePath(segments []restlicodec.Reader) (err error) {
+	t.Id, err = segments[0].ReadInt64()
+	return err
+}
+
+// newTestRequest returns a request for the given rest.li method of a test resource
+func newTestRequest(httpMethod, target string, method Method, body string) *http.Request {
+	var bodyReader io.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(httpMethod, target, bodyReader)
+	req.Header.Set(MethodHeader, method.String())
+	return req
+}
+
+// serveTestRequest serves the given request with h, which is usually the Handler of a test Server. Note that
+// Server.Handler should be called again after registering anything against the Server.
+func serveTestRequest(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	return res
+}
+
+// serveTestErrorRequest serves the given request like serveTestRequest, and also returns the decoded ErrorResponse if
+// the request failed
+func serveTestErrorRequest(t *testing.T, h http.Handler, req *http.Request) (int, *common.ErrorResponse) {
+	res := serveTestRequest(h, req)
+	if res.Header().Get(ErrorResponseHeader) == "" {
+		return res.Code, nil
+	}
+
+	reader, err := restlicodec.NewJsonReader(res.Body.Bytes())
+	require.NoError(t, err)
+	errRes, err := restlicodec.UnmarshalRestLi[*common.ErrorResponse](reader)
+	require.NoError(t, err)
+	require.Equal(t, int32(res.Code), *errRes.Status)
+	return res.Code, errRes
+}
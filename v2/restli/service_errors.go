@@ -0,0 +1,239 @@
+package restli
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+
+	"github.com/PapaCharlie/go-restli/v2/restlicodec"
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+)
+
+const errorDetailsField = "errorDetails"
+
+// ServiceErrorDefinition describes a service error declared by a resource or method's @ServiceErrors annotation.
+type ServiceErrorDefinition struct {
+	// Code is the service error code, which is sent in the ErrorResponse's Code field.
+	Code string
+	// Status is the HTTP status code the error is sent with.
+	Status int
+	// Message is the default message for this error, if any.
+	Message string
+	// ErrorDetailType is the fully qualified name of the record type of the error's details, if any.
+	ErrorDetailType string
+}
+
+func (d *ServiceErrorDefinition) newErrorResponse() common.ErrorResponse {
+	res := common.ErrorResponse{
+		Status: Int32Pointer(int32(d.Status)),
+		Code:   StringPointer(d.Code),
+	}
+	if d.Message != "" {
+		res.Message = StringPointer(d.Message)
+	}
+	if d.ErrorDetailType != "" {
+		res.ErrorDetailType = StringPointer(d.ErrorDetailType)
+	}
+	return res
+}
+
+// NewServiceErrorResponse returns a new common.ErrorResponse for the given ServiceErrorDefinition, for errors that do
+// not declare an error detail type.
+func NewServiceErrorResponse(definition ServiceErrorDefinition) *common.ErrorResponse {
+	res := definition.newErrorResponse()
+	return &res
+}
+
+// ServiceError is a service error whose details are typed. The details are serialized as the errorDetails field of the
+// ErrorResponse, therefore the ErrorDetails field of the embedded common.ErrorResponse is ignored.
+type ServiceError[D restlicodec.Marshaler] struct {
+	common.ErrorResponse
+	Details D
+}
+
+// NewServiceError returns a new ServiceError for the given ServiceErrorDefinition with the given details.
+func NewServiceError[D restlicodec.Marshaler](definition ServiceErrorDefinition, details D) *ServiceError[D] {
+	return &ServiceError[D]{
+		ErrorResponse: definition.newErrorResponse(),
+		Details:       details,
+	}
+}
+
+func (s *ServiceError[D]) Error() string {
+	return s.ErrorResponse.Error()
+}
+
+// Category returns the ErrorCategory corresponding to the error's status.
+func (s *ServiceError[D]) Category() *ErrorCategory {
+	if s.Status != nil {
+		return statusCategory(int(*s.Status))
+	}
+	return nil
+}
+
+func (s *ServiceError[D]) Is(target error) bool {
+	return isCategory(s, target)
+}
+
+func (s *ServiceError[D]) MarshalRestLi(writer restlicodec.Writer) error {
+	return writer.WriteMap(func(keyWriter func(string) restlicodec.Writer) (err error) {
+		details := s.ErrorResponse.ErrorDetails
+		s.ErrorResponse.ErrorDetails = nil
+		err = s.ErrorResponse.MarshalFields(keyWriter)
+		s.ErrorResponse.ErrorDetails = details
+		if err != nil {
+			return err
+		}
+
+		if !isNil(s.Details) {
+			return s.Details.MarshalRestLi(keyWriter(errorDetailsField))
+		}
+		return nil
+	})
+}
+
+func (s *ServiceError[D]) errorResponse() *common.ErrorResponse {
+	return &s.ErrorResponse
+}
+
+// errorResponse is implemented by all the errors that the server serializes as a rest.li error response instead of a
+// generic 500 (currently *common.ErrorResponse and *ServiceError).
+type errorResponse interface {
+	error
+	restlicodec.Marshaler
+	errorResponse() *common.ErrorResponse
+}
+
+type commonErrorResponse struct {
+	*common.ErrorResponse
+}
+
+func (c commonErrorResponse) errorResponse() *common.ErrorResponse {
+	return c.ErrorResponse
+}
+
+func asErrorResponse(err error) (errorResponse, bool) {
+	switch e := err.(type) {
+	case *common.ErrorResponse:
+		return commonErrorResponse{e}, true
+	case errorResponse:
+		return e, true
+	default:
+		return nil, false
+	}
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// IsServiceError returns true if the given error is a rest.li error with the given service error code. This can be
+// either a *Error returned by a Client, or an error returned by a resource implementation.
+func IsServiceError(err error, code string) bool {
+	res, ok := findErrorResponse(err)
+	return ok && res.Code != nil && *res.Code == code
+}
+
+// IsServiceErrorWithDetails is the same as IsServiceError, except it also returns the error's details. If the error was
+// returned by a Client, the details are deserialized from the body of the error response. Note that if the details
+// cannot be deserialized, this function returns false.
+func IsServiceErrorWithDetails[D restlicodec.Marshaler](err error, code string) (details D, ok bool) {
+	if !IsServiceError(err, code) {
+		return details, false
+	}
+
+	var serviceError *ServiceError[D]
+	if errors.As(err, &serviceError) {
+		return serviceError.Details, true
+	}
+
+	var restLiError *Error
+	if !errors.As(err, &restLiError) {
+		return details, false
+	}
+
+	reader, err := restlicodec.NewJsonReader(restLiError.ResponseBody)
+	if err != nil {
+		return details, false
+	}
+	err = reader.ReadMap(func(reader restlicodec.Reader, field string) (err error) {
+		if field == errorDetailsField {
+			details, err = restlicodec.UnmarshalRestLi[D](reader)
+			ok = err == nil
+			return err
+		} else {
+			return reader.Skip()
+		}
+	})
+	return details, ok && err == nil
+}
+
+func findErrorResponse(err error) (*common.ErrorResponse, bool) {
+	var restLiError *Error
+	if errors.As(err, &restLiError) {
+		return &restLiError.ErrorResponse, true
+	}
+
+	for err != nil {
+		if res, ok := asErrorResponse(err); ok {
+			return res.errorResponse(), true
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil, false
+}
+
+type methodKey struct {
+	method Method
+	name   string
+}
+
+// DeclareServiceErrors declares the service error codes the given method is allowed to return. The name is the finder,
+// batch finder or action name if the method is Method_finder, Method_batch_finder or Method_action respectively, and is
+// ignored otherwise. Once any service errors are declared on a resource, any of its methods that returns a rest.li
+// error with an undeclared code is considered a programming error, and a 500 is returned to the caller instead.
+func DeclareServiceErrors(s Server, segments []ResourcePathSegment, method Method, name string, codes ...string) {
+	p := s.subNode(segments)
+	if p.serviceErrors == nil {
+		p.serviceErrors = make(map[methodKey]map[string]bool)
+	}
+	key := newMethodKey(method, name)
+	// The declared codes are copied instead of being modified in place since they may be shared with a Handler
+	declared := copyMap(p.serviceErrors[key])
+	for _, c := range codes {
+		declared[c] = true
+	}
+	p.serviceErrors[key] = declared
+}
+
+func newMethodKey(method Method, name string) methodKey {
+	if method != Method_finder && method != Method_batch_finder && method != Method_action {
+		name = ""
+	}
+	return methodKey{method: method, name: name}
+}
+
+func (p *pathNode) checkServiceError(ctx *RequestContext, key methodKey, err error) error {
+	// Note that the service errors of a Handler's nodes are never nil since they are copied
+	if len(p.serviceErrors) == 0 {
+		return err
+	}
+	res, ok := findErrorResponse(err)
+	if !ok || res.Code == nil || p.serviceErrors[key][*res.Code] {
+		return err
+	}
+
+	log.Printf("go-restli: %q returned undeclared service error %q (%s)", ctx.Request.URL, *res.Code, err)
+	_, err = newErrorResponsef(nil, http.StatusInternalServerError, "Undeclared service error: %q", *res.Code)
+	return err
+}
@@ -0,0 +1,109 @@
+package restli
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testServiceErrorWithDetails = ServiceErrorDefinition{
+		Code:            "WITH_DETAILS",
+		Status:          http.StatusConflict,
+		Message:         "Error with details",
+		ErrorDetailType: "com.linkedin.restli.common.Link",
+	}
+	testServiceErrorWithoutDetails = ServiceErrorDefinition{
+		Code:   "WITHOUT_DETAILS",
+		Status: http.StatusNotFound,
+	}
+	serviceErrorsSegments = []ResourcePathSegment{NewResourcePathSegment("serviceErrors", false)}
+)
+
+func newServiceErrorsTestServer(returnedErr error) Server {
+	s := NewServer()
+	RegisterGet(s, serviceErrorsSegments,
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Link, error) {
+			return nil, returnedErr
+		})
+	DeclareServiceErrors(s, serviceErrorsSegments, Method_get, "",
+		testServiceErrorWithDetails.Code, testServiceErrorWithoutDetails.Code)
+	return s
+}
+
+// getServiceErrorsTestError calls the get method of the service errors test resource, and returns the error it failed
+// with
+func getServiceErrorsTestError(s Server) error {
+	res := serveTestRequest(s.Handler(), newTestRequest(http.MethodGet, "/serviceErrors", Method_get, ""))
+	return IsErrorResponse(res.Result())
+}
+
+func TestServiceErrorWithDetails(t *testing.T) {
+	details := &common.Link{Rel: "rel", Href: "href", Type: "type"}
+	serviceErr := NewServiceError(testServiceErrorWithDetails, details)
+
+	actual, ok := IsServiceErrorWithDetails[*common.Link](serviceErr, testServiceErrorWithDetails.Code)
+	require.True(t, ok)
+	require.Equal(t, details, actual)
+
+	err := getServiceErrorsTestError(newServiceErrorsTestServer(serviceErr))
+	var restLiErr *Error
+	require.True(t, errors.As(err, &restLiErr))
+	require.Equal(t, http.StatusConflict, restLiErr.Response.StatusCode)
+	require.Equal(t, testServiceErrorWithDetails.Message, *restLiErr.Message)
+	require.Equal(t, testServiceErrorWithDetails.ErrorDetailType, *restLiErr.ErrorDetailType)
+
+	require.True(t, IsServiceError(err, testServiceErrorWithDetails.Code))
+	require.False(t, IsServiceError(err, testServiceErrorWithoutDetails.Code))
+
+	actual, ok = IsServiceErrorWithDetails[*common.Link](err, testServiceErrorWithDetails.Code)
+	require.True(t, ok)
+	require.Equal(t, details, actual)
+}
+
+func TestServiceErrorWithoutDetails(t *testing.T) {
+	err := getServiceErrorsTestError(newServiceErrorsTestServer(NewServiceErrorResponse(testServiceErrorWithoutDetails)))
+	var restLiErr *Error
+	require.True(t, errors.As(err, &restLiErr))
+	require.Equal(t, http.StatusNotFound, restLiErr.Response.StatusCode)
+	require.True(t, IsServiceError(err, testServiceErrorWithoutDetails.Code))
+
+	_, ok := IsServiceErrorWithDetails[*common.Link](err, testServiceErrorWithoutDetails.Code)
+	require.False(t, ok)
+}
+
+func TestUndeclaredServiceError(t *testing.T) {
+	undeclared := NewServiceErrorResponse(ServiceErrorDefinition{Code: "UNDECLARED", Status: http.StatusBadRequest})
+	err := getServiceErrorsTestError(newServiceErrorsTestServer(undeclared))
+	var restLiErr *Error
+	require.True(t, errors.As(err, &restLiErr))
+	require.Equal(t, http.StatusInternalServerError, restLiErr.Response.StatusCode)
+	require.False(t, IsServiceError(err, "UNDECLARED"))
+
+	// Errors without a code are not service errors, and are therefore always allowed
+	err = getServiceErrorsTestError(newServiceErrorsTestServer(&common.ErrorResponse{
+		Status: Int32Pointer(http.StatusBadRequest),
+	}))
+	require.True(t, errors.As(err, &restLiErr))
+	require.Equal(t, http.StatusBadRequest, restLiErr.Response.StatusCode)
+}
+
+func TestServiceErrorOnUndeclaredResource(t *testing.T) {
+	// Resources that do not declare any service errors can return any service error
+	s := NewServer()
+	RegisterGet(s, serviceErrorsSegments,
+		func(*RequestContext, testResourcePath, common.EmptyRecord) (*common.Link, error) {
+			return nil, &common.ErrorResponse{
+				Status: Int32Pointer(http.StatusConflict),
+				Code:   StringPointer("MY_CODE"),
+			}
+		})
+	err := getServiceErrorsTestError(s)
+	var restLiErr *Error
+	require.True(t, errors.As(err, &restLiErr))
+	require.Equal(t, http.StatusConflict, restLiErr.Response.StatusCode)
+	require.True(t, IsServiceError(err, "MY_CODE"))
+}
@@ -0,0 +1,259 @@
+package restli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header that identifies the incoming request in a tracing system (see
+	// https://www.w3.org/TR/trace-context/#traceparent-header)
+	TraceParentHeader = "traceparent"
+	// TraceStateHeader is the W3C Trace Context header that carries vendor-specific trace information (see
+	// https://www.w3.org/TR/trace-context/#tracestate-header)
+	TraceStateHeader = "tracestate"
+)
+
+// TrackingHeaders are the LinkedIn-style tracking headers that are propagated alongside the W3C Trace Context headers.
+// They are carried as-is from incoming server requests to outgoing client requests.
+var TrackingHeaders = []string{"X-LI-UUID", "X-LI-Track"}
+
+// TraceContext holds the trace information propagated across rest.li calls. On the server side, it is extracted from
+// the incoming request's headers and added to the request's context before any Filter is called. On the client side,
+// it is read from the call's context and injected in the outgoing request's headers.
+type TraceContext struct {
+	// TraceID identifies the whole trace. It is all zeroes if the trace context is not valid.
+	TraceID [16]byte
+	// SpanID identifies the current span within the trace (the parent-id field of the traceparent header).
+	SpanID [8]byte
+	// Flags holds the trace flags, e.g. whether the trace is sampled.
+	Flags byte
+	// TraceState is the raw value of the tracestate header, if any.
+	TraceState string
+	// Tracking holds the values of any of the TrackingHeaders that were set.
+	Tracking http.Header
+}
+
+// IsValid returns true if the TraceContext has a non-zero TraceID and SpanID.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// Sampled returns true if the sampled trace flag is set.
+func (tc TraceContext) Sampled() bool {
+	return tc.Flags&1 == 1
+}
+
+// TraceParent formats the TraceContext as a version 00 traceparent header value.
+func (tc TraceContext) TraceParent() string {
+	return "00-" + hex.EncodeToString(tc.TraceID[:]) + "-" + hex.EncodeToString(tc.SpanID[:]) + "-" +
+		hex.EncodeToString([]byte{tc.Flags})
+}
+
+// Inject sets the trace headers in the given http.Header. The W3C headers are only set if the TraceContext is valid.
+func (tc TraceContext) Inject(h http.Header) {
+	if tc.IsValid() {
+		h.Set(TraceParentHeader, tc.TraceParent())
+		if tc.TraceState != "" {
+			h.Set(TraceStateHeader, tc.TraceState)
+		}
+	}
+	for k, v := range tc.Tracking {
+		h[k] = append([]string(nil), v...)
+	}
+}
+
+// ExtractTraceContext reads the trace headers from the given http.Header. It returns false if neither a valid
+// traceparent header nor any of the TrackingHeaders are set. As mandated by the spec, the tracestate header is ignored
+// if the traceparent header is missing or invalid.
+func ExtractTraceContext(h http.Header) (tc TraceContext, ok bool) {
+	if parseTraceParent(h.Get(canonicalTraceParentHeader), &tc) {
+		tc.TraceState = strings.Join(h.Values(TraceStateHeader), ",")
+		ok = true
+	}
+	for _, k := range TrackingHeaders {
+		if v := h.Values(k); len(v) > 0 {
+			if tc.Tracking == nil {
+				tc.Tracking = http.Header{}
+			}
+			tc.Tracking[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+			ok = true
+		}
+	}
+	return tc, ok
+}
+
+func parseTraceParent(s string, tc *TraceContext) bool {
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	const length = 2 + 1 + 32 + 1 + 16 + 1 + 2
+	if len(s) < length || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return false
+	}
+	version, ok := decodeLowerHex(s[:2])
+	if !ok || version[0] == 0xff {
+		return false
+	}
+	// Future versions may append fields, but version 00 must have the exact length
+	if (version[0] == 0 && len(s) != length) || (len(s) > length && s[length] != '-') {
+		return false
+	}
+
+	traceID, ok := decodeLowerHex(s[3:35])
+	if !ok {
+		return false
+	}
+	spanID, ok := decodeLowerHex(s[36:52])
+	if !ok {
+		return false
+	}
+	flags, ok := decodeLowerHex(s[53:55])
+	if !ok {
+		return false
+	}
+
+	copy(tc.TraceID[:], traceID)
+	copy(tc.SpanID[:], spanID)
+	tc.Flags = flags[0]
+	return tc.IsValid()
+}
+
+func decodeLowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+type traceContextCtxKey struct{}
+
+// WithTraceContext returns a new context.Context carrying the given TraceContext. Any Client request made with the
+// returned context will have the corresponding trace headers.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextCtxKey{}, tc)
+}
+
+// GetTraceContextFromContext returns the TraceContext carried by the given context.Context, if any.
+func GetTraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextCtxKey{}).(TraceContext)
+	return tc, ok
+}
+
+// Tracer creates spans for rest.li calls, either sent by a Client or received by a Server. A Tracer can be set on a
+// Client with the Client.Tracer field, and on a Server with SetTracer.
+type Tracer interface {
+	// StartSpan starts a new span for the given call, named with SpanName. The returned context.Context is used for
+	// the rest of the call, and should carry the span's TraceContext (see WithTraceContext) so that it can be
+	// propagated to the server, or to any calls made by the resource implementation.
+	StartSpan(ctx context.Context, name string, call Call) (context.Context, Span)
+}
+
+// Span is a single traced operation, started by a Tracer.
+type Span interface {
+	// End is called once the call has completed, with the error it returned (if any).
+	End(err error)
+}
+
+// SpanName returns the name of the span for the given call, which is the root resource, the method and, for finders
+// and actions, the name of the finder or action, joined by a ".". For example "collection.get" or
+// "collection.finder.search".
+func SpanName(call Call) string {
+	name := call.RootResource + "." + call.Method.String()
+	if call.Name != "" {
+		name += "." + call.Name
+	}
+	return name
+}
+
+// SetTracer sets the Tracer used to create a span for every call received by the given Server. Note that the incoming
+// trace context is always extracted, even if no Tracer is set.
+func SetTracer(s Server, tracer Tracer) {
+	s.subNode(nil).rootNode.tracer = tracer
+}
+
+// NewChildTraceContext returns a new TraceContext for a span whose parent is the given TraceContext. If the parent is
+// not valid, a new trace is started. Tracing headers are carried over from the parent.
+func NewChildTraceContext(parent TraceContext) TraceContext {
+	child := parent
+	if !parent.IsValid() {
+		_, _ = rand.Read(child.TraceID[:])
+		child.Flags = 1
+		child.TraceState = ""
+	}
+	_, _ = rand.Read(child.SpanID[:])
+	return child
+}
+
+// RecordedSpan is a span recorded by an InMemoryTracer.
+type RecordedSpan struct {
+	Name string
+	Call Call
+	// TraceContext is the span's own TraceContext.
+	TraceContext TraceContext
+	// ParentSpanID is the ID of the parent span, and is all zeroes if the span started a new trace.
+	ParentSpanID [8]byte
+	Err          error
+	Start        time.Time
+	End          time.Time
+}
+
+// InMemoryTracer is a Tracer that records all the spans in memory, primarily intended for tests.
+type InMemoryTracer struct {
+	lock  sync.Mutex
+	spans []RecordedSpan
+}
+
+// StartSpan implements Tracer by creating a child TraceContext of the one carried by ctx, if any.
+func (t *InMemoryTracer) StartSpan(ctx context.Context, name string, call Call) (context.Context, Span) {
+	parent, _ := GetTraceContextFromContext(ctx)
+	span := &inMemorySpan{
+		tracer: t,
+		RecordedSpan: RecordedSpan{
+			Name:         name,
+			Call:         call,
+			TraceContext: NewChildTraceContext(parent),
+			Start:        time.Now(),
+		},
+	}
+	if parent.IsValid() {
+		span.ParentSpanID = parent.SpanID
+	}
+	return WithTraceContext(ctx, span.TraceContext), span
+}
+
+// Spans returns all the spans that have ended, in the order in which they ended.
+func (t *InMemoryTracer) Spans() []RecordedSpan {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]RecordedSpan(nil), t.spans...)
+}
+
+// Reset drops all the recorded spans.
+func (t *InMemoryTracer) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.spans = nil
+}
+
+type inMemorySpan struct {
+	RecordedSpan
+	tracer *InMemoryTracer
+	once   sync.Once
+}
+
+func (s *inMemorySpan) End(err error) {
+	s.once.Do(func() {
+		s.Err = err
+		s.RecordedSpan.End = time.Now()
+		s.tracer.lock.Lock()
+		defer s.tracer.lock.Unlock()
+		s.tracer.spans = append(s.tracer.spans, s.RecordedSpan)
+	})
+}
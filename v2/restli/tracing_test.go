@@ -0,0 +1,159 @@
+package restli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/PapaCharlie/go-restli/v2/restlidata/generated/com/linkedin/restli/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTraceContext(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		Name        string
+		TraceParent string
+		Valid       bool
+	}{
+		{Name: "Valid", TraceParent: "00-" + traceID + "-" + spanID + "-01", Valid: true},
+		{Name: "FutureVersion", TraceParent: "01-" + traceID + "-" + spanID + "-01-extra", Valid: true},
+		{Name: "InvalidVersion", TraceParent: "ff-" + traceID + "-" + spanID + "-01"},
+		{Name: "TrailingData", TraceParent: "00-" + traceID + "-" + spanID + "-01-extra"},
+		{Name: "UpperCase", TraceParent: "00-" + "4BF92F3577B34DA6A3CE929D0E0E4736" + "-" + spanID + "-01"},
+		{Name: "ZeroTraceID", TraceParent: "00-00000000000000000000000000000000-" + spanID + "-01"},
+		{Name: "ZeroSpanID", TraceParent: "00-" + traceID + "-0000000000000000-01"},
+		{Name: "Truncated", TraceParent: "00-" + traceID + "-" + spanID},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set(TraceParentHeader, test.TraceParent)
+			h.Set(TraceStateHeader, "vendor=value")
+
+			tc, ok := ExtractTraceContext(h)
+			require.Equal(t, test.Valid, ok)
+			if !test.Valid {
+				require.Empty(t, tc.TraceState)
+				return
+			}
+			require.True(t, tc.Sampled())
+			require.Equal(t, "vendor=value", tc.TraceState)
+			require.Equal(t, "00-"+traceID+"-"+spanID+"-01", tc.TraceParent())
+
+			injected := http.Header{}
+			tc.Inject(injected)
+			require.Equal(t, "00-"+traceID+"-"+spanID+"-01", injected.Get(TraceParentHeader))
+			require.Equal(t, "vendor=value", injected.Get(TraceStateHeader))
+		})
+	}
+
+	h := http.Header{}
+	h.Set("X-LI-UUID", "abc")
+	tc, ok := ExtractTraceContext(h)
+	require.True(t, ok)
+	require.False(t, tc.IsValid())
+	require.Equal(t, "abc", tc.Tracking.Get("X-LI-UUID"))
+}
+
+type traceContextFilter struct {
+	seen TraceContext
+}
+
+func (f *traceContextFilter) PreRequest(req *http.Request) (context.Context, error) {
+	f.seen, _ = GetTraceContextFromContext(req.Context())
+	return nil, nil
+}
+
+func (f *traceContextFilter) PostRequest(context.Context, http.Header) error {
+	return nil
+}
+
+func newTracedClient(t *testing.T, serverTracer Tracer) (*Client, *traceContextFilter, *TraceContext) {
+	filter := new(traceContextFilter)
+	handlerTraceContext := new(TraceContext)
+	s := NewServer(filter)
+	RegisterUpdate(s, testSimpleSegments, nil,
+		func(ctx *RequestContext, _ testResourcePath, _ common.EmptyRecord, _ common.EmptyRecord) error {
+			*handlerTraceContext, _ = GetTraceContextFromContext(ctx.Request.Context())
+			return nil
+		})
+	if serverTracer != nil {
+		SetTracer(s, serverTracer)
+	}
+
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	serverUrl, _ := url.Parse(server.URL)
+
+	return &Client{
+		Client:           server.Client(),
+		HostnameResolver: &SimpleHostnameResolver{Hostname: serverUrl},
+	}, filter, handlerTraceContext
+}
+
+func newTestTraceContext() TraceContext {
+	tc := NewChildTraceContext(TraceContext{})
+	tc.TraceState = "vendor=value"
+	tc.Tracking = http.Header{"X-Li-Uuid": {"abc"}}
+	return tc
+}
+
+func TestTracePropagation(t *testing.T) {
+	c, filter, handlerTraceContext := newTracedClient(t, nil)
+
+	tc := newTestTraceContext()
+	err := Update(c, WithTraceContext(context.Background(), tc), simpleResourcePath{}, new(common.EmptyRecord), nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, tc, filter.seen)
+	require.Equal(t, tc, *handlerTraceContext)
+}
+
+func TestTracer(t *testing.T) {
+	serverTracer := new(InMemoryTracer)
+	c, filter, handlerTraceContext := newTracedClient(t, serverTracer)
+	clientTracer := new(InMemoryTracer)
+	c.Tracer = clientTracer
+
+	tc := newTestTraceContext()
+	err := Update(c, WithTraceContext(context.Background(), tc), simpleResourcePath{}, new(common.EmptyRecord), nil, nil)
+	require.NoError(t, err)
+
+	clientSpans := clientTracer.Spans()
+	require.Len(t, clientSpans, 1)
+	clientSpan := clientSpans[0]
+	require.Equal(t, "simple.update", clientSpan.Name)
+	require.Equal(t, Call{RootResource: "simple", Method: Method_update}, clientSpan.Call)
+	require.Equal(t, tc.SpanID, clientSpan.ParentSpanID)
+	require.Equal(t, tc.TraceID, clientSpan.TraceContext.TraceID)
+	require.NoError(t, clientSpan.Err)
+
+	serverSpans := serverTracer.Spans()
+	require.Len(t, serverSpans, 1)
+	serverSpan := serverSpans[0]
+	require.Equal(t, "simple.update", serverSpan.Name)
+	require.True(t, serverSpan.Call.Server)
+	require.Equal(t, clientSpan.TraceContext.SpanID, serverSpan.ParentSpanID)
+	require.Equal(t, tc.TraceID, serverSpan.TraceContext.TraceID)
+	require.Equal(t, tc.TraceState, serverSpan.TraceContext.TraceState)
+	require.Equal(t, tc.Tracking, serverSpan.TraceContext.Tracking)
+
+	require.Equal(t, serverSpan.TraceContext, filter.seen)
+	require.Equal(t, serverSpan.TraceContext, *handlerTraceContext)
+
+	// Calls that fail are also traced, with the corresponding error
+	clientTracer.Reset()
+	err = Delete(c, context.Background(), simpleResourcePath{}, nil)
+	require.Error(t, err)
+	clientSpans = clientTracer.Spans()
+	require.Len(t, clientSpans, 1)
+	require.Equal(t, "simple.delete", clientSpans[0].Name)
+	require.Equal(t, [8]byte{}, clientSpans[0].ParentSpanID)
+	require.Equal(t, err, clientSpans[0].Err)
+}
@@ -40,6 +40,12 @@ func EncodeTunnelledQuery(httpMethod, query string, body []byte) (newBody []byte
 }
 
 func DecodeTunnelledQuery(req *http.Request) (err error) {
+	return decodeTunnelledQuery(req, Limits{})
+}
+
+// decodeTunnelledQuery is the same as DecodeTunnelledQuery, but rejects tunnelled queries larger than
+// limits.MaxTunnelledQuerySize and tunnelled bodies larger than limits.MaxRequestBodySize.
+func decodeTunnelledQuery(req *http.Request, limits Limits) (err error) {
 	getAndDeleteHeader := func(h string) string {
 		v := req.Header.Get(h)
 		if v == "" {
@@ -49,7 +55,7 @@ func DecodeTunnelledQuery(req *http.Request) (err error) {
 		return v
 	}
 
-	tunnelledMethod := getAndDeleteHeader(MethodOverrideHeader)
+	tunnelledMethod := getAndDeleteHeader(canonicalMethodOverrideHeader)
 	if req.Method != http.MethodPost || tunnelledMethod == "" {
 		return nil
 	}
@@ -60,23 +66,36 @@ func DecodeTunnelledQuery(req *http.Request) (err error) {
 			MethodOverrideHeader)
 	}
 
-	body := new(bytes.Buffer)
-	_, err = io.Copy(body, req.Body)
-	if err != nil {
-		return err
-	}
-	err = req.Body.Close()
-	if err != nil {
-		return err
+	body := req.Body
+	if body == nil {
+		body = http.NoBody
 	}
+	defer func() {
+		closeErr := body.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
 	req.Body = nil
 
-	mediaType, params, err := mime.ParseMediaType(getAndDeleteHeader(ContentTypeHeader))
+	var mediaType string
+	var params map[string]string
+	if contentType := getAndDeleteHeader(ContentTypeHeader); contentType != "" {
+		mediaType, params, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return fmt.Errorf("go-restli: Invalid tunnelled %s %q: %w", ContentTypeHeader, contentType, err)
+		}
+	}
 	switch mediaType {
 	case FormUrlEncodedContentType:
-		req.URL.RawQuery = body.String()
+		query, err := readAtMost(body, limits.MaxTunnelledQuerySize, "Tunnelled query")
+		if err != nil {
+			return err
+		}
+		req.URL.RawQuery = string(query)
 		req.RequestURI = req.URL.RequestURI()
 		req.Body = http.NoBody
+		req.ContentLength = 0
 		return nil
 	case MultipartMixedContentType:
 		r := multipart.NewReader(body, params[MultipartBoundary])
@@ -91,15 +110,18 @@ func DecodeTunnelledQuery(req *http.Request) (err error) {
 			contentType := part.Header.Get(ContentTypeHeader)
 			switch contentType {
 			case FormUrlEncodedContentType:
-				query, _ := io.ReadAll(part)
+				query, err := readAtMost(part, limits.MaxTunnelledQuerySize, "Tunnelled query")
+				if err != nil {
+					return err
+				}
 				req.URL.RawQuery = string(query)
 			case ApplicationJsonContentType:
-				buf := new(bytes.Buffer)
-				_, err = io.Copy(buf, part)
+				buf, err := readAtMost(part, limits.MaxRequestBodySize, "Request body")
 				if err != nil {
 					return err
 				}
-				req.Body = io.NopCloser(buf)
+				req.Body = io.NopCloser(bytes.NewReader(buf))
+				req.ContentLength = int64(len(buf))
 				req.Header.Set(ContentTypeHeader, ApplicationJsonContentType)
 			default:
 				return fmt.Errorf("go-restli: Unknown tunnelled %s: %q", ContentTypeHeader, contentType)
@@ -114,6 +136,7 @@ func DecodeTunnelledQuery(req *http.Request) (err error) {
 		}
 		return nil
 	default:
-		return nil
+		_, err = io.Copy(io.Discard, body)
+		return err
 	}
 }
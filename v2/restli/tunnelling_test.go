@@ -52,6 +52,14 @@ func testQueryTunnelling(t *testing.T, withBody bool) {
 	})
 }
 
+func TestInvalidTunnelledContentType(t *testing.T) {
+	req := newTunnelledUpdateRequest("foo=bar")
+	req.Header.Set(ContentTypeHeader, "multipart/mixed; boundary")
+	code, errRes := serveTestErrorRequest(t, newLimitsTestServer().Handler(), req)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, *errRes.Message, "Invalid tunnelled Content-Type")
+}
+
 type zeroReader struct{}
 
 func (z zeroReader) Read(p []byte) (n int, err error) {
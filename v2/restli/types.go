@@ -21,6 +21,13 @@ func UnmarshalResourcePath[T ResourcePathUnmarshaler[T]](segments []restlicodec.
 	return t, t.UnmarshalResourcePath(segments)
 }
 
+// AssocKeyResourcePath is implemented by the resource paths of assocKey finders, which are called on a partial
+// association key instead of on the association itself. AssocKeys returns the names of the key parts of that partial
+// key.
+type AssocKeyResourcePath interface {
+	AssocKeys() []string
+}
+
 type ResourcePathString string
 
 func (s ResourcePathString) RootResource() string {
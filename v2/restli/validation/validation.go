@@ -0,0 +1,163 @@
+// Package validation implements the constraints declared by Pegasus @validate annotations. Generated records and
+// unions implement Validatable by calling the functions in this package for each of their annotated fields, and
+// descending into the records, unions, arrays and maps they contain so that all violations are reported with the path
+// of the offending field.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Validatable is implemented by all generated records and unions
+type Validatable interface {
+	// Validate returns an *Error listing all the violations of the constraints declared by the type's @validate
+	// annotations, or nil if there are none
+	Validate() error
+	// CollectViolations adds all the violations of the constraints declared by the type's @validate annotations to the
+	// given Collector
+	CollectViolations(c *Collector)
+}
+
+// Violation is a single violation of a constraint
+type Violation struct {
+	// Path is the path of the offending field, e.g. "foo.bar[1].baz"
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// Error lists all the violations found by Validate
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		violations[i] = v.String()
+	}
+	return "go-restli: Validation failed: " + strings.Join(violations, "; ")
+}
+
+// Collector accumulates the violations found while validating a value, along with the path of the field currently
+// being validated.
+type Collector struct {
+	scope      []string
+	violations []Violation
+}
+
+// Validate collects all the violations in the given value, returning them as an *Error if there are any. Values that
+// are neither Validatable nor slices, arrays or maps of Validatable values are always valid.
+func Validate(v any) error {
+	c := new(Collector)
+	Nested(c, "", v)
+	return c.Err()
+}
+
+// Err returns an *Error listing all the violations collected so far, sorted by path, or nil if there are none
+func (c *Collector) Err() error {
+	if len(c.violations) == 0 {
+		return nil
+	}
+	violations := append([]Violation(nil), c.violations...)
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return &Error{Violations: violations}
+}
+
+// Violationf records a violation of the given field, relative to the current scope. An empty field refers to the
+// value that is currently being validated.
+func (c *Collector) Violationf(field string, format string, a ...any) {
+	c.enter(field)
+	c.violations = append(c.violations, Violation{
+		Path:    strings.Join(c.scope, ""),
+		Message: fmt.Sprintf(format, a...),
+	})
+	c.exit(field)
+}
+
+func (c *Collector) enter(field string) {
+	switch {
+	case field == "":
+		return
+	case len(c.scope) == 0 || strings.HasPrefix(field, "["):
+		c.scope = append(c.scope, field)
+	default:
+		c.scope = append(c.scope, "."+field)
+	}
+}
+
+func (c *Collector) exit(field string) {
+	if field != "" {
+		c.scope = c.scope[:len(c.scope)-1]
+	}
+}
+
+// Nested collects the violations of the given value, which is typically a record or a union. Values that are not
+// Validatable are only descended into if they are slices, arrays or maps.
+func Nested(c *Collector, field string, v any) {
+	switch v := v.(type) {
+	case nil:
+		return
+	case Validatable:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return
+		}
+		c.enter(field)
+		v.CollectViolations(c)
+		c.exit(field)
+		return
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		c.enter(field)
+		for i := 0; i < rv.Len(); i++ {
+			Nested(c, index(i), rv.Index(i).Interface())
+		}
+		c.exit(field)
+	case reflect.Map:
+		c.enter(field)
+		iter := rv.MapRange()
+		for iter.Next() {
+			Nested(c, fmt.Sprint(iter.Key().Interface()), iter.Value().Interface())
+		}
+		c.exit(field)
+	}
+}
+
+// Slice calls validate on each element of the given slice, with the element's index as its path
+func Slice[T any](c *Collector, field string, items []T, validate func(c *Collector, item T)) {
+	c.enter(field)
+	for i, item := range items {
+		c.enter(index(i))
+		validate(c, item)
+		c.exit(index(i))
+	}
+	c.exit(field)
+}
+
+// Map calls validate on each value of the given map, with the value's key as its path
+func Map[T any](c *Collector, field string, items map[string]T, validate func(c *Collector, item T)) {
+	c.enter(field)
+	for k, item := range items {
+		c.enter(k)
+		validate(c, item)
+		c.exit(k)
+	}
+	c.exit(field)
+}
+
+func index(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
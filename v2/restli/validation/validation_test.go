@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	Name     string
+	Age      *int32
+	Children []*testRecord
+	Labels   map[string]*testRecord
+}
+
+func (r *testRecord) Validate() error {
+	return Validate(r)
+}
+
+func (r *testRecord) CollectViolations(c *Collector) {
+	Strlen(c, "name", r.Name, 1, 4)
+	Regex(c, "name", r.Name, "[a-z]*")
+	if r.Age != nil {
+		Min(c, "age", *r.Age, 0)
+		Max(c, "age", *r.Age, 150)
+	}
+	Slice(c, "children", r.Children, func(c *Collector, item *testRecord) {
+		Nested(c, "", item)
+	})
+	Map(c, "labels", r.Labels, func(c *Collector, item *testRecord) {
+		Nested(c, "", item)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	age := int32(200)
+	r := &testRecord{
+		Name: "abc",
+		Age:  &age,
+		Children: []*testRecord{
+			{Name: "child"},
+			{Name: "ok"},
+			nil,
+		},
+		Labels: map[string]*testRecord{
+			"foo": {Name: "A"},
+		},
+	}
+
+	err := r.Validate()
+	require.Equal(t, &Error{Violations: []Violation{
+		{Path: "age", Message: "200 is greater than the maximum of 150"},
+		{Path: "children[0].name", Message: "length 5 is greater than the maximum of 4"},
+		{Path: "labels.foo.name", Message: `"A" does not match "[a-z]*"`},
+	}}, err)
+	require.Equal(t, "go-restli: Validation failed: age: 200 is greater than the maximum of 150; "+
+		"children[0].name: length 5 is greater than the maximum of 4; "+
+		`labels.foo.name: "A" does not match "[a-z]*"`, err.Error())
+
+	require.NoError(t, (&testRecord{Name: "a"}).Validate())
+}
+
+func TestValidateCollections(t *testing.T) {
+	err := Validate(map[int64]*testRecord{1: {Name: ""}})
+	require.Equal(t, &Error{Violations: []Violation{
+		{Path: "1.name", Message: "length 0 is less than the minimum of 1"},
+	}}, err)
+
+	err = Validate([]*testRecord{{Name: "a"}, {Name: "b1"}})
+	require.Equal(t, &Error{Violations: []Violation{
+		{Path: "[1].name", Message: `"b1" does not match "[a-z]*"`},
+	}}, err)
+
+	require.NoError(t, Validate("not validatable"))
+	require.NoError(t, Validate((*testRecord)(nil)))
+}
+
+func TestCustom(t *testing.T) {
+	const name = "com.example.Even"
+	var configs []any
+	RegisterCustomValidator(name, func(value any, config any) error {
+		configs = append(configs, config)
+		if value.(int32)%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	})
+
+	c := new(Collector)
+	Custom(c, "a", name, int32(2), `{"strict":true}`)
+	Custom(c, "b", name, int32(3), `{"strict":true}`)
+	Custom(c, "c", "com.example.Unregistered", int32(3), `{}`)
+	require.Equal(t, &Error{Violations: []Violation{
+		{Path: "b", Message: name + ": must be even"},
+	}}, c.Err())
+	require.Equal(t, []any{map[string]any{"strict": true}, map[string]any{"strict": true}}, configs)
+}
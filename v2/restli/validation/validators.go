@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"encoding/json"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// Number is the set of types the range validators (integerRange, longRange, floatRange and doubleRange) apply to
+type Number interface {
+	~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Strlen implements @validate.strlen. The length of the string is its number of runes, and must be at least min and
+// at most max. A negative max means the length is not bounded.
+func Strlen[S ~string](c *Collector, field string, value S, min, max int) {
+	length := utf8.RuneCountInString(string(value))
+	switch {
+	case length < min:
+		c.Violationf(field, "length %d is less than the minimum of %d", length, min)
+	case max >= 0 && length > max:
+		c.Violationf(field, "length %d is greater than the maximum of %d", length, max)
+	}
+}
+
+var regexCache sync.Map
+
+// Regex implements @validate.regex. The entire string must match the given pattern, which must use the RE2 syntax.
+func Regex[S ~string](c *Collector, field string, value S, pattern string) {
+	re, ok := regexCache.Load(pattern)
+	if !ok {
+		// The pattern is compiled during code generation, it is therefore known to be valid
+		re, _ = regexCache.LoadOrStore(pattern, regexp.MustCompile("^(?:"+pattern+")$"))
+	}
+	if !re.(*regexp.Regexp).MatchString(string(value)) {
+		c.Violationf(field, "%q does not match %q", value, pattern)
+	}
+}
+
+// Min implements the lower bound of the range validators. The bound is inclusive.
+func Min[N Number](c *Collector, field string, value, min N) {
+	if value < min {
+		c.Violationf(field, "%v is less than the minimum of %v", value, min)
+	}
+}
+
+// Max implements the upper bound of the range validators. The bound is inclusive.
+func Max[N Number](c *Collector, field string, value, max N) {
+	if value > max {
+		c.Violationf(field, "%v is greater than the maximum of %v", value, max)
+	}
+}
+
+// CustomValidator implements a validator that is not built into go-restli, e.g. a validator declared as
+// @validate.`com.example.UrlValidator` = {"schemes": ["https"]}. The config is the JSON-decoded value of the
+// annotation, and the value is the value of the annotated field. Custom validators must therefore handle any Go type
+// the annotated fields can have. A non-nil error is reported as a violation.
+type CustomValidator func(value any, config any) error
+
+var (
+	customValidatorsLock sync.RWMutex
+	customValidators     = map[string]CustomValidator{}
+	customConfigs        sync.Map
+)
+
+// RegisterCustomValidator registers the implementation of the custom validator with the given name, as it appears in
+// the @validate annotation (e.g. "com.example.UrlValidator"). Annotations that reference validators that were not
+// registered are ignored.
+func RegisterCustomValidator(name string, validator CustomValidator) {
+	customValidatorsLock.Lock()
+	defer customValidatorsLock.Unlock()
+	customValidators[name] = validator
+}
+
+// Custom calls the custom validator with the given name, if it was registered with RegisterCustomValidator. The
+// config is the JSON encoding of the annotation's value.
+func Custom(c *Collector, field string, name string, value any, config string) {
+	customValidatorsLock.RLock()
+	validator, ok := customValidators[name]
+	customValidatorsLock.RUnlock()
+	if !ok {
+		return
+	}
+
+	decoded, ok := customConfigs.Load(config)
+	if !ok {
+		// The config is written by the code generator from the schema's annotations, it is therefore valid JSON
+		var v any
+		_ = json.Unmarshal([]byte(config), &v)
+		decoded, _ = customConfigs.LoadOrStore(config, v)
+	}
+
+	if err := validator(value, decoded); err != nil {
+		c.Violationf(field, "%s: %s", name, err)
+	}
+}
@@ -0,0 +1,513 @@
+package restlicodec
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The rest.li protocol 1.0.0 predates ROR2 and has no delimiters for maps and arrays. Instead, query parameters are
+// flattened: map fields are written using dotted parameter names (e.g. "param.field=value") and array items using
+// indexed parameter names (e.g. "param[0]=value"). Keys are either written as-is (primitive keys), or as compound keys
+// where each field is written as "field=value" and fields are separated by '&' (association and complex keys). The
+// functions in this file translate between the two representations, such that everything else can be implemented
+// solely in terms of ROR2. See https://linkedin.github.io/rest.li/spec/protocol#restli-protocol-10 for more details.
+// Note that because ROR2 is self-describing, translating from ROR2 to 1.0 does not require a schema. The inverse,
+// however, is done on a best-effort basis: array parameters with a single item cannot be told apart from primitive
+// parameters, and empty maps and arrays cannot be represented in 1.0 at all.
+
+// Protocol1Escape escapes the given string such that it can be used both in a path segment and in a query parameter of
+// a rest.li protocol 1.0.0 URL. It is equivalent to url.QueryEscape, except that spaces are escaped as "%20" instead of
+// "+".
+func Protocol1Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// EncodeProtocol1Query translates the given query, as built by BuildQueryParams, to its rest.li protocol 1.0.0
+// equivalent. Each parameter is translated with EncodeProtocol1Param, and the order of the parameters is preserved.
+func EncodeProtocol1Query(query string) (string, error) {
+	builder := new(strings.Builder)
+	for query != "" {
+		var param string
+		param, query, _ = strings.Cut(query, "&")
+		if param == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(param, "=")
+		err := EncodeProtocol1Param(name, value, func(name, value string) {
+			if builder.Len() != 0 {
+				builder.WriteByte('&')
+			}
+			builder.WriteString(name)
+			builder.WriteByte('=')
+			builder.WriteString(value)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
+
+// EncodeProtocol1Param flattens the given query-encoded ROR2 value of the given parameter into its rest.li protocol
+// 1.0.0 parameters, and calls paramWriter with the escaped name and value of each of them. Map fields are written in
+// lexicographic order, and empty maps and arrays are omitted.
+func EncodeProtocol1Param(name, ror2Value string, paramWriter func(name, value string)) error {
+	v, err := readRor2Interface(ror2Value, url.QueryUnescape)
+	if err != nil {
+		return err
+	}
+	flattenProtocol1Value(name, v, paramWriter)
+	return nil
+}
+
+// EncodeProtocol1Key translates the given ROR2 key, as written by NewRor2HeaderWriter (or any other ROR2 writer), to
+// its rest.li protocol 1.0.0 representation. Primitive keys are returned unescaped, while association and complex keys
+// are returned as compound keys (e.g. "k1=v1&k2=v2") whose names and values are escaped with Protocol1Escape. Note
+// that, as such, compound keys are always escaped once more than primitive keys when written into a URL.
+func EncodeProtocol1Key(ror2Key string) (string, error) {
+	key, _, err := encodeProtocol1Key(ror2Key)
+	return key, err
+}
+
+// EncodeProtocol1PathSegment translates the given ROR2 path segment, as written by Ror2PathWriter, to its rest.li
+// protocol 1.0.0 representation (see EncodeProtocol1Key).
+func EncodeProtocol1PathSegment(segment string) (string, error) {
+	key, compound, err := encodeProtocol1Key(segment)
+	if err != nil {
+		return "", err
+	}
+	if !compound {
+		key = Protocol1Escape(key)
+	}
+	return key, nil
+}
+
+func encodeProtocol1Key(ror2Key string) (key string, compound bool, err error) {
+	v, err := readRor2Interface(ror2Key, url.PathUnescape)
+	if err != nil {
+		return "", false, err
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, false, nil
+	case map[string]any:
+		builder := new(strings.Builder)
+		for _, k := range sortedKeys(v) {
+			flattenProtocol1Value(Protocol1Escape(k), v[k], func(name, value string) {
+				if builder.Len() != 0 {
+					builder.WriteByte('&')
+				}
+				builder.WriteString(name)
+				builder.WriteByte('=')
+				builder.WriteString(value)
+			})
+		}
+		return builder.String(), true, nil
+	default:
+		return "", false, &DeserializationError{Err: fmt.Errorf("illegal array key: %q", ror2Key)}
+	}
+}
+
+// DecodeProtocol1Query translates the given rest.li protocol 1.0.0 query to its ROR2 equivalent, such that it can be
+// read with ParseQueryParams. Repeated and indexed parameters are read as arrays, and dotted parameters as maps. The
+// given keyParams (e.g. "ids") are always read as arrays of keys, whose items are decoded with DecodeProtocol1Key if
+// they are not indexed. Because of this, primitive string keys that contain '=' cannot be passed in keyParams, since
+// they cannot be told apart from compound keys.
+func DecodeProtocol1Query(query string, keyParams ...string) (string, error) {
+	params, err := parseProtocol1Params(query, url.QueryUnescape)
+	if err != nil {
+		return "", err
+	}
+
+	isKeyParam := make(map[string]bool, len(keyParams))
+	for _, p := range keyParams {
+		isKeyParam[p] = true
+	}
+
+	builder := new(strings.Builder)
+	for i, name := range sortedKeys(params.fields) {
+		if i != 0 {
+			builder.WriteByte('&')
+		}
+		builder.WriteString(name)
+		builder.WriteByte('=')
+		v := params.fields[name]
+		if isKeyParam[name] {
+			err = v.writeRor2Keys(builder)
+		} else {
+			err = v.writeRor2(builder, Ror2QueryEscape)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return builder.String(), nil
+}
+
+// ParseProtocol1QueryParams is the equivalent of ParseQueryParams for rest.li protocol 1.0.0 queries (see
+// DecodeProtocol1Query).
+func ParseProtocol1QueryParams(query string, keyParams ...string) (QueryParamsReader, error) {
+	query, err := DecodeProtocol1Query(query, keyParams...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseQueryParams(query)
+}
+
+// DecodeProtocol1Key translates the given rest.li protocol 1.0.0 key (see EncodeProtocol1Key) to its ROR2
+// representation, which can be read with NewRor2Reader. Keys that contain '=' are read as compound keys, whose names and
+// values are unescaped, while all other keys are read as-is.
+func DecodeProtocol1Key(key string) (string, error) {
+	if strings.ContainsRune(key, '=') {
+		return decodeProtocol1CompoundKey(key, url.QueryUnescape)
+	}
+	return ror2Escape(key, Ror2PathEscape), nil
+}
+
+// DecodeProtocol1PathSegment translates the given raw (i.e. escaped) path segment of a rest.li protocol 1.0.0 URL to
+// its ROR2 equivalent, as written by Ror2PathWriter. Segments that contain an unescaped '=' are read as compound keys,
+// while all other segments are simply unescaped.
+func DecodeProtocol1PathSegment(segment string) (string, error) {
+	if strings.ContainsRune(segment, '=') {
+		return decodeProtocol1CompoundKey(segment, url.PathUnescape)
+	}
+	s, err := url.PathUnescape(segment)
+	if err != nil {
+		return "", &DeserializationError{Err: err}
+	}
+	return ror2Escape(s, Ror2PathEscape), nil
+}
+
+func decodeProtocol1CompoundKey(key string, unescape func(string) (string, error)) (string, error) {
+	v, err := parseProtocol1Params(key, unescape)
+	if err != nil {
+		return "", err
+	}
+	builder := new(strings.Builder)
+	err = v.writeRor2(builder, Ror2PathEscape)
+	if err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func readRor2Interface(data string, decoder func(string) (string, error)) (any, error) {
+	err := ValidateRor2Input(data)
+	if err != nil {
+		return nil, err
+	}
+	return (&ror2Reader{decoder: decoder, data: []byte(data)}).ReadInterface()
+}
+
+func flattenProtocol1Value(name string, v any, paramWriter func(name, value string)) {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			flattenProtocol1Value(name+"."+Protocol1Escape(k), v[k], paramWriter)
+		}
+	case []any:
+		for i, item := range v {
+			flattenProtocol1Value(name+"["+strconv.Itoa(i)+"]", item, paramWriter)
+		}
+	case string:
+		paramWriter(name, Protocol1Escape(v))
+	}
+}
+
+// protocol1Value is a value read from rest.li protocol 1.0.0 parameters. Exactly one of its fields is set, depending
+// on whether it was read from dotted parameters, indexed parameters or plain (possibly repeated) parameters.
+type protocol1Value struct {
+	fields map[string]*protocol1Value
+	items  map[int]*protocol1Value
+	values []string
+}
+
+func parseProtocol1Params(query string, unescape func(string) (string, error)) (*protocol1Value, error) {
+	root := &protocol1Value{fields: make(map[string]*protocol1Value)}
+	for query != "" {
+		var param string
+		param, query, _ = strings.Cut(query, "&")
+		if param == "" {
+			continue
+		}
+
+		rawName, rawValue, _ := strings.Cut(param, "=")
+		name, err := unescape(rawName)
+		if err != nil {
+			return nil, &DeserializationError{Err: err}
+		}
+		value, err := unescape(rawValue)
+		if err != nil {
+			return nil, &DeserializationError{Err: err}
+		}
+
+		err = root.add(name, name, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// add adds the given value at the given (remaining) path, i.e. the suffix of the parameter's name that has yet to be
+// traversed
+func (v *protocol1Value) add(param, path, value string) error {
+	if path == "" {
+		if v.fields != nil || v.items != nil {
+			return protocol1Errorf("parameter %q is both a primitive and a map or an array", param)
+		}
+		v.values = append(v.values, value)
+		return nil
+	}
+
+	if v.values != nil {
+		return protocol1Errorf("parameter %q is both a primitive and a map or an array", param)
+	}
+
+	var next *protocol1Value
+	if path[0] == '[' {
+		end := strings.IndexByte(path, ']')
+		if end < 0 {
+			return protocol1Errorf("parameter %q has an unclosed index", param)
+		}
+		index, err := strconv.Atoi(path[1:end])
+		if err != nil || index < 0 {
+			return protocol1Errorf("parameter %q has an invalid index", param)
+		}
+		path = path[end+1:]
+
+		if v.fields != nil {
+			return protocol1Errorf("parameter %q is both a map and an array", param)
+		}
+		if v.items == nil {
+			v.items = make(map[int]*protocol1Value)
+		}
+		next = v.items[index]
+		if next == nil {
+			next = new(protocol1Value)
+			v.items[index] = next
+		}
+	} else {
+		// The root has no leading '.', while all nested fields do
+		path = strings.TrimPrefix(path, ".")
+		end := strings.IndexAny(path, ".[")
+		if end < 0 {
+			end = len(path)
+		}
+		if end == 0 {
+			return protocol1Errorf("parameter %q has an empty field name", param)
+		}
+		field := path[:end]
+		path = path[end:]
+
+		if v.items != nil {
+			return protocol1Errorf("parameter %q is both a map and an array", param)
+		}
+		if v.fields == nil {
+			v.fields = make(map[string]*protocol1Value)
+		}
+		next = v.fields[field]
+		if next == nil {
+			next = new(protocol1Value)
+			v.fields[field] = next
+		}
+	}
+
+	return next.add(param, path, value)
+}
+
+func (v *protocol1Value) writeRor2(builder *strings.Builder, escaper func(string) string) (err error) {
+	switch {
+	case v.fields != nil:
+		builder.WriteByte('(')
+		for i, k := range sortedKeys(v.fields) {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(k)
+			builder.WriteByte(':')
+			err = v.fields[k].writeRor2(builder, escaper)
+			if err != nil {
+				return err
+			}
+		}
+		builder.WriteByte(')')
+	case v.items != nil:
+		builder.WriteString(list)
+		for i, item := range v.sortedItems() {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			err = item.writeRor2(builder, escaper)
+			if err != nil {
+				return err
+			}
+		}
+		builder.WriteByte(')')
+	case len(v.values) == 1:
+		builder.WriteString(ror2Escape(v.values[0], escaper))
+	default:
+		builder.WriteString(list)
+		for i, value := range v.values {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(ror2Escape(value, escaper))
+		}
+		builder.WriteByte(')')
+	}
+	return nil
+}
+
+// writeRor2Keys writes this value as an array of keys. Primitive values are decoded with DecodeProtocol1Key, and then
+// re-escaped for the query.
+func (v *protocol1Value) writeRor2Keys(builder *strings.Builder) (err error) {
+	if v.values == nil {
+		if v.fields != nil {
+			return protocol1Errorf("keys cannot be a map")
+		}
+		return v.writeRor2(builder, Ror2QueryEscape)
+	}
+
+	builder.WriteString(list)
+	for i, value := range v.values {
+		if i != 0 {
+			builder.WriteByte(',')
+		}
+		if strings.ContainsRune(value, '=') {
+			var key *protocol1Value
+			key, err = parseProtocol1Params(value, url.QueryUnescape)
+			if err != nil {
+				return err
+			}
+			err = key.writeRor2(builder, Ror2QueryEscape)
+			if err != nil {
+				return err
+			}
+		} else {
+			builder.WriteString(ror2Escape(value, Ror2QueryEscape))
+		}
+	}
+	builder.WriteByte(')')
+	return nil
+}
+
+func (v *protocol1Value) sortedItems() []*protocol1Value {
+	indexes := make([]int, 0, len(v.items))
+	for i := range v.items {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	items := make([]*protocol1Value, len(indexes))
+	for i, index := range indexes {
+		items[i] = v.items[index]
+	}
+	return items
+}
+
+func ror2Escape(s string, escaper func(string) string) string {
+	if s == "" {
+		return emptyString
+	}
+	return escaper(s)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func protocol1Errorf(format string, args ...any) error {
+	return &DeserializationError{Err: fmt.Errorf("invalid rest.li protocol 1.0.0 parameters: "+format, args...)}
+}
+
+// NewProtocol1KeysWriter returns a Writer that translates the keys of the maps held by the given top-level fields (e.g.
+// the "results" of a batch response or the "entities" of a batch update) to their rest.li protocol 1.0.0 representation
+// with EncodeProtocol1Key. All other fields are written as-is.
+func NewProtocol1KeysWriter(writer Writer, fields ...string) Writer {
+	return &protocol1KeysWriter{Writer: writer, fields: fields}
+}
+
+type protocol1KeysWriter struct {
+	Writer
+	fields []string
+	// keys is set on the writers of the maps held by fields, whose keys must be translated
+	keys bool
+}
+
+func (w *protocol1KeysWriter) WriteMap(mapWriter MapWriter) error {
+	var keyErr error
+	err := w.Writer.WriteMap(func(keyWriter func(key string) Writer) error {
+		return mapWriter(func(key string) Writer {
+			if w.keys {
+				k, err := EncodeProtocol1Key(key)
+				if err != nil && keyErr == nil {
+					keyErr = err
+				}
+				return keyWriter(k)
+			}
+			for _, f := range w.fields {
+				if f == key {
+					return &protocol1KeysWriter{Writer: keyWriter(key), keys: true}
+				}
+			}
+			return keyWriter(key)
+		})
+	})
+	if err == nil {
+		err = keyErr
+	}
+	return err
+}
+
+// NewProtocol1KeysReader is the inverse of NewProtocol1KeysWriter: it returns a Reader that translates the keys of the
+// maps held by the given top-level fields from their rest.li protocol 1.0.0 representation with DecodeProtocol1Key.
+func NewProtocol1KeysReader(reader Reader, fields ...string) Reader {
+	return &protocol1KeysReader{Reader: reader, fields: fields}
+}
+
+type protocol1KeysReader struct {
+	Reader
+	fields []string
+	// keys is set on the readers of the maps held by fields, whose keys must be translated
+	keys bool
+}
+
+func (r *protocol1KeysReader) wrap(reader Reader, field string) Reader {
+	if !r.keys {
+		for _, f := range r.fields {
+			if f == field {
+				return &protocol1KeysReader{Reader: reader, keys: true}
+			}
+		}
+	}
+	return reader
+}
+
+func (r *protocol1KeysReader) ReadMap(mapReader MapReader) error {
+	return r.Reader.ReadMap(func(reader Reader, field string) (err error) {
+		if r.keys {
+			field, err = DecodeProtocol1Key(field)
+			if err != nil {
+				return err
+			}
+		}
+		return mapReader(r.wrap(reader, field), field)
+	})
+}
+
+func (r *protocol1KeysReader) ReadRecord(requiredFields *RequiredFields, recordReader MapReader) error {
+	return r.Reader.ReadRecord(requiredFields, func(reader Reader, field string) error {
+		return recordReader(r.wrap(reader, field), field)
+	})
+}
@@ -0,0 +1,127 @@
+package restlicodec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocol1Query(t *testing.T) {
+	const ror2 = `a=List(1,2)&b=(x:foo%20bar,y:List((z:1)))&c=''&ids=List(1,(k1:v1,k2:v%262))`
+
+	query, err := EncodeProtocol1Query(ror2)
+	require.NoError(t, err)
+	require.Equal(t, `a[0]=1&a[1]=2&b.x=foo%20bar&b.y[0].z=1&c=&ids[0]=1&ids[1].k1=v1&ids[1].k2=v%262`, query)
+
+	decoded, err := DecodeProtocol1Query(query)
+	require.NoError(t, err)
+	require.Equal(t, ror2, decoded)
+
+	// Repeated parameters are arrays, and compound keys are only decoded in key params
+	decoded, err = DecodeProtocol1Query(`a=1&a=2&k=k1%3Dv1&ids=1&ids=k1%3Dv1%26k2%3Dv%25262`, "ids")
+	require.NoError(t, err)
+	require.Equal(t, `a=List(1,2)&ids=List(1,(k1:v1,k2:v%262))&k=k1%3Dv1`, decoded)
+
+	params, err := ParseProtocol1QueryParams(`ids=42`, "ids")
+	require.NoError(t, err)
+	var ids []int64
+	require.NoError(t, params["ids"].ReadArray(func(reader Reader) error {
+		id, err := reader.ReadInt64()
+		ids = append(ids, id)
+		return err
+	}))
+	require.Equal(t, []int64{42}, ids)
+
+	for _, q := range []string{`a=1&a.b=2`, `a[0]=1&a.b=2`, `a[x]=1`, `a.=1`, `a[0=1`} {
+		_, err = DecodeProtocol1Query(q)
+		require.Error(t, err, q)
+	}
+}
+
+func TestProtocol1Keys(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Ror2        string
+		Protocol1   string
+		PathSegment string
+	}{
+		{
+			Name:        "primitive",
+			Ror2:        `foo%20bar`,
+			Protocol1:   `foo bar`,
+			PathSegment: `foo%20bar`,
+		},
+		{
+			Name:        "empty",
+			Ror2:        `''`,
+			Protocol1:   ``,
+			PathSegment: ``,
+		},
+		{
+			Name:        "association",
+			Ror2:        `(dest:a=b,src:1)`,
+			Protocol1:   `dest=a%3Db&src=1`,
+			PathSegment: `dest=a%3Db&src=1`,
+		},
+		{
+			Name:        "complex",
+			Ror2:        `($params:(p:List(1,2)),k:foo%20bar)`,
+			Protocol1:   `%24params.p[0]=1&%24params.p[1]=2&k=foo%20bar`,
+			PathSegment: `%24params.p[0]=1&%24params.p[1]=2&k=foo%20bar`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			key, err := EncodeProtocol1Key(test.Ror2)
+			require.NoError(t, err)
+			require.Equal(t, test.Protocol1, key)
+
+			decoded, err := DecodeProtocol1Key(key)
+			require.NoError(t, err)
+			require.Equal(t, test.Ror2, decoded)
+
+			segment, err := EncodeProtocol1PathSegment(test.Ror2)
+			require.NoError(t, err)
+			require.Equal(t, test.PathSegment, segment)
+
+			if segment != "" {
+				decoded, err = DecodeProtocol1PathSegment(segment)
+				require.NoError(t, err)
+				require.Equal(t, test.Ror2, decoded)
+			}
+		})
+	}
+
+	_, err := EncodeProtocol1Key(`List(1,2)`)
+	require.Error(t, err)
+}
+
+func TestProtocol1KeysWriterAndReader(t *testing.T) {
+	w := NewCompactJsonWriter()
+	err := NewProtocol1KeysWriter(w, "results").WriteMap(func(keyWriter func(key string) Writer) error {
+		keyWriter("total").WriteInt(1)
+		return keyWriter("results").WriteMap(func(keyWriter func(key string) Writer) error {
+			keyWriter("(a:1,b:2)").WriteString("(c:3)")
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	data := w.Finalize()
+	require.Equal(t, `{"results":{"a=1&b=2":"(c:3)"},"total":1}`, data)
+
+	r, err := NewJsonReader([]byte(data))
+	require.NoError(t, err)
+	read := map[string]string{}
+	err = NewProtocol1KeysReader(r, "results").ReadRecord(nil, func(reader Reader, field string) error {
+		if field != "results" {
+			return reader.Skip()
+		}
+		return reader.ReadMap(func(reader Reader, key string) (err error) {
+			read[key], err = reader.ReadString()
+			return err
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"(a:1,b:2)": "(c:3)"}, read)
+}
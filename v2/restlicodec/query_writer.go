@@ -58,7 +58,7 @@ func BuildQueryParams(paramsWriter MapWriter) (out string, err error) {
 		return "", err
 	}
 
-	sort.Slice(entries, func(i, j int) bool { return entries[i].param < entries[j].param })
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].param < entries[j].param })
 
 	builder := new(strings.Builder)
 	for i, e := range entries {
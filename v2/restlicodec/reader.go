@@ -188,6 +188,10 @@ func (d *DeserializationError) Error() string {
 	return fmt.Sprintf("go-restli: Failed to deserialize %q (%+v)", d.Scope, d.Err)
 }
 
+func (d *DeserializationError) Unwrap() error {
+	return d.Err
+}
+
 type RequiredFields struct {
 	fields []string
 }
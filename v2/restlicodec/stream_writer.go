@@ -0,0 +1,151 @@
+package restlicodec
+
+import (
+	"io"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// DefaultFlushThreshold is the number of buffered bytes after which a StreamWriter created with a threshold of 0 will
+// flush to its backing io.Writer.
+const DefaultFlushThreshold = 32 * 1024
+
+// StreamWriter is a Writer that periodically flushes the serialized bytes to a backing io.Writer instead of buffering
+// the entire object in memory. Flushes only ever happen between array items and map entries, once the buffered output
+// exceeds the configured threshold. Because map entries are written directly to the output as they are provided
+// instead of being buffered and sorted, the output of a StreamWriter is only equivalent to the output of the
+// corresponding buffered Writer, not identical.
+type StreamWriter interface {
+	Writer
+	// Flush writes any buffered bytes to the backing io.Writer. Once an error has been returned by the backing
+	// io.Writer, subsequent calls to Flush will return the same error and no more bytes will be written.
+	Flush() error
+	// Flushed returns true if any bytes have already been written to the backing io.Writer. Once this returns true,
+	// the output cannot be discarded and the backing io.Writer contains a partial object.
+	Flushed() bool
+	// Buffered returns the number of bytes that have been serialized but not yet flushed.
+	Buffered() int
+}
+
+type streamState struct {
+	out       io.Writer
+	threshold int
+	flushed   bool
+	err       error
+}
+
+type streamWriter struct {
+	*genericWriter
+}
+
+// NewCompactJsonStreamWriter returns a StreamWriter that serializes objects using JSON directly into the given
+// io.Writer, flushing whenever more than flushThreshold bytes are buffered (DefaultFlushThreshold is used if
+// flushThreshold is 0). This representation has no extraneous whitespace and is intended for wire transport. Note that
+// Finalize flushes the remaining bytes and always returns the empty string.
+func NewCompactJsonStreamWriter(out io.Writer, flushThreshold int) StreamWriter {
+	return NewCompactJsonStreamWriterWithExcludedFields(out, flushThreshold, nil)
+}
+
+// NewCompactJsonStreamWriterWithExcludedFields is the same as NewCompactJsonStreamWriter except any fields matched by
+// the given PathSpec are excluded from serialization.
+func NewCompactJsonStreamWriterWithExcludedFields(out io.Writer, flushThreshold int, excludedFields PathSpec) StreamWriter {
+	if flushThreshold <= 0 {
+		flushThreshold = DefaultFlushThreshold
+	}
+	gw := newGenericWriter(&compactJsonWriter{new(jwriter.Writer)}, excludedFields)
+	gw.stream = &streamState{
+		out:       out,
+		threshold: flushThreshold,
+	}
+	return &streamWriter{genericWriter: gw}
+}
+
+func (s *streamWriter) Flush() error {
+	return s.flush()
+}
+
+func (s *streamWriter) Flushed() bool {
+	return s.stream.flushed
+}
+
+func (s *streamWriter) Buffered() int {
+	return s.Size()
+}
+
+func (s *streamWriter) Finalize() string {
+	_ = s.flush()
+	return ""
+}
+
+func (gw *genericWriter) flush() error {
+	if gw.stream.err != nil {
+		return gw.stream.err
+	}
+	if gw.Size() == 0 {
+		return nil
+	}
+	gw.stream.flushed = true
+	_, gw.stream.err = gw.getWriter().DumpTo(gw.stream.out)
+	return gw.stream.err
+}
+
+// maybeFlush flushes the buffered bytes if this is a streaming writer and the threshold has been exceeded.
+func (gw *genericWriter) maybeFlush() {
+	if gw.stream != nil && gw.Size() >= gw.stream.threshold {
+		_ = gw.flush()
+	}
+}
+
+// streamErr returns the error returned by the backing io.Writer of a streaming writer, if any. Returning it from
+// WriteMap and WriteArray stops the serialization as early as possible since the output is lost anyway.
+func (gw *genericWriter) streamErr() error {
+	if gw.stream != nil {
+		return gw.stream.err
+	}
+	return nil
+}
+
+// writeUnsortedMap is the streaming counterpart to WriteMap. Entries are written directly into the output as they are
+// provided by the MapWriter, meaning they can be flushed before the map is closed.
+func (gw *genericWriter) writeUnsortedMap(mapWriter MapWriter) (err error) {
+	entries := 0
+	started := false
+	err = mapWriter(func(key string) Writer {
+		if started {
+			// If started is true then a scope was entered and needs to be cleared
+			gw.exitScope()
+		} else {
+			started = true
+		}
+		gw.enterScope(key)
+		if gw.excludedFields.Matches(gw.scope) {
+			return NoopWriter
+		}
+
+		if entries == 0 {
+			gw.writeMapStart()
+		} else {
+			gw.maybeFlush()
+			gw.writeEntryDelimiter()
+		}
+		entries++
+
+		gw.writeKey(key)
+		gw.writeKeyDelimiter()
+		return gw
+	})
+	if err != nil {
+		return err
+	}
+
+	if started {
+		gw.exitScope()
+	}
+
+	if entries == 0 {
+		gw.writeEmptyMap()
+	} else {
+		gw.writeMapEnd()
+	}
+	return gw.streamErr()
+}
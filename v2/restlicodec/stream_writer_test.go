@@ -0,0 +1,82 @@
+package restlicodec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type objects []*Object
+
+func (o objects) MarshalRestLi(writer Writer) error {
+	return writer.WriteMap(func(keyWriter func(string) Writer) error {
+		keyWriter("total").WriteInt32(int32(len(o)))
+		return keyWriter("elements").WriteArray(func(itemWriter func() Writer) error {
+			for _, obj := range o {
+				if err := obj.MarshalRestLi(itemWriter()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func newObjects(count int) objects {
+	o := make(objects, count)
+	for i := range o {
+		o[i] = &Object{Status: int32(i)}
+	}
+	return o
+}
+
+func TestStreamWriter(t *testing.T) {
+	o := newObjects(100)
+
+	expectedWriter := NewCompactJsonWriter()
+	require.NoError(t, o.MarshalRestLi(expectedWriter))
+	expected := expectedWriter.Finalize()
+
+	out := new(bytes.Buffer)
+	w := NewCompactJsonStreamWriter(out, 64)
+	require.NoError(t, o.MarshalRestLi(w))
+	require.True(t, w.Flushed())
+	require.NotZero(t, out.Len())
+	require.Less(t, w.Buffered(), len(expected))
+	require.Empty(t, w.Finalize())
+	require.Zero(t, w.Buffered())
+
+	require.JSONEq(t, expected, out.String())
+}
+
+func TestStreamWriterUnderThreshold(t *testing.T) {
+	out := new(bytes.Buffer)
+	w := NewCompactJsonStreamWriter(out, 0)
+	require.NoError(t, newObjects(10).MarshalRestLi(w))
+	require.False(t, w.Flushed())
+	require.Zero(t, out.Len())
+	require.NotZero(t, w.Buffered())
+
+	require.NoError(t, w.Flush())
+	require.True(t, w.Flushed())
+	require.JSONEq(t, `{"total":10,"elements":[{"status":0},{"status":1},{"status":2},{"status":3},{"status":4},`+
+		`{"status":5},{"status":6},{"status":7},{"status":8},{"status":9}]}`, out.String())
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (f *failingWriter) Write([]byte) (int, error) {
+	return 0, f.err
+}
+
+func TestStreamWriterStopsOnWriteError(t *testing.T) {
+	expectedErr := errors.New("closed")
+	w := NewCompactJsonStreamWriter(&failingWriter{err: expectedErr}, 16)
+	require.ErrorIs(t, newObjects(100).MarshalRestLi(w), expectedErr)
+	require.True(t, w.Flushed())
+	require.ErrorIs(t, w.Flush(), expectedErr)
+}
@@ -149,6 +149,8 @@ type rawWriter interface {
 type genericWriter struct {
 	excludedFields PathSpec
 	scope          []string
+	// stream is only set for writers returned by the New*StreamWriter functions
+	stream *streamState
 	rawWriter
 }
 
@@ -174,6 +176,10 @@ func (gw *genericWriter) exitScope() {
 }
 
 func (gw *genericWriter) WriteMap(mapWriter MapWriter) (err error) {
+	if gw.stream != nil {
+		return gw.writeUnsortedMap(mapWriter)
+	}
+
 	type entry struct {
 		key    string
 		writer *jwriter.Writer
@@ -243,6 +249,7 @@ func (gw *genericWriter) WriteArray(arrayWriter ArrayWriter) (err error) {
 			gw.writeArrayStart()
 			empty = false
 		} else {
+			gw.maybeFlush()
 			gw.writeArrayItemDelimiter()
 		}
 		return gw
@@ -257,7 +264,7 @@ func (gw *genericWriter) WriteArray(arrayWriter ArrayWriter) (err error) {
 	} else {
 		gw.writeArrayEnd()
 	}
-	return nil
+	return gw.streamErr()
 }
 
 func (gw *genericWriter) IsKeyExcluded(key string) bool {
@@ -3,6 +3,7 @@ package common
 import (
 	"net/http"
 
+	"github.com/PapaCharlie/go-restli/v2/restli/validation"
 	"github.com/PapaCharlie/go-restli/v2/restlicodec"
 )
 
@@ -20,6 +21,7 @@ const (
 	MetadataField = "metadata"
 	EntityField   = "entity"
 	EntitiesField = "entities"
+	IsErrorField  = "isError"
 )
 
 var (
@@ -314,9 +316,17 @@ func (c *CreatedAndReturnedEntity[K, V]) MarshalRestLi(writer restlicodec.Writer
 	})
 }
 
+// ElementSource produces elements one at a time by calling yield for each element, which allows servers to stream the
+// results of finders and get_all methods instead of holding them all in memory. Any error returned by yield should be
+// returned immediately, as it indicates the response can no longer be written.
+type ElementSource[V restlicodec.Marshaler] func(yield func(V) error) error
+
 type Elements[V restlicodec.Marshaler] struct {
 	Elements []V
 	Paging   *CollectionMetadata
+	// Source is an optional ElementSource from which more elements will be read during serialization, after all the
+	// elements in Elements are written. It is never populated during deserialization.
+	Source ElementSource[V]
 }
 
 func (f *Elements[V]) NewInstance() *Elements[V] {
@@ -324,7 +334,24 @@ func (f *Elements[V]) NewInstance() *Elements[V] {
 }
 
 func (f *Elements[V]) marshalElements(keyWriter func(key string) restlicodec.Writer) (err error) {
-	return restlicodec.WriteArray(keyWriter(ElementsField), f.Elements, V.MarshalRestLi)
+	return writeElements(keyWriter(ElementsField), f.Elements, f.Source)
+}
+
+func writeElements[V restlicodec.Marshaler](writer restlicodec.Writer, elements []V, source ElementSource[V]) error {
+	if source == nil {
+		return restlicodec.WriteArray(writer, elements, V.MarshalRestLi)
+	}
+	return writer.WriteArray(func(itemWriter func() restlicodec.Writer) (err error) {
+		for _, v := range elements {
+			err = v.MarshalRestLi(itemWriter())
+			if err != nil {
+				return err
+			}
+		}
+		return source(func(v V) error {
+			return v.MarshalRestLi(itemWriter())
+		})
+	})
 }
 
 func (f *Elements[V]) marshalPaging(keyWriter func(key string) restlicodec.Writer) error {
@@ -367,10 +394,25 @@ func (f *Elements[V]) UnmarshalRestLi(reader restlicodec.Reader) error {
 	return reader.ReadRecord(elementsRequiredResponseFields, f.unmarshalRestLi)
 }
 
+// Validate returns a *validation.Error listing all the violations of the constraints declared by the @validate
+// annotations of the elements, or nil if there are none.
+func (f *Elements[V]) Validate() error {
+	return validation.Validate(f)
+}
+
+func (f *Elements[V]) CollectViolations(c *validation.Collector) {
+	validation.Slice(c, ElementsField, f.Elements, func(c *validation.Collector, item V) {
+		validation.Nested(c, "", item)
+	})
+}
+
 type ElementsWithMetadata[V, M restlicodec.Marshaler] struct {
 	Elements []V
 	Paging   *CollectionMetadata
 	Metadata M
+	// Source is an optional ElementSource from which more elements will be read during serialization, after all the
+	// elements in Elements are written. It is never populated during deserialization.
+	Source ElementSource[V]
 }
 
 func (f *ElementsWithMetadata[V, M]) NewInstance() *ElementsWithMetadata[V, M] {
@@ -379,7 +421,7 @@ func (f *ElementsWithMetadata[V, M]) NewInstance() *ElementsWithMetadata[V, M] {
 
 func (f *ElementsWithMetadata[V, M]) MarshalRestLi(writer restlicodec.Writer) (err error) {
 	return writer.WriteMap(func(keyWriter func(key string) restlicodec.Writer) (err error) {
-		err = restlicodec.WriteArray(keyWriter(ElementsField), f.Elements, V.MarshalRestLi)
+		err = writeElements(keyWriter(ElementsField), f.Elements, f.Source)
 		if err != nil {
 			return err
 		}
@@ -417,3 +459,121 @@ func (f *ElementsWithMetadata[V, M]) UnmarshalRestLi(reader restlicodec.Reader)
 		}
 	})
 }
+
+// BatchFinderCriteriaResult is the result of a single criteria of a batch finder. Batch finders return one result per
+// criteria, in the same order as the criteria. If Error is set, the criteria failed and Elements and Paging are
+// ignored.
+type BatchFinderCriteriaResult[V restlicodec.Marshaler] struct {
+	Elements []V
+	Paging   *CollectionMetadata
+	Error    *ErrorResponse
+}
+
+func (b *BatchFinderCriteriaResult[V]) NewInstance() *BatchFinderCriteriaResult[V] {
+	return new(BatchFinderCriteriaResult[V])
+}
+
+func (b *BatchFinderCriteriaResult[V]) MarshalRestLi(writer restlicodec.Writer) error {
+	return writer.WriteMap(func(keyWriter func(key string) restlicodec.Writer) (err error) {
+		if b.Error != nil {
+			return writeCriteriaError(keyWriter, b.Error)
+		}
+		return writeCriteriaElements(keyWriter, b.Elements, b.Paging)
+	})
+}
+
+func (b *BatchFinderCriteriaResult[V]) UnmarshalRestLi(reader restlicodec.Reader) error {
+	return reader.ReadRecord(elementsRequiredResponseFields, func(reader restlicodec.Reader, field string) (err error) {
+		return readCriteriaField(reader, field, &b.Elements, &b.Paging, &b.Error)
+	})
+}
+
+// BatchFinderCriteriaResultWithMetadata is the same as BatchFinderCriteriaResult, for batch finders that declare
+// metadata. The metadata is only serialized if the criteria did not fail.
+type BatchFinderCriteriaResultWithMetadata[V, M restlicodec.Marshaler] struct {
+	Elements []V
+	Paging   *CollectionMetadata
+	Metadata M
+	Error    *ErrorResponse
+}
+
+func (b *BatchFinderCriteriaResultWithMetadata[V, M]) NewInstance() *BatchFinderCriteriaResultWithMetadata[V, M] {
+	return new(BatchFinderCriteriaResultWithMetadata[V, M])
+}
+
+func (b *BatchFinderCriteriaResultWithMetadata[V, M]) MarshalRestLi(writer restlicodec.Writer) error {
+	return writer.WriteMap(func(keyWriter func(key string) restlicodec.Writer) (err error) {
+		if b.Error != nil {
+			return writeCriteriaError(keyWriter, b.Error)
+		}
+		err = b.Metadata.MarshalRestLi(keyWriter(MetadataField))
+		if err != nil {
+			return err
+		}
+		return writeCriteriaElements(keyWriter, b.Elements, b.Paging)
+	})
+}
+
+func (b *BatchFinderCriteriaResultWithMetadata[V, M]) UnmarshalRestLi(reader restlicodec.Reader) error {
+	return reader.ReadRecord(elementsRequiredResponseFields, func(reader restlicodec.Reader, field string) (err error) {
+		if field == MetadataField {
+			b.Metadata, err = restlicodec.UnmarshalRestLi[M](reader)
+			return err
+		}
+		return readCriteriaField(reader, field, &b.Elements, &b.Paging, &b.Error)
+	})
+}
+
+func writeCriteriaError(keyWriter func(key string) restlicodec.Writer, errorResponse *ErrorResponse) (err error) {
+	err = errorResponse.MarshalRestLi(keyWriter(ErrorField))
+	if err != nil {
+		return err
+	}
+	keyWriter(IsErrorField).WriteBool(true)
+	// elements is a required field, even for criteria that failed
+	return keyWriter(ElementsField).WriteArray(func(func() restlicodec.Writer) error { return nil })
+}
+
+func writeCriteriaElements[V restlicodec.Marshaler](
+	keyWriter func(key string) restlicodec.Writer,
+	elements []V,
+	paging *CollectionMetadata,
+) (err error) {
+	err = restlicodec.WriteArray(keyWriter(ElementsField), elements, V.MarshalRestLi)
+	if err != nil {
+		return err
+	}
+	if paging != nil {
+		err = paging.MarshalRestLi(keyWriter(PagingField))
+		if err != nil {
+			return err
+		}
+	}
+	keyWriter(IsErrorField).WriteBool(false)
+	return nil
+}
+
+func readCriteriaField[V restlicodec.Marshaler](
+	reader restlicodec.Reader,
+	field string,
+	elements *[]V,
+	paging **CollectionMetadata,
+	errorResponse **ErrorResponse,
+) (err error) {
+	switch field {
+	case ElementsField:
+		*elements, err = restlicodec.ReadArray(reader, restlicodec.UnmarshalRestLi[V])
+		return err
+	case PagingField:
+		*paging = new(CollectionMetadata)
+		return (*paging).UnmarshalRestLi(reader)
+	case ErrorField:
+		*errorResponse = new(ErrorResponse)
+		return (*errorResponse).UnmarshalRestLi(reader)
+	case IsErrorField:
+		// The presence of the error is sufficient to determine whether the criteria failed
+		return reader.Skip()
+	default:
+		return restlicodec.NoSuchFieldErr
+	}
+}
@@ -3,20 +3,27 @@ package io.papacharlie.gorestli;
 import com.google.common.collect.ImmutableSet;
 import com.linkedin.restli.common.ResourceMethod;
 import com.linkedin.restli.restspec.ActionSchema;
+import com.linkedin.restli.restspec.BatchFinderSchema;
 import com.linkedin.restli.restspec.FinderSchema;
+import com.linkedin.restli.restspec.MaxBatchSizeSchema;
 import com.linkedin.restli.restspec.ParameterSchema;
 import com.linkedin.restli.restspec.ResourceSchema;
 import com.linkedin.restli.restspec.RestMethodSchema;
+import com.linkedin.restli.restspec.ServiceErrorSchema;
+import com.linkedin.restli.restspec.ServiceErrorSchemaArray;
+import io.papacharlie.gorestli.json.MaxBatchSize;
 import io.papacharlie.gorestli.json.Method;
 import io.papacharlie.gorestli.json.Method.MethodType;
 import io.papacharlie.gorestli.json.Record.Field;
 import io.papacharlie.gorestli.json.RestliType;
+import io.papacharlie.gorestli.json.ServiceError;
 import java.util.ArrayList;
 import java.util.Collections;
 import java.util.List;
 import java.util.Set;
 
 import static io.papacharlie.gorestli.json.Method.MethodType.ACTION;
+import static io.papacharlie.gorestli.json.Method.MethodType.BATCH_FINDER;
 import static io.papacharlie.gorestli.json.Method.MethodType.FINDER;
 import static io.papacharlie.gorestli.json.Method.MethodType.REST_METHOD;
 
@@ -60,6 +67,7 @@ public class MethodParser {
     if (action.getReturns() != null) {
       method._return = _typeParser.parseFromRestSpec(action.getReturns());
     }
+    method._serviceErrors = toServiceErrors(action.getServiceErrors());
 
     return method;
   }
@@ -73,6 +81,26 @@ public class MethodParser {
     if (finder.getMetadata() != null) {
       method._metadata = _typeParser.parseFromRestSpec(finder.getMetadata().getType());
     }
+    method._serviceErrors = toServiceErrors(finder.getServiceErrors());
+    if (finder.hasAssocKeys()) {
+      method._assocKeys = new ArrayList<>(finder.getAssocKeys());
+    } else if (finder.hasAssocKey()) {
+      method._assocKeys = Collections.singletonList(finder.getAssocKey());
+    }
+    return method;
+  }
+
+  public Method newBatchFinderMethod(BatchFinderSchema batchFinder) {
+    Method method = newMethod(batchFinder.getName(), BATCH_FINDER, false);
+    method._doc = batchFinder.getDoc();
+    method._params = toFieldList(batchFinder.getParameters());
+    method._batchParam = batchFinder.getBatchParam();
+    method._isPagingSupported = Boolean.TRUE.equals(batchFinder.isPagingSupported());
+    method._return = _resourceSchema;
+    if (batchFinder.getMetadata() != null) {
+      method._metadata = _typeParser.parseFromRestSpec(batchFinder.getMetadata().getType());
+    }
+    method._serviceErrors = toServiceErrors(batchFinder.getServiceErrors());
     return method;
   }
 
@@ -90,6 +118,11 @@ public class MethodParser {
     method._isPagingSupported = Boolean.TRUE.equals(restMethod.isPagingSupported());
     method._return = _resourceSchema;
     method._returnEntity = Utils.supportsReturnEntity(restMethod);
+    if (restMethod.hasMaxBatchSize()) {
+      MaxBatchSizeSchema maxBatchSize = restMethod.getMaxBatchSize();
+      method._maxBatchSize = new MaxBatchSize(maxBatchSize.getValue(), Boolean.TRUE.equals(maxBatchSize.isValidate()));
+    }
+    method._serviceErrors = toServiceErrors(restMethod.getServiceErrors());
     return method;
   }
 
@@ -109,6 +142,24 @@ public class MethodParser {
     return fields;
   }
 
+  public List<ServiceError> toServiceErrors(ServiceErrorSchemaArray serviceErrors) {
+    if (serviceErrors == null || serviceErrors.isEmpty()) {
+      return Collections.emptyList();
+    }
+
+    List<ServiceError> errors = new ArrayList<>();
+    for (ServiceErrorSchema serviceError : serviceErrors) {
+      errors.add(new ServiceError(
+          serviceError.getCode(),
+          serviceError.getStatus(),
+          serviceError.getMessage(),
+          serviceError.hasErrorDetailType()
+              ? _typeParser.parseFromRestSpec(serviceError.getErrorDetailType())
+              : null));
+    }
+    return errors;
+  }
+
   private Method newMethod(String name, MethodType methodType, boolean onEntity) {
     Method method = new Method();
     method._name = name;
@@ -1,12 +1,18 @@
 package io.papacharlie.gorestli;
 
+import com.google.common.base.Preconditions;
 import com.linkedin.restli.restspec.ActionSchema;
 import com.linkedin.restli.restspec.ActionSchemaArray;
+import com.linkedin.restli.restspec.AlternativeKeySchema;
+import com.linkedin.restli.restspec.AlternativeKeySchemaArray;
+import com.linkedin.restli.restspec.AssociationSchema;
+import com.linkedin.restli.restspec.BatchFinderSchema;
 import com.linkedin.restli.restspec.CollectionSchema;
 import com.linkedin.restli.restspec.FinderSchema;
 import com.linkedin.restli.restspec.ResourceSchema;
 import com.linkedin.restli.restspec.RestMethodSchema;
 import com.linkedin.restli.restspec.SimpleSchema;
+import io.papacharlie.gorestli.json.AlternativeKey;
 import io.papacharlie.gorestli.json.PathKey;
 import io.papacharlie.gorestli.json.Resource;
 import io.papacharlie.gorestli.json.ResourcePathSegment;
@@ -36,6 +42,8 @@ public class ResourceParser {
     PathKey key;
     if (_schema.getCollection() != null) {
       key = _typeParser.collectionPathKey(_schema.getName(), namespace(), _schema.getCollection(), _resourceFile);
+    } else if (_schema.getAssociation() != null) {
+      key = _typeParser.associationPathKey(_schema.getName(), namespace(), _schema.getAssociation(), _resourceFile);
     } else {
       key = null;
     }
@@ -63,11 +71,6 @@ public class ResourceParser {
   }
 
   private Set<Resource> parse() {
-    // Skip parsing association resources and all subresources
-    if (_schema.hasAssociation()) {
-      return Collections.emptySet();
-    }
-
     Resource resource = newResource();
 
     Set<Resource> resourcesAndSubResources = new HashSet<>();
@@ -90,6 +93,7 @@ public class ResourceParser {
     if (_schema.getCollection() != null) {
       CollectionSchema collection = _schema.getCollection();
       addRestMethods(resource, collection.getMethods());
+      addAlternativeKeys(resource, collection.getAlternativeKeys());
       addActions(resource, collection.getActions(), false);
       addActions(resource, collection.getEntity().getActions(), true);
 
@@ -97,11 +101,35 @@ public class ResourceParser {
         resource.addMethod(_methodParser.newFinderMethod(finder));
       }
 
+      for (BatchFinderSchema batchFinder : Utils.emptyIfNull(collection.getBatchFinders())) {
+        resource.addMethod(_methodParser.newBatchFinderMethod(batchFinder));
+      }
+
       for (ResourceSchema subResource : Utils.emptyIfNull(collection.getEntity().getSubresources())) {
         resourcesAndSubResources.addAll(new ResourceParser(this, subResource).parse());
       }
     }
 
+    if (_schema.getAssociation() != null) {
+      AssociationSchema association = _schema.getAssociation();
+      addRestMethods(resource, association.getMethods());
+      addAlternativeKeys(resource, association.getAlternativeKeys());
+      addActions(resource, association.getActions(), false);
+      addActions(resource, association.getEntity().getActions(), true);
+
+      for (FinderSchema finder : Utils.emptyIfNull(association.getFinders())) {
+        resource.addMethod(_methodParser.newFinderMethod(finder));
+      }
+
+      for (BatchFinderSchema batchFinder : Utils.emptyIfNull(association.getBatchFinders())) {
+        resource.addMethod(_methodParser.newBatchFinderMethod(batchFinder));
+      }
+
+      for (ResourceSchema subResource : Utils.emptyIfNull(association.getEntity().getSubresources())) {
+        resourcesAndSubResources.addAll(new ResourceParser(this, subResource).parse());
+      }
+    }
+
     return resourcesAndSubResources;
   }
 
@@ -118,10 +146,21 @@ public class ResourceParser {
         resourceType,
         readOnlyFields,
         createOnlyFields,
+        _methodParser.toServiceErrors(_schema.getServiceErrors()),
         _resourcePathSegments
     );
   }
 
+  private void addAlternativeKeys(Resource resource, AlternativeKeySchemaArray alternativeKeys) {
+    for (AlternativeKeySchema altKey : Utils.emptyIfNull(alternativeKeys)) {
+      RestliType keyType = _typeParser.parseFromRestSpec(altKey.getType());
+      Preconditions.checkArgument(keyType._primitive != null || keyType._reference != null,
+          "Alternative key \"%s\" must be a primitive, an enum or a typeref", altKey.getName());
+      resource.addAlternativeKey(new AlternativeKey(altKey.getName(), altKey.getDoc(), keyType,
+          altKey.getKeyCoercer()));
+    }
+  }
+
   private String namespace() {
     return String.join(".", _namespaceChain);
   }
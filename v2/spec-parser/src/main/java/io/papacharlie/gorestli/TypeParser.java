@@ -2,6 +2,7 @@ package io.papacharlie.gorestli;
 
 import com.google.common.base.Preconditions;
 import com.google.common.collect.ImmutableSet;
+import com.linkedin.data.DataMap;
 import com.linkedin.data.schema.ArrayDataSchema;
 import com.linkedin.data.schema.DataSchema;
 import com.linkedin.data.schema.DataSchemaLocation;
@@ -13,10 +14,14 @@ import com.linkedin.data.schema.RecordDataSchema;
 import com.linkedin.data.schema.TyperefDataSchema;
 import com.linkedin.data.schema.UnionDataSchema;
 import com.linkedin.pegasus.generator.DataSchemaParser;
+import com.linkedin.restli.restspec.AssocKeySchema;
+import com.linkedin.restli.restspec.AssociationSchema;
 import com.linkedin.restli.restspec.CollectionSchema;
 import com.linkedin.restli.restspec.ResourceSchema;
 import com.linkedin.restli.restspec.RestSpecCodec;
 import com.linkedin.restli.tools.snapshot.gen.SnapshotGenerator;
+import io.papacharlie.gorestli.json.AssociationKey;
+import io.papacharlie.gorestli.json.AssociationKey.AssocKey;
 import io.papacharlie.gorestli.json.ComplexKey;
 import io.papacharlie.gorestli.json.DataType;
 import io.papacharlie.gorestli.json.Enum;
@@ -58,6 +63,8 @@ public class TypeParser {
       new Identifier("restlidata", "RawRecord")
   );
 
+  private static final String VALIDATE_PROPERTY = "validate";
+
   private final DataSchemaParser _parser;
   private final Set<String> _rawRecords;
   private final Map<Identifier, DataType> _dataTypes = new HashMap<>();
@@ -138,6 +145,24 @@ public class TypeParser {
     return new PathKey(collection.getIdentifier().getName(), pkType);
   }
 
+  public PathKey associationPathKey(String resourceName, String resourceNamespace, AssociationSchema association,
+      Path specFile) {
+    List<AssocKey> keys = new ArrayList<>();
+    for (AssocKeySchema assocKey : association.getAssocKeys()) {
+      RestliType keyType = parseFromRestSpec(assocKey.getType());
+      Preconditions.checkArgument(keyType._primitive != null || keyType._reference != null,
+          "Association key \"%s\" must be a primitive, an enum or a typeref", assocKey.getName());
+      keys.add(new AssocKey(assocKey.getName(), keyType));
+    }
+    AssociationKey key = new AssociationKey(resourceName, resourceNamespace, specFile, keys);
+    registerDataType(new DataType(key));
+
+    // Older restspecs do not declare the association's identifier, which then defaults to the resource's name suffixed
+    // with "Id"
+    String identifier = association.hasIdentifier() ? association.getIdentifier() : resourceName + "Id";
+    return new PathKey(identifier, key.restliType());
+  }
+
   private void parseDataType(RecordDataSchema schema, Path sourceFile) {
     if (_rawRecords.contains(schema.getFullName())) {
       return;
@@ -165,7 +190,8 @@ public class TypeParser {
               Arrays.asList(schema.getName(), field.getName())
           ),
           field.getOptional(),
-          field.getDefault()));
+          field.getDefault(),
+          validators(field)));
     }
 
     List<Identifier> includes = schema.getInclude().stream()
@@ -175,6 +201,29 @@ public class TypeParser {
     registerDataType(new DataType(new Record(schema, sourceFile, includes, fields)));
   }
 
+  /**
+   * Returns the @validate annotations of the given field, which include the annotations of the typerefs of its type.
+   * Annotations declared closer to the field take precedence, i.e. the field's own annotations override the ones of
+   * its typeref, which override the ones of the typeref it references, and so on.
+   */
+  private static Map<String, Object> validators(RecordDataSchema.Field field) {
+    List<Map<String, Object>> properties = new ArrayList<>();
+    properties.add(field.getProperties());
+    for (DataSchema type = field.getType(); type.getType() == TYPEREF; type = ((TyperefDataSchema) type).getRef()) {
+      properties.add(type.getProperties());
+    }
+    Collections.reverse(properties);
+
+    Map<String, Object> validators = new HashMap<>();
+    for (Map<String, Object> p : properties) {
+      Object validate = p.get(VALIDATE_PROPERTY);
+      if (validate instanceof DataMap) {
+        validators.putAll((DataMap) validate);
+      }
+    }
+    return validators;
+  }
+
   private void parseDataType(EnumDataSchema schema, Path sourceFile) {
     registerDataType(new DataType(new Enum(schema, sourceFile, schema.getSymbols(), schema.getSymbolDocs())));
   }
@@ -0,0 +1,15 @@
+package io.papacharlie.gorestli.json;
+
+public class AlternativeKey {
+  public final String _name;
+  public final String _doc;
+  public final RestliType _type;
+  public final String _keyCoercer;
+
+  public AlternativeKey(String name, String doc, RestliType type, String keyCoercer) {
+    _name = name;
+    _doc = doc;
+    _type = type;
+    _keyCoercer = keyCoercer;
+  }
+}
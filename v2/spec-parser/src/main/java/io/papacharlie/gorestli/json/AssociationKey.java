@@ -0,0 +1,32 @@
+package io.papacharlie.gorestli.json;
+
+import io.papacharlie.gorestli.Utils;
+import java.nio.file.Path;
+import java.util.List;
+
+
+public class AssociationKey extends NamedType {
+  private static final String DOC_FORMAT = "Association Key for %s";
+
+  public final List<AssocKey> _keys;
+
+  public AssociationKey(String resourceName, String resourceNamespace, Path restSpecFile, List<AssocKey> keys) {
+    super(associationKeyTypeName(resourceName), resourceNamespace, String.format(DOC_FORMAT, resourceName),
+        restSpecFile);
+    _keys = keys;
+  }
+
+  private static String associationKeyTypeName(String resourceName) {
+    return Utils.exportedIdentifier(resourceName) + "_AssociationKey";
+  }
+
+  public static class AssocKey {
+    public final String _name;
+    public final RestliType _type;
+
+    public AssocKey(String name, RestliType type) {
+      _name = name;
+      _type = type;
+    }
+  }
+}
@@ -8,6 +8,7 @@ public class DataType {
   private Fixed _fixed;
   private Record _record;
   private ComplexKey _complexKey;
+  private AssociationKey _associationKey;
   private StandaloneUnion _standaloneUnion;
   private Typeref _typeref;
 
@@ -27,6 +28,10 @@ public class DataType {
     _complexKey = Preconditions.checkNotNull(complexKey);
   }
 
+  public DataType(AssociationKey associationKey) {
+    _associationKey = Preconditions.checkNotNull(associationKey);
+  }
+
   public DataType(StandaloneUnion standaloneUnion) {
     _standaloneUnion = Preconditions.checkNotNull(standaloneUnion);
   }
@@ -65,6 +70,9 @@ public class DataType {
     if (_complexKey != null) {
       return _complexKey;
     }
+    if (_associationKey != null) {
+      return _associationKey;
+    }
     if (_standaloneUnion != null) {
       return _standaloneUnion;
     }
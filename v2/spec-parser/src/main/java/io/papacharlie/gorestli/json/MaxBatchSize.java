@@ -0,0 +1,11 @@
+package io.papacharlie.gorestli.json;
+
+public class MaxBatchSize {
+  public final int _value;
+  public final boolean _validate;
+
+  public MaxBatchSize(int value, boolean validate) {
+    _value = value;
+    _validate = validate;
+  }
+}
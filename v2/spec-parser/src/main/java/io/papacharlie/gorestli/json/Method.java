@@ -8,7 +8,8 @@ public class Method {
   public enum MethodType {
     REST_METHOD,
     ACTION,
-    FINDER
+    FINDER,
+    BATCH_FINDER
   }
 
   public MethodType _methodType;
@@ -20,4 +21,8 @@ public class Method {
   public RestliType _return;
   public boolean _returnEntity;
   public RestliType _metadata;
+  public MaxBatchSize _maxBatchSize;
+  public List<ServiceError> _serviceErrors;
+  public String _batchParam;
+  public List<String> _assocKeys;
 }
@@ -6,6 +6,7 @@ import io.papacharlie.gorestli.json.RestliType.Identifier;
 import java.nio.charset.StandardCharsets;
 import java.nio.file.Path;
 import java.util.List;
+import java.util.Map;
 
 import static io.papacharlie.gorestli.Utils.UGLY_GSON;
 
@@ -26,17 +27,24 @@ public class Record extends NamedType {
     public final RestliType _type;
     public final boolean _isOptional;
     public final String _defaultValue;
-
-    public Field(String name, String doc, RestliType type, Boolean isOptional, Object defaultValue) {
+    /**
+     * The field's @validate annotations, indexed by validator name (e.g. "strlen" or "com.example.UrlValidator"), or
+     * null if it has none.
+     */
+    public final Map<String, Object> _validators;
+
+    public Field(String name, String doc, RestliType type, Boolean isOptional, Object defaultValue,
+        Map<String, Object> validators) {
       _name = name;
       _doc = doc;
       _type = type;
       _isOptional = isOptional != null && isOptional;
       _defaultValue = serializeDefaultValue(defaultValue);
+      _validators = validators == null || validators.isEmpty() ? null : validators;
     }
 
     public Field(String name, String doc, RestliType type, Boolean isOptional) {
-      this(name, doc, type, isOptional, null);
+      this(name, doc, type, isOptional, null, null);
     }
 
     private static String serializeDefaultValue(Object value) {
@@ -14,11 +14,14 @@ public class Resource {
   public final RestliType _resourceSchema;
   public final Set<String> _readOnlyFields;
   public final Set<String> _createOnlyFields;
+  public final List<ServiceError> _serviceErrors;
   public final List<Method> _methods = new ArrayList<>();
   public final List<ResourcePathSegment> _resourcePathSegments;
+  public final List<AlternativeKey> _alternativeKeys = new ArrayList<>();
 
   public Resource(String namespace, String doc, Path sourceFile, RestliType resourceSchema,
-      Set<String> readOnlyFields, Set<String> createOnlyFields, List<ResourcePathSegment> resourcePathSegments) {
+      Set<String> readOnlyFields, Set<String> createOnlyFields, List<ServiceError> serviceErrors,
+      List<ResourcePathSegment> resourcePathSegments) {
     _namespace = namespace;
     _doc = doc;
     _sourceFile = sourceFile;
@@ -26,6 +29,7 @@ public class Resource {
     _resourceSchema = resourceSchema;
     _readOnlyFields = readOnlyFields;
     _createOnlyFields = createOnlyFields;
+    _serviceErrors = serviceErrors;
   }
 
   public Resource addMethod(Method m) {
@@ -33,6 +37,11 @@ public class Resource {
     return this;
   }
 
+  public Resource addAlternativeKey(AlternativeKey k) {
+    _alternativeKeys.add(k);
+    return this;
+  }
+
 
   @Override
   public int hashCode() {
@@ -0,0 +1,15 @@
+package io.papacharlie.gorestli.json;
+
+public class ServiceError {
+  public final String _code;
+  public final int _status;
+  public final String _message;
+  public final RestliType _errorDetailType;
+
+  public ServiceError(String code, int status, String message, RestliType errorDetailType) {
+    _code = code;
+    _status = status;
+    _message = message;
+    _errorDetailType = errorDetailType;
+  }
+}